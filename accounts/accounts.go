@@ -0,0 +1,80 @@
+// Package accounts defines the accounts whose keys are held by the node, and
+// the ledgers their location-prefixed addresses belong to.
+package accounts
+
+import (
+	"fmt"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/crypto"
+)
+
+// Account is an account whose key is held in a keystore.
+type Account struct {
+	Address common.Address `json:"address"`
+	URL     string         `json:"url"` // Path of the key file of the account
+}
+
+// Ledger is the ledger of a zone an address belongs to. The ledger is encoded
+// in the first bit of the second byte of an address, as its location is
+// encoded in the first byte.
+type Ledger uint8
+
+const (
+	QuaiLedger Ledger = iota // Account based ledger executing the EVM
+	QiLedger                 // UTXO based ledger of denominated outputs
+)
+
+// String implements fmt.Stringer.
+func (l Ledger) String() string {
+	switch l {
+	case QuaiLedger:
+		return "quai"
+	case QiLedger:
+		return "qi"
+	default:
+		return fmt.Sprintf("ledger(%d)", uint8(l))
+	}
+}
+
+// ParseLedger returns the ledger of the given name, "quai" or "qi".
+func ParseLedger(name string) (Ledger, error) {
+	switch name {
+	case "quai":
+		return QuaiLedger, nil
+	case "qi":
+		return QiLedger, nil
+	default:
+		return 0, fmt.Errorf("unknown ledger %q, want quai or qi", name)
+	}
+}
+
+// LedgerOf returns the ledger an address belongs to.
+func LedgerOf(addr common.Address) Ledger {
+	if addr.IsInQiLedgerScope() {
+		return QiLedger
+	}
+	return QuaiLedger
+}
+
+// AddressOf returns the address of the given bytes as seen from the location
+// they are prefixed with, i.e. as an address internal to its own zone.
+func AddressOf(b common.AddressBytes) common.Address {
+	return common.Bytes20ToAddress(b, common.LocationFromAddressBytes(b[:]))
+}
+
+// InScope reports whether an address belongs to the ledger of the zone at the
+// given location.
+func InScope(addr common.Address, location common.Location, ledger Ledger) bool {
+	return addr.Location().Equal(location) && LedgerOf(addr) == ledger
+}
+
+// TextHash returns the hash of a message signed with personal_sign. The
+// message is prefixed to make the signature distinguishable from the one of
+// a transaction:
+//
+//	keccak256("\x19Quai Signed Message:\n" + len(message) + message)
+func TextHash(data []byte) []byte {
+	msg := fmt.Sprintf("\x19Quai Signed Message:\n%d%s", len(data), data)
+	return crypto.Keccak256([]byte(msg))
+}
@@ -0,0 +1,106 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/google/uuid"
+)
+
+// Key is a private key together with the address it controls.
+type Key struct {
+	Id uuid.UUID // Version 4 random UUID, which identifies the key file
+	// The address is stored with the key to find the key file of an address
+	// without decrypting it
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// newKeyFromECDSA wraps a private key. Its address is the one of the location
+// it is prefixed with.
+func newKeyFromECDSA(privateKeyECDSA *ecdsa.PrivateKey) (*Key, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("could not create random uuid: %v", err)
+	}
+	return &Key{
+		Id:         id,
+		Address:    addressOf(&privateKeyECDSA.PublicKey),
+		PrivateKey: privateKeyECDSA,
+	}, nil
+}
+
+// newKey generates keys until the address of one of them is in the scope of
+// the given ledger of the zone at the given location. An address matches one
+// location and ledger out of 512, so this takes a few hundred attempts.
+func newKey(location common.Location, ledger accounts.Ledger) (*Key, error) {
+	for {
+		privateKeyECDSA, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		if accounts.InScope(addressOf(&privateKeyECDSA.PublicKey), location, ledger) {
+			return newKeyFromECDSA(privateKeyECDSA)
+		}
+	}
+}
+
+// addressOf returns the address of a public key within its own location.
+func addressOf(pub *ecdsa.PublicKey) common.Address {
+	return accounts.AddressOf(crypto.PubkeyToAddress(*pub, common.Location{}).Bytes20())
+}
+
+// keyFileName returns the name of the key file of an address, which sorts by
+// creation time: UTC--<created_at UTC ISO8601>--<address hex>.
+func keyFileName(addr common.Address) string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("UTC--%s--%s", toISO8601(ts), hex.EncodeToString(addr.Bytes()))
+}
+
+func toISO8601(t time.Time) string {
+	var tz string
+	name, offset := t.Zone()
+	if name == "UTC" {
+		tz = "Z"
+	} else {
+		tz = fmt.Sprintf("%03d00", offset/3600)
+	}
+	return fmt.Sprintf("%04d-%02d-%02dT%02d-%02d-%02d.%09d%s",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), tz)
+}
+
+// writeKeyFile writes the content of a key file to a temporary file in the
+// keystore directory first, and renames it once complete, so that a key file
+// is never seen half written.
+func writeKeyFile(file string, content []byte) error {
+	const dirPerm = 0700
+	if err := os.MkdirAll(filepath.Dir(file), dirPerm); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), file)
+}
+
+// zeroKey zeroes a private key in memory.
+func zeroKey(k *ecdsa.PrivateKey) {
+	b := k.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
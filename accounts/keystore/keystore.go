@@ -0,0 +1,434 @@
+// Package keystore implements encrypted storage of the keys of Quai and Qi
+// accounts, in scrypt-encrypted key files of the Web3 Secret Storage format.
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/wallet"
+)
+
+var (
+	// ErrLocked is returned if a key is needed to sign but its account has
+	// not been unlocked
+	ErrLocked = errors.New("account is locked")
+
+	// ErrNoMatch is returned if no key file of the keystore holds the key of
+	// an address
+	ErrNoMatch = errors.New("no key for given address or file")
+
+	// ErrAccountAlreadyExists is returned if a key is imported for an address
+	// the keystore already holds a key of
+	ErrAccountAlreadyExists = errors.New("account already exists")
+)
+
+type unlocked struct {
+	*Key
+	abort chan struct{}
+}
+
+// KeyStore manages the key files of a directory. Keys are decrypted on demand
+// for a single signature, or unlocked to be kept in memory until locked again
+// or until their unlock times out.
+type KeyStore struct {
+	keydir  string
+	scryptN int
+	scryptP int
+
+	mu       sync.RWMutex
+	unlocked map[common.AddressBytes]*unlocked // Currently unlocked keys, by address
+}
+
+// NewKeyStore creates a keystore for the given directory, which is created
+// when the first key is stored. Keys are encrypted with the given scrypt
+// parameters.
+func NewKeyStore(keydir string, scryptN, scryptP int) *KeyStore {
+	if abs, err := filepath.Abs(keydir); err == nil {
+		keydir = abs
+	}
+	return &KeyStore{
+		keydir:   keydir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[common.AddressBytes]*unlocked),
+	}
+}
+
+// Dir returns the directory of the key files.
+func (ks *KeyStore) Dir() string {
+	return ks.keydir
+}
+
+// Accounts returns the accounts of the key files in the keystore directory,
+// oldest first. Files which are not key files are skipped.
+func (ks *KeyStore) Accounts() []accounts.Account {
+	entries, err := os.ReadDir(ks.keydir)
+	if err != nil {
+		return nil
+	}
+	accs := make([]accounts.Account, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		// Skip directories, editor backups and the temporary files of keys
+		// being written
+		if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		path := filepath.Join(ks.keydir, name)
+		keyjson, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		addr, err := addressFromJSON(keyjson)
+		if err != nil {
+			continue
+		}
+		accs = append(accs, accounts.Account{Address: addr, URL: path})
+	}
+	sort.Slice(accs, func(i, j int) bool {
+		return accs[i].URL < accs[j].URL
+	})
+	return accs
+}
+
+// Find returns the account of the key file of an address.
+func (ks *KeyStore) Find(addr common.Address) (accounts.Account, error) {
+	for _, a := range ks.Accounts() {
+		if a.Address.Equal(addr) {
+			return a, nil
+		}
+	}
+	return accounts.Account{}, ErrNoMatch
+}
+
+// HasAddress reports whether the keystore holds a key of the given address.
+func (ks *KeyStore) HasAddress(addr common.Address) bool {
+	_, err := ks.Find(addr)
+	return err == nil
+}
+
+// NewAccount generates a new key of the given ledger of the zone at the given
+// location, and stores it encrypted with the passphrase.
+func (ks *KeyStore) NewAccount(passphrase string, location common.Location, ledger accounts.Ledger) (accounts.Account, error) {
+	if len(location) != common.ZONE_CTX {
+		return accounts.Account{}, errors.New("accounts can only be created in a zone")
+	}
+	key, err := newKey(location, ledger)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return ks.storeKey(key, passphrase)
+}
+
+// Import stores the key of a key file, decrypted with the passphrase, and
+// encrypted again with the new passphrase.
+func (ks *KeyStore) Import(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error) {
+	key, err := DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return ks.importKey(key, newPassphrase)
+}
+
+// ImportECDSA stores a private key encrypted with the passphrase.
+func (ks *KeyStore) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
+	key, err := newKeyFromECDSA(priv)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return ks.importKey(key, passphrase)
+}
+
+func (ks *KeyStore) importKey(key *Key, passphrase string) (accounts.Account, error) {
+	if ks.HasAddress(key.Address) {
+		return accounts.Account{Address: key.Address}, ErrAccountAlreadyExists
+	}
+	return ks.storeKey(key, passphrase)
+}
+
+func (ks *KeyStore) storeKey(key *Key, passphrase string) (accounts.Account, error) {
+	keyjson, err := EncryptKey(key, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	path := filepath.Join(ks.keydir, keyFileName(key.Address))
+	if err := writeKeyFile(path, keyjson); err != nil {
+		return accounts.Account{}, err
+	}
+	return accounts.Account{Address: key.Address, URL: path}, nil
+}
+
+// Export returns the key file of an address, encrypted with the new
+// passphrase.
+func (ks *KeyStore) Export(addr common.Address, passphrase, newPassphrase string) ([]byte, error) {
+	key, err := ks.getDecryptedKey(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return EncryptKey(key, newPassphrase, ks.scryptN, ks.scryptP)
+}
+
+// Unlock unlocks the key of an address until the keystore is closed or the
+// account is locked again.
+func (ks *KeyStore) Unlock(addr common.Address, passphrase string) error {
+	return ks.TimedUnlock(addr, passphrase, 0)
+}
+
+// Lock removes the key of an address from memory.
+func (ks *KeyStore) Lock(addr common.Address) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if u, found := ks.unlocked[addr.Bytes20()]; found {
+		ks.expire(addr.Bytes20(), u)
+	}
+	return nil
+}
+
+// TimedUnlock unlocks the key of an address for the given duration, or until
+// it is locked again if the duration is zero. Unlocking an account that is
+// already unlocked resets its timeout, except for an account unlocked
+// indefinitely, which stays so.
+func (ks *KeyStore) TimedUnlock(addr common.Address, passphrase string, timeout time.Duration) error {
+	key, err := ks.getDecryptedKey(addr, passphrase)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	u, found := ks.unlocked[addr.Bytes20()]
+	if found {
+		if u.abort == nil {
+			zeroKey(key.PrivateKey)
+			return nil
+		}
+		// Terminate the expire goroutine and replace it below
+		close(u.abort)
+	}
+	if timeout > 0 {
+		u = &unlocked{Key: key, abort: make(chan struct{})}
+		go ks.expireAfter(addr.Bytes20(), u, timeout)
+	} else {
+		u = &unlocked{Key: key}
+	}
+	ks.unlocked[addr.Bytes20()] = u
+	return nil
+}
+
+// Unlocked reports whether the key of an address is unlocked.
+func (ks *KeyStore) Unlocked(addr common.Address) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	_, found := ks.unlocked[addr.Bytes20()]
+	return found
+}
+
+func (ks *KeyStore) expireAfter(addr common.AddressBytes, u *unlocked, timeout time.Duration) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-u.abort:
+		// Locked or unlocked again
+	case <-t.C:
+		ks.mu.Lock()
+		// A new unlock replaces the entry, so only remove it if it is still
+		// the one this goroutine was started for
+		if ks.unlocked[addr] == u {
+			ks.expire(addr, u)
+		}
+		ks.mu.Unlock()
+	}
+}
+
+// expire zeroes an unlocked key and removes it. The caller must hold the
+// write lock.
+func (ks *KeyStore) expire(addr common.AddressBytes, u *unlocked) {
+	if u.abort != nil {
+		select {
+		case <-u.abort:
+		default:
+			close(u.abort)
+		}
+	}
+	zeroKey(u.PrivateKey)
+	delete(ks.unlocked, addr)
+}
+
+// Close locks all unlocked accounts.
+func (ks *KeyStore) Close() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	for addr, u := range ks.unlocked {
+		ks.expire(addr, u)
+	}
+}
+
+// SignHash signs a hash with the unlocked key of an address.
+func (ks *KeyStore) SignHash(addr common.Address, hash []byte) ([]byte, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	u, found := ks.unlocked[addr.Bytes20()]
+	if !found {
+		return nil, ErrLocked
+	}
+	return crypto.Sign(hash, u.PrivateKey)
+}
+
+// SignHashWithPassphrase signs a hash with the key of an address, decrypted
+// with the passphrase for this signature only.
+func (ks *KeyStore) SignHashWithPassphrase(addr common.Address, passphrase string, hash []byte) ([]byte, error) {
+	key, err := ks.getDecryptedKey(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return crypto.Sign(hash, key.PrivateKey)
+}
+
+// SignTx signs a Quai transaction with the unlocked key of an address, which
+// must be in the Quai ledger of the zone of the transaction. Qi transactions
+// are signed with SignQiTx.
+func (ks *KeyStore) SignTx(addr common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if tx.Type() == types.QiTxType {
+		return nil, errors.New("Qi transactions are signed with the keys of their inputs")
+	}
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	u, found := ks.unlocked[addr.Bytes20()]
+	if !found {
+		return nil, ErrLocked
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID, *addr.Location()), u.PrivateKey)
+}
+
+// SignTxWithPassphrase signs a Quai transaction with the key of an address,
+// decrypted with the passphrase for this signature only.
+func (ks *KeyStore) SignTxWithPassphrase(addr common.Address, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if tx.Type() == types.QiTxType {
+		return nil, errors.New("Qi transactions are signed with the keys of their inputs")
+	}
+	key, err := ks.getDecryptedKey(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID, *addr.Location()), key.PrivateKey)
+}
+
+// SignQiTx signs a Qi transaction with the unlocked keys of the addresses of
+// its inputs, as given by their public keys. The keys of multiple inputs are
+// aggregated with MuSig2 into the single signature of the transaction.
+func (ks *KeyStore) SignQiTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return signQiTx(tx, chainID, func(addr common.Address) (*ecdsa.PrivateKey, error) {
+		u, found := ks.unlocked[addr.Bytes20()]
+		if !found {
+			return nil, ErrLocked
+		}
+		return u.PrivateKey, nil
+	})
+}
+
+// SignQiTxWithPassphrase signs a Qi transaction with the keys of the addresses
+// of its inputs, all decrypted with the same passphrase for this signature
+// only.
+func (ks *KeyStore) SignQiTxWithPassphrase(passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var keys []*Key
+	defer func() {
+		for _, key := range keys {
+			zeroKey(key.PrivateKey)
+		}
+	}()
+	return signQiTx(tx, chainID, func(addr common.Address) (*ecdsa.PrivateKey, error) {
+		key, err := ks.getDecryptedKey(addr, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		return key.PrivateKey, nil
+	})
+}
+
+// signQiTx signs a Qi transaction with the keys the lookup returns for the
+// addresses of its inputs.
+func signQiTx(tx *types.Transaction, chainID *big.Int, lookup func(common.Address) (*ecdsa.PrivateKey, error)) (*types.Transaction, error) {
+	if tx.Type() != types.QiTxType {
+		return nil, errors.New("not a Qi transaction")
+	}
+	if len(tx.TxIn()) == 0 {
+		return nil, errors.New("Qi transaction has no inputs")
+	}
+	var (
+		keys     = make([]*btcec.PrivateKey, len(tx.TxIn()))
+		location common.Location
+	)
+	for i, in := range tx.TxIn() {
+		if _, err := crypto.UnmarshalPubkey(in.PubKey); err != nil {
+			return nil, errors.New("Qi transaction input has no uncompressed public key")
+		}
+		addr := accounts.AddressOf(crypto.PubkeyBytesToAddress(in.PubKey, common.Location{}).Bytes20())
+		if i == 0 {
+			location = *addr.Location()
+		}
+		key, err := lookup(addr)
+		if err != nil {
+			return nil, err
+		}
+		keys[i], _ = btcec.PrivKeyFromBytes(crypto.FromECDSA(key))
+	}
+	qiTx := &types.QiTx{
+		ChainID: chainID,
+		TxIn:    tx.TxIn(),
+		TxOut:   tx.TxOut(),
+	}
+	digest := types.LatestSignerForChainID(chainID, location).Hash(types.NewTx(qiTx))
+	sig, err := wallet.Sign(keys, digest)
+	if err != nil {
+		return nil, err
+	}
+	qiTx.Signature = sig
+	return types.NewTx(qiTx), nil
+}
+
+// getDecryptedKey decrypts the key file of an address with the passphrase.
+func (ks *KeyStore) getDecryptedKey(addr common.Address, passphrase string) (*Key, error) {
+	a, err := ks.Find(addr)
+	if err != nil {
+		return nil, err
+	}
+	keyjson, err := os.ReadFile(a.URL)
+	if err != nil {
+		return nil, err
+	}
+	key, err := DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if !key.Address.Equal(addr) {
+		return nil, ErrNoMatch
+	}
+	return key, nil
+}
@@ -0,0 +1,218 @@
+package keystore
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/wallet"
+)
+
+var testLocation = common.Location{0, 1}
+
+func newTestKeyStore(t *testing.T) *KeyStore {
+	return NewKeyStore(t.TempDir(), LightScryptN, LightScryptP)
+}
+
+func TestNewAccount(t *testing.T) {
+	ks := newTestKeyStore(t)
+	for _, ledger := range []accounts.Ledger{accounts.QuaiLedger, accounts.QiLedger} {
+		a, err := ks.NewAccount("foo", testLocation, ledger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !accounts.InScope(a.Address, testLocation, ledger) {
+			t.Errorf("account %s not in %s ledger of %s", a.Address.Hex(), ledger, testLocation.Name())
+		}
+		if _, err := os.Stat(a.URL); err != nil {
+			t.Errorf("key file of %s: %v", a.Address.Hex(), err)
+		}
+		if !ks.HasAddress(a.Address) {
+			t.Errorf("keystore does not have %s", a.Address.Hex())
+		}
+	}
+	if accs := ks.Accounts(); len(accs) != 2 {
+		t.Fatalf("have %d accounts, want 2", len(accs))
+	}
+	if _, err := ks.NewAccount("foo", common.Location{0}, accounts.QuaiLedger); err == nil {
+		t.Error("created account outside of a zone")
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	ks := newTestKeyStore(t)
+	a, err := ks.NewAccount("foo", testLocation, accounts.QuaiLedger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Export(a.Address, "bar", "baz"); err != ErrDecrypt {
+		t.Fatalf("export with wrong passphrase: have %v, want %v", err, ErrDecrypt)
+	}
+	keyjson, err := ks.Export(a.Address, "foo", "baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Import(keyjson, "baz", "qux"); err != ErrAccountAlreadyExists {
+		t.Fatalf("import of existing account: have %v, want %v", err, ErrAccountAlreadyExists)
+	}
+	ks2 := newTestKeyStore(t)
+	imported, err := ks2.Import(keyjson, "baz", "qux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imported.Address.Equal(a.Address) {
+		t.Fatalf("imported %s, want %s", imported.Address.Hex(), a.Address.Hex())
+	}
+	if err := ks2.Unlock(a.Address, "qux"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	ks := newTestKeyStore(t)
+	a, err := ks.NewAccount("foo", testLocation, accounts.QuaiLedger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := crypto.Keccak256([]byte("hello"))
+	if _, err := ks.SignHash(a.Address, hash); err != ErrLocked {
+		t.Fatalf("signing with locked account: have %v, want %v", err, ErrLocked)
+	}
+	if err := ks.Unlock(a.Address, "bar"); err != ErrDecrypt {
+		t.Fatalf("unlock with wrong passphrase: have %v, want %v", err, ErrDecrypt)
+	}
+	if err := ks.Unlock(a.Address, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := ks.SignHash(a.Address, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := addressOf(pub); !signer.Equal(a.Address) {
+		t.Fatalf("signed by %s, want %s", signer.Hex(), a.Address.Hex())
+	}
+	// Unlocking an account unlocked indefinitely with a timeout keeps it
+	// unlocked
+	if err := ks.TimedUnlock(a.Address, "foo", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !ks.Unlocked(a.Address) {
+		t.Fatal("account unlocked indefinitely was locked by a timeout")
+	}
+	if err := ks.Lock(a.Address); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.SignHash(a.Address, hash); err != ErrLocked {
+		t.Fatalf("signing with locked account: have %v, want %v", err, ErrLocked)
+	}
+	if err := ks.TimedUnlock(a.Address, "foo", 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if ks.Unlocked(a.Address) {
+		t.Fatal("account still unlocked after timeout")
+	}
+}
+
+func TestSignTx(t *testing.T) {
+	ks := newTestKeyStore(t)
+	a, err := ks.NewAccount("foo", testLocation, accounts.QuaiLedger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(1337)
+	to := a.Address
+	tx := types.NewTx(&types.QuaiTx{
+		ChainID:  chainID,
+		Nonce:    1,
+		MinerTip: big.NewInt(1),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1),
+	})
+	if _, err := ks.SignTx(a.Address, tx, chainID); err != ErrLocked {
+		t.Fatalf("signing with locked account: have %v, want %v", err, ErrLocked)
+	}
+	signed, err := ks.SignTxWithPassphrase(a.Address, "foo", tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID, testLocation), signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !from.Equal(a.Address) {
+		t.Fatalf("signed by %s, want %s", from.Hex(), a.Address.Hex())
+	}
+}
+
+func TestSignQiTx(t *testing.T) {
+	ks := newTestKeyStore(t)
+	chainID := big.NewInt(1337)
+	var (
+		ins     types.TxIns
+		pubKeys []*btcec.PublicKey
+	)
+	for i := 0; i < 2; i++ {
+		a, err := ks.NewAccount("foo", testLocation, accounts.QiLedger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ks.getDecryptedKey(a.Address, "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := crypto.FromECDSAPub(&key.PrivateKey.PublicKey)
+		pubKey, err := btcec.ParsePubKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+		ins = append(ins, types.TxIn{
+			PreviousOutPoint: types.OutPoint{TxHash: common.Hash{byte(i + 1)}},
+			PubKey:           pub,
+		})
+		if i == 0 {
+			if err := ks.Unlock(a.Address, "foo"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	out, err := ks.NewAccount("bar", testLocation, accounts.QiLedger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := types.NewTx(&types.QiTx{
+		ChainID: chainID,
+		TxIn:    ins,
+		TxOut:   types.TxOuts{{Denomination: 1, Address: out.Address.Bytes()}},
+	})
+	// Only the key of the first input is unlocked
+	if _, err := ks.SignQiTx(tx, chainID); err != ErrLocked {
+		t.Fatalf("signing with locked input: have %v, want %v", err, ErrLocked)
+	}
+	signed, err := ks.SignQiTxWithPassphrase("foo", tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggKey, err := wallet.AggregateKey(pubKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := types.LatestSignerForChainID(chainID, testLocation).Hash(signed)
+	if !signed.GetSchnorrSignature().Verify(digest[:], aggKey) {
+		t.Fatal("invalid signature")
+	}
+}
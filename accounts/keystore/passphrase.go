@@ -0,0 +1,244 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyHeaderKDF = "scrypt"
+
+	// StandardScryptN is the N parameter of Scrypt encryption algorithm, using 256MB
+	// memory and taking approximately 1s CPU time on a modern processor.
+	StandardScryptN = 1 << 18
+
+	// StandardScryptP is the P parameter of Scrypt encryption algorithm, using 256MB
+	// memory and taking approximately 1s CPU time on a modern processor.
+	StandardScryptP = 1
+
+	// LightScryptN is the N parameter of Scrypt encryption algorithm, using 4MB
+	// memory and taking approximately 100ms CPU time on a modern processor.
+	LightScryptN = 1 << 12
+
+	// LightScryptP is the P parameter of Scrypt encryption algorithm, using 4MB
+	// memory and taking approximately 100ms CPU time on a modern processor.
+	LightScryptP = 6
+
+	scryptR     = 8
+	scryptDKLen = 32
+
+	version = 3
+)
+
+// ErrDecrypt is returned if a key file cannot be decrypted with the given
+// passphrase.
+var ErrDecrypt = errors.New("could not decrypt key with given password")
+
+// encryptedKeyJSONV3 is a key file in the version 3 of the Web3 Secret Storage
+// format. The address is the hex encoding of its 20 bytes, without 0x prefix.
+type encryptedKeyJSONV3 struct {
+	Address string     `json:"address"`
+	Crypto  CryptoJSON `json:"crypto"`
+	Id      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+// CryptoJSON holds the encrypted private key of a key file and the parameters
+// to decrypt it.
+type CryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherparamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherparamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// EncryptDataV3 encrypts the data given as 'data' with the password 'auth'.
+func EncryptDataV3(data, auth []byte, scryptN, scryptP int) (CryptoJSON, error) {
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return CryptoJSON{}, fmt.Errorf("reading from crypto/rand failed: %v", err)
+	}
+	derivedKey, err := scrypt.Key(auth, salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return CryptoJSON{}, err
+	}
+	encryptKey := derivedKey[:16]
+
+	iv := make([]byte, aes.BlockSize) // 16
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return CryptoJSON{}, fmt.Errorf("reading from crypto/rand failed: %v", err)
+	}
+	cipherText, err := aesCTRXOR(encryptKey, data, iv)
+	if err != nil {
+		return CryptoJSON{}, err
+	}
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	scryptParamsJSON := make(map[string]interface{}, 5)
+	scryptParamsJSON["n"] = scryptN
+	scryptParamsJSON["r"] = scryptR
+	scryptParamsJSON["p"] = scryptP
+	scryptParamsJSON["dklen"] = scryptDKLen
+	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
+
+	return CryptoJSON{
+		Cipher:       "aes-128-ctr",
+		CipherText:   hex.EncodeToString(cipherText),
+		CipherParams: cipherparamsJSON{IV: hex.EncodeToString(iv)},
+		KDF:          keyHeaderKDF,
+		KDFParams:    scryptParamsJSON,
+		MAC:          hex.EncodeToString(mac),
+	}, nil
+}
+
+// EncryptKey encrypts a key using the specified scrypt parameters into a json
+// blob that can be decrypted later on.
+func EncryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
+	keyBytes := crypto.FromECDSA(key.PrivateKey)
+	cryptoStruct, err := EncryptDataV3(keyBytes, []byte(auth), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKeyJSONV3 := encryptedKeyJSONV3{
+		hex.EncodeToString(key.Address.Bytes()),
+		cryptoStruct,
+		key.Id.String(),
+		version,
+	}
+	return json.Marshal(encryptedKeyJSONV3)
+}
+
+// DecryptKey decrypts a key from a json blob, returning the private key itself.
+func DecryptKey(keyjson []byte, auth string) (*Key, error) {
+	k := new(encryptedKeyJSONV3)
+	if err := json.Unmarshal(keyjson, k); err != nil {
+		return nil, err
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("version not supported: %v", k.Version)
+	}
+	keyId, err := uuid.Parse(k.Id)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := DecryptDataV3(k.Crypto, auth)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	addr := addressOf(&key.PublicKey)
+	if stored, err := hex.DecodeString(k.Address); err != nil || !bytes.Equal(stored, addr.Bytes()) {
+		return nil, fmt.Errorf("key content mismatch: have address %x, want %s", stored, k.Address)
+	}
+	return &Key{
+		Id:         keyId,
+		Address:    addr,
+		PrivateKey: key,
+	}, nil
+}
+
+// DecryptDataV3 decrypts data encrypted with EncryptDataV3.
+func DecryptDataV3(cryptoJson CryptoJSON, auth string) ([]byte, error) {
+	if cryptoJson.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("cipher not supported: %v", cryptoJson.Cipher)
+	}
+	mac, err := hex.DecodeString(cryptoJson.MAC)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(cryptoJson.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(cryptoJson.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := getKDFKey(cryptoJson, auth)
+	if err != nil {
+		return nil, err
+	}
+	calculatedMAC := crypto.Keccak256(derivedKey[16:32], cipherText)
+	if !bytes.Equal(calculatedMAC, mac) {
+		return nil, ErrDecrypt
+	}
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+func getKDFKey(cryptoJSON CryptoJSON, auth string) ([]byte, error) {
+	if cryptoJSON.KDF != keyHeaderKDF {
+		return nil, fmt.Errorf("unsupported KDF: %s", cryptoJSON.KDF)
+	}
+	salt, err := hex.DecodeString(fmt.Sprint(cryptoJSON.KDFParams["salt"]))
+	if err != nil {
+		return nil, err
+	}
+	var params [4]int
+	for i, name := range []string{"dklen", "n", "r", "p"} {
+		// Numbers are decoded from json as float64
+		f, ok := cryptoJSON.KDFParams[name].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid KDF parameter %s", name)
+		}
+		params[i] = int(f)
+	}
+	dkLen, n, r, p := params[0], params[1], params[2], params[3]
+	if dkLen < scryptDKLen {
+		return nil, fmt.Errorf("derived key length %d too short", dkLen)
+	}
+	return scrypt.Key([]byte(auth), salt, n, r, p, dkLen)
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	// AES-128 is selected due to size of encryptKey.
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	stream := cipher.NewCTR(aesBlock, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, err
+}
+
+// addressFromJSON returns the address stored in a key file.
+func addressFromJSON(keyjson []byte) (common.Address, error) {
+	var k struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(keyjson, &k); err != nil {
+		return common.Address{}, err
+	}
+	b, err := hex.DecodeString(k.Address)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(b) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid address length %d", len(b))
+	}
+	var addr common.AddressBytes
+	copy(addr[:], b)
+	return accounts.AddressOf(addr), nil
+}
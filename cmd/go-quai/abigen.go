@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/quai/abi/bind"
+)
+
+const (
+	c_abiFlagName   = "abi"
+	c_binFlagName   = "bin"
+	c_typeFlagName  = "type"
+	c_pkgFlagName   = "pkg"
+	c_outFlagName   = "out"
+	c_aliasFlagName = "alias"
+)
+
+var abigenCmd = &cobra.Command{
+	Use:   "abigen",
+	Short: "generates Go bindings of a contract",
+	Long: `generates type-safe Go bindings of a contract from its ABI given with --abi, which
+are backed by the Quai client of the quaiclient package. The bindings call the view
+methods of the contract, send its transacting methods as signed Quai transactions and
+filter, watch and parse its events. If the bytecode is given with --bin, a deploy method
+creating the contract in the zone of the sender is generated as well.
+
+A contract lives in the zone its address is prefixed with, so the bindings bind the
+contract address to its zone and return the addresses of the contract in this zone. The
+transactions are sent from an account of the Quai ledger of the zone of the contract.
+
+Methods and events whose names collide once capitalised are renamed with --alias.`,
+	Args:                       cobra.NoArgs,
+	RunE:                       runAbigen,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+	Example:                    `go-quai abigen --abi token.abi --bin token.bin --pkg token --out token/token.go`,
+}
+
+func init() {
+	rootCmd.AddCommand(abigenCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, abigenCmd)
+		}
+	}
+	abigenCmd.Flags().String(c_abiFlagName, "", "path to the ABI of the contract, - for the standard input")
+	abigenCmd.Flags().String(c_binFlagName, "", "path to the bytecode of the contract, to generate a deploy method")
+	abigenCmd.Flags().String(c_typeFlagName, "", "name of the contract type, the package name if empty")
+	abigenCmd.Flags().String(c_pkgFlagName, "", "package name of the generated file")
+	abigenCmd.Flags().String(c_outFlagName, "", "path of the generated file")
+	abigenCmd.Flags().StringToString(c_aliasFlagName, nil, "renames of methods and events, e.g. _transfer=transferInternal")
+	abigenCmd.MarkFlagRequired(c_abiFlagName)
+	abigenCmd.MarkFlagRequired(c_pkgFlagName)
+	abigenCmd.MarkFlagRequired(c_outFlagName)
+}
+
+func runAbigen(cmd *cobra.Command, args []string) error {
+	abiPath, err := cmd.Flags().GetString(c_abiFlagName)
+	if err != nil {
+		return err
+	}
+	binPath, err := cmd.Flags().GetString(c_binFlagName)
+	if err != nil {
+		return err
+	}
+	pkg, err := cmd.Flags().GetString(c_pkgFlagName)
+	if err != nil {
+		return err
+	}
+	kind, err := cmd.Flags().GetString(c_typeFlagName)
+	if err != nil {
+		return err
+	}
+	out, err := cmd.Flags().GetString(c_outFlagName)
+	if err != nil {
+		return err
+	}
+	aliases, err := cmd.Flags().GetStringToString(c_aliasFlagName)
+	if err != nil {
+		return err
+	}
+	if kind == "" {
+		kind = pkg
+	}
+	if !regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`).MatchString(kind) {
+		return fmt.Errorf("invalid contract type %q", kind)
+	}
+
+	var abiJSON []byte
+	if abiPath == "-" {
+		abiJSON, err = io.ReadAll(os.Stdin)
+	} else {
+		abiJSON, err = os.ReadFile(abiPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the ABI: %v", err)
+	}
+	var bytecode []byte
+	if binPath != "" {
+		if bytecode, err = os.ReadFile(binPath); err != nil {
+			return fmt.Errorf("failed to read the bytecode: %v", err)
+		}
+	}
+	code, err := bind.Bind([]string{kind}, []string{string(abiJSON)}, []string{string(bytecode)}, nil, pkg, nil, aliases)
+	if err != nil {
+		return fmt.Errorf("failed to generate the bindings: %v", err)
+	}
+	return os.WriteFile(out, []byte(code), 0644)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/accounts/keystore"
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+const c_ledgerFlagName = "ledger"
+
+var accountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "manages the accounts of the keystore",
+	Long: `manages the accounts of the keystore, which holds the scrypt-encrypted keys of
+Quai and Qi addresses. The keystore is shared by all the slices of the data directory,
+unless another directory is set with --node.keystore. A running node unlocks the
+accounts of its zone listed in --node.unlock, and exposes the keystore through the
+personal RPC namespace.
+
+Passwords are read from the file given with --node.password, one per line, and
+otherwise from the standard input.`,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+}
+
+var accountNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "creates a new account",
+	Long: `creates a new account in the zone given with --location, for the ledger given with
+--ledger. Addresses are prefixed with the location of their zone and the ledger they
+belong to, so the key is generated until its address matches both.`,
+	Args:    cobra.NoArgs,
+	RunE:    runAccountNew,
+	Example: `go-quai account new --location zone-0-1 --ledger qi`,
+}
+
+var accountListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "lists the accounts of the keystore",
+	Args:  cobra.NoArgs,
+	RunE:  runAccountList,
+}
+
+var accountImportCmd = &cobra.Command{
+	Use:   "import <keyfile>",
+	Short: "imports a key into the keystore",
+	Long: `imports a key into the keystore. The key file is either an encrypted key file, whose
+password is the first one given and which is stored encrypted with the second one, or
+the first again if there is only one, or a file holding a hex encoded private key,
+which is stored encrypted with the first password.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAccountImport,
+}
+
+func init() {
+	rootCmd.AddCommand(accountCmd)
+	accountCmd.AddCommand(accountNewCmd, accountListCmd, accountImportCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, accountCmd)
+		}
+	}
+	accountNewCmd.Flags().String(c_locationFlagName, "zone-0-0", "zone of the account (zone-R-Z)")
+	accountNewCmd.Flags().String(c_ledgerFlagName, "quai", "ledger of the account (quai or qi)")
+}
+
+// makeKeyStore opens the keystore of the data directory.
+func makeKeyStore() *keystore.KeyStore {
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if viper.GetString(utils.EnvironmentFlag.Name) == params.DevName {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	return keystore.NewKeyStore(utils.KeyStoreDir(), scryptN, scryptP)
+}
+
+// readPasswords returns the passwords of the password file, or else reads one
+// password per prompt from the standard input.
+func readPasswords(prompts ...string) ([]string, error) {
+	if passwords := utils.MakePasswordList(); len(passwords) > 0 {
+		return passwords, nil
+	}
+	return promptPasswords(prompts...)
+}
+
+func promptPasswords(prompts ...string) ([]string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	passwords := make([]string, len(prompts))
+	for i, prompt := range prompts {
+		fmt.Print(prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil, fmt.Errorf("failed to read password: %v", err)
+		}
+		passwords[i] = strings.TrimRight(line, "\r\n")
+	}
+	return passwords, nil
+}
+
+func runAccountNew(cmd *cobra.Command, args []string) error {
+	locationName, err := cmd.Flags().GetString(c_locationFlagName)
+	if err != nil {
+		return err
+	}
+	location, err := utils.ParseSliceLocation(locationName)
+	if err != nil {
+		return err
+	}
+	ledgerName, err := cmd.Flags().GetString(c_ledgerFlagName)
+	if err != nil {
+		return err
+	}
+	ledger, err := accounts.ParseLedger(ledgerName)
+	if err != nil {
+		return err
+	}
+	passwords := utils.MakePasswordList()
+	if len(passwords) == 0 {
+		if passwords, err = promptPasswords("Password: ", "Repeat password: "); err != nil {
+			return err
+		}
+		if passwords[0] != passwords[1] {
+			return errors.New("passwords do not match")
+		}
+	}
+	ks := makeKeyStore()
+	acc, err := ks.NewAccount(passwords[0], location, ledger)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Address: %s\nLedger:  %s\nZone:    %s\nPath:    %s\n", acc.Address.Hex(), ledger, location.Name(), acc.URL)
+	return nil
+}
+
+func runAccountList(cmd *cobra.Command, args []string) error {
+	for i, acc := range makeKeyStore().Accounts() {
+		fmt.Printf("Account #%d: %s %s %s %s\n", i, acc.Address.Hex(), accounts.LedgerOf(acc.Address), acc.Address.Location().Name(), acc.URL)
+	}
+	return nil
+}
+
+func runAccountImport(cmd *cobra.Command, args []string) error {
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	ks := makeKeyStore()
+	var acc accounts.Account
+	if json.Valid(content) {
+		passwords, err := readPasswords("Password of the key file: ", "New password: ")
+		if err != nil {
+			return err
+		}
+		newPassword := passwords[0]
+		if len(passwords) > 1 {
+			newPassword = passwords[1]
+		}
+		if acc, err = ks.Import(content, passwords[0], newPassword); err != nil {
+			return err
+		}
+	} else {
+		key, err := crypto.LoadECDSA(args[0])
+		if err != nil {
+			return err
+		}
+		passwords, err := readPasswords("Password: ")
+		if err != nil {
+			return err
+		}
+		if acc, err = ks.ImportECDSA(key, passwords[0]); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Address: %s\nLedger:  %s\nZone:    %s\nPath:    %s\n", acc.Address.Hex(), accounts.LedgerOf(acc.Address), acc.Address.Location().Name(), acc.URL)
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+const c_locationFlagName = "location"
+
+var exportCmd = &cobra.Command{
+	Use:   "export <filename> [<blockNumFirst> <blockNumLast>]",
+	Short: "exports the blockchain of a slice into a file",
+	Long: `exports the canonical blocks of a slice, with their bodies, workshares, etxs and
+manifests, into a file. The slice is selected with the --location flag and has to be
+stopped while exporting. If only the file name is given, the whole chain is exported,
+otherwise only the given range of blocks. If the file name ends with .gz the output
+is gzip compressed. The file can be loaded again with the import command or the
+admin_importChain RPC of a node running the same slice.`,
+	Args:                       cobra.RangeArgs(1, 3),
+	RunE:                       runExport,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+	Example:                    `go-quai export --location zone-0-0 zone-0-0.rlp.gz`,
+	PreRunE:                    startCmdPreRun,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, exportCmd)
+		}
+	}
+	exportCmd.Flags().String(c_locationFlagName, "zone-0-0", "slice to export (prime, region-R or zone-R-Z)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if len(args) == 2 {
+		return errors.New("both the first and the last block number have to be given")
+	}
+	locationName, err := cmd.Flags().GetString(c_locationFlagName)
+	if err != nil {
+		return err
+	}
+	location, err := utils.ParseSliceLocation(locationName)
+	if err != nil {
+		return err
+	}
+
+	stack, db := utils.MakeSliceDatabase(location, true, log.Global)
+	defer stack.Close()
+
+	var first, last uint64
+	if len(args) == 3 {
+		if first, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+			return err
+		}
+		if last, err = strconv.ParseUint(args[2], 10, 64); err != nil {
+			return err
+		}
+	} else {
+		head := rawdb.ReadHeadBlockHash(db)
+		if head == (common.Hash{}) {
+			return errors.New("head block not found, the database is empty")
+		}
+		number := rawdb.ReadHeaderNumber(db, head)
+		if number == nil {
+			return errors.New("head block number not found")
+		}
+		last = *number
+	}
+	log.Global.WithFields(log.Fields{
+		"location": location.Name(),
+		"file":     args[0],
+		"first":    first,
+		"last":     last,
+	}).Info("Exporting blockchain")
+	return utils.ExportChain(db, location, args[0], first, last, log.Global)
+}
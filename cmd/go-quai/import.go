@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/common"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <filename>...",
+	Short: "imports blockchain files created by the export command",
+	Long: `imports one or more files created by the export command into the local slices.
+Every file needs a matching --location flag, given in the same order as the files,
+naming the slice it was exported from. The slices named by the --node.slices flag
+are started without networking or RPC endpoints, and the blocks are appended through
+the slice hierarchy exactly as they would be while syncing from peers, so files of
+dominant chains (prime and regions) should be imported together with the zones they
+coordinate. The command returns once every slice reached the last block of its file.`,
+	Args:                       cobra.MinimumNArgs(1),
+	RunE:                       runImport,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+	Example:                    `go-quai import --node.slices "[0 0]" --location prime --location region-0 --location zone-0-0 prime.rlp.gz region-0.rlp.gz zone-0-0.rlp.gz`,
+	PreRunE:                    startCmdPreRun,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, importCmd)
+		}
+	}
+	importCmd.Flags().StringArray(c_locationFlagName, nil, "slice each file was exported from (prime, region-R or zone-R-Z), once per file")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	locationNames, err := cmd.Flags().GetStringArray(c_locationFlagName)
+	if err != nil {
+		return err
+	}
+	locations := make([]common.Location, len(locationNames))
+	for i, name := range locationNames {
+		if locations[i], err = utils.ParseSliceLocation(name); err != nil {
+			return err
+		}
+	}
+	// The import runs offline, so none of the slices serve RPC requests
+	viper.Set(utils.HTTPEnabledFlag.Name, false)
+	viper.Set(utils.WSEnabledFlag.Name, false)
+
+	return utils.ImportChains(locations, args, viper.GetString(utils.NodeLogLevelFlag.Name))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+const (
+	c_bloomSizeFlagName = "bloomfilter.size"
+	c_dryRunFlagName    = "dry-run"
+)
+
+var pruneStateCmd = &cobra.Command{
+	Use:   "prune-state [<root>]",
+	Short: "prunes the stale EVM state of a zone",
+	Long: `prunes the stale EVM state of the zone selected with the --location flag, which has
+to be stopped while it is pruned. Every slice has its own chain database, and only
+zones keep an EVM state, so prime and regions have nothing to prune.
+
+The state tries at the given state root, and those of all the blocks from it up to the
+head, are kept along with the genesis state, and all other state trie nodes and
+contract code are deleted. If no root is given, the deepest state covered by the
+snapshot diff layers of the head is kept, which is usually the state 128 blocks below
+the head. The states kept are regenerated from the snapshot into a bloom filter, so
+the snapshot has to be fully generated, which it is once the node ran with its head
+state for a while.
+
+With --dry-run the database is left unchanged, and the number and size of the state
+entries that would be deleted are reported. If a pruning is interrupted, it is
+resumed by running the command again, or when the node starts.`,
+	Args:                       cobra.MaximumNArgs(1),
+	RunE:                       runPruneState,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+	Example:                    `go-quai prune-state --location zone-0-0 --dry-run`,
+	PreRunE:                    startCmdPreRun,
+}
+
+func init() {
+	rootCmd.AddCommand(pruneStateCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, pruneStateCmd)
+		}
+	}
+	pruneStateCmd.Flags().String(c_locationFlagName, "zone-0-0", "zone to prune (zone-R-Z)")
+	pruneStateCmd.Flags().Uint64(c_bloomSizeFlagName, 2048, "megabytes of memory allocated to the bloom filter of the kept state")
+	pruneStateCmd.Flags().Bool(c_dryRunFlagName, false, "only report the state entries that would be deleted")
+}
+
+func runPruneState(cmd *cobra.Command, args []string) error {
+	locationName, err := cmd.Flags().GetString(c_locationFlagName)
+	if err != nil {
+		return err
+	}
+	location, err := utils.ParseSliceLocation(locationName)
+	if err != nil {
+		return err
+	}
+	bloomSize, err := cmd.Flags().GetUint64(c_bloomSizeFlagName)
+	if err != nil {
+		return err
+	}
+	dryRun, err := cmd.Flags().GetBool(c_dryRunFlagName)
+	if err != nil {
+		return err
+	}
+	var root common.Hash
+	if len(args) == 1 {
+		data, err := hexutil.Decode(args[0])
+		if err != nil || len(data) != common.HashLength {
+			return fmt.Errorf("invalid state root %q", args[0])
+		}
+		root = common.BytesToHash(data)
+	}
+	log.Global.WithFields(log.Fields{
+		"location": location.Name(),
+		"root":     root,
+		"dryrun":   dryRun,
+	}).Info("Pruning state")
+
+	stats, err := utils.PruneState(location, root, bloomSize, dryRun, log.Global)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("Stale state entries: %d (%s)\n", stats.Nodes, stats.Size)
+	} else {
+		fmt.Printf("Pruned state entries: %d (%s)\n", stats.Nodes, stats.Size)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+)
+
+var rewindCmd = &cobra.Command{
+	Use:   "rewind <blockNumber|blockHash>",
+	Short: "rewinds the blockchain of a slice to an earlier block",
+	Long: `rewinds the slice selected with the --location flag to the given canonical block,
+which becomes its new head. The blocks above it are deleted together with their
+canonical mappings, their utxo and multiset data and their entries in the address
+outpoint and workshare indexes, so that a slice with a corrupted database can sync
+them again from its peers without a full resync.
+
+The slices named by the --node.slices flag are started without networking or RPC
+endpoints, and have to be stopped otherwise. If some of the deleted blocks were also
+blocks of the dom, the dom is rewound to the parent of the first of them, and the
+subs are rewound until they keep no block their dom deleted, so the doms and subs of
+the slice should be started along with it. A running node is rewound the same way
+with the debug_setHead RPC.`,
+	Args:                       cobra.ExactArgs(1),
+	RunE:                       runRewind,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+	Example:                    `go-quai rewind --node.slices "[0 0]" --location zone-0-0 1200`,
+	PreRunE:                    startCmdPreRun,
+}
+
+func init() {
+	rootCmd.AddCommand(rewindCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, rewindCmd)
+		}
+	}
+	rewindCmd.Flags().String(c_locationFlagName, "zone-0-0", "slice to rewind (prime, region-R or zone-R-Z)")
+}
+
+func runRewind(cmd *cobra.Command, args []string) error {
+	locationName, err := cmd.Flags().GetString(c_locationFlagName)
+	if err != nil {
+		return err
+	}
+	location, err := utils.ParseSliceLocation(locationName)
+	if err != nil {
+		return err
+	}
+	// The rewind runs offline, so none of the slices serve RPC requests
+	viper.Set(utils.HTTPEnabledFlag.Name, false)
+	viper.Set(utils.WSEnabledFlag.Name, false)
+
+	return utils.RewindChain(location, args[0], viper.GetString(utils.NodeLogLevelFlag.Name))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/node"
+)
+
+const (
+	c_namespacesFlagName = "namespaces"
+	c_expiryFlagName     = "expiry"
+)
+
+var rpcTokenCmd = &cobra.Command{
+	Use:   "rpc-token",
+	Short: "creates a token for the authenticated RPC server",
+	Long: `creates a JWT token for the authenticated RPC server enabled with --rpc.auth, signed
+with the secret of --rpc.jwtsecret, which is shared by all the slices of the data
+directory. The token is sent as a bearer token in the Authorization header.
+
+A token restricted with --namespaces may only call the methods of these API namespaces,
+so a miner can be given a token for the miner namespace without being able to call the
+admin or debug methods. A token without --expiry is only valid for a minute, clients
+holding the secret are expected to sign a fresh token for each request.`,
+	Args:                       cobra.NoArgs,
+	RunE:                       runRPCToken,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+	Example:                    `go-quai rpc-token --namespaces miner,quai --expiry 720h`,
+}
+
+func init() {
+	rootCmd.AddCommand(rpcTokenCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, rpcTokenCmd)
+		}
+	}
+	rpcTokenCmd.Flags().StringSlice(c_namespacesFlagName, nil, "API namespaces the token may call, all of them if empty")
+	rpcTokenCmd.Flags().Duration(c_expiryFlagName, 0, "validity of the token")
+}
+
+func runRPCToken(cmd *cobra.Command, args []string) error {
+	namespaces, err := cmd.Flags().GetStringSlice(c_namespacesFlagName)
+	if err != nil {
+		return err
+	}
+	expiry, err := cmd.Flags().GetDuration(c_expiryFlagName)
+	if err != nil {
+		return err
+	}
+	secret, err := node.ReadJWTSecret(utils.JWTSecretPath())
+	if err != nil {
+		return fmt.Errorf("failed to read the jwt secret, which is generated when the node starts with --%s: %v", utils.AuthEnabledFlag.Name, err)
+	}
+	token, err := node.NewJWTToken(secret, namespaces, expiry)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
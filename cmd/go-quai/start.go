@@ -21,6 +21,7 @@ import (
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/p2p/node"
 	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai"
 )
 
 var gitCommit, gitDate string
@@ -30,7 +31,12 @@ var startCmd = &cobra.Command{
 	Short: "starts a go-quai p2p node",
 	Long: `starts the go-quai daemon. The daemon will start a libp2p node and a http API.
 By default the node will bootstrap to the public bootstrap nodes and port 4001. 
-To bootstrap to a private node, use the --bootstrap flag.`,
+To bootstrap to a private node, use the --bootstrap flag.
+
+Setting --node.dev-period on the local environment starts the dev mode instead, which
+runs without peers and seals a coordinated block with the dev engine every dev period
+seconds, or as soon as transactions are pending if the period is 0. Developer accounts
+are funded on both ledgers of the running zones, and their keys are logged.`,
 	RunE:                       runStart,
 	SilenceUsage:               true,
 	SuggestionsMinimumDistance: 2,
@@ -76,10 +82,20 @@ func runStart(cmd *cobra.Command, args []string) error {
 	quitCh := make(chan struct{})
 
 	common.SanityCheck(quitCh)
-	// create a new p2p node
-	node, err := node.NewNode(ctx, quitCh)
-	if err != nil {
-		log.Global.WithField("error", err).Fatal("error creating node")
+	// create a new p2p node, or the in-process network of the dev mode
+	var p2p quai.NetworkingAPI
+	if utils.IsDevMode() {
+		log.Global.WithField("period", utils.DevPeriod()).Warn("Starting in dev mode")
+		if err := utils.WriteDevAllocs(utils.GetRunningZones()); err != nil {
+			log.Global.WithField("error", err).Fatal("error writing the dev allocations")
+		}
+		p2p = utils.NewDevNetwork()
+	} else {
+		p2pNode, err := node.NewNode(ctx, quitCh)
+		if err != nil {
+			log.Global.WithField("error", err).Fatal("error creating node")
+		}
+		p2p = p2pNode
 	}
 
 	logLevel := viper.GetString(utils.NodeLogLevelFlag.Name)
@@ -87,14 +103,17 @@ func runStart(cmd *cobra.Command, args []string) error {
 	startingExpansionNumber := viper.GetUint64(utils.StartingExpansionNumberFlag.Name)
 	// Start the  hierarchical co-ordinator
 	var nodeWg sync.WaitGroup
-	hc := utils.NewHierarchicalCoordinator(node, logLevel, &nodeWg, startingExpansionNumber)
-	err = hc.StartHierarchicalCoordinator()
+	hc := utils.NewHierarchicalCoordinator(p2p, logLevel, &nodeWg, startingExpansionNumber)
+	err := hc.StartHierarchicalCoordinator()
 	if err != nil {
 		log.Global.WithField("error", err).Fatal("error starting hierarchical coordinator")
 	}
+	if utils.IsDevMode() {
+		hc.StartDevSealer(utils.DevPeriod())
+	}
 
 	// start the p2p node
-	if err := node.Start(); err != nil {
+	if err := p2p.Start(); err != nil {
 		log.Global.WithField("error", err).Fatal("error starting node")
 	}
 
@@ -129,7 +148,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 	cancel()
 	// stop the hierarchical co-ordinator
 	hc.Stop()
-	if err := node.Stop(); err != nil {
+	if err := p2p.Stop(); err != nil {
 		panic(err)
 	}
 	log.Global.Warn("Node is offline")
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+var utxoSnapshotCmd = &cobra.Command{
+	Use:   "utxo-snapshot",
+	Short: "exports, imports and verifies snapshots of the utxo set of a zone",
+	Long: `exports, imports and verifies snapshots of the utxo set of a zone. A snapshot holds
+the utxo set at a block in checksummed chunks, and is checked against the utxo root
+committed to by the header of the block, which is the multiset hash of the set, when
+it is imported or verified. The zone is selected with the --location flag and has to
+be stopped while its database is used. If the file name ends with .gz the snapshot is
+gzip compressed.`,
+	SilenceUsage:               true,
+	SuggestionsMinimumDistance: 2,
+}
+
+var utxoSnapshotExportCmd = &cobra.Command{
+	Use:   "export <filename> [<blockNum>]",
+	Short: "exports the utxo set at a block into a file",
+	Long: `exports the utxo set at the given canonical block into a file, or at the head if no
+block is given. The set is rolled back from the head to the block, so the block has to
+be recent enough for the changes of the blocks above it to still be stored.`,
+	Args:    cobra.RangeArgs(1, 2),
+	RunE:    runUtxoSnapshotExport,
+	Example: `go-quai utxo-snapshot export --location zone-0-0 utxos.rlp.gz`,
+	PreRunE: startCmdPreRun,
+}
+
+var utxoSnapshotImportCmd = &cobra.Command{
+	Use:   "import <filename>",
+	Short: "imports a utxo snapshot into a new zone database",
+	Long: `imports a utxo snapshot into the database of a zone that has not processed any block
+yet. The snapshot is rejected if its utxos do not match its utxo root. The block of the
+snapshot becomes the snap sync pivot of the zone, so once the node is started it
+appends the blocks up to it without processing them, and only downloads the state
+tries at the block from its peers.`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runUtxoSnapshotImport,
+	Example: `go-quai utxo-snapshot import --location zone-0-0 utxos.rlp.gz`,
+	PreRunE: startCmdPreRun,
+}
+
+var utxoSnapshotVerifyCmd = &cobra.Command{
+	Use:   "verify <filename>",
+	Short: "verifies a utxo snapshot and reports the supply it holds",
+	Long: `verifies the checksums of a utxo snapshot and its utxos against its utxo root, without
+a database, and reports the number of utxos of each denomination and the Qi supply
+they hold. The utxo root should be compared with the header of the block at the
+snapshot's height from a trusted source.`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runUtxoSnapshotVerify,
+	Example: `go-quai utxo-snapshot verify --location zone-0-0 utxos.rlp.gz`,
+}
+
+func init() {
+	rootCmd.AddCommand(utxoSnapshotCmd)
+	utxoSnapshotCmd.AddCommand(utxoSnapshotExportCmd, utxoSnapshotImportCmd, utxoSnapshotVerifyCmd)
+
+	for _, flagGroup := range utils.Flags {
+		for _, flag := range flagGroup {
+			utils.CreateAndBindFlag(flag, utxoSnapshotCmd)
+		}
+	}
+	utxoSnapshotCmd.PersistentFlags().String(c_locationFlagName, "zone-0-0", "zone of the utxo set (zone-R-Z)")
+}
+
+// utxoSnapshotLocation returns the zone given with the location flag.
+func utxoSnapshotLocation(cmd *cobra.Command) (common.Location, error) {
+	locationName, err := cmd.Flags().GetString(c_locationFlagName)
+	if err != nil {
+		return nil, err
+	}
+	location, err := utils.ParseSliceLocation(locationName)
+	if err != nil {
+		return nil, err
+	}
+	if location.Context() != common.ZONE_CTX {
+		return nil, errors.New("utxo sets are only kept by zones")
+	}
+	return location, nil
+}
+
+func runUtxoSnapshotExport(cmd *cobra.Command, args []string) error {
+	location, err := utxoSnapshotLocation(cmd)
+	if err != nil {
+		return err
+	}
+	stack, db := utils.MakeSliceDatabase(location, true, log.Global)
+	defer stack.Close()
+
+	var number uint64
+	if len(args) == 2 {
+		if number, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+			return err
+		}
+	} else {
+		head := rawdb.ReadHeadBlockHash(db)
+		if head == (common.Hash{}) {
+			return errors.New("head block not found, the database is empty")
+		}
+		headNumber := rawdb.ReadHeaderNumber(db, head)
+		if headNumber == nil {
+			return errors.New("head block number not found")
+		}
+		number = *headNumber
+	}
+	hash := rawdb.ReadCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return fmt.Errorf("canonical block %d not found", number)
+	}
+	log.Global.WithFields(log.Fields{
+		"location": location.Name(),
+		"file":     args[0],
+		"number":   number,
+		"hash":     hash,
+	}).Info("Exporting utxo snapshot")
+	return utils.ExportUtxoSnapshot(db, location, args[0], hash, log.Global)
+}
+
+func runUtxoSnapshotImport(cmd *cobra.Command, args []string) error {
+	location, err := utxoSnapshotLocation(cmd)
+	if err != nil {
+		return err
+	}
+	stack, db := utils.MakeSliceDatabase(location, false, log.Global)
+	defer stack.Close()
+
+	header, err := utils.ImportUtxoSnapshot(db, location, args[0], log.Global)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Block:     %d %s\nUTXO root: %s\nUTXOs:     %d\n", header.Number, header.BlockHash.Hex(), header.UtxoRoot.Hex(), header.Size)
+	return nil
+}
+
+func runUtxoSnapshotVerify(cmd *cobra.Command, args []string) error {
+	location, err := utxoSnapshotLocation(cmd)
+	if err != nil {
+		return err
+	}
+	summary, err := utils.VerifyUtxoSnapshot(location, args[0])
+	if err != nil {
+		return err
+	}
+	header := summary.Header
+	fmt.Printf("Block:     %d %s\nUTXO root: %s\nUTXOs:     %d (%d locked)\n", header.Number, header.BlockHash.Hex(), header.UtxoRoot.Hex(), header.Size, summary.Locked)
+	for denomination, count := range summary.Denominations {
+		if count > 0 {
+			fmt.Printf("  denomination %2d: %d\n", denomination, count)
+		}
+	}
+	// The values of the denominations are in thousandths of a Qi
+	qi, rem := new(big.Int).QuoRem(summary.Supply, big.NewInt(1000), new(big.Int))
+	fmt.Printf("Supply:    %s.%03d Qi\n", qi, rem.Int64())
+	return nil
+}
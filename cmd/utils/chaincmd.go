@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/state/pruner"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/internal/quaiapi"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/node"
+	"github.com/dominant-strategies/go-quai/quai/quaiconfig"
+	"github.com/dominant-strategies/go-quai/rlp"
+)
+
+const (
+	// c_importPollInterval is how often the slices are checked for progress
+	// while importing
+	c_importPollInterval = 3 * time.Second
+	// c_importStallTimeout is how long an import may go without any slice
+	// making progress before it is aborted
+	c_importStallTimeout = 2 * time.Minute
+)
+
+// ParseSliceLocation parses a slice name as used for the slice data
+// directories, i.e. "prime", "region-R" or "zone-R-Z", into its location.
+func ParseSliceLocation(name string) (common.Location, error) {
+	parts := strings.Split(name, "-")
+	switch {
+	case len(parts) == 1 && parts[0] == "prime":
+		return common.Location{}, nil
+	case len(parts) == 2 && parts[0] == "region":
+		region, err := strconv.Atoi(parts[1])
+		if err != nil || region < 0 || region >= common.MaxRegions {
+			return nil, fmt.Errorf("invalid region in slice name %q", name)
+		}
+		return common.Location{byte(region)}, nil
+	case len(parts) == 3 && parts[0] == "zone":
+		region, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid region in slice name %q", name)
+		}
+		zone, err := strconv.Atoi(parts[2])
+		if err != nil || zone < 0 || zone >= common.MaxZones || region < 0 || region >= common.MaxRegions {
+			return nil, fmt.Errorf("invalid zone in slice name %q", name)
+		}
+		return common.NewLocation(region, zone)
+	}
+	return nil, fmt.Errorf("invalid slice name %q, expected prime, region-R or zone-R-Z", name)
+}
+
+// MakeSliceDatabase opens the chain database of the slice at the given
+// location without starting the node, so that it can be used by offline
+// commands. The returned node holds the data directory lock, and closing it
+// also closes the database.
+func MakeSliceDatabase(location common.Location, readonly bool, logger *log.Logger) (*node.Node, ethdb.Database) {
+	cfg := defaultNodeConfig()
+	cfg.NodeLocation = location
+	SetNodeConfig(&cfg, location, logger)
+	if cfg.DataDir == "" {
+		Fatalf("A data directory is required to open the %s database", location.Name())
+	}
+	stack, err := node.New(&cfg, logger)
+	if err != nil {
+		Fatalf("Failed to open the %s data directory: %v", location.Name(), err)
+	}
+	return stack, MakeChainDatabase(stack, readonly)
+}
+
+// ExportChain writes the canonical blocks first to last of the slice database
+// to the given file, gzip compressing the output if the file name ends with
+// ".gz".
+func ExportChain(db ethdb.Database, location common.Location, fn string, first uint64, last uint64, logger *log.Logger) error {
+	if _, err := os.Stat(fn); err == nil {
+		return errors.New("location would overwrite an existing file")
+	}
+	out, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var writer io.WriteCloser = out
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(out)
+	}
+	err = core.ExportWorkObjects(db, writer, first, last, location.Context(), logger)
+	// The gzip writer flushes the end of the compressed data when closed
+	if writer != out {
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ReadChainFile reads all the blocks of a file written by ExportChain for the
+// slice at the given location.
+func ReadChainFile(fn string, location common.Location) (types.WorkObjects, error) {
+	in, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	var reader io.Reader = in
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	blocks := make(types.WorkObjects, 0)
+	for {
+		block, err := core.ReadExportedWorkObject(stream, location)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: block %d: failed to parse: %v", fn, len(blocks), err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// ExportUtxoSnapshot writes the UTXO snapshot at the given canonical block of
+// the zone database to the given file, gzip compressing the output if the file
+// name ends with ".gz". The file is removed again if the export fails.
+func ExportUtxoSnapshot(db ethdb.Database, location common.Location, fn string, blockHash common.Hash, logger *log.Logger) error {
+	if _, err := os.Stat(fn); err == nil {
+		return errors.New("location would overwrite an existing file")
+	}
+	out, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var writer io.WriteCloser = out
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(out)
+	}
+	_, err = core.ExportUtxoSnapshot(db, writer, blockHash, location, logger)
+	if writer != out {
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(fn)
+	}
+	return err
+}
+
+// openSnapshotFile opens a file for reading, decompressing it if the file name
+// ends with ".gz".
+func openSnapshotFile(fn string) (io.Reader, func(), error) {
+	in, err := os.Open(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !strings.HasSuffix(fn, ".gz") {
+		return in, func() { in.Close() }, nil
+	}
+	reader, err := gzip.NewReader(in)
+	if err != nil {
+		in.Close()
+		return nil, nil, err
+	}
+	return reader, func() { reader.Close(); in.Close() }, nil
+}
+
+// ImportUtxoSnapshot loads the UTXO snapshot in the given file into the zone
+// database.
+func ImportUtxoSnapshot(db ethdb.Database, location common.Location, fn string, logger *log.Logger) (*core.UtxoSnapshotHeader, error) {
+	reader, closeFn, err := openSnapshotFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	return core.ImportUtxoSnapshot(db, reader, location, logger)
+}
+
+// VerifyUtxoSnapshot checks the UTXO snapshot in the given file against the
+// UTXO root it was taken at, and returns the supply it holds.
+func VerifyUtxoSnapshot(location common.Location, fn string) (*core.UtxoSnapshotSummary, error) {
+	reader, closeFn, err := openSnapshotFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	return core.VerifyUtxoSnapshot(reader, location)
+}
+
+// PruneState prunes the stale EVM state of the zone at the given location,
+// keeping the state at the given root and the states of the blocks above it.
+// If no root is given, the deepest state covered by the snapshot is kept. If
+// dryRun is set, the database is left unchanged and only the state entries
+// that would be deleted are counted. An interrupted pruning is resumed first.
+func PruneState(location common.Location, root common.Hash, bloomSize uint64, dryRun bool, logger *log.Logger) (pruner.PruneStats, error) {
+	if location.Context() != common.ZONE_CTX {
+		return pruner.PruneStats{}, fmt.Errorf("%s has no EVM state, the state is only kept by zones", location.Name())
+	}
+	stack, db := MakeSliceDatabase(location, dryRun, logger)
+	defer stack.Close()
+
+	trieCachePaths := []string{
+		stack.ResolvePath(quaiconfig.Defaults.TrieCleanCacheJournal),
+		stack.ResolvePath(quaiconfig.Defaults.ETXTrieCleanCacheJournal),
+	}
+	p, err := pruner.NewPruner(db, stack.ResolvePath(""), trieCachePaths, bloomSize, logger, location)
+	if err != nil {
+		return pruner.PruneStats{}, err
+	}
+	if dryRun {
+		return p.DryRun(root, location)
+	}
+	return p.Prune(root, location)
+}
+
+// ImportChains imports the chain files exported from the slices at the given
+// locations. The slice hierarchy is started on top of an offline network that
+// serves the blocks of all the files, so dominant blocks that a slice cannot
+// append by itself are retrieved and appended through the dom the same way
+// they would be during a sync from peers. It returns once every slice has
+// reached the last block of its file, or with an error if the import stalls.
+func ImportChains(locations []common.Location, files []string, logLevel string) error {
+	if len(locations) != len(files) {
+		return fmt.Errorf("got %d locations for %d files", len(locations), len(files))
+	}
+	network := newOfflineNetwork()
+	imports := make([]types.WorkObjects, len(files))
+	for i, fn := range files {
+		blocks, err := ReadChainFile(fn, locations[i])
+		if err != nil {
+			return err
+		}
+		if len(blocks) == 0 {
+			return fmt.Errorf("%s: no blocks to import", fn)
+		}
+		log.Global.WithFields(log.Fields{
+			"file":     fn,
+			"location": locations[i].Name(),
+			"blocks":   len(blocks),
+		}).Info("Loaded chain file")
+		network.AddBlocks(locations[i], blocks)
+		imports[i] = blocks
+	}
+
+	var nodeWg sync.WaitGroup
+	hc := NewHierarchicalCoordinator(network, logLevel, &nodeWg, viper.GetUint64(StartingExpansionNumberFlag.Name))
+	if err := hc.StartHierarchicalCoordinator(); err != nil {
+		return err
+	}
+	defer hc.Stop()
+
+	backends := make([]quaiapi.Backend, len(files))
+	for i, blocks := range imports {
+		backend := hc.consensus.GetBackend(locations[i])
+		if backend == nil || *backend == nil {
+			return fmt.Errorf("slice %s is not running, check the %s flag", locations[i].Name(), SlicesRunningFlag.Name)
+		}
+		backends[i] = *backend
+		for _, block := range blocks {
+			backends[i].WriteBlock(block)
+		}
+	}
+
+	var (
+		progress = make([]uint64, len(files))
+		lastMove = time.Now()
+		ticker   = time.NewTicker(c_importPollInterval)
+	)
+	defer ticker.Stop()
+	for range ticker.C {
+		done := true
+		for i, backend := range backends {
+			nodeCtx := locations[i].Context()
+			last := imports[i][len(imports[i])-1]
+			if rawdb.ReadCanonicalHash(backend.ChainDb(), last.NumberU64(nodeCtx)) != last.Hash() {
+				done = false
+			}
+			if height := backend.CurrentHeader().NumberU64(nodeCtx); height != progress[i] {
+				progress[i] = height
+				lastMove = time.Now()
+			}
+		}
+		if done {
+			log.Global.Info("Import done")
+			return nil
+		}
+		if time.Since(lastMove) > c_importStallTimeout {
+			for i := range backends {
+				log.Global.WithFields(log.Fields{
+					"location": locations[i].Name(),
+					"height":   progress[i],
+					"target":   imports[i][len(imports[i])-1].NumberU64(locations[i].Context()),
+				}).Error("Slice did not finish importing")
+			}
+			return errors.New("import stalled")
+		}
+	}
+	return nil
+}
+
+// RewindChain rewinds the slice at the given location to the canonical block
+// with the given number or hash. The slice hierarchy is started on top of an
+// offline network, so that the running doms and subs of the slice are rewound
+// with it where they share some of the deleted blocks.
+func RewindChain(location common.Location, target string, logLevel string) error {
+	var nodeWg sync.WaitGroup
+	hc := NewHierarchicalCoordinator(newOfflineNetwork(), logLevel, &nodeWg, viper.GetUint64(StartingExpansionNumberFlag.Name))
+	if err := hc.StartHierarchicalCoordinator(); err != nil {
+		return err
+	}
+	defer hc.Stop()
+
+	backend := hc.consensus.GetBackend(location)
+	if backend == nil || *backend == nil {
+		return fmt.Errorf("slice %s is not running, check the %s flag", location.Name(), SlicesRunningFlag.Name)
+	}
+	db := (*backend).ChainDb()
+	var hash common.Hash
+	if strings.HasPrefix(target, "0x") {
+		data, err := hexutil.Decode(target)
+		if err != nil || len(data) != common.HashLength {
+			return fmt.Errorf("invalid block hash %q", target)
+		}
+		hash = common.BytesToHash(data)
+	} else {
+		number, err := strconv.ParseUint(target, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid block number %q", target)
+		}
+		if hash = rawdb.ReadCanonicalHash(db, number); hash == (common.Hash{}) {
+			return fmt.Errorf("no canonical block %d in %s", number, location.Name())
+		}
+	}
+	log.Global.WithFields(log.Fields{
+		"location": location.Name(),
+		"hash":     hash,
+		"head":     (*backend).CurrentHeader().NumberArray(),
+	}).Info("Rewinding chain")
+	if err := (*backend).SetHead(hash); err != nil {
+		return err
+	}
+	log.Global.WithField("head", (*backend).CurrentHeader().NumberArray()).Info("Rewind done")
+	return nil
+}
@@ -3,10 +3,13 @@ package utils
 import (
 	"fmt"
 	"io"
+	"math/big"
+	"net"
 	"os"
 	"path"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -21,6 +24,7 @@ import (
 	"github.com/dominant-strategies/go-quai/quai"
 	"github.com/dominant-strategies/go-quai/quai/quaiconfig"
 	"github.com/dominant-strategies/go-quai/quaistats"
+	"github.com/dominant-strategies/go-quai/stratum"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -117,6 +121,7 @@ func defaultNodeConfig() node.Config {
 // makeFullNode loads quai configuration and creates the Quai backend.
 func makeFullNode(p2p quai.NetworkingAPI, nodeLocation common.Location, slicesRunning []common.Location, currentExpansionNumber uint8, genesisBlock *types.WorkObject, logger *log.Logger) (*node.Node, quaiapi.Backend) {
 	stack, cfg := makeConfigNode(slicesRunning, nodeLocation, currentExpansionNumber, logger)
+	unlockAccounts(stack, nodeLocation, logger)
 	startingExpansionNumber := viper.GetUint64(StartingExpansionNumberFlag.Name)
 	backend, _ := RegisterQuaiService(stack, p2p, cfg.Quai, cfg.Node.NodeLocation.Context(), currentExpansionNumber, startingExpansionNumber, genesisBlock, logger)
 	sendfullstats := viper.GetBool(SendFullStatsFlag.Name)
@@ -124,9 +129,54 @@ func makeFullNode(p2p quai.NetworkingAPI, nodeLocation common.Location, slicesRu
 	if cfg.Quaistats.URL != "" && backend.ProcessingState() {
 		RegisterQuaiStatsService(stack, backend, cfg.Quaistats.URL, sendfullstats)
 	}
+	// Add the stratum server if requested, only zones have work to hand out
+	if viper.GetBool(StratumEnabledFlag.Name) && backend.ProcessingState() && nodeLocation.Context() == common.ZONE_CTX {
+		RegisterStratumService(stack, backend, nodeLocation)
+	}
 	return stack, backend
 }
 
+// unlockAccounts unlocks the accounts of --node.unlock located in the zone of
+// the node, with the passwords of --node.password given in the same order. The
+// last password is used for the remaining accounts if there are fewer
+// passwords than accounts.
+func unlockAccounts(stack *node.Node, nodeLocation common.Location, logger *log.Logger) {
+	var unlocks []string
+	for _, account := range strings.Split(viper.GetString(UnlockedAccountFlag.Name), ",") {
+		if trimmed := strings.TrimSpace(account); trimmed != "" {
+			unlocks = append(unlocks, trimmed)
+		}
+	}
+	if len(unlocks) == 0 || nodeLocation.Context() != common.ZONE_CTX {
+		return
+	}
+	// Unlocked accounts could be used by anyone who can reach the RPC server
+	if !stack.Config().InsecureUnlockAllowed && stack.Config().ExtRPCEnabled() {
+		Fatalf("Account unlock with HTTP access is forbidden!")
+	}
+	passwords := MakePasswordList()
+	if len(passwords) == 0 {
+		Fatalf("No passwords given in --%s to unlock accounts", PasswordFileFlag.Name)
+	}
+	for i, account := range unlocks {
+		if !common.IsHexAddress(account) {
+			Fatalf("Invalid account in --%s: %s", UnlockedAccountFlag.Name, account)
+		}
+		addr := common.HexToAddress(account, nodeLocation)
+		if !addr.Location().Equal(nodeLocation) {
+			continue
+		}
+		password := passwords[len(passwords)-1]
+		if i < len(passwords) {
+			password = passwords[i]
+		}
+		if err := stack.KeyStore().Unlock(addr, password); err != nil {
+			Fatalf("Failed to unlock account %s: %v", account, err)
+		}
+		logger.WithField("address", addr.Hex()).Info("Unlocked account")
+	}
+}
+
 // RegisterQuaiService adds a Quai client to the stack.
 // The second return value is the full node instance, which may be nil if the
 // node is running as a light client.
@@ -146,6 +196,16 @@ func RegisterQuaiStatsService(stack *node.Node, backend quaiapi.Backend, url str
 	}
 }
 
+// RegisterStratumService configures the stratum mining server of the zone and
+// adds it to the given node.
+func RegisterStratumService(stack *node.Node, backend quaiapi.Backend, nodeLocation common.Location) {
+	addr := net.JoinHostPort(viper.GetString(StratumListenAddrFlag.Name), strconv.Itoa(GetStratumPort(nodeLocation)))
+	difficulty := new(big.Int).SetUint64(viper.GetUint64(StratumDifficultyFlag.Name))
+	if err := stratum.New(stack, backend, addr, difficulty); err != nil {
+		Fatalf("Failed to register the stratum service: %v", err)
+	}
+}
+
 // Fatalf formats a message to standard error and exits the program.
 // The message is also printed to standard output if standard error
 // is redirected to a different file.
@@ -0,0 +1,369 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/internal/quaiapi"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+const (
+	c_devAccountsPerLedger = 4
+	c_devTxPollInterval    = 250 * time.Millisecond
+	c_devHeadPollInterval  = 50 * time.Millisecond
+	c_devSealTimeout       = 10 * time.Second
+	// c_devSealsPerRound is the number of blocks sealed at most to get to a
+	// coordinated block, which the dev engine gives after a zone block and a
+	// region block
+	c_devSealsPerRound = 3
+	// c_devMaxEmptySeals is the number of blocks sealed for unchanged pending
+	// transactions without including any of them, after which the zone waits
+	// for its pool to change, so transactions that cannot be included do not
+	// keep the chain sealing
+	c_devMaxEmptySeals = 2
+)
+
+var (
+	// devQuaiBalance is the balance of each developer account on the Quai ledger
+	devQuaiBalance = new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
+
+	errDevStalePendingHeader = errors.New("pending header is not built on the current head")
+	errDevSealTimeout        = errors.New("timed out waiting for the sealed block to be appended")
+)
+
+// IsDevMode reports whether the node runs the instant sealing developer mode,
+// which is enabled by setting the dev period on the local environment. The
+// local environment alone does not enable it, since it is also used to run
+// local networks of several nodes mined by external miners, which the dev mode
+// would seal over and disconnect from their peers.
+func IsDevMode() bool {
+	return viper.GetString(EnvironmentFlag.Name) == params.LocalName && viper.IsSet(DevPeriodFlag.Name)
+}
+
+// DevPeriod returns the block period of the dev mode, zero if blocks are only
+// sealed for pending transactions.
+func DevPeriod() time.Duration {
+	return time.Duration(viper.GetUint64(DevPeriodFlag.Name)) * time.Second
+}
+
+// DevAccount is a developer account pre-funded in dev mode, with a balance on
+// the Quai ledger or with a UTXO on the Qi ledger.
+type DevAccount = chaingen.Account
+
+// DevAccounts returns the developer accounts of a ledger of the zone. The keys
+// are derived from a fixed seed, so the accounts are the same on every run.
+func DevAccounts(location common.Location, qi bool) []DevAccount {
+	return chaingen.SeededAccounts("go-quai dev", location, qi, c_devAccountsPerLedger)
+}
+
+// DevAllocDir returns the directory of the genesis allocation files funding the
+// developer accounts in dev mode.
+func DevAllocDir() string {
+	return filepath.Join(rootDataDir(), "devallocs")
+}
+
+// WriteDevAllocs writes the genesis allocation files funding the developer
+// accounts of the zones on both ledgers, and logs the accounts. Files which
+// already exist are kept, so they can be edited to fund other accounts.
+func WriteDevAllocs(locations []common.Location) error {
+	dir := DevAllocDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for _, location := range locations {
+		quaiAccounts := DevAccounts(location, false)
+		quaiAlloc := make(map[string]core.GenesisAccount, len(quaiAccounts))
+		for _, account := range quaiAccounts {
+			quaiAlloc[account.Address.Hex()] = core.GenesisAccount{Balance: devQuaiBalance}
+		}
+		if err := writeDevAlloc(core.GenesisQuaiAllocFile(dir, location), quaiAlloc); err != nil {
+			return err
+		}
+		qiAccounts := DevAccounts(location, true)
+		qiAlloc := make(map[string]core.GenesisUTXO, len(qiAccounts))
+		for _, account := range qiAccounts {
+			qiAlloc[account.Address.Hex()] = core.GenesisUTXO{
+				Denomination: types.MaxDenomination,
+				Hash:         crypto.Keccak256Hash(account.Address.Bytes()).Hex(),
+			}
+		}
+		if err := writeDevAlloc(core.GenesisQiAllocFile(dir, location), qiAlloc); err != nil {
+			return err
+		}
+
+		for _, accounts := range [][]DevAccount{quaiAccounts, qiAccounts} {
+			for _, account := range accounts {
+				log.Global.WithFields(log.Fields{
+					"location": location.Name(),
+					"address":  account.Address.Hex(),
+					"key":      hexutil.Encode(crypto.FromECDSA(account.Key)),
+				}).Info("Dev account")
+			}
+		}
+	}
+	return nil
+}
+
+func writeDevAlloc(filename string, alloc interface{}) error {
+	if _, err := os.Stat(filename); err == nil {
+		log.Global.WithField("file", filename).Info("Keeping existing dev allocation")
+		return nil
+	}
+	data, err := json.MarshalIndent(alloc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+// devPoolState tracks the pending transactions of a zone across the seals of
+// the dev mode.
+type devPoolState struct {
+	pending    int
+	qi         int
+	emptySeals int
+}
+
+// devSealer decides when the dev mode seals the blocks of the zones.
+type devSealer struct {
+	period    time.Duration
+	locations []common.Location
+	// stats returns the number of pending Quai and Qi transactions in the
+	// pool of the zone, and the number of its head
+	stats func(location common.Location) (pending int, qi int, number uint64)
+	// seal seals blocks in the zone, and returns the number of transactions
+	// from the pool they include
+	seal  func(location common.Location) (int, error)
+	quit  chan struct{}
+	pools map[string]devPoolState
+}
+
+// StartDevSealer starts sealing blocks in the running zones, every period if it
+// is positive, or else as soon as transactions are pending in a zone.
+func (hc *HierarchicalCoordinator) StartDevSealer(period time.Duration) {
+	sealer := &devSealer{
+		period:    period,
+		locations: hc.slicesRunning,
+		stats:     hc.devStats,
+		seal:      hc.devSeal,
+		quit:      hc.quitCh,
+		pools:     make(map[string]devPoolState),
+	}
+	hc.wg.Add(1)
+	go func() {
+		defer hc.wg.Done()
+		sealer.loop()
+	}()
+}
+
+func (s *devSealer) loop() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Global.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Error("Go-Quai Panicked")
+		}
+	}()
+
+	interval := s.period
+	if interval == 0 {
+		interval = c_devTxPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, location := range s.locations {
+				s.tick(location)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// tick seals blocks in the zone every tick if the dev mode has a period, or
+// else only if transactions are pending in its pool.
+func (s *devSealer) tick(location common.Location) {
+	if s.period > 0 {
+		if _, err := s.seal(location); err != nil {
+			logDevSealError(location, err)
+		}
+		return
+	}
+	// The developer accounts are funded by the allocations applied at the
+	// first block, so it is sealed right away for them to be usable
+	pending, qi, number := s.stats(location)
+	if pending+qi == 0 && number > 0 {
+		delete(s.pools, location.Name())
+		return
+	}
+	pool := s.pools[location.Name()]
+	if pool.pending != pending || pool.qi != qi {
+		pool = devPoolState{pending: pending, qi: qi}
+	}
+	if pool.emptySeals >= c_devMaxEmptySeals {
+		return
+	}
+	txs, err := s.seal(location)
+	if err != nil {
+		logDevSealError(location, err)
+		return
+	}
+	if txs == 0 {
+		pool.emptySeals++
+	} else {
+		pool.emptySeals = 0
+	}
+	s.pools[location.Name()] = pool
+}
+
+// devStats returns the number of pending Quai and Qi transactions in the pool
+// of the zone, and the number of its head.
+func (hc *HierarchicalCoordinator) devStats(location common.Location) (int, int, uint64) {
+	backend := hc.GetBackend(location)
+	pending, _, qi := backend.Stats()
+	return pending, qi, backend.CurrentHeader().NumberU64(common.ZONE_CTX)
+}
+
+// devSeal seals blocks in the zone until one of them is coordinated across
+// prime, region and zone, and returns the number of transactions from the pool
+// they include.
+func (hc *HierarchicalCoordinator) devSeal(location common.Location) (int, error) {
+	txs := 0
+	for i := 0; i < c_devSealsPerRound; i++ {
+		block, order, err := hc.devSealBlock(location)
+		if err != nil {
+			return txs, err
+		}
+		txs += devTxCount(block)
+		if order == common.PRIME_CTX {
+			break
+		}
+	}
+	// Reset the pool to the sealed head, so that its stats no longer count
+	// the transactions just included when the next round is decided
+	hc.GetBackend(location).SyncTxPool()
+	return txs, nil
+}
+
+// devSealBlock seals the pending header of the zone with the dev engine, and
+// submits it like a miner to every chain of the order of the block. It returns
+// once the block is appended and a new pending header is built on it.
+func (hc *HierarchicalCoordinator) devSealBlock(location common.Location) (*types.WorkObject, int, error) {
+	ctx := context.Background()
+	backend := hc.GetBackend(location)
+	raw, err := quaiapi.NewPublicBlockChainQuaiAPI(backend).GetPendingHeader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	protoWo := new(types.ProtoWorkObject)
+	if err := proto.Unmarshal(raw, protoWo); err != nil {
+		return nil, 0, err
+	}
+	header := new(types.WorkObject)
+	if err := header.ProtoDecode(protoWo, location, types.PEtxObject); err != nil {
+		return nil, 0, err
+	}
+	if header.ParentHash(common.ZONE_CTX) != backend.CurrentHeader().Hash() {
+		return nil, 0, errDevStalePendingHeader
+	}
+
+	results := make(chan *types.WorkObject, 1)
+	if err := backend.Engine().Seal(header, results, nil); err != nil {
+		return nil, 0, err
+	}
+	sealed := <-results
+	_, order, err := backend.CalcOrder(sealed)
+	if err != nil {
+		return nil, 0, err
+	}
+	protoSealed, err := sealed.ProtoEncode(types.PEtxObject)
+	if err != nil {
+		return nil, 0, err
+	}
+	raw, err = proto.Marshal(protoSealed)
+	if err != nil {
+		return nil, 0, err
+	}
+	for nodeCtx := common.ZONE_CTX; nodeCtx >= order; nodeCtx-- {
+		api := quaiapi.NewPublicBlockChainQuaiAPI(hc.GetBackendForLocationAndOrder(location, nodeCtx))
+		if err := api.ReceiveMinedHeader(ctx, raw); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	hash := sealed.Hash()
+	timeout := time.NewTimer(c_devSealTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(c_devHeadPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pendingHeader, err := backend.GetPendingHeader()
+			if err != nil || pendingHeader == nil {
+				continue
+			}
+			extended := true
+			for nodeCtx := order; nodeCtx <= common.ZONE_CTX; nodeCtx++ {
+				if pendingHeader.ParentHash(nodeCtx) != hash {
+					extended = false
+				}
+			}
+			if !extended {
+				continue
+			}
+			block, err := backend.BlockByHash(ctx, hash)
+			if err == nil && block == nil {
+				err = errors.New("sealed block not found")
+			}
+			return block, order, err
+		case <-timeout.C:
+			return nil, 0, errDevSealTimeout
+		case <-hc.quitCh:
+			return nil, 0, errDevSealTimeout
+		}
+	}
+}
+
+// logDevSealError logs a failed seal, unless the pending header of the zone is
+// only being rebuilt, in which case the seal is retried on the next tick.
+func logDevSealError(location common.Location, err error) {
+	if err == errDevStalePendingHeader {
+		return
+	}
+	log.Global.WithFields(log.Fields{"location": location.Name(), "err": err}).Warn("Dev mode failed to seal a block")
+}
+
+// devTxCount returns the number of transactions of the block sent to the pool,
+// leaving out the external transactions it includes.
+func devTxCount(block *types.WorkObject) int {
+	count := 0
+	for _, tx := range block.Transactions() {
+		if tx.Type() != types.ExternalTxType {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/quai"
+)
+
+// devNetwork implements the NetworkingAPI of the dev mode. Like the offline
+// network it never touches the network, but the blocks broadcast by the slices
+// are looped back into the consensus backend, the way gossipsub delivers our
+// own messages, so that the blocks sealed locally get appended.
+type devNetwork struct {
+	*offlineNetwork
+	consensus quai.ConsensusAPI
+}
+
+// NewDevNetwork creates the in-process network of the dev mode.
+func NewDevNetwork() quai.NetworkingAPI {
+	return &devNetwork{offlineNetwork: newOfflineNetwork()}
+}
+
+func (n *devNetwork) SetConsensusBackend(consensus quai.ConsensusAPI) {
+	n.consensus = consensus
+}
+
+// Broadcast hands the blocks to the slice at location, as if they were
+// received from a peer. Everything else has no one to be sent to.
+func (n *devNetwork) Broadcast(location common.Location, data interface{}) error {
+	view, ok := data.(*types.WorkObjectBlockView)
+	if !ok || n.consensus == nil {
+		return nil
+	}
+	n.consensus.OnNewBroadcast("", "", "", types.WorkObjectBlockView{WorkObject: types.CopyWorkObject(view.WorkObject)}, location)
+	return nil
+}
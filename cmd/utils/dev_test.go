@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/crypto"
+)
+
+// TestDevAccounts verifies the developer accounts are the same on every call,
+// and belong to the zone and the ledger they are derived for.
+func TestDevAccounts(t *testing.T) {
+	for _, location := range []common.Location{{0, 0}, {1, 2}} {
+		for _, qi := range []bool{false, true} {
+			accounts := DevAccounts(location, qi)
+			require.Len(t, accounts, c_devAccountsPerLedger)
+			assert.Equal(t, accounts, DevAccounts(location, qi))
+
+			seen := make(map[common.Address]bool)
+			for _, account := range accounts {
+				assert.False(t, seen[account.Address], "duplicate account %s", account.Address.Hex())
+				seen[account.Address] = true
+				assert.True(t, common.IsInChainScope(account.Address.Bytes(), location))
+				assert.Equal(t, qi, account.Address.IsInQiLedgerScope())
+				assert.Equal(t, account.Address, crypto.PubkeyToAddress(account.Key.PublicKey, location))
+			}
+		}
+	}
+	assert.NotEqual(t, DevAccounts(common.Location{0, 0}, false)[0].Address, DevAccounts(common.Location{0, 0}, true)[0].Address)
+}
+
+// testDevZone is a zone sealed by a dev sealer under test.
+type testDevZone struct {
+	mu       sync.Mutex
+	pending  int
+	included bool
+	number   uint64
+	sealed   chan int
+}
+
+func (z *testDevZone) stats(common.Location) (int, int, uint64) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	return z.pending, 0, z.number
+}
+
+// seal seals a block, which includes the pending transactions unless they
+// cannot be included.
+func (z *testDevZone) seal(common.Location) (int, error) {
+	z.mu.Lock()
+	txs := 0
+	if z.included {
+		txs, z.pending = z.pending, 0
+	}
+	z.number++
+	z.mu.Unlock()
+	z.sealed <- txs
+	return txs, nil
+}
+
+func (z *testDevZone) set(pending int, included bool) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.pending, z.included = pending, included
+}
+
+func startTestDevSealer(period time.Duration, zone *testDevZone) func() {
+	sealer := &devSealer{
+		period:    period,
+		locations: []common.Location{{0, 0}},
+		stats:     zone.stats,
+		seal:      zone.seal,
+		quit:      make(chan struct{}),
+		pools:     make(map[string]devPoolState),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sealer.loop()
+	}()
+	return func() {
+		close(sealer.quit)
+		// Drain a seal in flight so that the loop sees the quit channel
+		for {
+			select {
+			case <-zone.sealed:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+// TestDevSealerPending verifies the dev mode without period seals the first
+// block, and then only seals blocks when transactions arrive in the pool, until
+// they keep not being included.
+func TestDevSealerPending(t *testing.T) {
+	zone := &testDevZone{sealed: make(chan int)}
+	stop := startTestDevSealer(0, zone)
+	defer stop()
+
+	expectSeal := func() int {
+		select {
+		case txs := <-zone.sealed:
+			return txs
+		case <-time.After(10 * c_devTxPollInterval):
+			t.Fatal("no block sealed")
+			return 0
+		}
+	}
+	expectNoSeal := func() {
+		select {
+		case <-zone.sealed:
+			t.Fatal("block sealed without pending transactions")
+		case <-time.After(4 * c_devTxPollInterval):
+		}
+	}
+
+	// The first block is sealed right away for the allocations to apply
+	require.Equal(t, 0, expectSeal())
+	expectNoSeal()
+
+	zone.set(2, true)
+	require.Equal(t, 2, expectSeal())
+	expectNoSeal()
+
+	// Transactions which cannot be included only seal a few empty blocks
+	zone.set(1, false)
+	for i := 0; i < c_devMaxEmptySeals; i++ {
+		require.Equal(t, 0, expectSeal())
+	}
+	expectNoSeal()
+
+	// A change of the pool is tried again
+	zone.set(2, true)
+	require.Equal(t, 2, expectSeal())
+}
+
+// TestDevSealerPeriod verifies the dev mode with a period seals a block on
+// every tick, even without pending transactions.
+func TestDevSealerPeriod(t *testing.T) {
+	const period = 100 * time.Millisecond
+	zone := &testDevZone{sealed: make(chan int)}
+	stop := startTestDevSealer(period, zone)
+	defer stop()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-zone.sealed:
+		case <-time.After(10 * period):
+			t.Fatalf("no block sealed on tick %d", i)
+		}
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 3*period-period/2)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/common/constants"
 	"github.com/dominant-strategies/go-quai/common/fdlimit"
+	"github.com/dominant-strategies/go-quai/consensus/blake3pow"
 	"github.com/dominant-strategies/go-quai/core"
 	"github.com/dominant-strategies/go-quai/core/rawdb"
 	"github.com/dominant-strategies/go-quai/ethdb"
@@ -73,6 +74,8 @@ var NodeFlags = []Flag{
 	IPAddrFlag,
 	P2PPortFlag,
 	BootPeersFlag,
+	StaticPeersFlag,
+	TrustedPeersFlag,
 	PortMapFlag,
 	KeyFileFlag,
 	MinPeersFlag,
@@ -80,6 +83,7 @@ var NodeFlags = []Flag{
 	LocationFlag,
 	SoloFlag,
 	DBEngineFlag,
+	FreezerDepthFlag,
 	NetworkIdFlag,
 	SlicesRunningFlag,
 	DevPeriodFlag,
@@ -113,6 +117,8 @@ var NodeFlags = []Flag{
 	QuaiStatsURLFlag,
 	SendFullStatsFlag,
 	IndexAddressUtxos,
+	IndexWorkShares,
+	SnapSyncFlag,
 	ReIndex,
 	ValidateIndexer,
 	StartingExpansionNumberFlag,
@@ -157,6 +163,18 @@ var RPCFlags = []Flag{
 	WSAllowedOriginsFlag,
 	WSPathPrefixFlag,
 	WSPortStartFlag,
+	AuthEnabledFlag,
+	AuthListenAddrFlag,
+	AuthVirtualHostsFlag,
+	AuthApiFlag,
+	AuthPortStartFlag,
+	StratumEnabledFlag,
+	StratumListenAddrFlag,
+	StratumPortStartFlag,
+	StratumDifficultyFlag,
+	JWTSecretFlag,
+	IPCDisabledFlag,
+	IPCDirFlag,
 	PreloadJSFlag,
 	RPCGlobalTxFeeCapFlag,
 	RPCGlobalGasCapFlag,
@@ -245,6 +263,18 @@ var (
 		Usage: "list of bootstrap peers. Syntax: <multiaddress1>,<multiaddress2>,..." + generateEnvDoc(c_NodeFlagPrefix+"bootpeers"),
 	}
 
+	StaticPeersFlag = Flag{
+		Name:  c_NodeFlagPrefix + "static-peers",
+		Value: []string{},
+		Usage: "list of peers to always stay connected to, which are redialed when disconnected and never pruned. Syntax: <multiaddress1>,<multiaddress2>,..." + generateEnvDoc(c_NodeFlagPrefix+"static-peers"),
+	}
+
+	TrustedPeersFlag = Flag{
+		Name:  c_NodeFlagPrefix + "trusted-peers",
+		Value: []string{},
+		Usage: "list of peers allowed to connect above the max-peers limit. Syntax: <multiaddress1>,<multiaddress2>,..." + generateEnvDoc(c_NodeFlagPrefix+"trusted-peers"),
+	}
+
 	PortMapFlag = Flag{
 		Name:  c_NodeFlagPrefix + "portmap",
 		Value: true,
@@ -289,6 +319,12 @@ var (
 		Usage: "Backing database implementation to use ('leveldb' or 'pebble')" + generateEnvDoc(c_NodeFlagPrefix+"db-engine"),
 	}
 
+	FreezerDepthFlag = Flag{
+		Name:  c_NodeFlagPrefix + "freezer-depth",
+		Value: uint64(0),
+		Usage: "Depth after which finalized zone blocks are moved to the ancient store (0 = disabled)" + generateEnvDoc(c_NodeFlagPrefix+"freezer-depth"),
+	}
+
 	NetworkIdFlag = Flag{
 		Name:  c_NodeFlagPrefix + "networkid",
 		Value: 1,
@@ -304,7 +340,7 @@ var (
 	DevPeriodFlag = Flag{
 		Name:  c_NodeFlagPrefix + "dev-period",
 		Value: 0,
-		Usage: "Block period to use for the dev network (integer) (0 = mine only if transaction pending)" + generateEnvDoc(c_NodeFlagPrefix+"dev-period"),
+		Usage: "Block period in seconds of the instant sealing dev mode (0 = seal only if transactions are pending). Setting it on the local environment enables the dev mode, which is opt-in since the local environment also runs multi-node networks mined by external miners" + generateEnvDoc(c_NodeFlagPrefix+"dev-period"),
 	}
 
 	IdentityFlag = Flag{
@@ -547,6 +583,18 @@ var (
 		Usage: "Index address utxos" + generateEnvDoc(c_NodeFlagPrefix+"index-address-utxos"),
 	}
 
+	IndexWorkShares = Flag{
+		Name:  c_NodeFlagPrefix + "index-workshares",
+		Value: false,
+		Usage: "Index the rewards paid to the coinbases of blocks and included workshares" + generateEnvDoc(c_NodeFlagPrefix+"index-workshares"),
+	}
+
+	SnapSyncFlag = Flag{
+		Name:  c_NodeFlagPrefix + "snap-sync",
+		Value: false,
+		Usage: "Download the state of a recent block from peers instead of processing the whole chain on a new zone node" + generateEnvDoc(c_NodeFlagPrefix+"snap-sync"),
+	}
+
 	ReIndex = Flag{
 		Name:  c_NodeFlagPrefix + "reindex",
 		Value: false,
@@ -686,6 +734,78 @@ var (
 		Usage: "WS-RPC server listening port" + generateEnvDoc(c_RPCFlagPrefix+"ws-port"),
 	}
 
+	AuthEnabledFlag = Flag{
+		Name:  c_RPCFlagPrefix + "auth",
+		Value: false,
+		Usage: "Enable the JWT authenticated RPC server, serving HTTP and WS requests" + generateEnvDoc(c_RPCFlagPrefix+"auth"),
+	}
+
+	AuthListenAddrFlag = Flag{
+		Name:  c_RPCFlagPrefix + "auth-addr",
+		Value: node.DefaultHTTPHost,
+		Usage: "Authenticated RPC server listening interface" + generateEnvDoc(c_RPCFlagPrefix+"auth-addr"),
+	}
+
+	AuthVirtualHostsFlag = Flag{
+		Name:  c_RPCFlagPrefix + "auth-vhosts",
+		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
+		Usage: "Comma separated list of virtual hostnames from which to accept authenticated requests (server enforced). Accepts '*' wildcard." + generateEnvDoc(c_RPCFlagPrefix+"auth-vhosts"),
+	}
+
+	AuthApiFlag = Flag{
+		Name:  c_RPCFlagPrefix + "auth-api",
+		Value: "",
+		Usage: "API's offered over the authenticated RPC interface, all of them if empty" + generateEnvDoc(c_RPCFlagPrefix+"auth-api"),
+	}
+
+	AuthPortStartFlag = Flag{
+		Name:  c_RPCFlagPrefix + "auth-port",
+		Value: 7001,
+		Usage: "Authenticated RPC server listening port" + generateEnvDoc(c_RPCFlagPrefix+"auth-port"),
+	}
+
+	StratumEnabledFlag = Flag{
+		Name:  c_RPCFlagPrefix + "stratum",
+		Value: false,
+		Usage: "Enable the Stratum v1 mining server of the zones processing the state" + generateEnvDoc(c_RPCFlagPrefix+"stratum"),
+	}
+
+	StratumListenAddrFlag = Flag{
+		Name:  c_RPCFlagPrefix + "stratum-addr",
+		Value: node.DefaultHTTPHost,
+		Usage: "Stratum server listening interface" + generateEnvDoc(c_RPCFlagPrefix+"stratum-addr"),
+	}
+
+	StratumPortStartFlag = Flag{
+		Name:  c_RPCFlagPrefix + "stratum-port",
+		Value: 3333,
+		Usage: "Stratum server listening port" + generateEnvDoc(c_RPCFlagPrefix+"stratum-port"),
+	}
+
+	StratumDifficultyFlag = Flag{
+		Name:  c_RPCFlagPrefix + "stratum-difficulty",
+		Value: uint64(1 << 32),
+		Usage: "Default share difficulty of the stratum workers, which can request another one with a d=<difficulty> password" + generateEnvDoc(c_RPCFlagPrefix+"stratum-difficulty"),
+	}
+
+	JWTSecretFlag = Flag{
+		Name:  c_RPCFlagPrefix + "jwtsecret",
+		Value: "",
+		Usage: "Path to the hex encoded secret of the JWT tokens of the authenticated RPC server, generated if missing (default: <data-dir>/jwtsecret)" + generateEnvDoc(c_RPCFlagPrefix+"jwtsecret"),
+	}
+
+	IPCDisabledFlag = Flag{
+		Name:  c_RPCFlagPrefix + "ipc-disable",
+		Value: false,
+		Usage: "Disable the IPC-RPC server" + generateEnvDoc(c_RPCFlagPrefix+"ipc-disable"),
+	}
+
+	IPCDirFlag = Flag{
+		Name:  c_RPCFlagPrefix + "ipc-dir",
+		Value: "",
+		Usage: "Directory of the IPC sockets of the slices, named after them like zone-0-0.ipc (default: <data-dir>)" + generateEnvDoc(c_RPCFlagPrefix+"ipc-dir"),
+	}
+
 	PreloadJSFlag = Flag{
 		Name:  c_RPCFlagPrefix + "preload",
 		Value: "",
@@ -959,6 +1079,78 @@ func GetWSPort(nodeLocation common.Location) int {
 	panic("node location is not valid")
 }
 
+// setAuth configures the authenticated RPC listener from the set command line
+// flags, leaving its address empty if the endpoint is disabled.
+func setAuth(cfg *node.Config, nodeLocation common.Location) {
+	if viper.GetBool(AuthEnabledFlag.Name) && cfg.AuthAddr == "" {
+		cfg.AuthAddr = viper.GetString(AuthListenAddrFlag.Name)
+	}
+
+	cfg.AuthPort = GetAuthPort(nodeLocation)
+
+	cfg.AuthVirtualHosts = SplitAndTrim(viper.GetString(AuthVirtualHostsFlag.Name))
+
+	cfg.AuthModules = SplitAndTrim(viper.GetString(AuthApiFlag.Name))
+
+	cfg.JWTSecret = JWTSecretPath()
+}
+
+func GetAuthPort(nodeLocation common.Location) int {
+	var startPort int
+	if viper.IsSet(AuthPortStartFlag.Name) {
+		startPort = viper.GetInt(AuthPortStartFlag.Name)
+	} else {
+		startPort = AuthPortStartFlag.Value.(int)
+	}
+	switch nodeLocation.Context() {
+	case common.PRIME_CTX:
+		return startPort
+	case common.REGION_CTX:
+		return (startPort + c_regionPortOffset) + nodeLocation.Region()
+	case common.ZONE_CTX:
+		return (startPort + c_zonePortOffset) + 20*nodeLocation.Region() + nodeLocation.Zone()
+	}
+	panic("node location is not valid")
+}
+
+func GetStratumPort(nodeLocation common.Location) int {
+	var startPort int
+	if viper.IsSet(StratumPortStartFlag.Name) {
+		startPort = viper.GetInt(StratumPortStartFlag.Name)
+	} else {
+		startPort = StratumPortStartFlag.Value.(int)
+	}
+	switch nodeLocation.Context() {
+	case common.PRIME_CTX:
+		return startPort
+	case common.REGION_CTX:
+		return (startPort + c_regionPortOffset) + nodeLocation.Region()
+	case common.ZONE_CTX:
+		return (startPort + c_zonePortOffset) + 20*nodeLocation.Region() + nodeLocation.Zone()
+	}
+	panic("node location is not valid")
+}
+
+// JWTSecretPath returns the path of the jwt secret set with --rpc.jwtsecret, or
+// else the jwt secret of the data directory, which is shared by all the slices
+// it holds.
+func JWTSecretPath() string {
+	if viper.IsSet(JWTSecretFlag.Name) {
+		return viper.GetString(JWTSecretFlag.Name)
+	}
+	return filepath.Join(rootDataDir(), "jwtsecret")
+}
+
+// rootDataDir returns the data directory shared by all the slices of the node.
+func rootDataDir() string {
+	cfg := node.DefaultConfig
+	setRootDataDir(&cfg)
+	if cfg.DataDir == "" {
+		cfg.DataDir = node.DefaultConfig.DataDir
+	}
+	return cfg.DataDir
+}
+
 // setGasLimitCeil sets the gas limit ceils based on the network that is
 // running
 func setGasLimitCeil(cfg *quaiconfig.Config) {
@@ -1005,6 +1197,12 @@ func HexAddress(account string, nodeLocation common.Location) (common.Address, e
 // setCoinbase retrieves the etherbase either from the directly specified
 // command line flags or from the keystore if CLI indexed.
 func setCoinbase(cfg *quaiconfig.Config) {
+	// The dev mode pays the first developer accounts unless told otherwise
+	if IsDevMode() && !viper.IsSet(QuaiCoinbaseFlag.Name) && !viper.IsSet(QiCoinbaseFlag.Name) {
+		cfg.Miner.QuaiCoinbase = DevAccounts(cfg.NodeLocation, false)[0].Address
+		cfg.Miner.QiCoinbase = DevAccounts(cfg.NodeLocation, true)[0].Address
+		return
+	}
 	coinbaseMap, err := ParseCoinbaseAddresses()
 	if err != nil {
 		log.Global.Fatalf("error parsing coinbase addresses: %s", err)
@@ -1044,12 +1242,12 @@ func MakePasswordList() []string {
 func SetNodeConfig(cfg *node.Config, nodeLocation common.Location, logger *log.Logger) {
 	setHTTP(cfg, nodeLocation)
 	setWS(cfg, nodeLocation)
+	setAuth(cfg, nodeLocation)
+	setIPC(cfg, nodeLocation)
 	setNodeUserIdent(cfg)
 	setDataDir(cfg)
 
-	if viper.IsSet(KeyStoreDirFlag.Name) {
-		cfg.KeyStoreDir = viper.GetString(KeyStoreDirFlag.Name)
-	}
+	cfg.KeyStoreDir = KeyStoreDir()
 	if viper.GetString(EnvironmentFlag.Name) == params.DevName {
 		cfg.UseLightweightKDF = true
 	}
@@ -1064,9 +1262,69 @@ func SetNodeConfig(cfg *node.Config, nodeLocation common.Location, logger *log.L
 		logger.WithField("db-engine", dbEngine).Info("Using db engine")
 		cfg.DBEngine = dbEngine
 	}
+	if viper.IsSet(FreezerDepthFlag.Name) {
+		cfg.FreezerDepth = viper.GetUint64(FreezerDepthFlag.Name)
+	}
+}
+
+// KeyStoreDir returns the keystore directory set with --node.keystore, or else
+// the keystore directory of the data directory, which is shared by all the
+// slices it holds.
+func KeyStoreDir() string {
+	if viper.IsSet(KeyStoreDirFlag.Name) {
+		return viper.GetString(KeyStoreDirFlag.Name)
+	}
+	cfg := node.DefaultConfig
+	setRootDataDir(&cfg)
+	if cfg.DataDir == "" {
+		cfg.DataDir = node.DefaultConfig.DataDir
+	}
+	return filepath.Join(cfg.DataDir, "keystore")
 }
 
 func setDataDir(cfg *node.Config) {
+	setRootDataDir(cfg)
+
+	// Set specific directory for node location within the hierarchy
+	cfg.DataDir = filepath.Join(cfg.DataDir, sliceDirName(cfg.NodeLocation))
+}
+
+// sliceDirName returns the name of the data directory of the slice within the
+// data directory of the environment.
+func sliceDirName(location common.Location) string {
+	switch location.Context() {
+	case common.PRIME_CTX:
+		return "prime"
+	case common.REGION_CTX:
+		return "region-" + strconv.Itoa(location.Region())
+	case common.ZONE_CTX:
+		return "zone-" + strconv.Itoa(location.Region()) + "-" + strconv.Itoa(location.Zone())
+	}
+	panic("node location is not valid")
+}
+
+// setIPC sets the path of the IPC socket of the slice, which is named after the
+// slice in the IPC directory, unless IPC is disabled.
+func setIPC(cfg *node.Config, nodeLocation common.Location) {
+	if viper.GetBool(IPCDisabledFlag.Name) {
+		cfg.IPCPath = ""
+		return
+	}
+	dir := viper.GetString(IPCDirFlag.Name)
+	if dir == "" {
+		root := node.DefaultConfig
+		setRootDataDir(&root)
+		// Memory-only nodes have no data directory to hold their socket
+		if dir = root.DataDir; dir == "" {
+			dir = os.TempDir()
+		}
+	}
+	cfg.IPCPath = filepath.Join(dir, sliceDirName(nodeLocation)+".ipc")
+}
+
+// setRootDataDir sets the data directory of the environment, which holds the
+// data directories of the slices.
+func setRootDataDir(cfg *node.Config) {
 	environment := viper.GetString(EnvironmentFlag.Name)
 	switch {
 	case viper.IsSet(DataDirFlag.Name):
@@ -1082,18 +1340,6 @@ func setDataDir(cfg *node.Config) {
 	case environment == params.LocalName && cfg.DataDir == xdg.DataHome:
 		cfg.DataDir = filepath.Join(xdg.DataHome, params.LocalName)
 	}
-	// Set specific directory for node location within the hierarchy
-	switch cfg.NodeLocation.Context() {
-	case common.PRIME_CTX:
-		cfg.DataDir = filepath.Join(cfg.DataDir, "prime")
-	case common.REGION_CTX:
-		regionNum := strconv.Itoa(cfg.NodeLocation.Region())
-		cfg.DataDir = filepath.Join(cfg.DataDir, "region-"+regionNum)
-	case common.ZONE_CTX:
-		regionNum := strconv.Itoa(cfg.NodeLocation.Region())
-		zoneNum := strconv.Itoa(cfg.NodeLocation.Zone())
-		cfg.DataDir = filepath.Join(cfg.DataDir, "zone-"+regionNum+"-"+zoneNum)
-	}
 }
 
 func setTxPool(cfg *core.TxPoolConfig, nodeLocation common.Location) {
@@ -1306,12 +1552,22 @@ func SetQuaiConfig(stack *node.Node, cfg *quaiconfig.Config, slicesRunning []com
 	setTxPool(&cfg.TxPool, nodeLocation)
 
 	// If blake3 consensus engine is specifically asked use the blake3 engine
-	if viper.GetString(ConsensusEngineFlag.Name) == "blake3" {
+	if viper.GetString(ConsensusEngineFlag.Name) == "blake3" || IsDevMode() {
 		cfg.ConsensusEngine = "blake3"
 	} else {
 		cfg.ConsensusEngine = "progpow"
 	}
 	setConsensusEngineConfig(cfg)
+	// The dev mode seals with the dev engine and funds the developer accounts.
+	// Networks only give gas to the zone blocks once the miners had time to
+	// join, which the dev mode, without any peer, does not need to wait for,
+	// so transactions are included from the first block
+	if IsDevMode() {
+		cfg.Blake3Pow.PowMode = blake3pow.ModeDev
+		cfg.Blake3Pow.GenesisAllocDir = DevAllocDir()
+		timeToStartTx := uint64(0)
+		cfg.TimeToStartTx = &timeToStartTx
+	}
 
 	setWhitelist(cfg)
 
@@ -1394,6 +1650,8 @@ func SetQuaiConfig(stack *node.Node, cfg *quaiconfig.Config, slicesRunning []com
 		cfg.EnablePreimageRecording = viper.GetBool(VMEnableDebugFlag.Name)
 	}
 	cfg.IndexAddressUtxos = viper.GetBool(IndexAddressUtxos.Name)
+	cfg.IndexWorkShares = viper.GetBool(IndexWorkShares.Name)
+	cfg.SnapSync = viper.GetBool(SnapSyncFlag.Name)
 
 	if viper.IsSet(RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = viper.GetUint64(RPCGlobalGasCapFlag.Name)
@@ -409,6 +409,7 @@ func (hc *HierarchicalCoordinator) startNode(logPath string, quaiBackend quai.Co
 	if quaiBackend.ProcessingState(location) && location.Context() == common.ZONE_CTX {
 		// Subscribe to the new topics after setting the api backend
 		hc.p2p.Subscribe(location, &types.WorkObjectShareView{})
+		hc.p2p.Subscribe(location, common.Hashes{})
 	}
 
 	if location.Context() == common.PRIME_CTX || location.Context() == common.REGION_CTX || quaiBackend.ProcessingState(location) {
@@ -776,6 +777,10 @@ search:
 		otherNodes := hc.GetNodeListForLocation(location, badHashes)
 		for _, node := range otherNodes {
 			leaderBlock := backend.GetBlockByHash(node.hash)
+			if leaderBlock == nil {
+				// The block was deleted by a rewind of the slice
+				continue
+			}
 			modifiedConstraintMap, err = hc.calculateFrontierPoints(modifiedConstraintMap, leaderBlock, first)
 			first = false
 			if err != nil {
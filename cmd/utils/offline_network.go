@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/p2p"
+	"github.com/dominant-strategies/go-quai/p2p/protocol"
+	"github.com/dominant-strategies/go-quai/quai"
+)
+
+// offlineNetwork implements the NetworkingAPI without touching the network.
+// Instead of asking peers, requests are answered from a fixed set of
+// WorkObjects per slice, which lets the slice hierarchy run purely from local
+// data, e.g. while importing exported chain files.
+type offlineNetwork struct {
+	lock     sync.RWMutex
+	byHash   map[string]map[common.Hash]*types.WorkObject
+	byNumber map[string]map[uint64]*types.WorkObject
+}
+
+func newOfflineNetwork() *offlineNetwork {
+	return &offlineNetwork{
+		byHash:   make(map[string]map[common.Hash]*types.WorkObject),
+		byNumber: make(map[string]map[uint64]*types.WorkObject),
+	}
+}
+
+// AddBlocks makes the given blocks of the slice at location available to
+// requests from that slice.
+func (n *offlineNetwork) AddBlocks(location common.Location, blocks types.WorkObjects) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	key := location.Name()
+	if _, exists := n.byHash[key]; !exists {
+		n.byHash[key] = make(map[common.Hash]*types.WorkObject)
+		n.byNumber[key] = make(map[uint64]*types.WorkObject)
+	}
+	for _, block := range blocks {
+		n.byHash[key][block.Hash()] = block
+		n.byNumber[key][block.NumberU64(location.Context())] = block
+	}
+}
+
+func (n *offlineNetwork) Start() error { return nil }
+
+func (n *offlineNetwork) Stop() error { return nil }
+
+func (n *offlineNetwork) Subscribe(common.Location, interface{}) error { return nil }
+
+func (n *offlineNetwork) Unsubscribe(common.Location, interface{}) error { return nil }
+
+func (n *offlineNetwork) Broadcast(common.Location, interface{}) error { return nil }
+
+func (n *offlineNetwork) SetConsensusBackend(quai.ConsensusAPI) {}
+
+// Request answers block requests by hash, and prime block and header range
+// requests by number from the known blocks. The returned channel is always closed, so a
+// request for unknown data resolves to nil right away.
+func (n *offlineNetwork) Request(location common.Location, requestData interface{}, responseDataType interface{}) chan interface{} {
+	resultCh := make(chan interface{}, 1)
+	defer close(resultCh)
+
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	key := location.Name()
+	switch data := requestData.(type) {
+	case common.Hash:
+		block, exists := n.byHash[key][data]
+		if !exists {
+			return resultCh
+		}
+		switch responseDataType.(type) {
+		case *types.WorkObjectBlockView:
+			resultCh <- &types.WorkObjectBlockView{WorkObject: block}
+		case *types.WorkObjectHeaderView:
+			resultCh <- &types.WorkObjectHeaderView{WorkObject: block}
+		}
+	case *big.Int:
+		switch responseDataType.(type) {
+		case []*types.WorkObjectBlockView:
+			blocks := make([]*types.WorkObjectBlockView, 0, protocol.C_NumPrimeBlocksToDownload)
+			for i := uint64(0); i < protocol.C_NumPrimeBlocksToDownload; i++ {
+				block, exists := n.byNumber[key][data.Uint64()+i]
+				if !exists {
+					break
+				}
+				blocks = append(blocks, &types.WorkObjectBlockView{WorkObject: block})
+			}
+			if len(blocks) > 0 {
+				resultCh <- blocks
+			}
+		case []*types.WorkObjectHeaderView:
+			headers := make([]*types.WorkObjectHeaderView, 0, protocol.C_NumHeadersToDownload)
+			for i := uint64(0); i < protocol.C_NumHeadersToDownload; i++ {
+				block, exists := n.byNumber[key][data.Uint64()+i]
+				if !exists {
+					break
+				}
+				headers = append(headers, block.ConvertToHeaderView())
+			}
+			if len(headers) > 0 {
+				resultCh <- headers
+			}
+		}
+	}
+	return resultCh
+}
+
+// RequestFromPeer fails as there are no peers to request from.
+func (n *offlineNetwork) RequestFromPeer(core.PeerID, common.Location, interface{}, interface{}) (interface{}, error) {
+	return nil, errors.New("no peers in an offline network")
+}
+
+func (n *offlineNetwork) AdjustPeerQuality(core.PeerID, string, func(int) int) {}
+
+func (n *offlineNetwork) ProtectPeer(core.PeerID) {}
+
+func (n *offlineNetwork) UnprotectPeer(core.PeerID) {}
+
+func (n *offlineNetwork) BanPeer(core.PeerID) {}
+
+func (n *offlineNetwork) Peers() []p2p.PeerInfo { return nil }
+
+func (n *offlineNetwork) AddPeer(string) error {
+	return errors.New("no peers in an offline network")
+}
+
+func (n *offlineNetwork) BlockPeer(core.PeerID) error { return nil }
+
+func (n *offlineNetwork) UnblockSubnet(*net.IPNet) error { return nil }
+
+func (n *offlineNetwork) ListBlocked() p2p.BlockedPeers { return p2p.BlockedPeers{} }
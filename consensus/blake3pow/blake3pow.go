@@ -24,6 +24,15 @@ const (
 	ModeTest
 	ModeFake
 	ModeFullFake
+	// ModeDev accepts all blocks' seal as valid like ModeFake, and gives the
+	// region order to every block with zone entropy accumulated since the last
+	// region block, and the prime order to every block with region entropy
+	// accumulated since the last prime block, so that every third block is
+	// coordinated across prime, region and zone. Blocks are still sealed for
+	// their difficulty, so that they carry entropy, which the low difficulty of
+	// the local network makes instant. It backs the instant sealing developer
+	// mode.
+	ModeDev
 )
 
 // Config are the configuration parameters of the blake3pow.
@@ -36,6 +45,10 @@ type Config struct {
 
 	NodeLocation common.Location
 
+	// GenesisAllocDir is the directory of the genesis allocation files applied
+	// at the first block, core.DefaultGenesisAllocDir if empty
+	GenesisAllocDir string
+
 	MinDifficulty *big.Int
 
 	WorkShareThreshold int
@@ -435,7 +435,7 @@ func (blake3pow *Blake3pow) verifyHeader(chain consensus.ChainHeaderReader, head
 		}
 		// Verify the block's gas usage and verify the base fee.
 		// Verify that the gas limit remains within allowed bounds
-		expectedGasLimit := core.CalcGasLimit(parent, blake3pow.config.GasCeil)
+		expectedGasLimit := core.CalcGasLimit(chain.Config(), parent, blake3pow.config.GasCeil)
 		if expectedGasLimit != header.GasLimit() {
 			return fmt.Errorf("invalid gasLimit: have %d, want %d",
 				header.GasLimit(), expectedGasLimit)
@@ -445,7 +445,7 @@ func (blake3pow *Blake3pow) verifyHeader(chain consensus.ChainHeaderReader, head
 			return fmt.Errorf("invalid stateUsed: have %d, stateLimit %d", header.StateUsed(), header.StateLimit())
 		}
 		// Verify the stateLimit is correct based on the parent header.
-		expectedStateLimit := misc.CalcStateLimit(parent, params.StateCeil)
+		expectedStateLimit := misc.CalcStateLimit(chain.Config(), parent, params.StateCeil)
 		if header.StateLimit() != expectedStateLimit {
 			return fmt.Errorf("invalid stateLimit: have %v, want %v, parentStateLimit %v", expectedStateLimit, header.StateLimit(), parent.StateLimit())
 		}
@@ -566,7 +566,7 @@ func (blake3pow *Blake3pow) VerifySeal(header *types.WorkObjectHeader) (common.H
 // to make remote mining fast.
 func (blake3pow *Blake3pow) verifySeal(header *types.WorkObjectHeader) error {
 	// If we're running a fake PoW, accept any seal as valid
-	if blake3pow.config.PowMode == ModeFake || blake3pow.config.PowMode == ModeFullFake {
+	if blake3pow.config.PowMode == ModeFake || blake3pow.config.PowMode == ModeFullFake || blake3pow.config.PowMode == ModeDev {
 		time.Sleep(blake3pow.fakeDelay)
 		if blake3pow.fakeFail == header.NumberU64() {
 			return consensus.ErrInvalidPoW
@@ -601,7 +601,11 @@ func (blake3pow *Blake3pow) Finalize(chain consensus.ChainHeaderReader, batch et
 	var multiSet *multiset.MultiSet
 	if chain.IsGenesisHash(header.ParentHash(nodeCtx)) {
 		multiSet = multiset.New()
-		alloc := core.ReadGenesisAlloc("genallocs/gen_alloc_quai_"+nodeLocation.Name()+".json", blake3pow.logger)
+		allocDir := blake3pow.config.GenesisAllocDir
+		if allocDir == "" {
+			allocDir = core.DefaultGenesisAllocDir
+		}
+		alloc := core.ReadGenesisAlloc(core.GenesisQuaiAllocFile(allocDir, nodeLocation), blake3pow.logger)
 		blake3pow.logger.WithField("alloc", len(alloc)).Info("Allocating genesis accounts")
 
 		for addressString, account := range alloc {
@@ -624,7 +628,7 @@ func (blake3pow *Blake3pow) Finalize(chain consensus.ChainHeaderReader, batch et
 			}
 		}
 		addressOutpointMap := make(map[[20]byte][]*types.OutpointAndDenomination)
-		core.AddGenesisUtxos(chain.Database(), &utxosCreate, nodeLocation, addressOutpointMap, blake3pow.logger)
+		core.AddGenesisUtxos(chain.Database(), core.GenesisQiAllocFile(allocDir, nodeLocation), &utxosCreate, nodeLocation, addressOutpointMap, blake3pow.logger)
 		if chain.Config().IndexAddressUtxos {
 			chain.WriteAddressOutpoints(addressOutpointMap)
 			blake3pow.logger.Info("Indexed genesis utxos")
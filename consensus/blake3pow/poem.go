@@ -36,6 +36,23 @@ func (blake3pow *Blake3pow) CalcOrder(chain consensus.BlockReader, header *types
 
 	// Get entropy reduction of this header
 	intrinsicEntropy = blake3pow.IntrinsicLogEntropy(header.Hash())
+
+	// In developer mode a block is a region block as soon as the region has
+	// entropy from a zone block to account for, and a prime block as soon as
+	// the prime has entropy from a region block. Region blocks are needed to
+	// deliver the ETXs between the zones of a region, which prime blocks only
+	// confirm.
+	if blake3pow.config.PowMode == ModeDev {
+		order = common.ZONE_CTX
+		if header.ParentDeltaEntropy(common.REGION_CTX).Sign() > 0 {
+			order = common.PRIME_CTX
+		} else if header.ParentDeltaEntropy(common.ZONE_CTX).Sign() > 0 {
+			order = common.REGION_CTX
+		}
+		chain.AddToCalcOrderCache(header.Hash(), order, intrinsicEntropy)
+		return intrinsicEntropy, order, nil
+	}
+
 	target := new(big.Int).Div(common.Big2e256, header.Difficulty())
 	zoneThresholdEntropy := blake3pow.IntrinsicLogEntropy(common.BytesToHash(target.Bytes()))
 
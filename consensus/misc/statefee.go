@@ -6,10 +6,10 @@ import (
 	"github.com/dominant-strategies/go-quai/params"
 )
 
-func CalcStateLimit(parent *types.WorkObject, stateCeil uint64) uint64 {
+func CalcStateLimit(config *params.ChainConfig, parent *types.WorkObject, stateCeil uint64) uint64 {
 	// No Gas for TimeToStartTx days worth of zone blocks, this gives enough time to
 	// onboard new miners into the slice
-	if parent.NumberU64(common.ZONE_CTX) < params.TimeToStartTx {
+	if parent.NumberU64(common.ZONE_CTX) < config.StartTxNumber() {
 		return 0
 	}
 
@@ -434,7 +434,7 @@ func (progpow *Progpow) verifyHeader(chain consensus.ChainHeaderReader, header,
 		}
 		// Verify the block's gas usage and verify the base fee.
 		// Verify that the gas limit remains within allowed bounds
-		expectedGasLimit := core.CalcGasLimit(parent, progpow.config.GasCeil)
+		expectedGasLimit := core.CalcGasLimit(chain.Config(), parent, progpow.config.GasCeil)
 		if expectedGasLimit != header.GasLimit() {
 			return fmt.Errorf("invalid gasLimit: have %d, want %d",
 				header.GasLimit(), expectedGasLimit)
@@ -444,7 +444,7 @@ func (progpow *Progpow) verifyHeader(chain consensus.ChainHeaderReader, header,
 			return fmt.Errorf("invalid stateUsed: have %d, stateLimit %d", header.StateUsed(), header.StateLimit())
 		}
 		// Verify the StateLimit is correct based on the parent header.
-		expectedStateLimit := misc.CalcStateLimit(parent, params.StateCeil)
+		expectedStateLimit := misc.CalcStateLimit(chain.Config(), parent, params.StateCeil)
 		if header.StateLimit() != expectedStateLimit {
 			return fmt.Errorf("invalid StateLimit: have %d, want %d, parentStateLimit %d", expectedStateLimit, header.StateLimit(), parent.StateLimit())
 		}
@@ -659,7 +659,11 @@ func (progpow *Progpow) Finalize(chain consensus.ChainHeaderReader, batch ethdb.
 	var multiSet *multiset.MultiSet
 	if chain.IsGenesisHash(header.ParentHash(nodeCtx)) {
 		multiSet = multiset.New()
-		alloc := core.ReadGenesisAlloc("genallocs/gen_alloc_quai_"+nodeLocation.Name()+".json", progpow.logger)
+		allocDir := progpow.config.GenesisAllocDir
+		if allocDir == "" {
+			allocDir = core.DefaultGenesisAllocDir
+		}
+		alloc := core.ReadGenesisAlloc(core.GenesisQuaiAllocFile(allocDir, nodeLocation), progpow.logger)
 		progpow.logger.WithField("alloc", len(alloc)).Info("Allocating genesis accounts")
 
 		for addressString, account := range alloc {
@@ -682,7 +686,7 @@ func (progpow *Progpow) Finalize(chain consensus.ChainHeaderReader, batch ethdb.
 			}
 		}
 		addressOutpointMap := make(map[[20]byte][]*types.OutpointAndDenomination)
-		core.AddGenesisUtxos(chain.Database(), &utxosCreate, nodeLocation, addressOutpointMap, progpow.logger)
+		core.AddGenesisUtxos(chain.Database(), core.GenesisQiAllocFile(allocDir, nodeLocation), &utxosCreate, nodeLocation, addressOutpointMap, progpow.logger)
 		if chain.Config().IndexAddressUtxos {
 			chain.WriteAddressOutpoints(addressOutpointMap)
 			progpow.logger.Info("Indexed genesis utxos")
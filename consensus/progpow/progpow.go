@@ -158,6 +158,10 @@ type Config struct {
 
 	NodeLocation common.Location
 
+	// GenesisAllocDir is the directory of the genesis allocation files applied
+	// at the first block, core.DefaultGenesisAllocDir if empty
+	GenesisAllocDir string
+
 	WorkShareThreshold int
 
 	// When set, notifications sent by the remote sealer will
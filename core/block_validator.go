@@ -402,10 +402,10 @@ func (v *BlockValidator) ValidateState(block *types.WorkObject, statedb *state.S
 // CalcGasLimit computes the gas limit of the next block after parent. It aims
 // to keep the baseline gas close to the provided target, and increase it towards
 // the target if the baseline gas is lower.
-func CalcGasLimit(parent *types.WorkObject, gasCeil uint64) uint64 {
+func CalcGasLimit(config *params.ChainConfig, parent *types.WorkObject, gasCeil uint64) uint64 {
 	// No Gas for TimeToStartTx days worth of zone blocks, this gives enough time to
 	// onboard new miners into the slice
-	if parent.NumberU64(common.ZONE_CTX) < params.TimeToStartTx {
+	if parent.NumberU64(common.ZONE_CTX) < config.StartTxNumber() {
 		return 0
 	}
 
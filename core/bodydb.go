@@ -1,6 +1,7 @@
 package core
 
 import (
+	"sync/atomic"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -44,6 +45,11 @@ type BodyDb struct {
 	slicesRunning   []common.Location
 	processingState bool
 
+	// snapSyncPivot is the number of the block whose state is being snap
+	// synced, or zero if none. The blocks up to the pivot are appended
+	// without processing their state.
+	snapSyncPivot atomic.Uint64
+
 	logger *log.Logger
 }
 
@@ -71,6 +77,7 @@ func NewBodyDb(db ethdb.Database, engine consensus.Engine, hc *HeaderChain, chai
 	if nodeCtx == common.ZONE_CTX && bc.ProcessingState() {
 		bc.processor = NewStateProcessor(chainConfig, hc, engine, vmConfig, cacheConfig, txLookupLimit)
 		vm.InitializePrecompiles(chainConfig.Location)
+		bc.snapSyncPivot.Store(rawdb.ReadSnapSyncPivot(db))
 	}
 
 	return bc, nil
@@ -88,10 +95,18 @@ func (bc *BodyDb) Append(block *types.WorkObject) ([]*types.Log, []common.Unlock
 	var unlocks []common.Unlock
 	var err error
 	if nodeCtx == common.ZONE_CTX && bc.ProcessingState() {
-		// Process our block
-		logs, unlocks, err = bc.processor.Apply(batch, block)
-		if err != nil {
-			return nil, nil, err
+		if pivot := bc.snapSyncPivot.Load(); pivot != 0 {
+			// The state up to the pivot is downloaded instead of processed,
+			// and the state of the blocks after it builds on that download
+			if block.NumberU64(nodeCtx) > pivot {
+				return nil, nil, ErrSnapSyncing
+			}
+		} else {
+			// Process our block
+			logs, unlocks, err = bc.processor.Apply(batch, block)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 		rawdb.WriteTxLookupEntriesByBlock(batch, block, nodeCtx)
 	}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rlp"
+)
+
+const (
+	// exportReportInterval is the time between two progress logs while exporting
+	exportReportInterval = 8 * time.Second
+)
+
+var (
+	// ErrExportReorg is returned if the canonical chain changes while it is
+	// being exported
+	ErrExportReorg = errors.New("export failed: chain reorg during export")
+)
+
+// WriteExportedWorkObject writes a single WorkObject, including its body,
+// workshares, outbound etxs and manifest, to the export stream. Each entry in
+// the stream is an RLP byte string holding the protobuf encoding of the
+// WorkObject, which is the same encoding used to store it in the database.
+func WriteExportedWorkObject(w io.Writer, block *types.WorkObject) error {
+	protoBlock, err := block.ProtoEncode(types.BlockObject)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(protoBlock)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, data)
+}
+
+// ReadExportedWorkObject reads the next WorkObject written by
+// WriteExportedWorkObject from the stream. The location has to be the location
+// of the slice the stream was exported from. io.EOF is returned once the
+// stream is exhausted.
+func ReadExportedWorkObject(stream *rlp.Stream, location common.Location) (*types.WorkObject, error) {
+	data, err := stream.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	protoBlock := new(types.ProtoWorkObject)
+	if err := proto.Unmarshal(data, protoBlock); err != nil {
+		return nil, err
+	}
+	block := new(types.WorkObject)
+	if err := block.ProtoDecode(protoBlock, location, types.BlockObject); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// ExportWorkObjects writes the canonical WorkObjects numbered first to last
+// (inclusive) of the slice database to the given writer. It only reads from
+// the database, so it can be used both by a running node and by offline
+// tooling operating on a closed slice database.
+func ExportWorkObjects(db ethdb.Reader, w io.Writer, first uint64, last uint64, nodeCtx int, logger *log.Logger) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	logger.WithField("count", last-first+1).Info("Exporting batch of blocks")
+
+	var (
+		parentHash common.Hash
+		start      = time.Now()
+		reported   = time.Now()
+	)
+	for nr := first; nr <= last; nr++ {
+		hash := rawdb.ReadCanonicalHash(db, nr)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("export failed on #%d: canonical hash not found", nr)
+		}
+		block := rawdb.ReadWorkObject(db, nr, hash, types.BlockObject)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: block %s not found", nr, hash)
+		}
+		if nr > first && block.ParentHash(nodeCtx) != parentHash {
+			return ErrExportReorg
+		}
+		parentHash = block.Hash()
+		if err := WriteExportedWorkObject(w, block); err != nil {
+			return err
+		}
+		if time.Since(reported) >= exportReportInterval {
+			logger.WithFields(log.Fields{
+				"exported": nr - first + 1,
+				"elapsed":  common.PrettyDuration(time.Since(start)),
+			}).Info("Exporting blocks")
+			reported = time.Now()
+		}
+	}
+	logger.WithFields(log.Fields{
+		"exported": last - first + 1,
+		"elapsed":  common.PrettyDuration(time.Since(start)),
+	}).Info("Exported blocks")
+	return nil
+}
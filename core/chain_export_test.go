@@ -0,0 +1,160 @@
+package core_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/rlp"
+	"github.com/dominant-strategies/go-quai/trie"
+)
+
+// readExported reads all the blocks of an export stream of the slice.
+func readExported(t *testing.T, r io.Reader, location common.Location) []*types.WorkObject {
+	stream := rlp.NewStream(r, 0)
+	var blocks []*types.WorkObject
+	for {
+		block, err := core.ReadExportedWorkObject(stream, location)
+		if err == io.EOF {
+			return blocks
+		}
+		require.NoError(t, err)
+		blocks = append(blocks, block)
+	}
+}
+
+// importExported writes the blocks read from an export stream to the database
+// as its canonical chain.
+func importExported(db ethdb.Database, blocks []*types.WorkObject, nodeCtx int) {
+	for _, block := range blocks {
+		rawdb.WriteWorkObject(db, block.Hash(), block, types.BlockObject, nodeCtx)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64(nodeCtx))
+	}
+}
+
+// TestExportImportChain verifies the blocks of every slice exported and read
+// back keep their bodies, ETXs and manifests, and that a chain imported from
+// them exports to the same stream, compressed or not.
+func TestExportImportChain(t *testing.T) {
+	from, to := common.Location{0, 0}, common.Location{0, 1}
+	accounts := chaingen.Accounts(from, false, 3)
+	sender, recipient := accounts[1], accounts[2]
+	remote := chaingen.Accounts(to, false, 2)[1]
+	g, err := chaingen.New(chaingen.Config{
+		ExpansionNumber: 1,
+		QuaiAlloc:       map[common.Address]*big.Int{sender.Address: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))},
+	})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	_, err = g.GenerateBlocks(from, 5, func(i int, b *chaingen.BlockGen) {
+		if i == 0 {
+			return
+		}
+		_, err := b.Transfer(sender, recipient.Address, big.NewInt(params.Ether))
+		require.NoError(t, err)
+		_, err = b.AddEtx(sender, remote.Address, big.NewInt(params.Ether))
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	for _, location := range []common.Location{{}, {0}, from} {
+		nodeCtx := location.Context()
+		db := g.Database(location)
+		last := g.Head(location).NumberU64(nodeCtx)
+		require.Greater(t, last, uint64(0), "no block in %s", location.Name())
+
+		var exported bytes.Buffer
+		require.NoError(t, core.ExportWorkObjects(db, &exported, 1, last, nodeCtx, log.Global))
+		blocks := readExported(t, bytes.NewReader(exported.Bytes()), location)
+		require.Len(t, blocks, int(last))
+
+		var txs, etxs, manifests int
+		for i, block := range blocks {
+			number := uint64(i + 1)
+			stored := rawdb.ReadWorkObject(db, number, rawdb.ReadCanonicalHash(db, number), types.BlockObject)
+			require.NotNil(t, stored)
+			require.Equal(t, stored.Hash(), block.Hash())
+			require.Equal(t, types.DeriveSha(stored.Transactions(), trie.NewStackTrie(nil)), types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)))
+			require.Equal(t, types.DeriveSha(stored.OutboundEtxs(), trie.NewStackTrie(nil)), types.DeriveSha(block.OutboundEtxs(), trie.NewStackTrie(nil)))
+			require.Equal(t, stored.Manifest(), block.Manifest())
+			txs += len(block.Transactions())
+			etxs += len(block.OutboundEtxs())
+			manifests += len(block.Manifest())
+		}
+		if nodeCtx == common.ZONE_CTX {
+			require.NotZero(t, txs, "no transaction exported")
+			require.NotZero(t, etxs, "no ETX exported")
+		} else {
+			require.NotZero(t, manifests, "no manifest exported from %s", location.Name())
+		}
+
+		// A chain imported from the stream exports the same stream
+		imported := rawdb.NewMemoryDatabase(log.Global)
+		importExported(imported, blocks, nodeCtx)
+		var reexported bytes.Buffer
+		require.NoError(t, core.ExportWorkObjects(imported, &reexported, 1, last, nodeCtx, log.Global))
+		require.Equal(t, exported.Bytes(), reexported.Bytes())
+
+		// The same stream compressed to a file reads back the same blocks
+		fn := filepath.Join(t.TempDir(), location.Name()+".rlp.gz")
+		out, err := os.Create(fn)
+		require.NoError(t, err)
+		writer := gzip.NewWriter(out)
+		require.NoError(t, core.ExportWorkObjects(imported, writer, 1, last, nodeCtx, log.Global))
+		require.NoError(t, writer.Close())
+		require.NoError(t, out.Close())
+
+		in, err := os.Open(fn)
+		require.NoError(t, err)
+		reader, err := gzip.NewReader(in)
+		require.NoError(t, err)
+		decompressed := readExported(t, reader, location)
+		reader.Close()
+		in.Close()
+		require.Len(t, decompressed, len(blocks))
+		for i, block := range decompressed {
+			require.Equal(t, blocks[i].Hash(), block.Hash())
+			require.Equal(t, types.DeriveSha(blocks[i].Transactions(), trie.NewStackTrie(nil)), types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)))
+		}
+	}
+}
+
+// TestExportChainReorg verifies the export fails if the canonical chain it
+// walks does not link, as after a reorg during the export.
+func TestExportChainReorg(t *testing.T) {
+	location := common.Location{0, 0}
+	g, err := chaingen.New(chaingen.Config{})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	blocks, err := g.GenerateBlocks(location, 4, nil)
+	require.NoError(t, err)
+	fork, err := g.GenerateBlocksFrom(blocks[0], 1, nil)
+	require.NoError(t, err)
+
+	db := rawdb.NewMemoryDatabase(log.Global)
+	importExported(db, blocks, common.ZONE_CTX)
+	first, last := blocks[0].NumberU64(common.ZONE_CTX), blocks[3].NumberU64(common.ZONE_CTX)
+	require.NoError(t, core.ExportWorkObjects(db, io.Discard, first, last, common.ZONE_CTX, log.Global))
+
+	// The fork block replaces the second block in the canonical chain, which
+	// the third block does not descend from
+	importExported(db, fork, common.ZONE_CTX)
+	require.ErrorIs(t, core.ExportWorkObjects(db, io.Discard, first, last, common.ZONE_CTX, log.Global), core.ErrExportReorg)
+	require.NoError(t, core.ExportWorkObjects(db, io.Discard, first, first+1, common.ZONE_CTX, log.Global))
+}
@@ -71,6 +71,8 @@ type ChainIndexerChain interface {
 	NodeCtx() int
 	// StateAt returns the state for a state trie root and utxo root
 	StateAt(root common.Hash, etxRoot common.Hash, quaiStateSize *big.Int) (*state.StateDB, error)
+	// WorkShareDistance returns the distance of a workshare to the block including it
+	WorkShareDistance(wo *types.WorkObject, ws *types.WorkObjectHeader) (*big.Int, error)
 }
 
 // ChainIndexer does a post-processing job for equally sized sections of the
@@ -108,12 +110,14 @@ type ChainIndexer struct {
 	lock              sync.Mutex
 	pruneLock         sync.Mutex
 	indexAddressUtxos bool
+	indexWorkShares   bool
+	WorkShareDistance workShareDistanceFunc
 }
 
 // NewChainIndexer creates a new chain indexer to do background processing on
 // chain segments of a given size after certain number of confirmations passed.
 // The throttling parameter might be used to prevent database thrashing.
-func NewChainIndexer(chainDb ethdb.Database, indexDb ethdb.Database, backend ChainIndexerBackend, section, confirm uint64, throttling time.Duration, kind string, nodeCtx int, logger *log.Logger, indexAddressUtxos bool) *ChainIndexer {
+func NewChainIndexer(chainDb ethdb.Database, indexDb ethdb.Database, backend ChainIndexerBackend, section, confirm uint64, throttling time.Duration, kind string, nodeCtx int, logger *log.Logger, indexAddressUtxos bool, indexWorkShares bool) *ChainIndexer {
 	c := &ChainIndexer{
 		chainDb:           chainDb,
 		indexDb:           indexDb,
@@ -125,6 +129,7 @@ func NewChainIndexer(chainDb ethdb.Database, indexDb ethdb.Database, backend Cha
 		throttling:        throttling,
 		logger:            logger,
 		indexAddressUtxos: indexAddressUtxos,
+		indexWorkShares:   indexWorkShares,
 	}
 	// Initialize database dependent fields and start the updater
 	c.loadValidSections()
@@ -167,6 +172,7 @@ func (c *ChainIndexer) Start(chain ChainIndexerChain, config params.ChainConfig)
 	sub := chain.SubscribeChainHeadEvent(events)
 	c.GetBloom = chain.GetBloom
 	c.StateAt = chain.StateAt
+	c.WorkShareDistance = chain.WorkShareDistance
 	go c.eventLoop(chain.CurrentHeader(), events, sub, chain.NodeCtx(), config)
 }
 
@@ -371,6 +377,15 @@ func (c *ChainIndexer) indexerLoop(currentHeader *types.WorkObject, qiIndexerCh
 			time2 := time.Since(start)
 
 			var time3, time4, time5 time.Duration
+			if block.ParentHash(nodeCtx) != prevHash && c.GetHeaderByHash(prevHash) == nil {
+				// The previous head was deleted by a rewind of the slice, which
+				// already reverted the outpoints and workshare rewards of the
+				// deleted blocks
+				c.logger.WithField("block", block.NumberU64(nodeCtx)).Warn("ChainIndexer: Previous head was rewound, resuming from the new head")
+				c.newHead(block.NumberU64(nodeCtx), true)
+				prevHeader, prevHash = block, block.Hash()
+				continue
+			}
 			if block.ParentHash(nodeCtx) != prevHash {
 				// Reorg to the common ancestor if needed (might not exist in light sync mode, skip reorg then)
 				// TODO: This seems a bit brittle, can we detect this case explicitly?
@@ -379,9 +394,10 @@ func (c *ChainIndexer) indexerLoop(currentHeader *types.WorkObject, qiIndexerCh
 					c.logger.WithField("err", err).Error("ChainIndexer: Failed to index: failed to find common ancestor")
 					continue
 				}
-				// If indexAddressUtxos flag is enabled, update the address utxo map
+				// If indexAddressUtxos or indexWorkShares flag is enabled, update the address utxo map
+				// and the workshare index
 				// TODO: Need to be able to turn on/off indexer and fix corrupted state
-				if c.indexAddressUtxos {
+				if c.indexAddressUtxos || c.indexWorkShares {
 					// Delete each header and rollback state processor until common header
 					// Accumulate the hash slice stack
 					var hashStack []*types.WorkObject
@@ -424,9 +440,17 @@ func (c *ChainIndexer) indexerLoop(currentHeader *types.WorkObject, qiIndexerCh
 					time3 = time.Since(start)
 
 					// Remove all outpoints of the reorg headers (old chain)
-					err := c.reorgUtxoIndexer(prevHashStack, nodeCtx)
-					if err != nil {
-						c.logger.Error("ChainIndexer: Failed to reorg utxo indexer", "err", err)
+					if c.indexAddressUtxos {
+						err := c.reorgUtxoIndexer(prevHashStack, nodeCtx)
+						if err != nil {
+							c.logger.Error("ChainIndexer: Failed to reorg utxo indexer", "err", err)
+						}
+					}
+					// Remove the workshare rewards paid by the old chain
+					if c.indexWorkShares {
+						if err := unindexWorkShares(c.chainDb, prevHashStack, nodeCtx, c.logger); err != nil {
+							c.logger.WithField("err", err).Error("ChainIndexer: Failed to reorg workshare index")
+						}
 					}
 
 					time4 = time.Since(start)
@@ -438,7 +462,12 @@ func (c *ChainIndexer) indexerLoop(currentHeader *types.WorkObject, qiIndexerCh
 							c.logger.Error("ChainIndexer: Failed to read block during reorg")
 							continue
 						}
-						c.addOutpointsToIndexer(nodeCtx, config, block)
+						if c.indexAddressUtxos {
+							c.addOutpointsToIndexer(nodeCtx, config, block)
+						}
+						if c.indexWorkShares {
+							c.addWorkSharesToIndexer(block)
+						}
 					}
 				}
 
@@ -450,6 +479,9 @@ func (c *ChainIndexer) indexerLoop(currentHeader *types.WorkObject, qiIndexerCh
 				if c.indexAddressUtxos {
 					c.addOutpointsToIndexer(nodeCtx, config, block)
 				}
+				if c.indexWorkShares {
+					c.addWorkSharesToIndexer(block)
+				}
 				time4 = time.Since(start)
 				c.newHead(block.NumberU64(nodeCtx), false)
 				time5 = time.Since(start)
@@ -1018,16 +1050,34 @@ func (c *ChainIndexer) addOutpointsToIndexer(nodeCtx int, config params.ChainCon
 	}
 }
 
+// addWorkSharesToIndexer indexes the rewards the block pays to its coinbases
+// and to the coinbases of the workshares it includes.
+func (c *ChainIndexer) addWorkSharesToIndexer(block *types.WorkObject) {
+	if err := indexWorkShares(c.chainDb, block, c.WorkShareDistance); err != nil {
+		c.logger.WithFields(log.Fields{
+			"block": block.NumberU64(common.ZONE_CTX),
+			"hash":  block.Hash(),
+			"err":   err,
+		}).Error("ChainIndexer: Failed to index workshares")
+	}
+}
+
 // reorgUtxoIndexer adds back previously removed outpoints and removes newly added outpoints.
 // This is done in reverse order from the old header to the common ancestor.
 func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int) error {
+	return reorgUtxoIndex(c.chainDb, headers, nodeCtx, c.logger)
+}
 
+// reorgUtxoIndex reverts the address outpoints and lockups indexed for the
+// given blocks, which have to be ordered from the newest to the oldest and
+// still be canonical along with their spent utxos.
+func reorgUtxoIndex(db ethdb.Database, headers []*types.WorkObject, nodeCtx int, logger *log.Logger) error {
 	for _, header := range headers {
 		addressOutpoints := make(map[[20]byte][]*types.OutpointAndDenomination)
 		addressLockups := make(map[[20]byte][]*types.Lockup)
-		block := rawdb.ReadWorkObject(c.chainDb, header.NumberU64(nodeCtx), header.Hash(), types.BlockObject)
+		block := rawdb.ReadWorkObject(db, header.NumberU64(nodeCtx), header.Hash(), types.BlockObject)
 		if block == nil {
-			c.logger.Errorf("ChainIndexer: Error reading block during reorg hash: %s", block.Hash().String())
+			logger.Errorf("ChainIndexer: Error reading block during reorg hash: %s", header.Hash().String())
 			continue
 		}
 		for _, tx := range block.QiTransactions() {
@@ -1045,7 +1095,7 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 		for _, etx := range block.Body().ExternalTransactions() {
 			if etx.EtxType() == types.CoinbaseType && etx.To().IsInQuaiLedgerScope() {
 				if len(etx.Data()) == 0 {
-					c.logger.Error("ChainIndexer: Coinbase transaction has no data", "tx", etx.Hash())
+					logger.Error("ChainIndexer: Coinbase transaction has no data", "tx", etx.Hash())
 					continue
 				}
 				coinbaseAddr := etx.To().Bytes20()
@@ -1059,7 +1109,7 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 				addressLockups[coinbaseAddr] = make([]*types.Lockup, 0)
 			} else if etx.EtxType() == types.CoinbaseType && etx.To().IsInQiLedgerScope() {
 				if len(etx.Data()) == 0 {
-					c.logger.Error("ChainIndexer: Coinbase transaction has no data", "tx", etx.Hash())
+					logger.Error("ChainIndexer: Coinbase transaction has no data", "tx", etx.Hash())
 					continue
 				}
 				coinbaseAddr := etx.To().Bytes20()
@@ -1074,11 +1124,11 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 			}
 		}
 		// Re-create spent UTXOs (inputs)
-		sutxos, err := rawdb.ReadSpentUTXOs(c.chainDb, block.Hash())
+		sutxos, err := rawdb.ReadSpentUTXOs(db, block.Hash())
 		if err != nil {
 			return err
 		}
-		trimmedUtxos, err := rawdb.ReadTrimmedUTXOs(c.chainDb, block.Hash())
+		trimmedUtxos, err := rawdb.ReadTrimmedUTXOs(db, block.Hash())
 		if err != nil {
 			return err
 		}
@@ -1091,7 +1141,7 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 				Denomination: sutxo.Denomination,
 				Lock:         sutxo.Lock,
 			}
-			height := rawdb.ReadUtxoToBlockHeight(c.chainDb, sutxo.TxHash, sutxo.Index)
+			height := rawdb.ReadUtxoToBlockHeight(db, sutxo.TxHash, sutxo.Index)
 			addr20 := [20]byte(sutxo.Address)
 			binary.BigEndian.PutUint32(addr20[16:], height)
 			addressOutpoints[addr20] = append(addressOutpoints[addr20], outpointAndDenom)
@@ -1114,15 +1164,15 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 			targetBlockHeight := block.NumberU64(nodeCtx) - blockDepth
 
 			// Fetch the block at the calculated target height
-			targetBlock := c.GetBlockByNumber(targetBlockHeight)
+			targetBlock := rawdb.ReadWorkObject(db, targetBlockHeight, rawdb.ReadCanonicalHash(db, targetBlockHeight), types.BlockObject)
 			if targetBlock == nil {
-				c.logger.Errorf("ChainIndexer: Unable to process block depth %d block at height %d not found", blockDepth, targetBlockHeight)
+				logger.Errorf("ChainIndexer: Unable to process block depth %d block at height %d not found", blockDepth, targetBlockHeight)
 				continue
 			}
 			for _, etx := range targetBlock.Body().ExternalTransactions() {
 				if etx.EtxType() == types.CoinbaseType && etx.To().IsInQuaiLedgerScope() {
 					if len(etx.Data()) == 0 {
-						c.logger.Error("ChainIndexer: Coinbase transaction has no data", "tx", etx.Hash())
+						logger.Error("ChainIndexer: Coinbase transaction has no data", "tx", etx.Hash())
 						continue
 					}
 					lockupByte := etx.Data()[0]
@@ -1131,9 +1181,9 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 					coinbaseAddr := etx.To().Bytes20()
 					binary.BigEndian.PutUint32(coinbaseAddr[16:], uint32(targetBlockHeight))
 					if _, exists := addressLockups[coinbaseAddr]; !exists {
-						lockups, err := rawdb.ReadLockupsForAddressAtBlock(c.chainDb, coinbaseAddr)
+						lockups, err := rawdb.ReadLockupsForAddressAtBlock(db, coinbaseAddr)
 						if err != nil {
-							c.logger.Errorf("ChainIndexer: Error reading lockups for address: %v", err)
+							logger.Errorf("ChainIndexer: Error reading lockups for address: %v", err)
 							continue
 						}
 						addressLockups[coinbaseAddr] = lockups
@@ -1143,9 +1193,9 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 					addr20 := etx.To().Bytes20()
 					binary.BigEndian.PutUint32(addr20[16:], uint32(targetBlockHeight))
 					if _, exists := addressLockups[addr20]; !exists {
-						lockups, err := rawdb.ReadLockupsForAddressAtBlock(c.chainDb, addr20)
+						lockups, err := rawdb.ReadLockupsForAddressAtBlock(db, addr20)
 						if err != nil {
-							c.logger.Errorf("ChainIndexer: Error reading lockups for address: %v", err)
+							logger.Errorf("ChainIndexer: Error reading lockups for address: %v", err)
 							continue
 						}
 						addressLockups[addr20] = lockups
@@ -1154,11 +1204,11 @@ func (c *ChainIndexer) reorgUtxoIndexer(headers []*types.WorkObject, nodeCtx int
 				}
 			}
 		}
-		err = rawdb.WriteAddressOutpoints(c.chainDb, addressOutpoints)
+		err = rawdb.WriteAddressOutpoints(db, addressOutpoints)
 		if err != nil {
 			panic(err)
 		}
-		err = rawdb.WriteAddressLockups(c.chainDb, addressLockups)
+		err = rawdb.WriteAddressLockups(db, addressLockups)
 		if err != nil {
 			panic(err)
 		}
@@ -0,0 +1,692 @@
+// Package chaingen generates chains across the prime, region and zone
+// contexts of a hierarchy for tests. The blocks are built by the workers of
+// in-memory slices, sealed with the developer mode of blake3pow and appended
+// the way mined blocks are, so the databases it produces hold valid termini,
+// manifests, ETX rollups and coinbase UTXOs, and can be opened by NewCore.
+package chaingen
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus/blake3pow"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/core/vm"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/ethdb/memorydb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/trie"
+)
+
+const (
+	c_pendingHeaderPollInterval = 10 * time.Millisecond
+	c_pendingHeaderTimeout      = 10 * time.Second
+	c_transferGas               = 100000
+)
+
+var (
+	// transferGasPrice is the gas price of the transactions signed by BlockGen
+	transferGasPrice = big.NewInt(1e16)
+
+	errPendingHeaderTimeout = errors.New("timed out waiting for the genesis pending headers")
+)
+
+// Config is the configuration of a Generator.
+type Config struct {
+	// ExpansionNumber sets the number of regions and zones of the hierarchy
+	ExpansionNumber uint8
+	// QuaiAlloc funds Quai ledger accounts of any zone at its first block
+	QuaiAlloc map[common.Address]*big.Int
+	// QiAlloc gives a UTXO of the denomination to Qi ledger accounts of any
+	// zone at its first block, spendable at GenesisOutPoint
+	QiAlloc map[common.Address]uint8
+	// MinerPreference is the preference of the coinbases for the Qi ledger, 0
+	// pays every block to the Quai coinbase and 1 to the Qi coinbase
+	MinerPreference float64
+	// Freezer, if set, is the directory of the ancient stores of the zone
+	// databases, which move the blocks deeper than FreezerThreshold to it
+	Freezer          string
+	FreezerThreshold uint64
+	// Logger defaults to the global logger
+	Logger *log.Logger
+}
+
+// Account is a deterministic account of a zone ledger.
+type Account struct {
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// Accounts returns n accounts of a ledger of the zone. The keys are derived
+// from a fixed seed, so the accounts are the same on every run. The first
+// account of each ledger is the coinbase of the blocks of the zone.
+func Accounts(location common.Location, qi bool, n int) []Account {
+	return SeededAccounts("go-quai chaingen", location, qi, n)
+}
+
+// SeededAccounts returns n accounts of a ledger of the zone, whose keys are
+// derived from the seed, the ledger and the zone.
+func SeededAccounts(seedPrefix string, location common.Location, qi bool, n int) []Account {
+	ledger := "quai"
+	if qi {
+		ledger = "qi"
+	}
+	accounts := make([]Account, 0, n)
+	seed := crypto.Keccak256([]byte(seedPrefix + " " + ledger + " " + location.Name()))
+	for len(accounts) < n {
+		seed = crypto.Keccak256(seed)
+		key, err := crypto.ToECDSA(seed)
+		if err != nil {
+			continue
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey, location)
+		if !common.IsInChainScope(address.Bytes(), location) || address.IsInQiLedgerScope() != qi {
+			continue
+		}
+		accounts = append(accounts, Account{Key: key, Address: address})
+	}
+	return accounts
+}
+
+// GenesisOutPoint returns the outpoint of the UTXO given to address by
+// Config.QiAlloc.
+func GenesisOutPoint(address common.Address) types.OutPoint {
+	return types.OutPoint{TxHash: crypto.Keccak256Hash(address.Bytes()), Index: 0}
+}
+
+// slice is a chain of the hierarchy run by the generator.
+type slice struct {
+	db   ethdb.Database
+	core *core.Core
+	head *types.WorkObject
+}
+
+// backend wires the cores of the hierarchy to each other, the way the
+// consensus backends of a node do.
+type backend struct {
+	*core.Core
+	genesisHash common.Hash
+}
+
+func (b backend) NewGenesisPendingHeader(pendingHeader *types.WorkObject, domTerminus common.Hash, hash common.Hash) error {
+	return b.Core.NewGenesisPendigHeader(pendingHeader, domTerminus, hash)
+}
+
+// GetPrimeBlock serves the genesis block as the parent of the genesis block.
+// On a network the zones other than the first one are started by an expansion
+// of the tree, and take their expansion number from the prime block before
+// their prime terminus. The generator starts every zone from the genesis
+// block, so the genesis block stands in for that block.
+func (b backend) GetPrimeBlock(hash common.Hash) *types.WorkObject {
+	if hash == (common.Hash{}) {
+		hash = b.genesisHash
+	}
+	return b.Core.GetPrimeBlock(hash)
+}
+
+// Generator generates blocks on a hierarchy of in-memory chains.
+type Generator struct {
+	config        Config
+	logger        *log.Logger
+	genesis       *core.Genesis
+	genesisHash   common.Hash
+	allocDir      string
+	slicesRunning []common.Location
+	slices        map[string]*slice
+	// time is the time of the last generated block
+	time uint64
+}
+
+// New creates the chains of every context of the hierarchy of the expansion
+// number, from the local genesis block, and wires them together.
+func New(config Config) (*Generator, error) {
+	logger := config.Logger
+	if logger == nil {
+		logger = log.Global
+	}
+	g := &Generator{
+		config:  config,
+		logger:  logger,
+		genesis: core.DefaultLocalGenesisBlock("blake3", 0),
+		slices:  make(map[string]*slice),
+	}
+	g.time = g.genesis.Timestamp
+	numRegions, numZones := common.GetHierarchySizeForExpansionNumber(config.ExpansionNumber)
+	for i := 0; i < int(numRegions); i++ {
+		for j := 0; j < int(numZones); j++ {
+			g.slicesRunning = append(g.slicesRunning, common.Location{byte(i), byte(j)})
+		}
+	}
+
+	allocDir, err := os.MkdirTemp("", "chaingen")
+	if err != nil {
+		return nil, err
+	}
+	g.allocDir = allocDir
+	if err := g.writeAllocs(); err != nil {
+		os.RemoveAll(allocDir)
+		return nil, err
+	}
+
+	locations := []common.Location{{}}
+	for i := 0; i < int(numRegions); i++ {
+		locations = append(locations, common.Location{byte(i)})
+	}
+	locations = append(locations, g.slicesRunning...)
+	for _, location := range locations {
+		db, err := g.newDatabase(location)
+		if err != nil {
+			g.Stop()
+			return nil, err
+		}
+		_, hash, err := core.SetupGenesisBlockWithOverride(db, g.genesis, 0, location, uint64(config.ExpansionNumber), logger)
+		if err != nil {
+			g.Stop()
+			return nil, err
+		}
+		g.genesisHash = hash
+		c, err := g.newCore(db, location)
+		if err != nil {
+			g.Stop()
+			return nil, err
+		}
+		g.slices[location.Name()] = &slice{db: db, core: c, head: c.CurrentHeader()}
+	}
+
+	prime := g.slice(common.Location{})
+	for i := 0; i < int(numRegions); i++ {
+		region := g.slice(common.Location{byte(i)})
+		for j := 0; j < int(numZones); j++ {
+			zone := g.slice(common.Location{byte(i), byte(j)})
+			region.core.SetSubInterface(backend{zone.core, g.genesisHash}, common.Location{byte(i), byte(j)})
+			zone.core.SetDomInterface(backend{region.core, g.genesisHash})
+		}
+		prime.core.SetSubInterface(backend{region.core, g.genesisHash}, common.Location{byte(i)})
+		region.core.SetDomInterface(backend{prime.core, g.genesisHash})
+	}
+	// Prime starts handing the genesis pending headers down as soon as it has
+	// a sub, which may be before the whole hierarchy is wired, so they are
+	// handed down again
+	if err := prime.core.NewGenesisPendigHeader(nil, g.genesisHash, g.genesisHash); err != nil {
+		g.Stop()
+		return nil, err
+	}
+	if err := g.waitGenesisPendingHeaders(); err != nil {
+		g.Stop()
+		return nil, err
+	}
+	return g, nil
+}
+
+// newDatabase creates the in-memory database of the chain at location, with an
+// ancient store in the freezer directory for the zones if there is one.
+func (g *Generator) newDatabase(location common.Location) (ethdb.Database, error) {
+	if g.config.Freezer == "" || location.Context() != common.ZONE_CTX {
+		return rawdb.NewMemoryDatabase(g.logger), nil
+	}
+	freezer := filepath.Join(g.config.Freezer, location.Name())
+	return rawdb.NewDatabaseWithFreezer(memorydb.New(g.logger), freezer, "", false, g.config.FreezerThreshold, common.ZONE_CTX, g.logger, location)
+}
+
+// newCore creates the core of the chain at location on db.
+func (g *Generator) newCore(db ethdb.Database, location common.Location) (*core.Core, error) {
+	chainConfig := *g.genesis.Config
+	chainConfig.Location = location
+	chainConfig.DefaultGenesisHash = g.genesisHash
+	// Transactions are only given gas once the miners had time to join, which
+	// generated chains have no reason to wait for
+	timeToStartTx := uint64(0)
+	chainConfig.TimeToStartTx = &timeToStartTx
+
+	engine := blake3pow.New(blake3pow.Config{
+		PowMode:            blake3pow.ModeDev,
+		GenesisAllocDir:    g.allocDir,
+		DurationLimit:      params.LocalDurationLimit,
+		NodeLocation:       location,
+		GasCeil:            params.LocalGasCeil,
+		MinDifficulty:      new(big.Int).Div(g.genesis.Difficulty, common.Big2),
+		WorkShareThreshold: params.WorkSharesThresholdDiff,
+	}, nil, false, g.logger)
+	engine.SetThreads(-1)
+
+	// The default extra data holds the version of the node and of Go, which
+	// would change the generated blocks between builds
+	minerConfig := core.Config{ExtraData: []byte("chaingen")}
+	if location.Context() == common.ZONE_CTX {
+		minerConfig.QuaiCoinbase = Accounts(location, false, 1)[0].Address
+		minerConfig.QiCoinbase = Accounts(location, true, 1)[0].Address
+		minerConfig.MinerPreference = g.config.MinerPreference
+		minerConfig.GasCeil = params.LocalGasCeil
+		minerConfig.GasPrice = big.NewInt(params.GWei)
+		minerConfig.WorkShareThreshold = params.WorkSharesThresholdDiff
+	}
+	txPoolConfig := core.DefaultTxPoolConfig
+	txPoolConfig.Journal = ""
+	txLookupLimit := uint64(0)
+	isLocalBlock := func(*types.WorkObject) bool { return false }
+
+	return core.NewCore(db, &minerConfig, isLocalBlock, &txPoolConfig, &txLookupLimit, &chainConfig, g.slicesRunning, g.config.ExpansionNumber, nil, engine, nil, vm.Config{}, g.genesis, g.logger)
+}
+
+// writeAllocs writes the genesis allocation files of every zone.
+func (g *Generator) writeAllocs() error {
+	for _, location := range g.slicesRunning {
+		quaiAlloc := make(map[string]core.GenesisAccount)
+		for address, balance := range g.config.QuaiAlloc {
+			if common.IsInChainScope(address.Bytes(), location) {
+				quaiAlloc[address.Hex()] = core.GenesisAccount{Balance: balance}
+			}
+		}
+		if err := writeAlloc(core.GenesisQuaiAllocFile(g.allocDir, location), quaiAlloc); err != nil {
+			return err
+		}
+		qiAlloc := make(map[string]core.GenesisUTXO)
+		for address, denomination := range g.config.QiAlloc {
+			if common.IsInChainScope(address.Bytes(), location) {
+				outpoint := GenesisOutPoint(address)
+				qiAlloc[address.Hex()] = core.GenesisUTXO{
+					Denomination: uint32(denomination),
+					Index:        uint32(outpoint.Index),
+					Hash:         outpoint.TxHash.Hex(),
+				}
+			}
+		}
+		if err := writeAlloc(core.GenesisQiAllocFile(g.allocDir, location), qiAlloc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeAlloc(filename string, alloc interface{}) error {
+	data, err := json.Marshal(alloc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+// waitGenesisPendingHeaders waits for prime to hand the pending headers of the
+// genesis block down to every zone.
+func (g *Generator) waitGenesisPendingHeaders() error {
+	timeout := time.After(c_pendingHeaderTimeout)
+	for _, location := range g.slicesRunning {
+		for g.slice(location).core.Slice().ReadBestPh() == nil {
+			select {
+			case <-time.After(c_pendingHeaderPollInterval):
+			case <-timeout:
+				return errPendingHeaderTimeout
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Generator) slice(location common.Location) *slice {
+	return g.slices[location.Name()]
+}
+
+// Locations returns the zones of the hierarchy.
+func (g *Generator) Locations() []common.Location {
+	return g.slicesRunning
+}
+
+// Core returns the core of the chain at location, which may be a prime,
+// region or zone location.
+func (g *Generator) Core(location common.Location) *core.Core {
+	if s := g.slice(location); s != nil {
+		return s.core
+	}
+	return nil
+}
+
+// Database returns the database of the chain at location.
+func (g *Generator) Database(location common.Location) ethdb.Database {
+	if s := g.slice(location); s != nil {
+		return s.db
+	}
+	return nil
+}
+
+// Head returns the last block generated in the chain at location.
+func (g *Generator) Head(location common.Location) *types.WorkObject {
+	if s := g.slice(location); s != nil {
+		return s.head
+	}
+	return nil
+}
+
+// Open creates a new core on the database of the chain at location, which has
+// to be stopped by the caller. The generator should be stopped first, so that
+// the chain is not run by two cores.
+func (g *Generator) Open(location common.Location) (*core.Core, error) {
+	s := g.slice(location)
+	if s == nil {
+		return nil, fmt.Errorf("location %s is not generated", location.Name())
+	}
+	return g.newCore(s.db, location)
+}
+
+// Stop stops the cores of the generator, leaving their databases open.
+func (g *Generator) Stop() {
+	for _, s := range g.slices {
+		s.core.Stop()
+	}
+	os.RemoveAll(g.allocDir)
+}
+
+// BlockGen builds a zone block in the callback of GenerateBlocks.
+type BlockGen struct {
+	i      int
+	parent *types.WorkObject
+	config *params.ChainConfig
+	pool   *core.TxPool
+	txs    []*types.Transaction
+}
+
+// Index returns the index of the block among the generated blocks.
+func (b *BlockGen) Index() int {
+	return b.i
+}
+
+// Parent returns the zone parent of the block.
+func (b *BlockGen) Parent() *types.WorkObject {
+	return b.parent
+}
+
+// Number returns the zone number of the block.
+func (b *BlockGen) Number() uint64 {
+	return b.parent.NumberU64(common.ZONE_CTX) + 1
+}
+
+// Nonce returns the next nonce of the Quai account, counting the transactions
+// already added to the block.
+func (b *BlockGen) Nonce(address common.Address) uint64 {
+	internal, err := address.InternalAndQuaiAddress()
+	if err != nil {
+		return 0
+	}
+	// The pool promotes the added transactions asynchronously, so its pending
+	// nonce may not count them yet
+	nonce := b.pool.Nonce(internal)
+	signer := types.LatestSigner(b.config)
+	for _, tx := range b.txs {
+		if tx.Type() != types.QuaiTxType || tx.Nonce() < nonce {
+			continue
+		}
+		if from, err := types.Sender(signer, tx); err == nil && from.Equal(address) {
+			nonce = tx.Nonce() + 1
+		}
+	}
+	return nonce
+}
+
+// AddTx adds a Quai or Qi transaction to the block. Transactions sending value
+// to another zone emit the ETXs of the block. The transaction is validated
+// like a transaction sent to the node, and has to be included in the block.
+func (b *BlockGen) AddTx(tx *types.Transaction) error {
+	if err := b.pool.AddLocal(tx); err != nil {
+		return err
+	}
+	b.txs = append(b.txs, tx)
+	return nil
+}
+
+// Transfer adds a Quai transaction sending value from the account to the
+// address, and returns it. The address is read in the zone of the block, to
+// which it is external if it belongs to another zone.
+func (b *BlockGen) Transfer(from Account, to common.Address, value *big.Int) (*types.Transaction, error) {
+	to = common.BytesToAddress(to.Bytes(), b.config.Location)
+	tx, err := types.SignTx(types.NewTx(&types.QuaiTx{
+		ChainID:  b.config.ChainID,
+		Nonce:    b.Nonce(from.Address),
+		MinerTip: common.Big0,
+		GasPrice: transferGasPrice,
+		Gas:      c_transferGas,
+		To:       &to,
+		Value:    value,
+	}), types.LatestSigner(b.config), from.Key)
+	if err != nil {
+		return nil, err
+	}
+	return tx, b.AddTx(tx)
+}
+
+// AddEtx adds an ETX paying value to the address in another zone, and returns
+// the transaction emitting it. ETXs are only emitted by transactions, so it is
+// a Quai transfer from the account of the zone of the block.
+func (b *BlockGen) AddEtx(from Account, to common.Address, value *big.Int) (*types.Transaction, error) {
+	if common.IsInChainScope(to.Bytes(), b.config.Location) {
+		return nil, fmt.Errorf("address %s is not in another zone", to.Hex())
+	}
+	return b.Transfer(from, to, value)
+}
+
+// SpendQi adds a Qi transaction spending the UTXO of the account at the
+// outpoint to a UTXO of the denomination for the address, and returns it. The
+// difference between the denominations pays the fee.
+func (b *BlockGen) SpendQi(from Account, outpoint types.OutPoint, to common.Address, denomination uint8) (*types.Transaction, error) {
+	qiTx := &types.QiTx{
+		ChainID: b.config.ChainID,
+		TxIn:    types.TxIns{{PreviousOutPoint: outpoint, PubKey: crypto.FromECDSAPub(&from.Key.PublicKey)}},
+		TxOut:   types.TxOuts{{Denomination: denomination, Address: to.Bytes()}},
+	}
+	key, _ := btcec.PrivKeyFromBytes(crypto.FromECDSA(from.Key))
+	digest := types.LatestSigner(b.config).Hash(types.NewTx(qiTx))
+	sig, err := schnorr.Sign(key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	qiTx.Signature = sig
+	tx := types.NewTx(qiTx)
+	return tx, b.AddTx(tx)
+}
+
+// GenerateBlocks generates n blocks in the zone at location, calling gen, if
+// not nil, to add the transactions of each block. Every block is sealed with
+// the lowest nonce for its difficulty, and given its order by the developer
+// mode, so every third block is coordinated across the hierarchy, after a
+// region block which delivers the ETXs from the other zones. The blocks are
+// appended to every chain of their order before the next one is built, and the
+// same configuration and callbacks always generate the same blocks.
+//
+// The first block of a zone applies the genesis allocations, which can
+// therefore only be spent from its second block.
+func (g *Generator) GenerateBlocks(location common.Location, n int, gen func(int, *BlockGen)) ([]*types.WorkObject, error) {
+	if location.Context() != common.ZONE_CTX || g.slice(location) == nil {
+		return nil, fmt.Errorf("location %s is not a generated zone", location.Name())
+	}
+	blocks := make([]*types.WorkObject, 0, n)
+	for i := 0; i < n; i++ {
+		block, err := g.generateBlock(location, i, gen)
+		if err != nil {
+			return blocks, fmt.Errorf("block %d of zone %s: %w", i, location.Name(), err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// GenerateBlocksFrom generates n blocks on top of the parent, which can be any
+// block of its zone, like GenerateBlocks does on the head of the zone. The
+// chains are forked from the parent: the zone and its dom chains are moved
+// back to the parent and to the dom blocks it was built on, and the generated
+// blocks become their heads, so the blocks after the fork point are reorged
+// out, including the ones of the other zones coordinated after it. The
+// transactions of the blocks reorged out are dropped from the pool, so the
+// generated blocks only include the ones added by gen.
+func (g *Generator) GenerateBlocksFrom(parent *types.WorkObject, n int, gen func(int, *BlockGen)) ([]*types.WorkObject, error) {
+	location := parent.Location()
+	if location.Context() != common.ZONE_CTX || g.slice(location) == nil {
+		return nil, fmt.Errorf("location %s is not a generated zone", location.Name())
+	}
+	if err := g.setHeads(location, parent.Hash()); err != nil {
+		return nil, err
+	}
+	zone := g.slice(location)
+	if _, err := zone.core.GeneratePendingHeader(zone.head, false); err != nil {
+		return nil, err
+	}
+	zone.core.TxPool().Clear()
+	return g.GenerateBlocks(location, n, gen)
+}
+
+// setHeads sets the head of the zone to the block with the given hash, and the
+// heads of its dom chains to the dom blocks the block was built on, or to the
+// block itself in the contexts of its order.
+func (g *Generator) setHeads(location common.Location, hash common.Hash) error {
+	slices := []*slice{g.slice(common.Location{}), g.slice(common.Location{byte(location.Region())}), g.slice(location)}
+	block := slices[common.ZONE_CTX].core.GetBlockByHash(hash)
+	if block == nil {
+		return fmt.Errorf("block %s is not in zone %s", hash, location.Name())
+	}
+	_, order, err := slices[common.ZONE_CTX].core.CalcOrder(block)
+	if err != nil {
+		return err
+	}
+	for ctx := common.PRIME_CTX; ctx <= common.ZONE_CTX; ctx++ {
+		domHash := block.Hash()
+		if ctx < order {
+			domHash = block.ParentHash(ctx)
+		}
+		head := slices[ctx].core.GetBlockByHash(domHash)
+		if head == nil {
+			return fmt.Errorf("block %s is not in context %d", domHash, ctx)
+		}
+		slices[ctx].head = head
+	}
+	return nil
+}
+
+func (g *Generator) generateBlock(location common.Location, i int, gen func(int, *BlockGen)) (*types.WorkObject, error) {
+	var (
+		prime  = g.slice(common.Location{})
+		region = g.slice(common.Location{byte(location.Region())})
+		zone   = g.slice(location)
+	)
+	// Move the zone to its head before the transactions are added, so that
+	// they are validated against its state
+	if _, err := zone.core.GeneratePendingHeader(zone.head, false); err != nil {
+		return nil, err
+	}
+	zone.core.TxPool().Sync()
+	b := &BlockGen{i: i, parent: zone.head, config: zone.core.Config(), pool: zone.core.TxPool()}
+	if gen != nil {
+		gen(i, b)
+	}
+	zone.core.TxPool().Sync()
+
+	primePendingHeader, err := prime.core.GeneratePendingHeader(prime.head, false)
+	if err != nil {
+		return nil, err
+	}
+	regionPendingHeader, err := region.core.GeneratePendingHeader(region.head, false)
+	if err != nil {
+		return nil, err
+	}
+	zonePendingHeader, err := zone.core.GeneratePendingHeader(zone.head, true)
+	if err != nil {
+		return nil, err
+	}
+	pendingHeader := zone.core.MakeFullPendingHeader(primePendingHeader, regionPendingHeader, zonePendingHeader)
+	// The pending header is given the current time, which is replaced by the
+	// clock of the generator, so that the blocks do not depend on when they
+	// are generated. It is shared by all the zones, so the block is still
+	// after its parents of every context.
+	g.time++
+	pendingHeader.WorkObjectHeader().SetTime(g.time)
+	// The header also announces the transactions the pool promoted to be
+	// broadcast, which depend on when its reorgs ran. The generator broadcasts
+	// no transactions, so it announces none.
+	pendingHeader.WorkObjectHeader().SetTxHash(types.EmptyRootHash)
+
+	results := make(chan *types.WorkObject, 1)
+	if err := zone.core.Engine().Seal(types.CopyWorkObject(pendingHeader), results, nil); err != nil {
+		return nil, err
+	}
+	sealed := <-results
+	_, order, err := zone.core.CalcOrder(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pending header carries the body of the zone block, which the dom
+	// chains strip down to their own view of the block
+	block := types.NewWorkObject(sealed.WorkObjectHeader(), sealed.Body(), nil)
+	zone.core.Slice().WriteBlock(block)
+	slices := []*slice{prime, region, zone}
+	for ctx := common.REGION_CTX; ctx >= order; ctx-- {
+		view, err := g.constructMinedBlock(slices[ctx].core, sealed)
+		if err != nil {
+			return nil, err
+		}
+		slices[ctx].core.Slice().WriteBlock(view)
+	}
+	if _, err := slices[order].core.InsertChain(types.WorkObjects{block}); err != nil {
+		return nil, err
+	}
+	for ctx := order; ctx <= common.ZONE_CTX; ctx++ {
+		head := slices[ctx].core.GetBlockByHash(block.Hash())
+		if head == nil {
+			return nil, fmt.Errorf("block %s was not appended in context %d", block.Hash(), ctx)
+		}
+		slices[ctx].head = head
+	}
+
+	included := make(map[common.Hash]bool, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		included[tx.Hash()] = true
+	}
+	for _, tx := range b.txs {
+		if !included[tx.Hash()] {
+			return nil, fmt.Errorf("transaction %s was not included", tx.Hash())
+		}
+	}
+	// Leave every chain at its head, for the databases to be opened there
+	for ctx := common.PRIME_CTX; ctx <= common.ZONE_CTX; ctx++ {
+		if _, err := slices[ctx].core.GeneratePendingHeader(slices[ctx].head, false); err != nil {
+			return nil, err
+		}
+	}
+	return zone.head, nil
+}
+
+// constructMinedBlock builds the view of the sealed block of the dom chain of
+// c, the way the block is rebuilt when it is submitted by a miner.
+func (g *Generator) constructMinedBlock(c *core.Core, sealed *types.WorkObject) (*types.WorkObject, error) {
+	block, err := c.ConstructLocalMinedBlock(types.CopyWorkObject(sealed))
+	if err == nil || !errors.Is(err, core.ErrBadSubManifest) {
+		return block, err
+	}
+	// The manifest of the pending body is the one of the zone, so the manifest
+	// of the subordinate chain is rebuilt from its parent
+	nodeCtx := c.NodeCtx()
+	subParentHash := block.ParentHash(nodeCtx + 1)
+	var subManifest types.BlockManifest
+	if subParent := c.GetBlockByHash(subParentHash); subParent != nil {
+		subManifest = types.BlockManifest{subParentHash}
+	} else {
+		subManifest, err = c.GetSubManifest(block.Location(), subParentHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(subManifest) == 0 || block.ManifestHash(nodeCtx+1) != types.DeriveSha(subManifest, trie.NewStackTrie(nil)) {
+		return nil, errors.New("reconstructed sub manifest does not match manifest hash")
+	}
+	return types.NewWorkObjectWithHeaderAndTx(block.WorkObjectHeader(), block.Tx()).WithBody(block.Header(), block.Transactions(), block.OutboundEtxs(), block.Uncles(), subManifest, block.InterlinkHashes()), nil
+}
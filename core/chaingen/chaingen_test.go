@@ -0,0 +1,197 @@
+package chaingen
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+var (
+	testBalance = new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
+	testValue   = big.NewInt(params.Ether)
+)
+
+func balance(t *testing.T, c *core.Core, address common.Address) *big.Int {
+	internal, err := address.InternalAndQuaiAddress()
+	require.NoError(t, err)
+	statedb, err := c.State()
+	require.NoError(t, err)
+	return statedb.GetBalance(internal)
+}
+
+// TestGenerateBlocks verifies the blocks generated in a zone include their
+// transactions, are coordinated with prime and region, are the same on every
+// run and can be loaded by a new core.
+func TestGenerateBlocks(t *testing.T) {
+	location := common.Location{0, 0}
+	accounts := Accounts(location, false, 3)
+	sender, recipient := accounts[1], accounts[2]
+	config := Config{QuaiAlloc: map[common.Address]*big.Int{sender.Address: testBalance}}
+
+	generate := func() (*Generator, []*types.WorkObject) {
+		g, err := New(config)
+		require.NoError(t, err)
+		blocks, err := g.GenerateBlocks(location, 6, func(i int, b *BlockGen) {
+			if i > 0 {
+				_, err := b.Transfer(sender, recipient.Address, testValue)
+				require.NoError(t, err)
+			}
+		})
+		require.NoError(t, err)
+		return g, blocks
+	}
+	g, blocks := generate()
+	require.Len(t, blocks, 6)
+	for i, block := range blocks {
+		assert.Equal(t, uint64(i+1), block.NumberU64(common.ZONE_CTX))
+	}
+	zone := g.Core(location)
+	assert.Equal(t, new(big.Int).Mul(testValue, big.NewInt(5)), balance(t, zone, recipient.Address))
+
+	prime := g.Core(common.Location{})
+	assert.Greater(t, prime.CurrentHeader().NumberU64(common.PRIME_CTX), uint64(0))
+	assert.Equal(t, prime.CurrentHeader().Hash(), g.Core(common.Location{0}).CurrentHeader().Hash())
+	assert.NotNil(t, zone.GetBlockByHash(prime.CurrentHeader().Hash()))
+
+	other, otherBlocks := generate()
+	other.Stop()
+	for i := range blocks {
+		assert.Equal(t, blocks[i].Hash(), otherBlocks[i].Hash())
+	}
+
+	g.Stop()
+	reopened, err := g.Open(location)
+	require.NoError(t, err)
+	defer reopened.Stop()
+	assert.Equal(t, blocks[len(blocks)-1].Hash(), reopened.CurrentHeader().Hash())
+	assert.Equal(t, new(big.Int).Mul(testValue, big.NewInt(5)), balance(t, reopened, recipient.Address))
+}
+
+// TestGenerateCrossZoneEtx verifies a transfer to another zone is emitted as an
+// ETX, rolled up by the region and credited in the destination zone.
+func TestGenerateCrossZoneEtx(t *testing.T) {
+	from, to := common.Location{0, 0}, common.Location{0, 1}
+	sender := Accounts(from, false, 2)[1]
+	recipient := Accounts(to, false, 2)[1]
+	g, err := New(Config{
+		ExpansionNumber: 1,
+		QuaiAlloc:       map[common.Address]*big.Int{sender.Address: testBalance},
+	})
+	require.NoError(t, err)
+	defer g.Stop()
+	require.Equal(t, []common.Location{from, to}, g.Locations())
+
+	_, err = g.GenerateBlocks(to, 1, nil)
+	require.NoError(t, err)
+	blocks, err := g.GenerateBlocks(from, 2, func(i int, b *BlockGen) {
+		if i == 1 {
+			// ETXs are only sent to other zones
+			_, err := b.AddEtx(sender, Accounts(from, false, 3)[2].Address, testValue)
+			require.Error(t, err)
+			_, err = b.AddEtx(sender, recipient.Address, testValue)
+			require.NoError(t, err)
+		}
+	})
+	require.NoError(t, err)
+	var etx *types.Transaction
+	for _, tx := range blocks[1].OutboundEtxs() {
+		if tx.To() != nil && bytes.Equal(tx.To().Bytes(), recipient.Address.Bytes()) {
+			etx = tx
+		}
+	}
+	require.NotNil(t, etx, "no ETX paying the recipient")
+	assert.Equal(t, testValue, etx.Value())
+
+	for i := 0; i < 8 && balance(t, g.Core(to), recipient.Address).Sign() == 0; i++ {
+		_, err = g.GenerateBlocks(from, 1, nil)
+		require.NoError(t, err)
+		_, err = g.GenerateBlocks(to, 1, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, testValue, balance(t, g.Core(to), recipient.Address))
+}
+
+// TestGenerateBlocksFrom verifies blocks generated on a fork point reorg the
+// chains onto them, and that the chain can be reorged back.
+func TestGenerateBlocksFrom(t *testing.T) {
+	location := common.Location{0, 0}
+	accounts := Accounts(location, false, 4)
+	sender, recipient, other := accounts[1], accounts[2], accounts[3]
+	g, err := New(Config{QuaiAlloc: map[common.Address]*big.Int{sender.Address: testBalance}})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	send := func(to common.Address) func(int, *BlockGen) {
+		return func(i int, b *BlockGen) {
+			_, err := b.Transfer(sender, to, testValue)
+			require.NoError(t, err)
+		}
+	}
+	blocks, err := g.GenerateBlocks(location, 2, nil)
+	require.NoError(t, err)
+	main, err := g.GenerateBlocks(location, 3, send(recipient.Address))
+	require.NoError(t, err)
+	zone := g.Core(location)
+	require.Equal(t, new(big.Int).Mul(testValue, big.NewInt(3)), balance(t, zone, recipient.Address))
+
+	// The fork replaces the blocks after the fork point, and their transfers
+	fork, err := g.GenerateBlocksFrom(blocks[1], 4, send(other.Address))
+	require.NoError(t, err)
+	head := fork[len(fork)-1]
+	assert.Equal(t, head.Hash(), zone.CurrentHeader().Hash())
+	assert.Equal(t, head.Hash(), g.Head(location).Hash())
+	for i, block := range fork {
+		assert.Equal(t, blocks[1].NumberU64(common.ZONE_CTX)+uint64(i)+1, block.NumberU64(common.ZONE_CTX))
+		assert.NotEqual(t, main[0].Hash(), block.Hash())
+		assert.Equal(t, block.Hash(), rawdb.ReadCanonicalHash(g.Database(location), block.NumberU64(common.ZONE_CTX)))
+	}
+	assert.Equal(t, blocks[1].Hash(), fork[0].ParentHash(common.ZONE_CTX))
+	assert.Equal(t, 0, balance(t, zone, recipient.Address).Sign())
+	assert.Equal(t, new(big.Int).Mul(testValue, big.NewInt(4)), balance(t, zone, other.Address))
+	assert.Equal(t, g.Core(common.Location{}).CurrentHeader().Hash(), g.Core(common.Location{0}).CurrentHeader().Hash())
+
+	// Extending the abandoned chain reorgs back onto it
+	_, err = g.GenerateBlocksFrom(main[len(main)-1], 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, main[0].Hash(), rawdb.ReadCanonicalHash(g.Database(location), main[0].NumberU64(common.ZONE_CTX)))
+	assert.Equal(t, new(big.Int).Mul(testValue, big.NewInt(3)), balance(t, zone, recipient.Address))
+	assert.Equal(t, 0, balance(t, zone, other.Address).Sign())
+}
+
+// TestGenerateQiTx verifies a Qi transaction spending a genesis UTXO is
+// included and creates the UTXO it pays.
+func TestGenerateQiTx(t *testing.T) {
+	location := common.Location{0, 0}
+	accounts := Accounts(location, true, 3)
+	sender, recipient := accounts[1], accounts[2]
+	g, err := New(Config{QiAlloc: map[common.Address]uint8{sender.Address: types.MaxDenomination}})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	var qiTx *types.Transaction
+	blocks, err := g.GenerateBlocks(location, 2, func(i int, b *BlockGen) {
+		if i == 1 {
+			var err error
+			qiTx, err = b.SpendQi(sender, GenesisOutPoint(sender.Address), recipient.Address, types.MaxDenomination-1)
+			require.NoError(t, err)
+		}
+	})
+	require.NoError(t, err)
+	assert.Contains(t, blocks[1].Transactions(), qiTx)
+
+	db := g.Database(location)
+	assert.Nil(t, rawdb.GetUTXO(db, GenesisOutPoint(sender.Address).TxHash, 0))
+	utxo := rawdb.GetUTXO(db, qiTx.Hash(), 0)
+	require.NotNil(t, utxo)
+	assert.Equal(t, uint8(types.MaxDenomination-1), utxo.Denomination)
+	assert.Equal(t, recipient.Address.Bytes(), utxo.Address)
+}
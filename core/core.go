@@ -66,6 +66,7 @@ type Core struct {
 	appendQueue     *lru.Cache[common.Hash, blockNumberAndRetryCounter]
 	processingCache *expireLru.LRU[common.Hash, interface{}]
 	remoteTxQueue   *lru.Cache[common.Hash, types.Transaction]
+	utxoRollbacks   *lru.Cache[common.Hash, utxoRollback]
 
 	writeBlockLock sync.RWMutex
 
@@ -109,6 +110,9 @@ func NewCore(db ethdb.Database, config *Config, isLocalBlock func(block *types.W
 	remoteTxQueue, _ := lru.New[common.Hash, types.Transaction](c_maxRemoteTxQueue)
 	c.remoteTxQueue = remoteTxQueue
 
+	utxoRollbacks, _ := lru.New[common.Hash, utxoRollback](c_utxoRollbackCacheSize)
+	c.utxoRollbacks = utxoRollbacks
+
 	go c.updateAppendQueue()
 	go c.startStatsTimer()
 	if c.NodeCtx() == common.ZONE_CTX && c.ProcessingState() {
@@ -764,6 +768,14 @@ func (c *Core) GenerateRecoveryPendingHeader(pendingHeader *types.WorkObject, ch
 	return c.sl.GenerateRecoveryPendingHeader(pendingHeader, checkpointHashes)
 }
 
+func (c *Core) SetHead(hash common.Hash) error {
+	return c.sl.SetHead(hash)
+}
+
+func (c *Core) SetHeadFromDom(domTerminus common.Hash, domPendingHeader *types.WorkObject) error {
+	return c.sl.SetHeadFromDom(domTerminus, domPendingHeader)
+}
+
 func (c *Core) IsBlockHashABadHash(hash common.Hash) bool {
 	return c.sl.IsBlockHashABadHash(hash)
 }
@@ -1296,6 +1308,15 @@ func (c *Core) GetLockupsByAddress(address common.Address) ([]*types.Lockup, err
 	return rawdb.ReadLockupsForAddress(c.sl.sliceDb, address)
 }
 
+// GetWorkSharesByCoinbase returns the rewards paid to a coinbase for blocks and
+// included workshares by the canonical blocks from the start to the end number.
+func (c *Core) GetWorkSharesByCoinbase(address common.Address, start, end uint64) ([]*types.WorkShareReward, error) {
+	if !c.sl.config.IndexWorkShares {
+		return nil, ErrWorkShareIndexDisabled
+	}
+	return rawdb.ReadWorkShareRewards(c.sl.sliceDb, address, start, end)
+}
+
 func (c *Core) GetUTXOsByAddress(address common.Address) ([]*types.UtxoEntry, error) {
 	outpointsForAddress, err := c.GetOutpointsByAddress(address)
 	if err != nil {
@@ -1336,6 +1357,13 @@ func (c *Core) AddRemotes(txs types.Transactions) {
 	}
 }
 
+// AddPeerTxs validates the transactions fetched from a peer and adds them to
+// the pool right away, instead of queueing them like AddRemotes, so that the
+// caller knows which of them were accepted.
+func (c *Core) AddPeerTxs(txs types.Transactions) []error {
+	return c.sl.txPool.AddRemotes(txs)
+}
+
 func (c *Core) TxPoolPending(enforceTips bool) (map[common.AddressBytes]types.Transactions, error) {
 	return c.sl.txPool.TxPoolPending(enforceTips)
 }
@@ -1367,6 +1395,10 @@ func (c *Core) Stats() (int, int, int) {
 	return c.sl.txPool.Stats()
 }
 
+func (c *Core) SyncTxPool() {
+	c.sl.txPool.Sync()
+}
+
 func (c *Core) Content() (map[common.InternalAddress]types.Transactions, map[common.InternalAddress]types.Transactions) {
 	return c.sl.txPool.Content()
 }
@@ -1379,6 +1411,10 @@ func (c *Core) ContentFrom(addr common.Address) (types.Transactions, types.Trans
 	}
 	return c.sl.txPool.ContentFrom(internal)
 }
+func (c *Core) QiConflicts(outpoints []types.OutPoint) map[types.OutPoint]common.Hash {
+	return c.sl.txPool.QiConflicts(outpoints)
+}
+
 func (c *Core) SendTxToSharingClients(tx *types.Transaction) {
 	c.sl.txPool.SendTxToSharingClients(tx)
 }
@@ -74,8 +74,17 @@ var (
 	// ErrBadBlockHash is returned when block being appended is in the badBlockHashes list
 	ErrBadBlockHash = errors.New("block hash exists in bad block hashes list")
 
+	// ErrSnapSyncing is returned when a block following the snap sync pivot is appended before the state at the pivot is downloaded
+	ErrSnapSyncing = errors.New("state at snap sync pivot is not downloaded yet")
+
 	// ErrPendingHeaderNotInCache is returned when a coord gives an update but the slice has not yet created the referenced ph
 	ErrPendingHeaderNotInCache = errors.New("no pending header found in cache")
+
+	// ErrWorkShareIndexDisabled is returned when querying the workshare index of a node which does not index workshares
+	ErrWorkShareIndexDisabled = errors.New("workshare index is disabled")
+
+	// ErrFrozenBlocks is returned when the head is set back below blocks which were moved to the ancient store
+	ErrFrozenBlocks = errors.New("blocks are frozen in the ancient store")
 )
 
 // List of evm-call-message pre-checking errors. All state transition messages will
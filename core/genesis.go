@@ -27,6 +27,7 @@ import (
 
 	"math/big"
 	"os"
+	"path/filepath"
 
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/common/hexutil"
@@ -45,6 +46,10 @@ import (
 
 var errGenesisNoConfig = errors.New("genesis has no chain configuration")
 
+// DefaultGenesisAllocDir is the directory the genesis allocation files of the
+// zones are read from, relative to the working directory.
+const DefaultGenesisAllocDir = "genallocs"
+
 // Genesis specifies the header fields, state of a genesis block. It also defines hard
 // fork switch-over blocks through the chain configuration.
 type Genesis struct {
@@ -469,6 +474,18 @@ func DeveloperGenesisBlock(period uint64, faucet common.Address) *Genesis {
 	}
 }
 
+// GenesisQuaiAllocFile returns the path of the file in dir allocating the Quai
+// ledger accounts of the zone at the first block.
+func GenesisQuaiAllocFile(dir string, location common.Location) string {
+	return filepath.Join(dir, "gen_alloc_quai_"+location.Name()+".json")
+}
+
+// GenesisQiAllocFile returns the path of the file in dir allocating the Qi
+// ledger UTXOs of the zone at the first block.
+func GenesisQiAllocFile(dir string, location common.Location) string {
+	return filepath.Join(dir, "gen_alloc_qi_"+location.Name()+".json")
+}
+
 func ReadGenesisAlloc(filename string, logger *log.Logger) map[string]GenesisAccount {
 	jsonFile, err := os.Open(filename)
 	if err != nil {
@@ -522,8 +539,8 @@ func ReadGenesisQiAlloc(filename string, logger *log.Logger) map[string]GenesisU
 }
 
 // WriteGenesisUtxoSet writes the genesis utxo set to the database
-func AddGenesisUtxos(db ethdb.Database, utxosCreate *[]common.Hash, nodeLocation common.Location, addressOutpointMap map[[20]byte][]*types.OutpointAndDenomination, logger *log.Logger) {
-	qiAlloc := ReadGenesisQiAlloc("genallocs/gen_alloc_qi_"+nodeLocation.Name()+".json", logger)
+func AddGenesisUtxos(db ethdb.Database, filename string, utxosCreate *[]common.Hash, nodeLocation common.Location, addressOutpointMap map[[20]byte][]*types.OutpointAndDenomination, logger *log.Logger) {
+	qiAlloc := ReadGenesisQiAlloc(filename, logger)
 	// logger.WithField("alloc", len(qiAlloc)).Info("Allocating genesis accounts")
 	for addressString, utxo := range qiAlloc {
 		addr := common.HexToAddress(addressString, nodeLocation)
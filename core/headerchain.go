@@ -478,6 +478,9 @@ func (hc *HeaderChain) SetCurrentHeader(head *types.WorkObject) error {
 	if err != nil {
 		return err
 	}
+	if err := hc.checkUnfrozen(commonHeader.NumberU64(nodeCtx)); err != nil {
+		return err
+	}
 	newHeader := types.CopyWorkObject(head)
 
 	// Delete each header and rollback state processor until common header
@@ -1083,7 +1086,7 @@ func (hc *HeaderChain) Export(w io.Writer) error {
 
 // ExportN writes a subset of the active chain to the given writer.
 func (hc *HeaderChain) ExportN(w io.Writer, first uint64, last uint64) error {
-	return nil
+	return ExportWorkObjects(hc.headerDb, w, first, last, hc.NodeCtx(), hc.logger)
 }
 
 // GetBlockFromCacheOrDb looks up the body cache first and then checks the db
@@ -1200,6 +1203,21 @@ func (hc *HeaderChain) StateAt(root, etxRoot common.Hash, quaiStateSize *big.Int
 	return hc.bc.processor.StateAt(root, etxRoot, quaiStateSize)
 }
 
+// checkUnfrozen returns ErrFrozenBlocks if some of the blocks above number were
+// moved to the ancient store, which only appends, so that the head is not set
+// back below them.
+func (hc *HeaderChain) checkUnfrozen(number uint64) error {
+	frozen, err := hc.headerDb.Ancients()
+	if err != nil {
+		// The database has no ancient store
+		return nil
+	}
+	if number+1 < frozen {
+		return fmt.Errorf("%w: block %d is below the %d frozen blocks", ErrFrozenBlocks, number, frozen)
+	}
+	return nil
+}
+
 func (hc *HeaderChain) SlicesRunning() []common.Location {
 	return hc.slicesRunning
 }
@@ -1302,7 +1320,7 @@ func (hc *HeaderChain) UpdateEtxEligibleSlices(header *types.WorkObject, locatio
 	byteIndex := position / 8      // Find the byte index within the array
 	bitIndex := uint(position % 8) // Find the specific bit within the byte, cast to uint for bit operations
 	newHash := header.EtxEligibleSlices()
-	if header.NumberU64(common.ZONE_CTX) > params.TimeToStartTx {
+	if header.NumberU64(common.ZONE_CTX) > hc.config.StartTxNumber() {
 		// Set the position bit to 1
 		newHash[byteIndex] |= 1 << bitIndex
 	} else {
@@ -34,13 +34,41 @@ import (
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db ethdb.Reader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
-
+	if len(data) == 0 {
+		// The canonical mapping of the frozen blocks is only kept in the
+		// freezer
+		data, _ = db.Ancient(freezerHashTable, number)
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
 	return common.BytesToHash(data)
 }
 
+// readAncient retrieves the frozen data of the given kind for the canonical
+// block with the given number and hash. The hash comparison is necessary since
+// the freezer only maintains the canonical data.
+func readAncient(db ethdb.AncientReader, kind string, number uint64, hash common.Hash) []byte {
+	data, _ := db.Ancient(kind, number)
+	if len(data) == 0 {
+		return nil
+	}
+	if h, _ := db.Ancient(freezerHashTable, number); common.BytesToHash(h) != hash {
+		return nil
+	}
+	return data
+}
+
+// readAncientByHash is like readAncient, looking up the number of the block
+// from the hash to number mapping, which is never frozen.
+func readAncientByHash(db ethdb.Reader, kind string, hash common.Hash) []byte {
+	number := ReadHeaderNumber(db, hash)
+	if number == nil {
+		return nil
+	}
+	return readAncient(db, kind, *number, hash)
+}
+
 // WriteCanonicalHash stores the hash assigned to a canonical block number.
 func WriteCanonicalHash(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	if err := db.Put(headerHashKey(number), hash.Bytes()); err != nil {
@@ -112,6 +140,12 @@ func WriteProcessedState(db ethdb.KeyValueWriter, hash common.Hash) {
 	}
 }
 
+func DeleteProcessedState(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(processedStateKey(hash)); err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to delete processed state for block " + hash.String())
+	}
+}
+
 // ReadHeadHeaderHash retrieves the hash of the current canonical head header.
 func ReadHeadHeaderHash(db ethdb.KeyValueReader) common.Hash {
 	data, _ := db.Get(headHeaderKey)
@@ -287,6 +321,9 @@ func DeleteAllPbBodyKeys(db ethdb.KeyValueWriter) {
 func ReadTermini(db ethdb.Reader, hash common.Hash) *types.Termini {
 	key := terminiKey(hash)
 	data, _ := db.Get(key)
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerTerminiTable, hash)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -337,6 +374,9 @@ func ReadWorkObjectHeader(db ethdb.Reader, number uint64, hash common.Hash, woTy
 		key = headerKey(number, hash)
 	}
 	data, _ := db.Get(key)
+	if len(data) == 0 {
+		data = readAncient(db, freezerHeaderTable, number, hash)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -453,6 +493,9 @@ func DeleteBlockWithoutNumber(db ethdb.KeyValueWriter, hash common.Hash, number
 func ReadWorkObjectBody(db ethdb.Reader, hash common.Hash, woType types.WorkObjectView) *types.WorkObjectBody {
 	key := workObjectBodyKey(hash)
 	data, _ := db.Get(key)
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerBodiesTable, hash)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -477,6 +520,9 @@ func ReadWorkObjectBody(db ethdb.Reader, hash common.Hash, woType types.WorkObje
 func ReadWorkObjectBodyHeaderOnly(db ethdb.Reader, hash common.Hash) *types.WorkObjectBody {
 	key := workObjectBodyKey(hash)
 	data, _ := db.Get(key)
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerBodiesTable, hash)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -907,8 +953,11 @@ func DeletePendingEtxsRollup(db ethdb.KeyValueWriter, hash common.Hash) {
 
 // ReadManifest retreives the manifest corresponding to a given block
 func ReadManifest(db ethdb.Reader, hash common.Hash) types.BlockManifest {
-	// Try to look up the data in leveldb.
+	// Try to look up the data in leveldb, then in the freezer.
 	data, _ := db.Get(manifestKey(hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerManifestTable, hash)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -1301,6 +1350,62 @@ func ReadLockupsForAddress(db ethdb.Database, address common.Address) ([]*types.
 	return lockups, nil
 }
 
+// WriteWorkShareRewards stores the rewards paid to a coinbase by the canonical
+// block with the given number.
+func WriteWorkShareRewards(db ethdb.KeyValueWriter, address common.Address, number uint64, rewards []*types.WorkShareReward) {
+	protoRewards := &types.ProtoWorkShareRewards{
+		Rewards: make([]*types.ProtoWorkShareReward, 0, len(rewards)),
+	}
+	for _, reward := range rewards {
+		protoRewards.Rewards = append(protoRewards.Rewards, reward.ProtoEncode())
+	}
+	data, err := proto.Marshal(protoRewards)
+	if err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to proto Marshal workshare rewards")
+	}
+	if err := db.Put(workShareRewardsKey(address, number), data); err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to store workshare rewards")
+	}
+}
+
+// ReadWorkShareRewards retrieves the rewards paid to a coinbase by the
+// canonical blocks from the start to the end number, in block order.
+func ReadWorkShareRewards(db ethdb.Database, address common.Address, start, end uint64) ([]*types.WorkShareReward, error) {
+	prefix := append(append([]byte{}, workShareRewardsPrefix...), address.Bytes()...)
+	it := db.NewIterator(prefix, encodeBlockNumber(start))
+	defer it.Release()
+	rewards := make([]*types.WorkShareReward, 0)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(prefix):]) > end {
+			break
+		}
+		protoRewards := new(types.ProtoWorkShareRewards)
+		if err := proto.Unmarshal(it.Value(), protoRewards); err != nil {
+			return nil, err
+		}
+		for _, protoReward := range protoRewards.GetRewards() {
+			reward := new(types.WorkShareReward)
+			if err := reward.ProtoDecode(protoReward); err != nil {
+				return nil, err
+			}
+			rewards = append(rewards, reward)
+		}
+	}
+	return rewards, it.Error()
+}
+
+// DeleteWorkShareRewards deletes the rewards paid to a coinbase by the block
+// with the given number.
+func DeleteWorkShareRewards(db ethdb.KeyValueWriter, address common.Address, number uint64) {
+	if err := db.Delete(workShareRewardsKey(address, number)); err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to delete workshare rewards")
+	}
+}
+
 func WriteGenesisHashes(db ethdb.KeyValueWriter, hashes common.Hashes) {
 	protoHashes := hashes.ProtoEncode()
 	data, err := proto.Marshal(protoHashes)
@@ -1432,6 +1537,12 @@ func WriteMultiSet(db ethdb.KeyValueWriter, blockHash common.Hash, multiSet *mul
 	}
 }
 
+func DeleteMultiSet(db ethdb.KeyValueWriter, blockHash common.Hash) {
+	if err := db.Delete(multiSetKey(blockHash)); err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to delete multiSet")
+	}
+}
+
 func ReadTokenChoicesSet(db ethdb.Reader, blockHash common.Hash) *types.TokenChoiceSet {
 	data, _ := db.Get(tokenChoiceSetKey(blockHash))
 	if len(data) == 0 {
@@ -1622,6 +1733,37 @@ func DeleteUTXOSetSize(db ethdb.KeyValueWriter, blockHash common.Hash) {
 	}
 }
 
+// ReadSnapSyncPivot retrieves the number of the block whose state is being
+// snap synced, or zero if no snap sync is in progress.
+func ReadSnapSyncPivot(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(snapSyncPivotKey)
+	if len(data) == 0 {
+		return 0
+	}
+	if len(data) != 8 {
+		db.Logger().WithField("data", data).Fatal("Invalid snap sync pivot data")
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteSnapSyncPivot stores the number of the block whose state is being snap
+// synced.
+func WriteSnapSyncPivot(db ethdb.KeyValueWriter, number uint64) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, number)
+	if err := db.Put(snapSyncPivotKey, data); err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to store snap sync pivot")
+	}
+}
+
+// DeleteSnapSyncPivot deletes the snap sync pivot once the state at it is
+// complete.
+func DeleteSnapSyncPivot(db ethdb.KeyValueWriter) {
+	if err := db.Delete(snapSyncPivotKey); err != nil {
+		db.Logger().WithField("err", err).Fatal("Failed to delete snap sync pivot")
+	}
+}
+
 func ReadLastTrimmedBlock(db ethdb.Reader, blockHash common.Hash) uint64 {
 	data, _ := db.Get(lastTrimmedBlockKey(blockHash))
 	if len(data) == 0 {
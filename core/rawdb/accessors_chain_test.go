@@ -711,7 +711,7 @@ func TestAncientReceiptsStorage(t *testing.T) {
 	db := NewMemoryDatabase(log.Global)
 
 	dataDir := os.TempDir() + "/testFreezer"
-	freezerDb, err := NewDatabaseWithFreezer(db, dataDir, os.TempDir(), false, common.ZONE_CTX, log.Global, common.Location{0, 0})
+	freezerDb, err := NewDatabaseWithFreezer(db, dataDir, os.TempDir(), false, 0, common.ZONE_CTX, log.Global, common.Location{0, 0})
 
 	require.NoError(t, err)
 	defer func() {
@@ -727,7 +727,7 @@ func TestAncientReceiptsStorage(t *testing.T) {
 	receipts := createReceipts(types.Transactions{tx1, tx2})
 	hash := receipts[0].BlockHash
 
-	freezerDb.AppendAncient(0, hash.Bytes(), receipts.Bytes(freezerDb.Logger()))
+	freezerDb.AppendAncient(0, hash.Bytes(), nil, nil, receipts.Bytes(freezerDb.Logger()), nil, nil)
 
 	txs := types.Transactions{tx1, tx2}
 	writeBlockForReceipts(db, hash, txs)
@@ -738,6 +738,79 @@ func TestAncientReceiptsStorage(t *testing.T) {
 
 }
 
+func TestAncientWorkObjectStorage(t *testing.T) {
+	db := NewMemoryDatabase(log.Global)
+
+	// Write a canonical chain of three blocks, the first two of which are
+	// deeper than the finality depth
+	var blocks []*types.WorkObject
+	for i := int64(0); i < 3; i++ {
+		wo := createTestWorkObject()
+		wo.WorkObjectHeader().SetNumber(big.NewInt(i))
+		if i > 0 {
+			wo.WorkObjectHeader().SetParentHash(blocks[i-1].Hash())
+		}
+		termini := types.EmptyTermini()
+		termini.SetDomTermini(common.Hashes{{byte(i + 1)}})
+
+		WriteWorkObject(db, wo.Hash(), wo, types.BlockObject, common.ZONE_CTX)
+		WriteCanonicalHash(db, wo.Hash(), uint64(i))
+		WriteTermini(db, wo.Hash(), termini)
+		WriteManifest(db, wo.Hash(), types.BlockManifest{{byte(i + 1)}})
+		blocks = append(blocks, wo)
+	}
+	WriteHeadBlockHash(db, blocks[2].Hash())
+	WriteHeadHeaderHash(db, blocks[2].Hash())
+
+	dataDir := os.TempDir() + "/testWorkObjectFreezer"
+	freezerDb, err := NewDatabaseWithFreezer(db, dataDir, "", false, 1, common.ZONE_CTX, log.Global, common.Location{0, 0})
+	require.NoError(t, err)
+	defer func() {
+		err := freezerDb.Close()
+		require.NoError(t, err)
+
+		err = os.RemoveAll(dataDir)
+		require.NoError(t, err)
+	}()
+
+	// Wait for the background freezer to move the finalized blocks
+	require.NoError(t, freezerDb.(*freezerdb).Freeze(1))
+	frozen, err := freezerDb.Ancients()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), frozen)
+
+	// The frozen block is removed from the key-value store, but is still
+	// readable through the freezer
+	wo := blocks[1]
+	if data, _ := db.Get(headerKey(1, wo.Hash())); len(data) != 0 {
+		t.Fatal("Frozen header kept in the key-value store")
+	}
+	if data, _ := db.Get(workObjectBodyKey(wo.Hash())); len(data) != 0 {
+		t.Fatal("Frozen body kept in the key-value store")
+	}
+	require.Equal(t, wo.Hash(), ReadCanonicalHash(freezerDb, 1))
+	if entry := ReadWorkObject(freezerDb, 1, wo.Hash(), types.BlockObject); entry == nil || entry.Hash() != wo.Hash() {
+		t.Fatalf("Frozen work object not found: %v", entry)
+	}
+	if entry := ReadHeader(freezerDb, 1, wo.Hash()); entry == nil || entry.Hash() != wo.Hash() {
+		t.Fatalf("Frozen header not found: %v", entry)
+	}
+	if entry := ReadTermini(freezerDb, wo.Hash()); entry == nil || entry.DomTermini()[0] != (common.Hash{2}) {
+		t.Fatalf("Frozen termini not found: %v", entry)
+	}
+	if entry := ReadManifest(freezerDb, wo.Hash()); len(entry) != 1 || entry[0] != (common.Hash{2}) {
+		t.Fatalf("Frozen manifest not found: %v", entry)
+	}
+
+	// The genesis and the blocks above the finality depth stay in the
+	// key-value store
+	for _, wo := range []*types.WorkObject{blocks[0], blocks[2]} {
+		if entry := ReadWorkObject(db, wo.NumberU64(common.ZONE_CTX), wo.Hash(), types.BlockObject); entry == nil {
+			t.Fatalf("Block %d missing from the key-value store", wo.NumberU64(common.ZONE_CTX))
+		}
+	}
+}
+
 func createBlockWithTransactions(txs types.Transactions) *types.WorkObject {
 	woBody := types.EmptyWorkObjectBody()
 	woBody.SetHeader(types.EmptyHeader())
@@ -813,6 +886,48 @@ func TestAddressOutpointsStorage(t *testing.T) {
 	}
 }
 
+func TestWorkShareRewardsStorage(t *testing.T) {
+	db := NewMemoryDatabase(log.Global)
+
+	address := common.HexToAddress("0x008aeeda4d805471df9b2a5b0f38a0c3bcba786b", db.Location())
+	address2 := common.HexToAddress("0x008b2a5b0f38a0c3bcba786ba3df9b2a5b0f38a0", db.Location())
+
+	if entry, err := ReadWorkShareRewards(db, address, 0, 100); len(entry) != 0 || err != nil {
+		t.Fatalf("Non existent workshare rewards returned: %v", entry)
+	}
+
+	newReward := func(number uint64, workShare common.Hash) *types.WorkShareReward {
+		return &types.WorkShareReward{
+			BlockHash:    common.Hash{byte(number)},
+			BlockNumber:  number,
+			WorkShare:    workShare,
+			Distance:     2,
+			Lock:         1,
+			Reward:       big.NewInt(1000),
+			LockedValue:  big.NewInt(1050),
+			UnlockHeight: number + 100,
+		}
+	}
+	WriteWorkShareRewards(db, address, 255, []*types.WorkShareReward{newReward(255, common.Hash{1}), newReward(255, common.Hash{2})})
+	WriteWorkShareRewards(db, address, 10, []*types.WorkShareReward{newReward(10, common.Hash{3})})
+	WriteWorkShareRewards(db, address, 256, []*types.WorkShareReward{newReward(256, common.Hash{4})})
+	WriteWorkShareRewards(db, address2, 10, []*types.WorkShareReward{newReward(10, common.Hash{5})})
+
+	entry, err := ReadWorkShareRewards(db, address, 10, 255)
+	require.NoError(t, err)
+	require.Equal(t, []*types.WorkShareReward{newReward(10, common.Hash{3}), newReward(255, common.Hash{1}), newReward(255, common.Hash{2})}, entry)
+
+	entry, err = ReadWorkShareRewards(db, address, 11, 1000)
+	require.NoError(t, err)
+	require.Len(t, entry, 3)
+	require.Equal(t, uint64(256), entry[2].BlockNumber)
+
+	DeleteWorkShareRewards(db, address, 255)
+	entry, err = ReadWorkShareRewards(db, address, 0, 1000)
+	require.NoError(t, err)
+	require.Equal(t, []*types.WorkShareReward{newReward(10, common.Hash{3}), newReward(256, common.Hash{4})}, entry)
+}
+
 func TestGenesisHashesStorage(t *testing.T) {
 	db := NewMemoryDatabase(log.Global)
 
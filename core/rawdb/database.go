@@ -101,7 +101,7 @@ func (db *nofreezedb) AncientSize(kind string) (uint64, error) {
 }
 
 // AppendAncient returns an error as we don't have a backing chain freezer.
-func (db *nofreezedb) AppendAncient(number uint64, hash, receipts []byte) error {
+func (db *nofreezedb) AppendAncient(number uint64, hash, header, body, receipts, termini, manifest []byte) error {
 	return errNotSupported
 }
 
@@ -125,10 +125,11 @@ func NewDatabase(db ethdb.KeyValueStore) ethdb.Database {
 
 // NewDatabaseWithFreezer creates a high level database on top of a given key-
 // value data store with a freezer moving immutable chain segments into cold
-// storage.
-func NewDatabaseWithFreezer(db ethdb.KeyValueStore, freezer string, namespace string, readonly bool, nodeCtx int, logger *log.Logger, location common.Location) (ethdb.Database, error) {
+// storage. Zone blocks deeper than the threshold are frozen in the background,
+// unless the threshold is zero.
+func NewDatabaseWithFreezer(db ethdb.KeyValueStore, freezer string, namespace string, readonly bool, threshold uint64, nodeCtx int, logger *log.Logger, location common.Location) (ethdb.Database, error) {
 	// Create the idle freezer instance
-	frdb, err := newFreezer(freezer, namespace, readonly, logger)
+	frdb, err := newFreezer(freezer, namespace, readonly, threshold, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +197,14 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, freezer string, namespace st
 			// feezer.
 		}
 	}
+	// Freeze the finalized zone blocks in the background
+	if !readonly && threshold > 0 && nodeCtx == common.ZONE_CTX {
+		frdb.wg.Add(1)
+		go func() {
+			defer frdb.wg.Done()
+			frdb.freeze(db, nodeCtx, location)
+		}()
+	}
 	return &freezerdb{
 		KeyValueStore: db,
 		AncientStore:  frdb,
@@ -253,6 +262,7 @@ type OpenOptions struct {
 	Type              string // "leveldb" | "pebble"
 	Directory         string // the datadir
 	AncientsDirectory string // the ancients-dir
+	FreezerThreshold  uint64 // the depth after which blocks are frozen, zero disables freezing
 	Namespace         string // the namespace for database relevant metrics
 	Cache             int    // the capacity(in megabytes) of the data caching
 	Handles           int    // number of files to be open simultaneously
@@ -299,7 +309,7 @@ func Open(o OpenOptions, nodeCtx int, logger *log.Logger, location common.Locati
 	if len(o.AncientsDirectory) == 0 {
 		return kvdb, nil
 	}
-	frdb, err := NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly, nodeCtx, logger, location)
+	frdb, err := NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly, o.FreezerThreshold, nodeCtx, logger, location)
 	if err != nil {
 		kvdb.Close()
 		return nil, err
@@ -364,11 +374,12 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte, logger *log.
 		bloomBits       stat
 
 		// Ancient store statistics
-		ancientHeadersSize  common.StorageSize
-		ancientBodiesSize   common.StorageSize
-		ancientReceiptsSize common.StorageSize
-		ancientTdsSize      common.StorageSize
-		ancientHashesSize   common.StorageSize
+		ancientHeadersSize   common.StorageSize
+		ancientBodiesSize    common.StorageSize
+		ancientReceiptsSize  common.StorageSize
+		ancientTerminiSize   common.StorageSize
+		ancientManifestsSize common.StorageSize
+		ancientHashesSize    common.StorageSize
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -455,8 +466,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte, logger *log.
 		}
 	}
 	// Inspect append-only file store then.
-	ancientSizes := []*common.StorageSize{&ancientHeadersSize, &ancientBodiesSize, &ancientReceiptsSize, &ancientHashesSize, &ancientTdsSize}
-	for i, category := range []string{freezerReceiptTable, freezerHashTable, freezerDifficultyTable} {
+	ancientSizes := []*common.StorageSize{&ancientHeadersSize, &ancientBodiesSize, &ancientReceiptsSize, &ancientTerminiSize, &ancientManifestsSize, &ancientHashesSize}
+	for i, category := range []string{freezerHeaderTable, freezerBodiesTable, freezerReceiptTable, freezerTerminiTable, freezerManifestTable, freezerHashTable} {
 		if size, err := db.AncientSize(category); err == nil {
 			*ancientSizes[i] += common.StorageSize(size)
 			total += common.StorageSize(size)
@@ -486,7 +497,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte, logger *log.
 		{"Ancient store", "Headers", ancientHeadersSize.String(), ancients.String()},
 		{"Ancient store", "Bodies", ancientBodiesSize.String(), ancients.String()},
 		{"Ancient store", "Receipt lists", ancientReceiptsSize.String(), ancients.String()},
-		{"Ancient store", "Difficulties", ancientTdsSize.String(), ancients.String()},
+		{"Ancient store", "Termini", ancientTerminiSize.String(), ancients.String()},
+		{"Ancient store", "Manifests", ancientManifestsSize.String(), ancients.String()},
 		{"Ancient store", "Block number->hash", ancientHashesSize.String(), ancients.String()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
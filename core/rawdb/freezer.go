@@ -30,7 +30,6 @@ import (
 	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/dominant-strategies/go-quai/ethdb"
 	"github.com/dominant-strategies/go-quai/log"
-	"github.com/dominant-strategies/go-quai/params"
 	"github.com/prometheus/tsdb/fileutil"
 )
 
@@ -75,7 +74,7 @@ type freezer struct {
 	// 64-bit aligned fields can be atomic. The struct is guaranteed to be so aligned,
 	// so take advantage of that (https://golang.org/pkg/sync/atomic/#pkg-note-BUG).
 	frozen    uint64 // Number of blocks already frozen
-	threshold uint64 // Number of recent blocks not to freeze (params.FullImmutabilityThreshold apart from tests)
+	threshold uint64 // Number of recent blocks not to freeze (the configured finality depth)
 
 	readonly     bool
 	tables       map[string]*freezerTable // Data tables for storing everything
@@ -92,7 +91,7 @@ type freezer struct {
 
 // newFreezer creates a chain freezer that moves ancient chain data into
 // append-only flat file containers.
-func newFreezer(datadir string, namespace string, readonly bool, logger *log.Logger) (*freezer, error) {
+func newFreezer(datadir string, namespace string, readonly bool, threshold uint64, logger *log.Logger) (*freezer, error) {
 	// Create the initial freezer object
 	// Ensure the datadir is not a symbolic link if it exists.
 	if info, err := os.Lstat(datadir); !os.IsNotExist(err) {
@@ -110,7 +109,7 @@ func newFreezer(datadir string, namespace string, readonly bool, logger *log.Log
 	// Open all the supported data tables
 	freezer := &freezer{
 		readonly:     readonly,
-		threshold:    params.FullImmutabilityThreshold,
+		threshold:    threshold,
 		tables:       make(map[string]*freezerTable),
 		instanceLock: lock,
 		trigger:      make(chan chan struct{}),
@@ -200,7 +199,7 @@ func (f *freezer) AncientSize(kind string) (uint64, error) {
 // Notably, this function is lock free but kind of thread-safe. All out-of-order
 // injection will be rejected. But if two injections with same number happen at
 // the same time, we can get into the trouble.
-func (f *freezer) AppendAncient(number uint64, hash, receipts []byte) (err error) {
+func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, termini, manifest []byte) (err error) {
 	if f.readonly {
 		return errReadOnly
 	}
@@ -223,21 +222,27 @@ func (f *freezer) AppendAncient(number uint64, hash, receipts []byte) (err error
 		}
 	}()
 	// Inject all the components into the relevant data tables
-	if err := f.tables[freezerHashTable].Append(f.frozen, hash[:]); err != nil {
-		f.logger.WithFields(log.Fields{
-			"number": f.frozen,
-			"hash":   hash,
-			"err":    err,
-		}).Error("Failed to append ancient hash")
-		return err
+	blobs := []struct {
+		kind string
+		blob []byte
+	}{
+		{freezerHashTable, hash},
+		{freezerHeaderTable, header},
+		{freezerBodiesTable, body},
+		{freezerReceiptTable, receipts},
+		{freezerTerminiTable, termini},
+		{freezerManifestTable, manifest},
 	}
-	if err := f.tables[freezerReceiptTable].Append(f.frozen, receipts); err != nil {
-		f.logger.WithFields(log.Fields{
-			"number": f.frozen,
-			"hash":   hash,
-			"err":    err,
-		}).Error("Failed to append ancient receipts")
-		return err
+	for _, item := range blobs {
+		if err := f.tables[item.kind].Append(f.frozen, item.blob); err != nil {
+			f.logger.WithFields(log.Fields{
+				"number": f.frozen,
+				"hash":   common.BytesToHash(hash),
+				"table":  item.kind,
+				"err":    err,
+			}).Error("Failed to append ancient data")
+			return err
+		}
 	}
 	atomic.AddUint64(&f.frozen, 1) // Only modify atomically
 	return nil
@@ -368,20 +373,33 @@ func (f *freezer) freeze(db ethdb.KeyValueStore, nodeCtx int, location common.Lo
 				f.logger.WithField("number", f.frozen).Error("Canonical hash missing, can't freeze")
 				break
 			}
-			receipts := ReadReceiptsProto(nfdb, hash, f.frozen)
-			if len(receipts) == 0 {
+			header, _ := nfdb.Get(headerKey(f.frozen, hash))
+			if len(header) == 0 {
 				f.logger.WithFields(log.Fields{
 					"number": f.frozen,
 					"hash":   hash,
-				}).Error("Block receipts missing, can't freeze")
+				}).Error("Block header missing, can't freeze")
 				break
 			}
+			body, _ := nfdb.Get(workObjectBodyKey(hash))
+			if len(body) == 0 {
+				f.logger.WithFields(log.Fields{
+					"number": f.frozen,
+					"hash":   hash,
+				}).Error("Block body missing, can't freeze")
+				break
+			}
+			// Blocks without transactions have empty receipts, and the termini
+			// and manifests are not stored for every block
+			receipts := ReadReceiptsProto(nfdb, hash, f.frozen)
+			termini, _ := nfdb.Get(terminiKey(hash))
+			manifest, _ := nfdb.Get(manifestKey(hash))
 			f.logger.WithFields(log.Fields{
 				"number": f.frozen,
 				"hash":   hash,
 			}).Trace("Deep froze ancient block")
 			// Inject all the components into the relevant data tables
-			if err := f.AppendAncient(f.frozen, hash[:], receipts); err != nil {
+			if err := f.AppendAncient(f.frozen, hash[:], header, body, receipts, termini, manifest); err != nil {
 				break
 			}
 			ancients = append(ancients, hash)
@@ -396,6 +414,8 @@ func (f *freezer) freeze(db ethdb.KeyValueStore, nodeCtx int, location common.Lo
 			// Always keep the genesis block in active database
 			if first+uint64(i) != 0 {
 				DeleteBlockWithoutNumber(batch, ancients[i], first+uint64(i), types.BlockObject)
+				DeleteTermini(batch, ancients[i])
+				DeleteManifest(batch, ancients[i])
 				DeleteCanonicalHash(batch, first+uint64(i))
 			}
 		}
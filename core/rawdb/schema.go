@@ -60,6 +60,10 @@ var (
 	// snapshotSyncStatusKey tracks the snapshot sync status across restarts.
 	snapshotSyncStatusKey = []byte("SnapshotSyncStatus")
 
+	// snapSyncPivotKey tracks the number of the block whose state is being
+	// snap synced across restarts.
+	snapSyncPivotKey = []byte("SnapSyncPivot")
+
 	// uncleanShutdownKey tracks the list of local crashes
 	uncleanShutdownKey = []byte("unclean-shutdown") // config prefix for the db
 
@@ -83,6 +87,7 @@ var (
 	inboundEtxsPrefix       = []byte("ie")    // inboundEtxsPrefix + hash -> types.Transactions
 	AddressUtxosPrefix      = []byte("au")    // addressUtxosPrefix + address -> []types.UtxoEntry
 	AddressLockupsPrefix    = []byte("al")    // addressLockupsPrefix + address -> []types.Lockup
+	workShareRewardsPrefix  = []byte("wr")    // workShareRewardsPrefix + address + num (uint64 big endian) -> []types.WorkShareReward
 	utxoToBlockHeightPrefix = []byte("ub")    // utxoToBlockHeightPrefix + hash -> uint64
 	processedStatePrefix    = []byte("ps")    // processedStatePrefix + hash -> boolean
 	multiSetPrefix          = []byte("ms")    // multiSetPrefix + hash -> multiset
@@ -120,23 +125,31 @@ const (
 	// freezerHashTable indicates the name of the freezer canonical hash table.
 	freezerHashTable = "hashes"
 
+	// freezerHeaderTable indicates the name of the freezer work object header table.
+	freezerHeaderTable = "headers"
+
 	// freezerBodiesTable indicates the name of the freezer block body table.
 	freezerBodiesTable = "bodies"
 
 	// freezerReceiptTable indicates the name of the freezer receipts table.
 	freezerReceiptTable = "receipts"
 
-	// freezerDifficultyTable indicates the name of the freezer total difficulty table.
-	freezerDifficultyTable = "diffs"
+	// freezerTerminiTable indicates the name of the freezer termini table.
+	freezerTerminiTable = "termini"
+
+	// freezerManifestTable indicates the name of the freezer manifest table.
+	freezerManifestTable = "manifests"
 )
 
 // FreezerNoSnappy configures whether compression is disabled for the ancient-tables.
-// Hashes and difficulties don't compress well.
+// Hashes, termini and manifests don't compress well.
 var FreezerNoSnappy = map[string]bool{
-	freezerHashTable:       true,
-	freezerBodiesTable:     false,
-	freezerReceiptTable:    false,
-	freezerDifficultyTable: true,
+	freezerHashTable:     true,
+	freezerHeaderTable:   false,
+	freezerBodiesTable:   false,
+	freezerReceiptTable:  false,
+	freezerTerminiTable:  true,
+	freezerManifestTable: true,
 }
 
 // LegacyTxLookupEntry is the legacy TxLookupEntry definition with some unnecessary
@@ -312,6 +325,11 @@ func addressLockupsKey(address [20]byte) []byte {
 	return append(AddressLockupsPrefix, address[:]...)
 }
 
+// workShareRewardsKey = workShareRewardsPrefix + address + num (uint64 big endian)
+func workShareRewardsKey(address common.Address, number uint64) []byte {
+	return append(append(append([]byte{}, workShareRewardsPrefix...), address.Bytes()...), encodeBlockNumber(number)...)
+}
+
 var UtxoKeyLength = len(UtxoPrefix) + common.HashLength + 2
 
 // This can be optimized via VLQ encoding as btcd has done
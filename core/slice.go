@@ -52,6 +52,8 @@ type CoreBackend interface {
 	Append(header *types.WorkObject, manifest types.BlockManifest, domTerminus common.Hash, domOrigin bool, newInboundEtxs types.Transactions) (types.Transactions, error)
 	DownloadBlocksInManifest(hash common.Hash, manifest types.BlockManifest, entropy *big.Int)
 	GenerateRecoveryPendingHeader(pendingHeader *types.WorkObject, checkpointHashes types.Termini) error
+	SetHead(hash common.Hash) error
+	SetHeadFromDom(domTerminus common.Hash, domPendingHeader *types.WorkObject) error
 	GetPendingEtxsRollupFromSub(hash common.Hash, location common.Location) (types.PendingEtxsRollup, error)
 	GetPendingEtxsFromSub(hash common.Hash, location common.Location) (types.PendingEtxs, error)
 	NewGenesisPendingHeader(pendingHeader *types.WorkObject, domTerminus common.Hash, hash common.Hash) error
@@ -154,6 +156,10 @@ func NewSlice(db ethdb.Database, config *Config, txConfig *TxPoolConfig, txLooku
 	}
 
 	if nodeCtx == common.ZONE_CTX && sl.ProcessingState() {
+		// Subscribe to the AsyncPh updates from the worker before the loop
+		// starts, so that the slice can be stopped right away
+		sl.asyncPhCh = make(chan *types.WorkObject, c_asyncPhUpdateChanSize)
+		sl.asyncPhSub = sl.miner.worker.SubscribeAsyncPendingHeader(sl.asyncPhCh)
 		go sl.asyncPendingHeaderLoop()
 		go sl.asyncWorkShareUpdateLoop()
 	}
@@ -455,10 +461,6 @@ func (sl *Slice) asyncPendingHeaderLoop() {
 		}
 	}()
 
-	// Subscribe to the AsyncPh updates from the worker
-	sl.asyncPhCh = make(chan *types.WorkObject, c_asyncPhUpdateChanSize)
-	sl.asyncPhSub = sl.miner.worker.SubscribeAsyncPendingHeader(sl.asyncPhCh)
-
 	for {
 		select {
 		case asyncPh := <-sl.asyncPhCh:
@@ -640,7 +642,11 @@ func (sl *Slice) pcrc(batch ethdb.Batch, header *types.WorkObject, domTerminus c
 
 // GetPendingHeader is used by the miner to request the current pending header
 func (sl *Slice) GetPendingHeader() (*types.WorkObject, error) {
-	phCopy := types.CopyWorkObject(sl.ReadBestPh())
+	bestPh := sl.ReadBestPh()
+	if bestPh == nil {
+		return nil, errors.New("no pending header found")
+	}
+	phCopy := types.CopyWorkObject(bestPh)
 	return phCopy, nil
 }
 
@@ -1186,7 +1192,10 @@ func (sl *Slice) Stop() {
 	rawdb.WriteBadHashesList(sl.sliceDb, badHashes)
 	sl.miner.worker.StorePendingBlockBody()
 
-	rawdb.WriteBestPendingHeader(sl.sliceDb, sl.ReadBestPh())
+	// The slice may be stopped before the genesis pending header reached it
+	if bestPh := sl.ReadBestPh(); bestPh != nil {
+		rawdb.WriteBestPendingHeader(sl.sliceDb, bestPh)
+	}
 
 	sl.scope.Close()
 	close(sl.quit)
@@ -1382,6 +1391,222 @@ func (sl *Slice) ComputeRecoveryPendingHeader(hash common.Hash) types.PendingHea
 	return types.NewPendingHeader(pendingHeader, *termini)
 }
 
+// SetHead rewinds the slice to the given canonical block, deleting all the
+// blocks above it together with their canonical mappings, utxo data and
+// indexed outpoints. If some of the deleted blocks were also blocks of the
+// dom, the dom is rewound to the parent of the first of them. The subs are
+// rewound so that they keep no block the slice deleted, and the pending
+// headers of the slice and its subs are recomputed on the new heads.
+func (sl *Slice) SetHead(hash common.Hash) error {
+	nodeCtx := sl.NodeCtx()
+	rewound, err := sl.rewind(hash)
+	if err != nil {
+		return err
+	}
+	if nodeCtx != common.PRIME_CTX && sl.domInterface != nil {
+		var domHead common.Hash
+		for _, header := range rewound {
+			_, order, err := sl.CalcOrder(header)
+			if err != nil {
+				return err
+			}
+			if order < nodeCtx {
+				domHead = header.ParentHash(nodeCtx - 1)
+			}
+		}
+		if domHead != (common.Hash{}) {
+			// The dom rewinds its subs and recomputes their pending headers
+			// once it is rewound, which includes this slice
+			return sl.domInterface.SetHead(domHead)
+		}
+	}
+	return sl.updateRewoundPendingHeaders(sl.ReadBestPh())
+}
+
+// SetHeadFromDom is called by the dom once it was rewound, with the last block
+// of this slice the dom still has. The blocks above it which were also dom
+// blocks are deleted, and the pending headers of the slice and its subs are
+// recomputed on top of the given dom pending header.
+func (sl *Slice) SetHeadFromDom(domTerminus common.Hash, domPendingHeader *types.WorkObject) error {
+	nodeCtx := sl.NodeCtx()
+	terminus := sl.hc.GetHeaderByHash(domTerminus)
+	if terminus == nil {
+		return fmt.Errorf("dom terminus %s not found", domTerminus)
+	}
+	if rawdb.ReadCanonicalHash(sl.sliceDb, terminus.NumberU64(nodeCtx)) != domTerminus {
+		return fmt.Errorf("dom terminus %s is not canonical", domTerminus)
+	}
+	var head common.Hash
+	for header := sl.hc.CurrentHeader(); header.NumberU64(nodeCtx) > terminus.NumberU64(nodeCtx); {
+		_, order, err := sl.CalcOrder(header)
+		if err != nil {
+			return err
+		}
+		if order < nodeCtx {
+			head = header.ParentHash(nodeCtx)
+		}
+		if header = sl.hc.GetHeaderByHash(header.ParentHash(nodeCtx)); header == nil {
+			return errors.New("could not find a canonical header above the dom terminus")
+		}
+	}
+	if head != (common.Hash{}) {
+		if _, err := sl.rewind(head); err != nil {
+			return err
+		}
+	}
+	return sl.updateRewoundPendingHeaders(domPendingHeader)
+}
+
+// rewind deletes the blocks of the slice above the given canonical block,
+// which becomes the new head, and returns the deleted headers from the newest
+// to the oldest.
+func (sl *Slice) rewind(hash common.Hash) ([]*types.WorkObject, error) {
+	nodeCtx := sl.NodeCtx()
+	sl.hc.headermu.Lock()
+	defer sl.hc.headermu.Unlock()
+
+	target := sl.hc.GetBlockByHash(hash)
+	if target == nil {
+		return nil, fmt.Errorf("block %s not found", hash)
+	}
+	if rawdb.ReadCanonicalHash(sl.sliceDb, target.NumberU64(nodeCtx)) != hash {
+		return nil, fmt.Errorf("block %s is not canonical", hash)
+	}
+	currentHeader := sl.hc.CurrentHeader()
+	if target.NumberU64(nodeCtx) > currentHeader.NumberU64(nodeCtx) {
+		return nil, fmt.Errorf("block %s is above the head", hash)
+	}
+	if err := sl.hc.checkUnfrozen(target.NumberU64(nodeCtx)); err != nil {
+		return nil, err
+	}
+	var rewound []*types.WorkObject
+	for header := currentHeader; header.Hash() != hash; {
+		rewound = append(rewound, header)
+		if header = sl.hc.GetHeaderByHash(header.ParentHash(nodeCtx)); header == nil {
+			return nil, errors.New("could not find a canonical header above the new head")
+		}
+	}
+	if len(rewound) == 0 {
+		return nil, nil
+	}
+	sl.logger.WithFields(log.Fields{
+		"number": target.NumberArray(),
+		"hash":   hash,
+		"blocks": len(rewound),
+	}).Warn("Rewinding the slice")
+
+	// The outpoint index is reverted first, as it needs the spent utxos of the
+	// deleted blocks and the canonical blocks below them
+	if nodeCtx == common.ZONE_CTX && sl.ProcessingState() && sl.config.IndexAddressUtxos {
+		if err := reorgUtxoIndex(sl.sliceDb, rewound, nodeCtx, sl.logger); err != nil {
+			return nil, err
+		}
+	}
+	if nodeCtx == common.ZONE_CTX && sl.ProcessingState() && sl.config.IndexWorkShares {
+		if err := unindexWorkShares(sl.sliceDb, rewound, nodeCtx, sl.logger); err != nil {
+			return nil, err
+		}
+	}
+	// Setting the head back deletes the canonical mappings above it and rolls
+	// the utxo set back
+	if err := sl.hc.SetCurrentHeader(target); err != nil {
+		return nil, err
+	}
+
+	batch := sl.sliceDb.NewBatch()
+	for _, header := range rewound {
+		number := header.NumberU64(nodeCtx)
+		if nodeCtx == common.ZONE_CTX {
+			if block := sl.hc.GetBlockByHash(header.Hash()); block != nil {
+				txHashes := make([]common.Hash, 0, len(block.Transactions()))
+				for _, tx := range block.Transactions() {
+					txHashes = append(txHashes, tx.Hash())
+				}
+				rawdb.DeleteTxLookupEntries(batch, txHashes)
+			}
+			rawdb.DeleteSpentUTXOs(batch, header.Hash())
+			rawdb.DeleteCreatedUTXOKeys(batch, header.Hash())
+			rawdb.DeleteTrimmedUTXOs(batch, header.Hash())
+			rawdb.DeleteMultiSet(batch, header.Hash())
+			rawdb.DeleteUTXOSetSize(batch, header.Hash())
+			rawdb.DeleteTokenChoicesSet(batch, header.Hash())
+			rawdb.DeleteBetas(batch, header.Hash())
+			rawdb.DeleteProcessedState(batch, header.Hash())
+		} else {
+			rawdb.DeletePendingEtxs(batch, header.Hash())
+			rawdb.DeletePendingEtxsRollup(batch, header.Hash())
+		}
+		if nodeCtx == common.PRIME_CTX {
+			rawdb.DeleteInterlinkHashes(batch, header.Hash())
+		}
+		rawdb.DeleteWorkObject(batch, header.Hash(), number, types.BlockObject)
+		rawdb.DeleteHeaderNumber(batch, header.Hash())
+		rawdb.DeleteTermini(batch, header.Hash())
+		rawdb.DeleteManifest(batch, header.Hash())
+		rawdb.DeleteInboundEtxs(batch, header.Hash())
+		rawdb.DeleteBloom(batch, header.Hash(), number)
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+
+	// slice caches
+	sl.miner.worker.pendingBlockBody.Purge()
+	// headerchain caches
+	sl.hc.headerCache.Purge()
+	sl.hc.numberCache.Purge()
+	sl.hc.pendingEtxsRollup.Purge()
+	sl.hc.pendingEtxs.Purge()
+	// bodydb caches
+	sl.hc.bc.blockCache.Purge()
+	sl.hc.bc.bodyCache.Purge()
+	sl.hc.bc.bodyProtoCache.Purge()
+
+	if nodeCtx == common.ZONE_CTX && sl.ProcessingState() {
+		// Recover the snaps if the new head is not covered by them anymore
+		processor := sl.hc.bc.processor
+		if processor.snaps == nil || processor.snaps.Snapshot(target.EVMRoot()) == nil {
+			processor.snaps, _ = snapshot.New(sl.sliceDb, processor.stateCache.TrieDB(), processor.cacheConfig.SnapshotLimit, target.EVMRoot(), true, true, sl.logger)
+		}
+	}
+	return rewound, nil
+}
+
+// updateRewoundPendingHeaders recomputes the pending header of the slice on its
+// head, combined with the given dom pending header, and passes it on to the
+// subs along with the last block of each sub the slice still has.
+func (sl *Slice) updateRewoundPendingHeaders(domPendingHeader *types.WorkObject) error {
+	nodeCtx := sl.NodeCtx()
+	head := sl.hc.GetBlockByHash(sl.hc.CurrentHeader().Hash())
+	if head == nil {
+		return errors.New("could not find the head block")
+	}
+	localPendingHeaderWithTermini := sl.ComputeRecoveryPendingHeader(head.Hash())
+	pendingHeader := localPendingHeaderWithTermini.WorkObject()
+	if pendingHeader == nil {
+		return errors.New("failed to generate the pending header of the new head")
+	}
+	if nodeCtx != common.PRIME_CTX && domPendingHeader != nil {
+		pendingHeader = sl.combinePendingHeader(pendingHeader, domPendingHeader, nodeCtx, true)
+	}
+	sl.SetBestPh(pendingHeader)
+	if nodeCtx == common.ZONE_CTX {
+		// The pool and the indexers follow the head through the chain head
+		// events
+		sl.hc.chainHeadFeed.Send(ChainHeadEvent{head})
+	} else {
+		for i, sub := range sl.subInterface {
+			if sub == nil {
+				continue
+			}
+			if err := sub.SetHeadFromDom(localPendingHeaderWithTermini.Termini().SubTerminiAtIndex(i), pendingHeader); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // AddToBadHashesList adds a given set of badHashes to the BadHashesList
 func (sl *Slice) AddToBadHashesList(badHashes []common.Hash) {
 	for _, hash := range badHashes {
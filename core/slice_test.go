@@ -0,0 +1,111 @@
+package core_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+// TestSliceSetHead verifies a zone rewound below blocks coordinated with its
+// doms deletes its blocks above the new head along with their state, and
+// rewinds the doms so that they keep none of the deleted blocks.
+func TestSliceSetHead(t *testing.T) {
+	location := common.Location{0, 0}
+	accounts := chaingen.Accounts(location, false, 3)
+	sender, recipient := accounts[1], accounts[2]
+	g, err := chaingen.New(chaingen.Config{
+		QuaiAlloc: map[common.Address]*big.Int{sender.Address: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))},
+	})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	zone := g.Core(location)
+	blocks, err := g.GenerateBlocks(location, 6, func(i int, b *chaingen.BlockGen) {
+		if i == 0 {
+			return
+		}
+		_, err := b.Transfer(sender, recipient.Address, big.NewInt(params.Ether))
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	// Every block above the new head is also a block of the doms it was
+	// coordinated with, so they are rewound as well
+	head, rewound := blocks[1], blocks[2:]
+	coordinated := false
+	for _, block := range rewound {
+		if g.Core(common.Location{}).GetHeaderByHash(block.Hash()) != nil {
+			coordinated = true
+		}
+	}
+	require.True(t, coordinated, "no block above the new head is a prime block")
+
+	require.Error(t, zone.SetHead(common.HexToHash("0x01")))
+	require.NoError(t, zone.SetHead(head.Hash()))
+
+	require.Equal(t, head.Hash(), zone.CurrentHeader().Hash())
+	db := g.Database(location)
+	for _, block := range rewound {
+		number := block.NumberU64(common.ZONE_CTX)
+		require.Equal(t, common.Hash{}, rawdb.ReadCanonicalHash(db, number))
+		require.Nil(t, zone.GetBlockByHash(block.Hash()))
+		require.Nil(t, rawdb.ReadTermini(db, block.Hash()))
+		require.Nil(t, rawdb.ReadMultiSet(db, block.Hash()))
+		for _, ctx := range []common.Location{{}, {0}} {
+			require.Nil(t, g.Core(ctx).GetHeaderByHash(block.Hash()), "block %d still in %s", number, ctx.Name())
+		}
+		for _, tx := range block.Transactions() {
+			require.Nil(t, rawdb.ReadTxLookupEntry(db, tx.Hash()))
+		}
+	}
+	for _, ctx := range []common.Location{{}, {0}} {
+		c := g.Core(ctx)
+		require.NotNil(t, zone.GetHeaderByHash(c.CurrentHeader().Hash()), "head of %s not in the zone", ctx.Name())
+	}
+
+	statedb, err := zone.State()
+	require.NoError(t, err)
+	internal, err := recipient.Address.InternalAndQuaiAddress()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(params.Ether), statedb.GetBalance(internal))
+
+	pendingHeader, err := zone.GetPendingHeader()
+	require.NoError(t, err)
+	require.Equal(t, head.Hash(), pendingHeader.ParentHash(common.ZONE_CTX))
+}
+
+// TestSliceSetHeadFrozen verifies a zone is not rewound below the blocks moved
+// to its ancient store, which would otherwise be read back as canonical.
+func TestSliceSetHeadFrozen(t *testing.T) {
+	location := common.Location{0, 0}
+	g, err := chaingen.New(chaingen.Config{Freezer: t.TempDir(), FreezerThreshold: 2})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	blocks, err := g.GenerateBlocks(location, 6, nil)
+	require.NoError(t, err)
+	db := g.Database(location)
+	require.NoError(t, db.(interface{ Freeze(uint64) error }).Freeze(2))
+	frozen, err := db.Ancients()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), frozen)
+
+	zone := g.Core(location)
+	head := zone.CurrentHeader()
+	require.ErrorIs(t, zone.SetHead(blocks[1].Hash()), core.ErrFrozenBlocks)
+	require.Equal(t, head.Hash(), zone.CurrentHeader().Hash())
+	require.Equal(t, blocks[2].Hash(), rawdb.ReadCanonicalHash(db, 3))
+
+	// The last frozen block can still become the head
+	require.NoError(t, zone.SetHead(blocks[3].Hash()))
+	require.Equal(t, blocks[3].Hash(), zone.CurrentHeader().Hash())
+	require.Equal(t, common.Hash{}, rawdb.ReadCanonicalHash(db, 5))
+	require.Nil(t, zone.GetBlockByHash(blocks[4].Hash()))
+}
@@ -0,0 +1,377 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus/misc"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto/multiset"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+const (
+	// c_utxoRangeSize is the number of UTXOs of the set read for a UTXO range
+	// request
+	c_utxoRangeSize = 2048
+	// c_utxoRangeMaxDepth is how far below the head a block can be to serve
+	// its UTXO set, as the set is rolled back from the head to serve it
+	c_utxoRangeMaxDepth = 1024
+	// c_utxoRollbackCacheSize is the number of UTXO set rollbacks cached for
+	// the blocks whose UTXO set is being served
+	c_utxoRollbackCacheSize = 4
+)
+
+var (
+	errNoUtxoSet        = errors.New("state is only kept by zones processing it")
+	errSnapSyncingState = errors.New("state is being snap synced")
+)
+
+// utxoRollback holds the UTXO set changes that roll the set at a head back to
+// the set at one of its ancestors. The keys of the UTXOs the rollback deletes
+// map to nil.
+type utxoRollback struct {
+	head    common.Hash
+	entries map[string]*types.UtxoEntry
+}
+
+// SnapSyncPivot returns the number of the block whose state is being snap
+// synced, or zero if there is no snap sync in progress.
+func (c *Core) SnapSyncPivot() uint64 {
+	if c.NodeCtx() != common.ZONE_CTX || !c.ProcessingState() {
+		return 0
+	}
+	return c.sl.hc.bc.snapSyncPivot.Load()
+}
+
+// SetSnapSyncPivot sets the number of the block whose state is snap synced.
+// The blocks up to the pivot are appended without processing their state, so
+// a snap sync can only start on a chain that has not processed any block yet,
+// and the pivot can only be moved to a block that is not appended yet.
+func (c *Core) SetSnapSyncPivot(number uint64) error {
+	if c.NodeCtx() != common.ZONE_CTX || !c.ProcessingState() {
+		return errNoUtxoSet
+	}
+	bc := c.sl.hc.bc
+	head := c.CurrentHeader().NumberU64(common.ZONE_CTX)
+	if bc.snapSyncPivot.Load() == 0 && head != 0 {
+		return errors.New("cannot snap sync a chain that has processed blocks")
+	}
+	if number == 0 || number < head {
+		return fmt.Errorf("snap sync pivot %d is behind the head %d", number, head)
+	}
+	rawdb.WriteSnapSyncPivot(c.sl.sliceDb, number)
+	bc.snapSyncPivot.Store(number)
+	return nil
+}
+
+// CommitSnapSync completes the snap sync at the pivot. The state tries and the
+// UTXO set at the pivot have to be downloaded and verified, with multiSet and
+// utxoSetSize their digest and size. The states are the block states of the
+// pivot and of the blocks below it within the trim depths. Once committed,
+// the blocks following the pivot are processed on top of the downloaded state.
+func (c *Core) CommitSnapSync(pivot *types.WorkObject, multiSet *multiset.MultiSet, utxoSetSize uint64, states map[common.Hash]*types.SyncBlockState) error {
+	bc := c.sl.hc.bc
+	number := pivot.NumberU64(common.ZONE_CTX)
+	if number == 0 || bc.snapSyncPivot.Load() != number {
+		return fmt.Errorf("block %d is not the snap sync pivot", number)
+	}
+	if rawdb.ReadCanonicalHash(c.sl.sliceDb, number) != pivot.Hash() {
+		return errors.New("snap sync pivot is not canonical")
+	}
+	if state := states[pivot.Hash()]; state == nil || state.TokenChoiceSet == nil || state.Betas == nil {
+		return errors.New("missing block state of the snap sync pivot")
+	}
+	batch := c.sl.sliceDb.NewBatch()
+	for hash, state := range states {
+		if state.TokenChoiceSet != nil {
+			if err := rawdb.WriteTokenChoicesSet(batch, hash, state.TokenChoiceSet); err != nil {
+				return err
+			}
+		}
+		if state.Betas != nil {
+			if err := rawdb.WriteBetas(batch, hash, state.Betas.Beta0(), state.Betas.Beta1()); err != nil {
+				return err
+			}
+		}
+		if err := rawdb.WriteCreatedUTXOKeys(batch, hash, state.CreatedUtxoKeys); err != nil {
+			return err
+		}
+	}
+	rawdb.WriteMultiSet(batch, pivot.Hash(), multiSet)
+	rawdb.WriteUTXOSetSize(batch, pivot.Hash(), utxoSetSize)
+	rawdb.WriteProcessedState(batch, pivot.Hash())
+	rawdb.DeleteSnapSyncPivot(batch)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	bc.snapSyncPivot.Store(0)
+	// The snapshot tree was loaded for the genesis state, so regenerate it
+	// from the downloaded state
+	if snaps := bc.processor.snaps; snaps != nil {
+		snaps.Rebuild(pivot.EVMRoot())
+	}
+	c.logger.WithFields(log.Fields{
+		"number":      number,
+		"hash":        pivot.Hash(),
+		"utxoSetSize": utxoSetSize,
+	}).Info("Snap sync complete, processing the following blocks")
+	return nil
+}
+
+// StateTrieNode returns the data a snap syncing node downloads for the given
+// hash, which is a node of the state or ETX set trie, or contract code.
+func (c *Core) StateTrieNode(hash common.Hash) ([]byte, error) {
+	if c.NodeCtx() != common.ZONE_CTX || !c.ProcessingState() {
+		return nil, errNoUtxoSet
+	}
+	processor := c.sl.hc.bc.processor
+	if data, err := processor.TrieNode(hash); err == nil && len(data) > 0 {
+		return data, nil
+	}
+	if data, err := processor.etxCache.TrieDB().Node(hash); err == nil && len(data) > 0 {
+		return data, nil
+	}
+	return processor.ContractCode(hash)
+}
+
+// SyncBlockState returns the block state a snap syncing node downloads for the
+// given block, or nil if the state of the block is not known.
+func (c *Core) SyncBlockState(hash common.Hash) *types.SyncBlockState {
+	if c.NodeCtx() != common.ZONE_CTX || !c.ProcessingState() {
+		return nil
+	}
+	tokenChoiceSet := rawdb.ReadTokenChoicesSet(c.sl.sliceDb, hash)
+	betas := rawdb.ReadBetas(c.sl.sliceDb, hash)
+	if tokenChoiceSet == nil || betas == nil {
+		return nil
+	}
+	createdUtxoKeys, err := rawdb.ReadCreatedUTXOKeys(c.sl.sliceDb, hash)
+	if err != nil {
+		return nil
+	}
+	return &types.SyncBlockState{
+		TokenChoiceSet:  tokenChoiceSet,
+		Betas:           betas,
+		CreatedUtxoKeys: createdUtxoKeys,
+	}
+}
+
+// CreatedUtxoKeys rebuilds from the body of the block the keys of the UTXOs
+// that processing it creates, which are the outputs of its Qi transactions to
+// the zone and the UTXOs paid by the ETXs it includes to the Qi ledger. A snap
+// syncing node appends the blocks below the pivot without processing them, so
+// it checks the keys served by its peers against them. The keys are sorted by
+// denomination, as they are stored.
+func CreatedUtxoKeys(block *types.WorkObject, location common.Location) ([][]byte, error) {
+	number := block.NumberU64(common.ZONE_CTX)
+	keys := make([][]byte, 0)
+	for _, tx := range block.Transactions() {
+		switch {
+		case tx.Type() == types.QiTxType:
+			for i, txOut := range tx.TxOut() {
+				to := common.BytesToAddress(txOut.Address, location)
+				if to.Location().Equal(location) && to.IsInQiLedgerScope() {
+					keys = append(keys, rawdb.UtxoKeyWithDenomination(tx.Hash(), uint16(i), txOut.Denomination))
+				}
+			}
+		case tx.Type() != types.ExternalTxType || tx.To() == nil || !tx.To().IsInQiLedgerScope():
+			continue
+		case types.IsCoinBaseTx(tx):
+			if len(tx.Data()) == 0 || int(tx.Data()[0]) > len(params.LockupByteToBlockDepth)-1 {
+				return nil, fmt.Errorf("coinbase tx %x has an invalid lockup byte", tx.Hash())
+			}
+			value := params.CalculateCoinbaseValueWithLockup(tx.Value(), tx.Data()[0], number)
+			keys = appendDenominationKeys(keys, tx.Hash(), value, nil)
+		case tx.ETXSender().Location().Equal(*tx.To().Location()):
+			// Quai to Qi conversions create UTXOs until their gas runs out
+			if tx.Gas() < params.TxGas {
+				continue
+			}
+			gas := tx.Gas() - params.TxGas
+			keys = appendDenominationKeys(keys, tx.Hash(), tx.Value(), &gas)
+		default:
+			keys = append(keys, rawdb.UtxoKeyWithDenomination(tx.OriginatingTxHash(), tx.ETXIndex(), uint8(tx.Value().Uint64())))
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i][len(keys[i])-1] < keys[j][len(keys[j])-1]
+	})
+	return keys, nil
+}
+
+// appendDenominationKeys appends the keys of the UTXOs an ETX splits its value
+// into, the largest denominations first. If gas is given, each UTXO costs the
+// gas of a value transfer, and no more are created once it runs out.
+func appendDenominationKeys(keys [][]byte, hash common.Hash, value *big.Int, gas *uint64) [][]byte {
+	denominations := misc.FindMinDenominations(value)
+	index := uint16(0)
+	for denomination := types.MaxDenomination; denomination >= 0; denomination-- {
+		for j := uint64(0); j < denominations[uint8(denomination)]; j++ {
+			if index >= types.MaxOutputIndex || (gas != nil && *gas < params.CallValueTransferGas) {
+				return keys
+			}
+			if gas != nil {
+				*gas -= params.CallValueTransferGas
+			}
+			keys = append(keys, rawdb.UtxoKeyWithDenomination(hash, index, uint8(denomination)))
+			index++
+		}
+	}
+	return keys
+}
+
+// UtxoRange returns the UTXOs of the set at the given canonical block starting
+// at the given outpoint. The set is rolled back from the current head to the
+// block, which hence has to be at most c_utxoRangeMaxDepth below it.
+func (c *Core) UtxoRange(blockHash common.Hash, start types.OutPoint) (*types.UtxoRange, error) {
+	if c.NodeCtx() != common.ZONE_CTX || !c.ProcessingState() {
+		return nil, errNoUtxoSet
+	}
+	if c.SnapSyncPivot() != 0 {
+		return nil, errSnapSyncingState
+	}
+	rollback, err := c.utxoRollbackTo(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	startKey := rawdb.UtxoKey(start.TxHash, start.Index)
+	it := c.sl.sliceDb.NewIterator(rawdb.UtxoPrefix, startKey[len(rawdb.UtxoPrefix):])
+	defer it.Release()
+
+	// Read up to c_utxoRangeSize UTXOs of the current set, the key following
+	// them bounding the range served
+	utxos := make(map[string]*types.UtxoEntry)
+	var next []byte
+	for count := 0; it.Next(); {
+		key := it.Key()
+		if len(key) != rawdb.UtxoKeyLength {
+			continue
+		}
+		if count == c_utxoRangeSize {
+			next = common.CopyBytes(key)
+			break
+		}
+		count++
+		if _, exists := rollback[string(key)]; exists {
+			continue
+		}
+		utxoProto := new(types.ProtoTxOut)
+		if err := proto.Unmarshal(it.Value(), utxoProto); err != nil {
+			return nil, err
+		}
+		utxo := new(types.UtxoEntry)
+		if err := utxo.ProtoDecode(utxoProto); err != nil {
+			return nil, err
+		}
+		utxos[string(key)] = utxo
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	// Roll the range back to the set at the block
+	for key, utxo := range rollback {
+		if utxo == nil || bytes.Compare([]byte(key), startKey) < 0 || (next != nil && bytes.Compare([]byte(key), next) >= 0) {
+			continue
+		}
+		utxos[key] = utxo
+	}
+	keys := make([]string, 0, len(utxos))
+	for key := range utxos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	utxoRange := &types.UtxoRange{Utxos: make([]*types.SpentUtxoEntry, 0, len(keys))}
+	for _, key := range keys {
+		txHash, index, err := rawdb.ReverseUtxoKey([]byte(key))
+		if err != nil {
+			return nil, err
+		}
+		utxoRange.Utxos = append(utxoRange.Utxos, &types.SpentUtxoEntry{OutPoint: types.OutPoint{TxHash: txHash, Index: index}, UtxoEntry: utxos[key]})
+	}
+	if next != nil {
+		txHash, index, err := rawdb.ReverseUtxoKey(next)
+		if err != nil {
+			return nil, err
+		}
+		utxoRange.Next = &types.OutPoint{TxHash: txHash, Index: index}
+	}
+	return utxoRange, nil
+}
+
+// utxoRollbackTo returns the UTXO set changes rolling the set at the current
+// head back to the set at the given canonical block, which has to be at most
+// c_utxoRangeMaxDepth below the head.
+func (c *Core) utxoRollbackTo(blockHash common.Hash) (map[string]*types.UtxoEntry, error) {
+	nodeCtx := c.NodeCtx()
+	block := c.GetHeaderByHash(blockHash)
+	if block == nil || rawdb.ReadCanonicalHash(c.sl.sliceDb, block.NumberU64(nodeCtx)) != blockHash {
+		return nil, errors.New("block is not canonical")
+	}
+	head := c.CurrentHeader()
+	if head.NumberU64(nodeCtx) < block.NumberU64(nodeCtx) {
+		return nil, errors.New("block is ahead of the head")
+	}
+	if head.NumberU64(nodeCtx)-block.NumberU64(nodeCtx) > c_utxoRangeMaxDepth {
+		return nil, fmt.Errorf("block is more than %d blocks below the head", c_utxoRangeMaxDepth)
+	}
+	if rollback, exists := c.utxoRollbacks.Get(blockHash); exists && rollback.head == head.Hash() {
+		return rollback.entries, nil
+	}
+	entries, err := readUtxoRollback(c.sl.sliceDb, head, blockHash, nodeCtx)
+	if err != nil {
+		return nil, err
+	}
+	c.utxoRollbacks.Add(blockHash, utxoRollback{head: head.Hash(), entries: entries})
+	return entries, nil
+}
+
+// readUtxoRollback returns the UTXO set changes rolling the set at the given
+// head back to the set at its ancestor with the given hash. The spent and
+// trimmed UTXOs of each block are restored and the UTXOs it created are
+// deleted, from the head down to the ancestor. The keys of the deleted UTXOs
+// map to nil.
+func readUtxoRollback(db ethdb.Reader, head *types.WorkObject, blockHash common.Hash, nodeCtx int) (map[string]*types.UtxoEntry, error) {
+	entries := make(map[string]*types.UtxoEntry)
+	for header := head; header.Hash() != blockHash; {
+		spent, err := rawdb.ReadSpentUTXOs(db, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		trimmed, err := rawdb.ReadTrimmedUTXOs(db, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		for _, sutxo := range append(spent, trimmed...) {
+			entries[string(rawdb.UtxoKey(sutxo.TxHash, sutxo.Index))] = sutxo.UtxoEntry
+		}
+		created, err := rawdb.ReadCreatedUTXOKeys(db, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range created {
+			if len(key) == rawdb.UtxoKeyWithDenominationLength {
+				key = key[:rawdb.UtxoKeyLength] // The last byte of the key is the denomination
+			}
+			entries[string(key)] = nil
+		}
+		if header.NumberU64(nodeCtx) == 0 {
+			return nil, errors.New("block is not an ancestor of the head")
+		}
+		header = rawdb.ReadHeader(db, header.NumberU64(nodeCtx)-1, header.ParentHash(nodeCtx))
+		if header == nil {
+			return nil, errors.New("missing ancestor of the head")
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,82 @@
+package core_test
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+// sortedKeys returns a copy of the keys in byte order.
+func sortedKeys(keys [][]byte) [][]byte {
+	sorted := append([][]byte{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	return sorted
+}
+
+// TestCreatedUtxoKeys verifies the created UTXO keys rebuilt from the body of
+// a block are the keys stored when it is processed, for the outputs of Qi
+// transactions, Quai to Qi conversions and Qi coinbases.
+func TestCreatedUtxoKeys(t *testing.T) {
+	location := common.Location{0, 0}
+	quaiSender := chaingen.Accounts(location, false, 2)[1]
+	qiAccounts := chaingen.Accounts(location, true, 4)
+	qiSender, qiRecipient, converted := qiAccounts[1], qiAccounts[2], qiAccounts[3]
+	g, err := chaingen.New(chaingen.Config{
+		MinerPreference: 1,
+		QuaiAlloc:       map[common.Address]*big.Int{quaiSender.Address: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))},
+		QiAlloc:         map[common.Address]uint8{qiSender.Address: types.MaxDenomination},
+	})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	blocks, err := g.GenerateBlocks(location, 12, func(i int, b *chaingen.BlockGen) {
+		switch i {
+		case 1:
+			_, err := b.SpendQi(qiSender, chaingen.GenesisOutPoint(qiSender.Address), qiRecipient.Address, types.MaxDenomination-1)
+			require.NoError(t, err)
+		case 2:
+			_, err := b.Transfer(quaiSender, converted.Address, new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether)))
+			require.NoError(t, err)
+		}
+	})
+	require.NoError(t, err)
+
+	db := g.Database(location)
+	var created int
+	kinds := make(map[string]bool)
+	for _, block := range blocks {
+		stored, err := rawdb.ReadCreatedUTXOKeys(db, block.Hash())
+		require.NoError(t, err)
+		keys, err := core.CreatedUtxoKeys(block, location)
+		require.NoError(t, err)
+		require.Equal(t, sortedKeys(stored), sortedKeys(keys), "block %d", block.NumberU64(common.ZONE_CTX))
+		for i := 1; i < len(keys); i++ {
+			require.LessOrEqual(t, keys[i-1][len(keys[i-1])-1], keys[i][len(keys[i])-1])
+		}
+		created += len(keys)
+		for _, tx := range block.Transactions() {
+			switch {
+			case tx.Type() == types.QiTxType:
+				kinds["qi"] = true
+			case tx.Type() == types.ExternalTxType && types.IsCoinBaseTx(tx) && tx.To().IsInQiLedgerScope():
+				kinds["coinbase"] = true
+			case tx.Type() == types.ExternalTxType && types.IsConversionTx(tx) && tx.To().IsInQiLedgerScope():
+				kinds["conversion"] = true
+			}
+		}
+	}
+	require.NotZero(t, created)
+	for _, kind := range []string{"qi", "coinbase", "conversion"} {
+		require.True(t, kinds[kind], "no %s UTXO created", kind)
+	}
+}
@@ -0,0 +1,49 @@
+package core_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/state/snapshot"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/trie"
+)
+
+// TestStopJournalsSnapshot verifies a stopped zone journals its snapshot, so
+// that the diff layers of its last blocks are loaded again instead of the
+// snapshot being regenerated at the head, which the state pruning relies on to
+// pick its target.
+func TestStopJournalsSnapshot(t *testing.T) {
+	location := common.Location{0, 0}
+	accounts := chaingen.Accounts(location, false, 3)
+	sender, recipient := accounts[1], accounts[2]
+	g, err := chaingen.New(chaingen.Config{
+		QuaiAlloc: map[common.Address]*big.Int{sender.Address: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))},
+	})
+	require.NoError(t, err)
+	blocks, err := g.GenerateBlocks(location, 4, func(i int, b *chaingen.BlockGen) {
+		if i == 0 {
+			return
+		}
+		_, err := b.Transfer(sender, recipient.Address, big.NewInt(params.Ether))
+		require.NoError(t, err)
+	})
+	g.Stop()
+	require.NoError(t, err)
+
+	db := g.Database(location)
+	require.NotEmpty(t, rawdb.ReadSnapshotJournal(db))
+	head, parent := blocks[len(blocks)-1], blocks[len(blocks)-2]
+	require.NotEqual(t, head.EVMRoot(), parent.EVMRoot())
+
+	snaps, err := snapshot.New(db, trie.NewDatabase(db), 256, head.EVMRoot(), false, false, log.Global)
+	require.NoError(t, err)
+	require.NotNil(t, snaps.Snapshot(head.EVMRoot()))
+	require.NotNil(t, snaps.Snapshot(parent.EVMRoot()), "diff layer of the parent not journaled")
+}
@@ -71,22 +71,36 @@ var (
 //   - iterate the database, delete all other state entries which
 //     don't belong to the target state and the genesis state
 //
+// Unlike the state of a node that keeps its tries in memory, the state of
+// every processed block is committed to disk, and the chain is processed on
+// top of the state of its head. So besides the target state, the states of
+// all the blocks from the target to the head are kept, as well as their ETX
+// set tries which are stored alongside the state tries.
+//
 // It can take several hours(around 2 hours for mainnet) to finish
 // the whole pruning work. It's recommended to run this offline tool
 // periodically in order to release the disk usage and improve the
 // disk read performance to some extent.
 type Pruner struct {
-	db            ethdb.Database
-	stateBloom    *stateBloom
-	datadir       string
-	trieCachePath string
-	headHeader    *types.Header
-	snaptree      *snapshot.Tree
-	logger        *log.Logger
+	db             ethdb.Database
+	stateBloom     *stateBloom
+	datadir        string
+	trieCachePaths []string
+	headBlock      *types.WorkObject
+	snaptree       *snapshot.Tree
+	logger         *log.Logger
+}
+
+// PruneStats holds the number and size of the state entries a pruning deleted,
+// or would delete in a dry run.
+type PruneStats struct {
+	Nodes int
+	Size  common.StorageSize
 }
 
-// NewPruner creates the pruner instance.
-func NewPruner(db ethdb.Database, datadir, trieCachePath string, bloomSize uint64, logger *log.Logger, location common.Location) (*Pruner, error) {
+// NewPruner creates the pruner instance. The trie cache paths are the journals
+// of the clean trie caches, which are deleted before pruning.
+func NewPruner(db ethdb.Database, datadir string, trieCachePaths []string, bloomSize uint64, logger *log.Logger, location common.Location) (*Pruner, error) {
 	headBlock := rawdb.ReadHeadBlock(db)
 	if headBlock == nil {
 		return nil, errors.New("failed to load head block")
@@ -108,17 +122,20 @@ func NewPruner(db ethdb.Database, datadir, trieCachePath string, bloomSize uint6
 		return nil, err
 	}
 	return &Pruner{
-		db:            db,
-		stateBloom:    stateBloom,
-		datadir:       datadir,
-		trieCachePath: trieCachePath,
-		headHeader:    headBlock.Header(),
-		snaptree:      snaptree,
-		logger:        logger,
+		db:             db,
+		stateBloom:     stateBloom,
+		datadir:        datadir,
+		trieCachePaths: trieCachePaths,
+		headBlock:      headBlock,
+		snaptree:       snaptree,
+		logger:         logger,
 	}, nil
 }
 
-func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, stateBloom *stateBloom, bloomPath string, middleStateRoots map[common.Hash]struct{}, start time.Time, logger *log.Logger) error {
+// sweep iterates the database and deletes all the state entries which are not
+// in the state bloom. If dryRun is set nothing is deleted, and only the
+// entries that would be deleted are counted.
+func sweep(maindb ethdb.Database, stateBloom *stateBloom, dryRun bool, logger *log.Logger) (PruneStats, error) {
 	// Delete all stale trie nodes in the disk. With the help of state bloom
 	// the trie nodes(and codes) belong to the active state will be filtered
 	// out. A very small part of stale tries will also be filtered because of
@@ -127,13 +144,13 @@ func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, sta
 	// dangling node is the state root is super low. So the dangling nodes in
 	// theory will never ever be visited again.
 	var (
-		count  int
-		size   common.StorageSize
+		stats  PruneStats
 		pstart = time.Now()
 		logged = time.Now()
 		batch  = maindb.NewBatch()
 		iter   = maindb.NewIterator(nil, nil)
 	)
+	defer func() { iter.Release() }()
 	for iter.Next() {
 		key := iter.Key()
 
@@ -147,18 +164,16 @@ func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, sta
 			if isCode {
 				checkKey = codeKey
 			}
-			if _, exist := middleStateRoots[common.BytesToHash(checkKey)]; exist {
-				logger.WithField("hash", common.BytesToHash(checkKey)).Debug("Forcibly delete the middle state roots")
-			} else {
-				if ok, err := stateBloom.Contain(checkKey); err != nil {
-					return err
-				} else if ok {
-					continue
-				}
+			if ok, err := stateBloom.Contain(checkKey); err != nil {
+				return stats, err
+			} else if ok {
+				continue
+			}
+			stats.Nodes += 1
+			stats.Size += common.StorageSize(len(key) + len(iter.Value()))
+			if !dryRun {
+				batch.Delete(common.CopyBytes(key))
 			}
-			count += 1
-			size += common.StorageSize(len(key) + len(iter.Value()))
-			batch.Delete(key)
 
 			var eta time.Duration // Realistically will never remain uninited
 			if done := binary.BigEndian.Uint64(key[:8]); done > 0 {
@@ -170,17 +185,20 @@ func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, sta
 			}
 			if time.Since(logged) > 8*time.Second {
 				logger.WithFields(log.Fields{
-					"nodes":   count,
-					"size":    size,
+					"nodes":   stats.Nodes,
+					"size":    stats.Size,
 					"elapsed": common.PrettyDuration(time.Since(pstart)),
 					"eta":     common.PrettyDuration(eta),
+					"dryrun":  dryRun,
 				}).Info("Pruning state data")
 				logged = time.Now()
 			}
 			// Recreate the iterator after every batch commit in order
 			// to allow the underlying compactor to delete the entries.
 			if batch.ValueSize() >= ethdb.IdealBatchSize {
-				batch.Write()
+				if err := batch.Write(); err != nil {
+					return stats, err
+				}
 				batch.Reset()
 
 				iter.Release()
@@ -188,29 +206,49 @@ func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, sta
 			}
 		}
 	}
+	if err := iter.Error(); err != nil {
+		return stats, err
+	}
 	if batch.ValueSize() > 0 {
-		batch.Write()
+		if err := batch.Write(); err != nil {
+			return stats, err
+		}
 		batch.Reset()
 	}
-	iter.Release()
 	logger.WithFields(log.Fields{
-		"nodes":   count,
-		"size":    size,
+		"nodes":   stats.Nodes,
+		"size":    stats.Size,
 		"elapsed": common.PrettyDuration(time.Since(pstart)),
+		"dryrun":  dryRun,
 	}).Info("Pruned state data")
+	return stats, nil
+}
 
+func prune(snaptree *snapshot.Tree, root common.Hash, headRoot common.Hash, maindb ethdb.Database, stateBloom *stateBloom, bloomPath string, start time.Time, logger *log.Logger) (PruneStats, error) {
+	stats, err := sweep(maindb, stateBloom, false, logger)
+	if err != nil {
+		return stats, err
+	}
 	// Pruning is done, now drop the "useless" layers from the snapshot.
-	// Firstly, flushing the target layer into the disk. After that all
-	// diff layers below the target will all be merged into the disk.
-	if err := snaptree.Cap(root, 0); err != nil {
-		return err
+	// The diff layers from the head down to the target are kept, as their
+	// states are, and the layers below them are flattened into the disk
+	// layer, which then holds the target state.
+	if layers := snaptree.Snapshots(headRoot, -1, true); len(layers) > 0 {
+		depth := len(layers)
+		for i, layer := range layers {
+			if layer.Root() == root {
+				depth = i
+				break
+			}
+		}
+		if err := snaptree.Cap(headRoot, depth); err != nil {
+			return stats, err
+		}
 	}
-	// Secondly, flushing the snapshot journal into the disk. All diff
-	// layers upon are dropped silently. Eventually the entire snapshot
-	// tree is converted into a single disk layer with the pruning target
-	// as the root.
-	if _, err := snaptree.Journal(root); err != nil {
-		return err
+	// Secondly, flushing the snapshot journal into the disk, so that the
+	// snapshot is loaded at the head on the next start.
+	if _, err := snaptree.Journal(headRoot); err != nil {
+		return stats, err
 	}
 	// Delete the state bloom, it marks the entire pruning procedure is
 	// finished. If any crashes or manual exit happens before this,
@@ -220,7 +258,7 @@ func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, sta
 
 	// Start compactions, will remove the deleted data from the disk immediately.
 	// Note for small pruning, the compaction is skipped.
-	if count >= rangeCompactionThreshold {
+	if stats.Nodes >= rangeCompactionThreshold {
 		cstart := time.Now()
 		for b := 0x00; b <= 0xf0; b += 0x10 {
 			var (
@@ -236,129 +274,155 @@ func prune(snaptree *snapshot.Tree, root common.Hash, maindb ethdb.Database, sta
 			}).Info("Compacting database")
 			if err := maindb.Compact(start, end); err != nil {
 				logger.WithField("err", err).Error("Database compaction failed")
-				return err
+				return stats, err
 			}
 		}
 		logger.WithField("elapsed", common.PrettyDuration(time.Since(cstart))).Info("Database compaction finished")
 	}
 	logger.WithFields(log.Fields{
-		"pruned":  size,
+		"pruned":  stats.Size,
 		"elapsed": common.PrettyDuration(time.Since(start)),
 	}).Info("State pruning successful")
-	return nil
+	return stats, nil
+}
+
+// retainedBlocks returns the blocks whose state is kept by pruning to the
+// given state root, from the block of the root up to the head. If no root is
+// given, the target is the deepest block within the snapshot diff layers of
+// the head. The target state has to be covered by the snapshot.
+func (p *Pruner) retainedBlocks(root common.Hash) ([]*types.WorkObject, error) {
+	var (
+		nodeCtx = p.headBlock.Location().Context()
+		blocks  = []*types.WorkObject{p.headBlock}
+		limit   = len(p.snaptree.Snapshots(p.headBlock.EVMRoot(), -1, true))
+	)
+	for {
+		block := blocks[len(blocks)-1]
+		if root != (common.Hash{}) && block.EVMRoot() == root {
+			break
+		}
+		if len(blocks) > limit || block.NumberU64(nodeCtx) == 0 {
+			if root != (common.Hash{}) {
+				return nil, fmt.Errorf("state %x is not the state of one of the last %d blocks", root, limit+1)
+			}
+			break
+		}
+		parent := rawdb.ReadHeader(p.db, block.NumberU64(nodeCtx)-1, block.ParentHash(nodeCtx))
+		if parent == nil {
+			return nil, fmt.Errorf("missing block %d %x", block.NumberU64(nodeCtx)-1, block.ParentHash(nodeCtx))
+		}
+		blocks = append(blocks, parent)
+	}
+	// If no root is given, pick the deepest block whose state is in the
+	// snapshot
+	if root == (common.Hash{}) {
+		for len(blocks) > 1 && p.snaptree.Snapshot(blocks[len(blocks)-1].EVMRoot()) == nil {
+			blocks = blocks[:len(blocks)-1]
+		}
+	}
+	target := blocks[len(blocks)-1]
+	if p.snaptree.Snapshot(target.EVMRoot()) == nil {
+		return nil, fmt.Errorf("state %x of block %d is not covered by the snapshot", target.EVMRoot(), target.NumberU64(nodeCtx))
+	}
+	if blob := rawdb.ReadTrieNode(p.db, target.EVMRoot()); len(blob) == 0 {
+		return nil, fmt.Errorf("associated state[%x] is not present", target.EVMRoot())
+	}
+	return blocks, nil
+}
+
+// buildBloom commits the state entries of the retained blocks and of the
+// genesis into the state bloom. The target state is regenerated from the
+// snapshot, and the states above it are added as their differences to the
+// state of their parent.
+func (p *Pruner) buildBloom(blocks []*types.WorkObject, location common.Location) error {
+	target := blocks[len(blocks)-1]
+	if err := snapshot.GenerateTrie(p.snaptree, target.EVMRoot(), p.db, p.stateBloom); err != nil {
+		return err
+	}
+	if err := extractTrieDiff(p.db, common.Hash{}, target.EtxSetRoot(), p.stateBloom, false); err != nil {
+		return err
+	}
+	for i := len(blocks) - 2; i >= 0; i-- {
+		parent, block := blocks[i+1], blocks[i]
+		if err := extractTrieDiff(p.db, parent.EVMRoot(), block.EVMRoot(), p.stateBloom, true); err != nil {
+			return err
+		}
+		if err := extractTrieDiff(p.db, parent.EtxSetRoot(), block.EtxSetRoot(), p.stateBloom, false); err != nil {
+			return err
+		}
+	}
+	// Traverse the genesis, put all genesis state entries into the
+	// bloom filter too.
+	return extractGenesis(p.db, p.stateBloom, location)
 }
 
 // Prune deletes all historical state nodes except the nodes belong to the
-// specified state version. If user doesn't specify the state version, use
-// the bottom-most snapshot diff layer as the target.
-func (p *Pruner) Prune(root common.Hash, location common.Location) error {
+// specified state version and to the states of the blocks above it. If user
+// doesn't specify the state version, use the bottom-most snapshot diff layer
+// as the target.
+func (p *Pruner) Prune(root common.Hash, location common.Location) (PruneStats, error) {
 	// If the state bloom filter is already committed previously,
 	// reuse it for pruning instead of generating a new one. It's
 	// mandatory because a part of state may already be deleted,
 	// the recovery procedure is necessary.
 	_, stateBloomRoot, err := findBloomFilter(p.datadir)
 	if err != nil {
-		return err
+		return PruneStats{}, err
 	}
 	if stateBloomRoot != (common.Hash{}) {
-		return RecoverPruning(p.datadir, p.db, p.trieCachePath, location, p.logger)
+		p.logger.WithField("root", stateBloomRoot).Info("Resuming interrupted state pruning")
+		return recoverPruning(p.datadir, p.db, p.trieCachePaths, p.logger)
 	}
-	// If the target state root is not specified, use the HEAD-127 as the
-	// target. The reason for picking it is:
-	// - in most of the normal cases, the related state is available
-	// - the probability of this layer being reorg is very low
-	var layers []snapshot.Snapshot
-	if root == (common.Hash{}) {
-		// Retrieve all snapshot layers from the current HEAD.
-		// In theory there are 128 difflayers + 1 disk layer present,
-		// so 128 diff layers are expected to be returned.
-		layers = p.snaptree.Snapshots(p.headHeader.EVMRoot(), 128, true)
-		if len(layers) != 128 {
-			// Reject if the accumulated diff layers are less than 128. It
-			// means in most of normal cases, there is no associated state
-			// with bottom-most diff layer.
-			return fmt.Errorf("snapshot not old enough yet: need %d more blocks", 128-len(layers))
-		}
-		// Use the bottom-most diff layer as the target
-		root = layers[len(layers)-1].Root()
-	}
-	// Ensure the root is really present. The weak assumption
-	// is the presence of root can indicate the presence of the
-	// entire trie.
-	if blob := rawdb.ReadTrieNode(p.db, root); len(blob) == 0 {
-		// The special case is for clique based networks(rinkeby, goerli
-		// and some other private networks), it's possible that two
-		// consecutive blocks will have same root. In this case snapshot
-		// difflayer won't be created. So HEAD-127 may not paired with
-		// head-127 layer. Instead the paired layer is higher than the
-		// bottom-most diff layer. Try to find the bottom-most snapshot
-		// layer with state available.
-		//
-		// Note HEAD and HEAD-1 is ignored. Usually there is the associated
-		// state available, but we don't want to use the topmost state
-		// as the pruning target.
-		var found bool
-		for i := len(layers) - 2; i >= 2; i-- {
-			if blob := rawdb.ReadTrieNode(p.db, layers[i].Root()); len(blob) != 0 {
-				root = layers[i].Root()
-				found = true
-				p.logger.WithFields(log.Fields{
-					"root":  root,
-					"depth": i,
-				}).Info("Selecting middle-layer as the pruning target")
-				break
-			}
-		}
-		if !found {
-			if len(layers) > 0 {
-				return errors.New("no snapshot paired state")
-			}
-			return fmt.Errorf("associated state[%x] is not present", root)
-		}
-	} else {
-		if len(layers) > 0 {
-			p.logger.WithFields(log.Fields{
-				"root":   root,
-				"height": p.headHeader.NumberU64(common.ZONE_CTX) - 127,
-			}).Info("Selecting bottom-most difflayer as the pruning target")
-		} else {
-			p.logger.WithField("root", root).Info("Selecting user-specified state as the pruning target")
-		}
+	blocks, err := p.retainedBlocks(root)
+	if err != nil {
+		return PruneStats{}, err
 	}
+	target := blocks[len(blocks)-1]
+	p.logger.WithFields(log.Fields{
+		"root":     target.EVMRoot(),
+		"number":   target.NumberU64(location.Context()),
+		"retained": len(blocks),
+	}).Info("Selected the pruning target")
+
 	// Before start the pruning, delete the clean trie cache first.
 	// It's necessary otherwise in the next restart we will hit the
 	// deleted state root in the "clean cache" so that the incomplete
 	// state is picked for usage.
-	deleteCleanTrieCache(p.trieCachePath, p.logger)
+	deleteCleanTrieCaches(p.trieCachePaths, p.logger)
 
-	// All the state roots of the middle layer should be forcibly pruned,
-	// otherwise the dangling state will be left.
-	middleRoots := make(map[common.Hash]struct{})
-	for _, layer := range layers {
-		if layer.Root() == root {
-			break
-		}
-		middleRoots[layer.Root()] = struct{}{}
-	}
-	// Traverse the target state, re-construct the whole state trie and
-	// commit to the given bloom filter.
+	// Traverse the retained states, re-construct the whole state tries and
+	// commit them to the given bloom filter.
 	start := time.Now()
-	if err := snapshot.GenerateTrie(p.snaptree, root, p.db, p.stateBloom); err != nil {
-		return err
-	}
-	// Traverse the genesis, put all genesis state entries into the
-	// bloom filter too.
-	if err := extractGenesis(p.db, p.stateBloom, location); err != nil {
-		return err
+	if err := p.buildBloom(blocks, location); err != nil {
+		return PruneStats{}, err
 	}
-	filterName := bloomFilterName(p.datadir, root)
+	filterName := bloomFilterName(p.datadir, target.EVMRoot())
 
 	p.logger.WithField("name", filterName).Info("Writing state bloom to disk")
 	if err := p.stateBloom.Commit(filterName, filterName+stateBloomFileTempSuffix); err != nil {
-		return err
+		return PruneStats{}, err
 	}
 	p.logger.WithField("name", filterName).Info("State bloom filter committed")
-	return prune(p.snaptree, root, p.db, p.stateBloom, filterName, middleRoots, start, p.logger)
+	return prune(p.snaptree, target.EVMRoot(), p.headBlock.EVMRoot(), p.db, p.stateBloom, filterName, start, p.logger)
+}
+
+// DryRun selects the pruning target the same way as Prune and builds the
+// state bloom, but only counts the state entries that would be deleted. It
+// neither commits the bloom nor modifies the database.
+func (p *Pruner) DryRun(root common.Hash, location common.Location) (PruneStats, error) {
+	if _, stateBloomRoot, err := findBloomFilter(p.datadir); err != nil {
+		return PruneStats{}, err
+	} else if stateBloomRoot != (common.Hash{}) {
+		return PruneStats{}, fmt.Errorf("an interrupted pruning to state %x has to be resumed first", stateBloomRoot)
+	}
+	blocks, err := p.retainedBlocks(root)
+	if err != nil {
+		return PruneStats{}, err
+	}
+	if err := p.buildBloom(blocks, location); err != nil {
+		return PruneStats{}, err
+	}
+	return sweep(p.db, p.stateBloom, true, p.logger)
 }
 
 // RecoverPruning will resume the pruning procedure during the system restart.
@@ -368,17 +432,22 @@ func (p *Pruner) Prune(root common.Hash, location common.Location) error {
 // pruning can be resumed. What's more if the bloom filter is constructed, the
 // pruning **has to be resumed**. Otherwise a lot of dangling nodes may be left
 // in the disk.
-func RecoverPruning(datadir string, db ethdb.Database, trieCachePath string, location common.Location, logger *log.Logger) error {
+func RecoverPruning(datadir string, db ethdb.Database, trieCachePaths []string, location common.Location, logger *log.Logger) error {
+	_, err := recoverPruning(datadir, db, trieCachePaths, logger)
+	return err
+}
+
+func recoverPruning(datadir string, db ethdb.Database, trieCachePaths []string, logger *log.Logger) (PruneStats, error) {
 	stateBloomPath, stateBloomRoot, err := findBloomFilter(datadir)
 	if err != nil {
-		return err
+		return PruneStats{}, err
 	}
 	if stateBloomPath == "" {
-		return nil // nothing to recover
+		return PruneStats{}, nil // nothing to recover
 	}
 	headBlock := rawdb.ReadHeadBlock(db)
 	if headBlock == nil {
-		return errors.New("failed to load head work object")
+		return PruneStats{}, errors.New("failed to load head work object")
 	}
 	// Initialize the snapshot tree in recovery mode to handle this special case:
 	// - Users run the `prune-state` command multiple times
@@ -390,11 +459,11 @@ func RecoverPruning(datadir string, db ethdb.Database, trieCachePath string, loc
 	// still feasible to recover the pruning correctly.
 	snaptree, err := snapshot.New(db, trie.NewDatabase(db), 256, headBlock.EVMRoot(), false, true, logger)
 	if err != nil {
-		return err // The relevant snapshot(s) might not exist
+		return PruneStats{}, err // The relevant snapshot(s) might not exist
 	}
 	stateBloom, err := NewStateBloomFromDisk(stateBloomPath, logger)
 	if err != nil {
-		return err
+		return PruneStats{}, err
 	}
 	logger.WithField("path", stateBloomPath).Info("Loaded state bloom filter")
 
@@ -402,27 +471,76 @@ func RecoverPruning(datadir string, db ethdb.Database, trieCachePath string, loc
 	// It's necessary otherwise in the next restart we will hit the
 	// deleted state root in the "clean cache" so that the incomplete
 	// state is picked for usage.
-	deleteCleanTrieCache(trieCachePath, logger)
+	deleteCleanTrieCaches(trieCachePaths, logger)
 
-	// All the state roots of the middle layers should be forcibly pruned,
-	// otherwise the dangling state will be left.
-	var (
-		found       bool
-		layers      = snaptree.Snapshots(headBlock.EVMRoot(), 128, true)
-		middleRoots = make(map[common.Hash]struct{})
-	)
-	for _, layer := range layers {
+	// The bloom holds the states from the target up to the head it was
+	// built for, so the target has to be one of the layers of the head.
+	var found bool
+	for _, layer := range snaptree.Snapshots(headBlock.EVMRoot(), -1, false) {
 		if layer.Root() == stateBloomRoot {
 			found = true
 			break
 		}
-		middleRoots[layer.Root()] = struct{}{}
 	}
 	if !found {
 		logger.Error("Pruning target state is not existent")
-		return errors.New("non-existent target state")
+		return PruneStats{}, errors.New("non-existent target state")
 	}
-	return prune(snaptree, stateBloomRoot, db, stateBloom, stateBloomPath, middleRoots, time.Now(), logger)
+	return prune(snaptree, stateBloomRoot, headBlock.EVMRoot(), db, stateBloom, stateBloomPath, time.Now(), logger)
+}
+
+// extractTrieDiff commits the nodes of the trie at root that are not part of
+// the trie at parent into the given bloomfilter. An empty parent commits the
+// whole trie. If the tries are state tries, the differences of the storage
+// tries and the code of the changed accounts are committed too.
+func extractTrieDiff(db ethdb.Database, parent common.Hash, root common.Hash, stateBloom *stateBloom, isState bool) error {
+	if root == (common.Hash{}) || root == emptyRoot || root == parent {
+		return nil
+	}
+	triedb := trie.NewDatabase(db)
+	newTrie, err := trie.New(root, triedb)
+	if err != nil {
+		return err
+	}
+	oldTrie, err := trie.New(parent, triedb)
+	if err != nil {
+		return err
+	}
+	it, _ := trie.NewDifferenceIterator(oldTrie.NodeIterator(nil), newTrie.NodeIterator(nil))
+	for it.Next(true) {
+		// Embedded nodes don't have hash.
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			stateBloom.Put(hash.Bytes(), nil)
+		}
+		if !isState || !it.Leaf() {
+			continue
+		}
+		var acc state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &acc); err != nil {
+			return err
+		}
+		// The keys of the state trie are hashed already, so the account of
+		// the parent state is read from the plain trie
+		parentStorage := emptyRoot
+		if blob, err := oldTrie.TryGet(it.LeafKey()); err != nil {
+			return err
+		} else if len(blob) > 0 {
+			var parentAcc state.Account
+			if err := rlp.DecodeBytes(blob, &parentAcc); err != nil {
+				return err
+			}
+			parentStorage = parentAcc.Root
+		}
+		if acc.Root != parentStorage {
+			if err := extractTrieDiff(db, parentStorage, acc.Root, stateBloom, false); err != nil {
+				return err
+			}
+		}
+		if !bytes.Equal(acc.CodeHash, emptyCode) {
+			stateBloom.Put(acc.CodeHash, nil)
+		}
+	}
+	return it.Error()
 }
 
 // extractGenesis loads the genesis state and commits all the state entries
@@ -440,6 +558,9 @@ func extractGenesis(db ethdb.Database, stateBloom *stateBloom, location common.L
 	if genesis == nil {
 		return errors.New("missing genesis block")
 	}
+	if err := extractTrieDiff(db, common.Hash{}, genesis.EtxSetRoot(), stateBloom, false); err != nil {
+		return err
+	}
 	t, err := trie.NewSecure(genesis.EVMRoot(), trie.NewDatabase(db))
 	if err != nil {
 		return err
@@ -523,14 +644,16 @@ The clean trie cache is not found. Please delete it by yourself after the
 pruning. Remember don't start the Quai without deleting the clean trie cache
 otherwise the entire database may be damaged!
 
-Check the command description "quai snapshot prune-state --help" for more details.
+Check the command description "go-quai prune-state --help" for more details.
 `
 
-func deleteCleanTrieCache(path string, logger *log.Logger) {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		logger.Warn(warningLog)
-		return
+func deleteCleanTrieCaches(paths []string, logger *log.Logger) {
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			logger.Warn(warningLog)
+			continue
+		}
+		os.RemoveAll(path)
+		logger.WithField("path", path).Info("Deleted trie clean cache")
 	}
-	os.RemoveAll(path)
-	logger.WithField("path", path).Info("Deleted trie clean cache")
 }
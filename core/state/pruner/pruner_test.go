@@ -0,0 +1,179 @@
+package pruner
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/trie"
+)
+
+var testLocation = common.Location{0, 0}
+
+// newTestChain generates a zone chain whose blocks change the state after the
+// first one, which applies the allocations, and stops it so that its snapshot
+// is journaled. It returns the database of the zone and its blocks, the genesis
+// first.
+func newTestChain(t *testing.T) (ethdb.Database, []*types.WorkObject) {
+	accounts := chaingen.Accounts(testLocation, false, 3)
+	sender, recipient := accounts[1], accounts[2]
+	g, err := chaingen.New(chaingen.Config{
+		QuaiAlloc: map[common.Address]*big.Int{sender.Address: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))},
+	})
+	require.NoError(t, err)
+	genesis := g.Head(testLocation)
+	blocks, err := g.GenerateBlocks(testLocation, 8, func(i int, b *chaingen.BlockGen) {
+		if i == 0 {
+			return
+		}
+		_, err := b.Transfer(sender, recipient.Address, big.NewInt(params.Ether))
+		require.NoError(t, err)
+	})
+	g.Stop()
+	require.NoError(t, err)
+	return g.Database(testLocation), append([]*types.WorkObject{genesis}, blocks...)
+}
+
+// requireState checks every node of the state and ETX set tries of the block
+// is in the database.
+func requireState(t *testing.T, db ethdb.Database, block *types.WorkObject) {
+	for _, root := range []common.Hash{block.EVMRoot(), block.EtxSetRoot()} {
+		if root == (common.Hash{}) || root == emptyRoot {
+			continue
+		}
+		tr, err := trie.New(root, trie.NewDatabase(db))
+		require.NoError(t, err, "block %d", block.NumberU64(common.ZONE_CTX))
+		it := tr.NodeIterator(nil)
+		for it.Next(true) {
+		}
+		require.NoError(t, it.Error(), "block %d", block.NumberU64(common.ZONE_CTX))
+	}
+}
+
+// requirePruned checks the state roots of the blocks below the target are
+// deleted, unless they are the root of a retained state.
+func requirePruned(t *testing.T, db ethdb.Database, blocks []*types.WorkObject, target int) {
+	retained := map[common.Hash]bool{blocks[0].EVMRoot(): true}
+	for _, block := range blocks[target:] {
+		retained[block.EVMRoot()] = true
+		requireState(t, db, block)
+	}
+	requireState(t, db, blocks[0])
+	pruned := 0
+	for _, block := range blocks[1:target] {
+		if retained[block.EVMRoot()] {
+			continue
+		}
+		require.Empty(t, rawdb.ReadTrieNode(db, block.EVMRoot()), "state of block %d not pruned", block.NumberU64(common.ZONE_CTX))
+		pruned++
+	}
+	require.NotZero(t, pruned)
+}
+
+// dump returns a copy of all the entries of the database.
+func dump(db ethdb.Database) map[string][]byte {
+	entries := make(map[string][]byte)
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		entries[string(it.Key())] = bytes.Clone(it.Value())
+	}
+	return entries
+}
+
+// TestPrune verifies pruning keeps the states from the target up to the head
+// and the genesis state, and deletes the states below the target.
+func TestPrune(t *testing.T) {
+	db, blocks := newTestChain(t)
+	target := len(blocks) - 3
+
+	p, err := NewPruner(db, t.TempDir(), nil, 0, log.Global, testLocation)
+	require.NoError(t, err)
+	stats, err := p.Prune(blocks[target].EVMRoot(), testLocation)
+	require.NoError(t, err)
+	require.NotZero(t, stats.Nodes)
+	requirePruned(t, db, blocks, target)
+
+	// The head can be pruned again, with the target state as its disk layer
+	p, err = NewPruner(db, t.TempDir(), nil, 0, log.Global, testLocation)
+	require.NoError(t, err)
+	_, err = p.Prune(blocks[target+1].EVMRoot(), testLocation)
+	require.NoError(t, err)
+	requirePruned(t, db, blocks, target+1)
+}
+
+// TestPruneDryRun verifies a dry run leaves the database unchanged, and counts
+// the state entries the pruning deletes.
+func TestPruneDryRun(t *testing.T) {
+	db, blocks := newTestChain(t)
+	target := len(blocks) - 3
+	before := dump(db)
+
+	datadir := t.TempDir()
+	p, err := NewPruner(db, datadir, nil, 0, log.Global, testLocation)
+	require.NoError(t, err)
+	stats, err := p.DryRun(blocks[target].EVMRoot(), testLocation)
+	require.NoError(t, err)
+	require.NotZero(t, stats.Nodes)
+	require.Equal(t, before, dump(db))
+
+	// The dry run does not leave a bloom to resume either
+	path, _, err := findBloomFilter(datadir)
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	// It counts what the pruning deletes
+	p, err = NewPruner(db, datadir, nil, 0, log.Global, testLocation)
+	require.NoError(t, err)
+	pruned, err := p.Prune(blocks[target].EVMRoot(), testLocation)
+	require.NoError(t, err)
+	require.Equal(t, stats, pruned)
+}
+
+// TestPruneResume verifies a pruning interrupted once its state bloom is
+// committed is resumed by the next one.
+func TestPruneResume(t *testing.T) {
+	db, blocks := newTestChain(t)
+	target := len(blocks) - 3
+	root := blocks[target].EVMRoot()
+
+	// Interrupt the pruning once its bloom is committed, before any state is
+	// deleted
+	datadir := t.TempDir()
+	p, err := NewPruner(db, datadir, nil, 0, log.Global, testLocation)
+	require.NoError(t, err)
+	retained, err := p.retainedBlocks(root)
+	require.NoError(t, err)
+	require.NoError(t, p.buildBloom(retained, testLocation))
+	filterName := bloomFilterName(datadir, root)
+	require.NoError(t, p.stateBloom.Commit(filterName, filterName+stateBloomFileTempSuffix))
+
+	// A dry run refuses to run over it, and the next pruning resumes it
+	// whatever its own target
+	p, err = NewPruner(db, datadir, nil, 0, log.Global, testLocation)
+	require.NoError(t, err)
+	_, err = p.DryRun(root, testLocation)
+	require.Error(t, err)
+	stats, err := p.Prune(common.Hash{}, testLocation)
+	require.NoError(t, err)
+	require.NotZero(t, stats.Nodes)
+	requirePruned(t, db, blocks, target)
+
+	path, _, err := findBloomFilter(datadir)
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	// Nothing is left to recover
+	stats, err = recoverPruning(datadir, db, nil, log.Global)
+	require.NoError(t, err)
+	require.Zero(t, stats.Nodes)
+}
@@ -0,0 +1,76 @@
+package core_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+// TestStateAtTransactionAfterEtx verifies the state a transaction is replayed
+// on holds the Quai paid by the ETXs included before it in the block.
+func TestStateAtTransactionAfterEtx(t *testing.T) {
+	from, to := common.Location{0, 0}, common.Location{0, 1}
+	sender := chaingen.Accounts(from, false, 2)[1]
+	accounts := chaingen.Accounts(to, false, 4)
+	recipient, payer, payee := accounts[1], accounts[2], accounts[3]
+	balance := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
+	value := big.NewInt(params.Ether)
+	g, err := chaingen.New(chaingen.Config{
+		ExpansionNumber: 1,
+		QuaiAlloc: map[common.Address]*big.Int{
+			sender.Address: balance,
+			payer.Address:  balance,
+		},
+	})
+	require.NoError(t, err)
+	defer g.Stop()
+
+	_, err = g.GenerateBlocks(to, 1, nil)
+	require.NoError(t, err)
+	_, err = g.GenerateBlocks(from, 2, func(i int, b *chaingen.BlockGen) {
+		if i == 1 {
+			_, err := b.AddEtx(sender, recipient.Address, value)
+			require.NoError(t, err)
+		}
+	})
+	require.NoError(t, err)
+
+	// Every block of the destination zone has a Quai transaction, until one
+	// of them follows the ETX paying the recipient
+	var block *types.WorkObject
+	paid, index := false, -1
+	for i := 0; i < 8 && index < 0; i++ {
+		_, err = g.GenerateBlocks(from, 1, nil)
+		require.NoError(t, err)
+		blocks, err := g.GenerateBlocks(to, 1, func(i int, b *chaingen.BlockGen) {
+			_, err := b.Transfer(payer, payee.Address, value)
+			require.NoError(t, err)
+		})
+		require.NoError(t, err)
+		block = blocks[0]
+		for j, tx := range block.Transactions() {
+			if tx.Type() == types.ExternalTxType && tx.To() != nil && bytes.Equal(tx.To().Bytes(), recipient.Address.Bytes()) {
+				paid = true
+			}
+			if tx.Type() == types.QuaiTxType && paid {
+				index = j
+				break
+			}
+		}
+	}
+	require.GreaterOrEqual(t, index, 0, "no transaction after the ETX paying the recipient")
+
+	msg, _, statedb, err := g.Core(to).StateAtTransaction(block, index, 0)
+	require.NoError(t, err)
+	require.Equal(t, value, msg.Value())
+	internal, err := recipient.Address.InternalAndQuaiAddress()
+	require.NoError(t, err)
+	require.Equal(t, value, statedb.GetBalance(internal))
+}
@@ -303,14 +303,7 @@ func (p *StateProcessor) Process(block *types.WorkObject, batch ethdb.Batch) (ty
 	time3 := common.PrettyDuration(time.Since(start))
 
 	// Iterate over and process the individual transactions.
-	etxRLimit := len(parent.Transactions()) / params.ETXRegionMaxFraction
-	if etxRLimit < params.ETXRLimitMin {
-		etxRLimit = params.ETXRLimitMin
-	}
-	etxPLimit := len(parent.Transactions()) / params.ETXPrimeMaxFraction
-	if etxPLimit < params.ETXPLimitMin {
-		etxPLimit = params.ETXPLimitMin
-	}
+	etxRLimit, etxPLimit := etxLimits(parent)
 	minimumEtxCount := params.MinEtxCount
 	maximumEtxCount := params.MaxEtxCount
 	etxCount := 0
@@ -495,7 +488,7 @@ func (p *StateProcessor) Process(block *types.WorkObject, batch ethdb.Batch) (ty
 						}
 					}
 				}
-				if block.NumberU64(common.ZONE_CTX) > params.TimeToStartTx {
+				if block.NumberU64(common.ZONE_CTX) > p.config.StartTxNumber() {
 					// subtract the minimum tx gas from the gas pool
 					if err := gp.SubGas(params.TxGas); err != nil {
 						return nil, nil, nil, nil, 0, 0, 0, nil, nil, err
@@ -582,9 +575,7 @@ func (p *StateProcessor) Process(block *types.WorkObject, batch ethdb.Batch) (ty
 					continue // locked and redeemed later
 				}
 				fees := big.NewInt(0)
-				prevZeroBal := prepareApplyETX(statedb, msg.Value(), nodeLocation)
-				receipt, fees, err = applyTransaction(msg, parent, p.config, p.hc, gp, statedb, blockNumber, blockHash, etx, usedGas, usedState, vmenv, &etxRLimit, &etxPLimit, p.logger)
-				statedb.SetBalance(common.ZeroInternal(nodeLocation), prevZeroBal) // Reset the balance to what it previously was. Residual balance will be lost
+				receipt, fees, err = applyQuaiEtx(msg, parent, p.config, p.hc, gp, statedb, blockNumber, blockHash, etx, usedGas, usedState, vmenv, &etxRLimit, &etxPLimit, p.logger)
 				if err != nil {
 					return nil, nil, nil, nil, 0, 0, 0, nil, nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 				}
@@ -670,10 +661,10 @@ func (p *StateProcessor) Process(block *types.WorkObject, batch ethdb.Batch) (ty
 		etxAvailable = true
 	}
 
-	if block.NumberU64(common.ZONE_CTX) <= params.TimeToStartTx && (etxAvailable && etxCount < minimumEtxCount || etxCount > maximumEtxCount) {
+	if block.NumberU64(common.ZONE_CTX) <= p.config.StartTxNumber() && (etxAvailable && etxCount < minimumEtxCount || etxCount > maximumEtxCount) {
 		return nil, nil, nil, nil, 0, 0, 0, nil, nil, fmt.Errorf("total number of ETXs %d is not within the range %d to %d", etxCount, minimumEtxCount, maximumEtxCount)
 	}
-	if block.NumberU64(common.ZONE_CTX) > params.TimeToStartTx && (etxAvailable && totalEtxGas < minimumEtxGas) || totalEtxGas > maximumEtxGas {
+	if block.NumberU64(common.ZONE_CTX) > p.config.StartTxNumber() && (etxAvailable && totalEtxGas < minimumEtxGas) || totalEtxGas > maximumEtxGas {
 		p.logger.Errorf("prevInboundEtxs: %d, oldestIndex: %d, etxHash: %s", len(prevInboundEtxs), oldestIndex.Int64(), etx.Hash().Hex())
 		return nil, nil, nil, nil, 0, 0, 0, nil, nil, fmt.Errorf("total gas used by ETXs %d is not within the range %d to %d", totalEtxGas, minimumEtxGas, maximumEtxGas)
 	}
@@ -1851,30 +1842,76 @@ func (p *StateProcessor) StateAtTransaction(block *types.WorkObject, txIndex int
 	if err != nil {
 		return nil, vm.BlockContext{}, nil, err
 	}
+	// Apply the previous inbound ETXs to the ETX set and redeem the locked
+	// Quai, which the block does before its transactions
+	if prevInboundEtxs := rawdb.ReadInboundEtxs(p.hc.bc.db, parent.Hash()); len(prevInboundEtxs) > 0 {
+		if err := statedb.PushETXs(prevInboundEtxs); err != nil {
+			return nil, vm.BlockContext{}, nil, fmt.Errorf("could not push prev inbound etxs: %w", err)
+		}
+	}
+	if err, _ := RedeemLockedQuai(p.hc, block, parent, statedb); err != nil {
+		return nil, vm.BlockContext{}, nil, fmt.Errorf("error redeeming locked quai: %w", err)
+	}
 	if txIndex == 0 && len(block.Transactions()) == 0 {
 		return nil, vm.BlockContext{}, statedb, nil
 	}
-	// Recompute transactions up to the target index.
-	signer := types.MakeSigner(p.hc.Config(), block.Number(nodeCtx))
+	context, err := NewEVMBlockContext(block, parent, p.hc, nil)
+	if err != nil {
+		return nil, vm.BlockContext{}, nil, err
+	}
+	var (
+		signer               = types.MakeSigner(p.hc.Config(), block.Number(nodeCtx))
+		vmenv                = vm.NewEVM(context, vm.TxContext{}, statedb, p.hc.Config(), vm.Config{})
+		gp                   = new(types.GasPool).AddGas(block.GasLimit())
+		usedGas, usedState   uint64
+		etxRLimit, etxPLimit = etxLimits(parent)
+	)
+	// Recompute transactions up to the target index, applying the Quai
+	// transactions and the ETXs as Process does. Qi transactions only spend
+	// and create UTXOs, and are skipped.
 	for idx, tx := range block.Transactions() {
-		// Assemble the transaction call message and return if the requested offset
-		msg, _ := tx.AsMessage(signer, block.BaseFee())
-		txContext := NewEVMTxContext(msg)
-		context, err := NewEVMBlockContext(block, parent, p.hc, nil)
-		if err != nil {
-			return nil, vm.BlockContext{}, nil, err
-		}
 		if idx == txIndex {
+			// Only Quai transactions are executed by the EVM
+			if tx.Type() != types.QuaiTxType {
+				return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction %#x is not a Quai transaction", tx.Hash())
+			}
+			msg, err := tx.AsMessage(signer, block.BaseFee())
+			if err != nil {
+				return nil, vm.BlockContext{}, nil, err
+			}
 			return msg, context, statedb, nil
 		}
-		// Not yet the searched for transaction, execute on top of the current state
-		vmenv := vm.NewEVM(context, txContext, statedb, p.hc.Config(), vm.Config{})
-		statedb.Prepare(tx.Hash(), idx)
-		if _, err := ApplyMessage(vmenv, msg, new(types.GasPool).AddGas(tx.Gas())); err != nil {
-			return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		switch tx.Type() {
+		case types.QuaiTxType:
+			msg, err := tx.AsMessage(signer, block.BaseFee())
+			if err != nil {
+				return nil, vm.BlockContext{}, nil, err
+			}
+			statedb.Prepare(tx.Hash(), idx)
+			if _, _, err := applyTransaction(msg, parent, p.hc.Config(), p.hc, gp, statedb, block.Number(nodeCtx), block.Hash(), tx, &usedGas, &usedState, vmenv, &etxRLimit, &etxPLimit, p.logger); err != nil {
+				return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+			}
+		case types.ExternalTxType:
+			// ETXs are included in the order of the ETX set
+			etx, err := statedb.PopETX()
+			if err != nil {
+				return nil, vm.BlockContext{}, nil, fmt.Errorf("could not pop etx from statedb: %w", err)
+			}
+			if etx == nil || etx.Hash() != tx.Hash() {
+				return nil, vm.BlockContext{}, nil, fmt.Errorf("etx %#x is not in order or not found in unspent etx set", tx.Hash())
+			}
+			if !isQuaiEtx(etx) {
+				continue
+			}
+			msg, err := tx.AsMessageWithSender(signer, block.BaseFee(), nil)
+			if err != nil {
+				return nil, vm.BlockContext{}, nil, err
+			}
+			statedb.Prepare(tx.Hash(), idx)
+			if _, _, err := applyQuaiEtx(msg, parent, p.hc.Config(), p.hc, gp, statedb, block.Number(nodeCtx), block.Hash(), etx, &usedGas, &usedState, vmenv, &etxRLimit, &etxPLimit, p.logger); err != nil {
+				return nil, vm.BlockContext{}, nil, fmt.Errorf("etx %#x failed: %v", tx.Hash(), err)
+			}
 		}
-		// Ensure any modifications are committed to the state
-		statedb.Finalise(true)
 	}
 	return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
@@ -1952,10 +1989,50 @@ func (p *StateProcessor) Stop() {
 		etxTrieDB := p.etxCache.TrieDB()
 		etxTrieDB.SaveCache(p.cacheConfig.ETXTrieCleanJournal)
 	}
+	// Persist the snapshot diff layers, so that the snapshot is loaded instead
+	// of regenerated on the next start, and can be used to prune the state
+	if p.snaps != nil {
+		if _, err := p.snaps.Journal(p.hc.CurrentHeader().EVMRoot()); err != nil {
+			p.logger.WithField("err", err).Error("Failed to journal state snapshot")
+		}
+	}
 	close(p.quit)
 	p.logger.Info("State Processor stopped")
 }
 
+// applyQuaiEtx applies an ETX sending Quai to the ledger of the zone, which
+// pays the value from the zero address for the duration of the call.
+func applyQuaiEtx(msg types.Message, parent *types.WorkObject, config *params.ChainConfig, bc ChainContext, gp *types.GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, etx *types.Transaction, usedGas *uint64, usedState *uint64, evm *vm.EVM, etxRLimit, etxPLimit *int, logger *log.Logger) (*types.Receipt, *big.Int, error) {
+	prevZeroBal := prepareApplyETX(statedb, msg.Value(), config.Location)
+	receipt, fees, err := applyTransaction(msg, parent, config, bc, gp, statedb, blockNumber, blockHash, etx, usedGas, usedState, evm, etxRLimit, etxPLimit, logger)
+	statedb.SetBalance(common.ZeroInternal(config.Location), prevZeroBal) // Reset the balance to what it previously was. Residual balance will be lost
+	return receipt, fees, err
+}
+
+// isQuaiEtx reports whether the ETX is applied by the EVM when it is included.
+// Coinbase ETXs and ETXs to the Qi ledger create UTXOs instead, and Qi to Quai
+// conversions are locked and redeemed later.
+func isQuaiEtx(etx *types.Transaction) bool {
+	if types.IsCoinBaseTx(etx) || etx.To().IsInQiLedgerScope() {
+		return false
+	}
+	return !types.IsConversionTx(etx)
+}
+
+// etxLimits returns the number of cross-region and cross-prime ETXs the
+// transactions of the child of parent can emit.
+func etxLimits(parent *types.WorkObject) (int, int) {
+	etxRLimit := len(parent.Transactions()) / params.ETXRegionMaxFraction
+	if etxRLimit < params.ETXRLimitMin {
+		etxRLimit = params.ETXRLimitMin
+	}
+	etxPLimit := len(parent.Transactions()) / params.ETXPrimeMaxFraction
+	if etxPLimit < params.ETXPLimitMin {
+		etxPLimit = params.ETXPLimitMin
+	}
+	return etxRLimit, etxPLimit
+}
+
 func prepareApplyETX(statedb *state.StateDB, value *big.Int, nodeLocation common.Location) *big.Int {
 	prevZeroBal := statedb.GetBalance(common.ZeroInternal(nodeLocation)) // Get current zero address balance
 	statedb.SetBalance(common.ZeroInternal(nodeLocation), value)         // Use zero address at temp placeholder and set it to value
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"io"
 	"os"
+	"time"
 
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core/types"
@@ -40,6 +41,15 @@ type devNull struct{}
 func (*devNull) Write(p []byte) (n int, err error) { return len(p), nil }
 func (*devNull) Close() error                      { return nil }
 
+// journalQiTx is a journal entry of a local Qi transaction, along with the time
+// it was first received so that it keeps expiring from the pool on its original
+// schedule. Quai transactions are journaled as RLP strings and these entries as
+// RLP lists, so the two can be told apart when the journal is loaded.
+type journalQiTx struct {
+	Tx       *types.Transaction
+	Received uint64 // Unix time in seconds
+}
+
 // txJournal is a rotating log of transactions with the aim of storing locally
 // created transactions to allow non-executed ones to survive node restarts.
 type txJournal struct {
@@ -57,8 +67,9 @@ func newTxJournal(path string, logger *log.Logger) *txJournal {
 }
 
 // load parses a transaction journal dump from disk, loading its contents into
-// the specified pool.
-func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
+// the specified pool. Quai transactions are injected with add, and Qi ones with
+// addQi along with the time they were first received.
+func (journal *txJournal) load(add func([]*types.Transaction) []error, addQi func([]*types.Transaction, []time.Time) []error) error {
 	// Skip the parsing if the journal file doesn't exist at all
 	if _, err := os.Stat(journal.path); os.IsNotExist(err) {
 		return nil
@@ -76,13 +87,13 @@ func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
 
 	// Inject all transactions from the journal into the pool
 	stream := rlp.NewStream(input, 0)
-	total, dropped := 0, 0
+	total, qiTotal, dropped := 0, 0, 0
 
 	// Create a method to load a limited batch of transactions and bump the
 	// appropriate progress counters. Then use this method to load all the
 	// journaled transactions in small-ish batches.
-	loadBatch := func(txs types.Transactions) {
-		for _, err := range add(txs) {
+	countDropped := func(errs []error) {
+		for _, err := range errs {
 			if err != nil {
 				journal.logger.WithField("err", err).Debug("Failed to add journaled transaction")
 				dropped++
@@ -90,18 +101,41 @@ func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
 		}
 	}
 	var (
-		failure error
-		batch   types.Transactions
+		failure    error
+		batch      types.Transactions
+		qiBatch    types.Transactions
+		qiReceived []time.Time
 	)
 	for {
 		// Parse the next transaction and terminate on error
+		var kind rlp.Kind
+		if kind, _, err = stream.Kind(); err == nil && kind == rlp.List {
+			entry := new(journalQiTx)
+			if err = stream.Decode(entry); err == nil {
+				// New Qi transaction parsed, queue up for later, import if threshold is reached
+				qiTotal++
+
+				qiReceived = append(qiReceived, time.Unix(int64(entry.Received), 0))
+				if qiBatch = append(qiBatch, entry.Tx); qiBatch.Len() > 1024 {
+					countDropped(addQi(qiBatch, qiReceived))
+					qiBatch, qiReceived = qiBatch[:0], qiReceived[:0]
+				}
+				continue
+			}
+		}
 		tx := new(types.Transaction)
-		if err = stream.Decode(tx); err != nil {
+		if err == nil {
+			err = stream.Decode(tx)
+		}
+		if err != nil {
 			if err != io.EOF {
 				failure = err
 			}
 			if batch.Len() > 0 {
-				loadBatch(batch)
+				countDropped(add(batch))
+			}
+			if qiBatch.Len() > 0 {
+				countDropped(addQi(qiBatch, qiReceived))
 			}
 			break
 		}
@@ -109,13 +143,14 @@ func (journal *txJournal) load(add func([]*types.Transaction) []error) error {
 		total++
 
 		if batch = append(batch, tx); batch.Len() > 1024 {
-			loadBatch(batch)
+			countDropped(add(batch))
 			batch = batch[:0]
 		}
 	}
 	journal.logger.WithFields(log.Fields{
-		"transactions": total,
-		"dropped":      dropped,
+		"transactions":   total,
+		"qitransactions": qiTotal,
+		"dropped":        dropped,
 	}).Info("Loaded local transaction journal")
 
 	return failure
@@ -132,9 +167,20 @@ func (journal *txJournal) insert(tx *types.Transaction) error {
 	return nil
 }
 
+// insertQi adds the specified Qi transaction to the local disk journal.
+func (journal *txJournal) insertQi(tx *types.TxWithMinerFee) error {
+	if journal.writer == nil {
+		return errNoActiveJournal
+	}
+	if err := rlp.Encode(journal.writer, &journalQiTx{Tx: tx.Tx(), Received: uint64(tx.Received().Unix())}); err != nil {
+		return err
+	}
+	return nil
+}
+
 // rotate regenerates the transaction journal based on the current contents of
 // the transaction pool.
-func (journal *txJournal) rotate(all map[common.InternalAddress]types.Transactions) error {
+func (journal *txJournal) rotate(all map[common.InternalAddress]types.Transactions, qi []*types.TxWithMinerFee) error {
 	// Close the current journal (if any is open)
 	if journal.writer != nil {
 		if err := journal.writer.Close(); err != nil {
@@ -157,6 +203,12 @@ func (journal *txJournal) rotate(all map[common.InternalAddress]types.Transactio
 		}
 		journaled += len(txs)
 	}
+	for _, tx := range qi {
+		if err = rlp.Encode(replacement, &journalQiTx{Tx: tx.Tx(), Received: uint64(tx.Received().Unix())}); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
 	replacement.Close()
 
 	// Replace the live journal with the newly generated one
@@ -169,8 +221,9 @@ func (journal *txJournal) rotate(all map[common.InternalAddress]types.Transactio
 	}
 	journal.writer = sink
 	journal.logger.WithFields(log.Fields{
-		"transactions": journaled,
-		"accounts":     len(all),
+		"transactions":   journaled,
+		"accounts":       len(all),
+		"qitransactions": len(qi),
 	}).Info("Regenerated local transaction journal")
 
 	return nil
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+func TestTxJournalQiTxs(t *testing.T) {
+	journal := newTxJournal(filepath.Join(t.TempDir(), "transactions.rlp"), log.Global)
+
+	rotated := newTestQiTx(t, 100, types.OutPoint{TxHash: common.Hash{1}, Index: 0})
+	inserted := newTestQiTx(t, 200, types.OutPoint{TxHash: common.Hash{2}, Index: 0})
+	require.NoError(t, journal.rotate(nil, []*types.TxWithMinerFee{rotated}))
+	require.NoError(t, journal.insertQi(inserted))
+	require.NoError(t, journal.close())
+
+	var (
+		hashes   []common.Hash
+		received []time.Time
+	)
+	err := journal.load(func(txs []*types.Transaction) []error {
+		t.Fatalf("loaded %d quai transactions", len(txs))
+		return nil
+	}, func(txs []*types.Transaction, times []time.Time) []error {
+		for _, tx := range txs {
+			hashes = append(hashes, tx.Hash())
+		}
+		received = append(received, times...)
+		return make([]error, len(txs))
+	})
+	require.NoError(t, err)
+	require.Equal(t, []common.Hash{rotated.Tx().Hash(), inserted.Tx().Hash()}, hashes)
+	require.Equal(t, []time.Time{
+		time.Unix(rotated.Received().Unix(), 0),
+		time.Unix(inserted.Received().Unix(), 0),
+	}, received)
+}
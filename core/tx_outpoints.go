@@ -0,0 +1,99 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+)
+
+// qiOutpointIndex tracks the outpoints spent by the Qi transactions of the
+// pool, so that a transaction spending an outpoint already claimed by another
+// pooled transaction is either rejected or replaces it.
+type qiOutpointIndex struct {
+	spenders map[types.OutPoint]common.Hash
+	lock     sync.RWMutex
+}
+
+func newQiOutpointIndex() *qiOutpointIndex {
+	return &qiOutpointIndex{spenders: make(map[types.OutPoint]common.Hash)}
+}
+
+// add claims the outpoints spent by tx.
+func (idx *qiOutpointIndex) add(tx *types.Transaction) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	hash := tx.Hash()
+	for _, in := range tx.TxIn() {
+		idx.spenders[in.PreviousOutPoint] = hash
+	}
+}
+
+// remove releases the outpoints spent by tx, unless they were claimed since by
+// another transaction.
+func (idx *qiOutpointIndex) remove(tx *types.Transaction) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	hash := tx.Hash()
+	for _, in := range tx.TxIn() {
+		if idx.spenders[in.PreviousOutPoint] == hash {
+			delete(idx.spenders, in.PreviousOutPoint)
+		}
+	}
+}
+
+// conflicts returns the hashes of the pooled transactions spending any of the
+// outpoints spent by tx, other than tx itself.
+func (idx *qiOutpointIndex) conflicts(tx *types.Transaction) []common.Hash {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+
+	var (
+		hash   = tx.Hash()
+		seen   = make(map[common.Hash]struct{})
+		hashes []common.Hash
+	)
+	for _, in := range tx.TxIn() {
+		other, ok := idx.spenders[in.PreviousOutPoint]
+		if !ok || other == hash {
+			continue
+		}
+		if _, ok := seen[other]; !ok {
+			seen[other] = struct{}{}
+			hashes = append(hashes, other)
+		}
+	}
+	return hashes
+}
+
+// claimed returns the pooled transactions spending the given outpoints, or
+// the spenders of all the claimed outpoints if none is given.
+func (idx *qiOutpointIndex) claimed(outpoints []types.OutPoint) map[types.OutPoint]common.Hash {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+
+	if len(outpoints) == 0 {
+		claimed := make(map[types.OutPoint]common.Hash, len(idx.spenders))
+		for outpoint, hash := range idx.spenders {
+			claimed[outpoint] = hash
+		}
+		return claimed
+	}
+	claimed := make(map[types.OutPoint]common.Hash)
+	for _, outpoint := range outpoints {
+		if hash, ok := idx.spenders[outpoint]; ok {
+			claimed[outpoint] = hash
+		}
+	}
+	return claimed
+}
+
+// len returns the number of claimed outpoints.
+func (idx *qiOutpointIndex) len() int {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+
+	return len(idx.spenders)
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+// newTestQiPool returns a pool holding only the Qi pool and its outpoint index.
+func newTestQiPool(t *testing.T, size int) *TxPool {
+	pool := &TxPool{config: TxPoolConfig{PriceBump: 10}, logger: log.Global, qiSpent: newQiOutpointIndex()}
+	qiPool, err := lru.NewWithEvict[common.Hash, *types.TxWithMinerFee](size, func(_ common.Hash, tx *types.TxWithMinerFee) {
+		pool.qiSpent.remove(tx.Tx())
+	})
+	require.NoError(t, err)
+	pool.qiPool = qiPool
+	return pool
+}
+
+// newTestQiTx returns an unsigned transaction spending the outpoints, with a
+// fresh key so that every transaction has a distinct hash.
+func newTestQiTx(t *testing.T, fee int64, outpoints ...types.OutPoint) *types.TxWithMinerFee {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	pubKey := crypto.CompressPubkey(&key.PublicKey)
+	ins := make(types.TxIns, 0, len(outpoints))
+	for _, outpoint := range outpoints {
+		ins = append(ins, types.TxIn{PreviousOutPoint: outpoint, PubKey: pubKey})
+	}
+	tx := types.NewTx(&types.QiTx{ChainID: big.NewInt(1), TxIn: ins, TxOut: types.TxOuts{}})
+	withFee, err := types.NewTxWithMinerFee(tx, big.NewInt(fee), time.Now())
+	require.NoError(t, err)
+	return withFee
+}
+
+// addTestQiTx adds the transaction the way addQiTxs does after validation.
+func addTestQiTx(pool *TxPool, tx *types.TxWithMinerFee) error {
+	if err := pool.replaceQiConflictsLocked(tx); err != nil {
+		return err
+	}
+	pool.qiPool.Add(tx.Tx().Hash(), tx)
+	pool.qiSpent.add(tx.Tx())
+	return nil
+}
+
+func TestQiPoolReplaceByFee(t *testing.T) {
+	pool := newTestQiPool(t, 10)
+	a := types.OutPoint{TxHash: common.Hash{1}, Index: 0}
+	b := types.OutPoint{TxHash: common.Hash{1}, Index: 1}
+	c := types.OutPoint{TxHash: common.Hash{2}, Index: 0}
+
+	tx1 := newTestQiTx(t, 100, a)
+	tx2 := newTestQiTx(t, 100, b)
+	require.NoError(t, addTestQiTx(pool, tx1))
+	require.NoError(t, addTestQiTx(pool, tx2))
+
+	// Spending both outpoints has to pay 10% over both fees
+	underpriced := newTestQiTx(t, 219, a, b, c)
+	require.True(t, errors.Is(addTestQiTx(pool, underpriced), ErrReplaceUnderpriced))
+	require.Equal(t, 2, pool.qiPool.Len())
+	require.Equal(t, map[types.OutPoint]common.Hash{a: tx1.Tx().Hash()}, pool.QiConflicts([]types.OutPoint{a, c}))
+
+	replacement := newTestQiTx(t, 220, a, b, c)
+	require.NoError(t, addTestQiTx(pool, replacement))
+	require.Equal(t, 1, pool.qiPool.Len())
+	claimed := pool.QiConflicts(nil)
+	require.Len(t, claimed, 3)
+	for _, outpoint := range []types.OutPoint{a, b, c} {
+		require.Equal(t, replacement.Tx().Hash(), claimed[outpoint])
+	}
+
+	// Including a transaction spending one of the outpoints removes the spender
+	mined := newTestQiTx(t, 1, c)
+	pool.removeQiTxsLocked([]*types.Transaction{mined.Tx()})
+	require.Equal(t, 0, pool.qiPool.Len())
+	require.Empty(t, pool.QiConflicts(nil))
+}
+
+func TestQiPoolEvictionReleasesOutpoints(t *testing.T) {
+	pool := newTestQiPool(t, 1)
+	a := types.OutPoint{TxHash: common.Hash{1}, Index: 0}
+	b := types.OutPoint{TxHash: common.Hash{2}, Index: 0}
+
+	require.NoError(t, addTestQiTx(pool, newTestQiTx(t, 100, a)))
+	tx := newTestQiTx(t, 100, b)
+	require.NoError(t, addTestQiTx(pool, tx))
+	require.Equal(t, map[types.OutPoint]common.Hash{b: tx.Tx().Hash()}, pool.QiConflicts(nil))
+
+	// The outpoint of the evicted transaction can be spent without a fee bump
+	require.NoError(t, addTestQiTx(pool, newTestQiTx(t, 1, a)))
+}
@@ -101,6 +101,10 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrQiTxExpired is returned if a journaled Qi transaction outlived the
+	// lifetime of Qi transactions in the pool while the node was down.
+	ErrQiTxExpired = errors.New("qi transaction expired")
 )
 
 var (
@@ -332,6 +336,7 @@ type TxPool struct {
 	journal        *txJournal                                      // Journal of local transaction to back up to disk
 	qiPool         *lru.Cache[common.Hash, *types.TxWithMinerFee]  // Qi pool to store Qi transactions
 	qiTxFees       *lru.Cache[[16]byte, *big.Int]                  // Recent Qi transaction fees (hash is truncated to 16 bytes to save space)
+	qiSpent        *qiOutpointIndex                                // Outpoints spent by the transactions of the Qi pool
 	pending        map[common.InternalAddress]*txList              // All currently processable transactions
 	queue          map[common.InternalAddress]*txList              // Queued but non-processable transactions
 	beats          map[common.InternalAddress]time.Time            // Last heartbeat from each known account
@@ -357,8 +362,9 @@ type TxPool struct {
 	reqPromoteCh    chan *accountSet
 	queueTxEventCh  chan *types.Transaction
 	reorgDoneCh     chan chan struct{}
-	reorgShutdownCh chan struct{}  // requests shutdown of scheduleReorgLoop
-	wg              sync.WaitGroup // tracks loop, scheduleReorgLoop
+	reorgShutdownCh chan struct{}      // requests shutdown of scheduleReorgLoop
+	clearCh         chan chan struct{} // requests Clear from loop
+	wg              sync.WaitGroup     // tracks loop, scheduleReorgLoop
 
 	logger *log.Logger
 
@@ -429,6 +435,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		broadcastSet:       make(types.Transactions, 0),
 		reorgDoneCh:        make(chan chan struct{}, chainHeadChanSize),
 		reorgShutdownCh:    make(chan struct{}),
+		clearCh:            make(chan chan struct{}),
 		gasPrice:           new(big.Int).SetUint64(config.PriceLimit),
 		localTxsCount:      0,
 		remoteTxsCount:     0,
@@ -439,7 +446,11 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		poolSharingTxCh:    make(chan *types.Transaction, 100),
 	}
 
-	qiPool, _ := lru.New[common.Hash, *types.TxWithMinerFee](int(config.QiPoolSize))
+	// Evicted, expired and removed Qi transactions release their outpoints
+	pool.qiSpent = newQiOutpointIndex()
+	qiPool, _ := lru.NewWithEvict[common.Hash, *types.TxWithMinerFee](int(config.QiPoolSize), func(_ common.Hash, tx *types.TxWithMinerFee) {
+		pool.qiSpent.remove(tx.Tx())
+	})
 	pool.qiPool = qiPool
 
 	senders, _ := lru.New[common.Hash, common.InternalAddress](int(config.MaxSenders))
@@ -472,10 +483,10 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal, logger)
 
-		if err := pool.journal.load(pool.AddLocals); err != nil {
+		if err := pool.journal.load(pool.AddLocals, pool.addJournaledQiTxs); err != nil {
 			logger.WithField("err", err).Warn("Failed to load transaction journal")
 		}
-		if err := pool.journal.rotate(pool.local()); err != nil {
+		if err := pool.journal.rotate(pool.local(), pool.localQi()); err != nil {
 			logger.WithField("err", err).Warn("Failed to rotate transaction journal")
 		}
 	}
@@ -542,6 +553,24 @@ func (pool *TxPool) loop() {
 				head = ev.Block
 			}
 
+		// Handle Clear, once the head events received before it are handled,
+		// so that their reorgs do not reinject transactions afterwards
+		case done := <-pool.clearCh:
+			for drained := false; !drained; {
+				select {
+				case ev := <-pool.chainHeadCh:
+					if ev.Block != nil {
+						pool.requestReset(head, ev.Block)
+						head = ev.Block
+					}
+				default:
+					drained = true
+				}
+			}
+			<-pool.requestReset(nil, pool.chain.CurrentBlock())
+			pool.clear()
+			close(done)
+
 		// System shutdown.
 		case <-pool.chainHeadSub.Err():
 			close(pool.reorgShutdownCh)
@@ -585,7 +614,7 @@ func (pool *TxPool) loop() {
 		case <-journal.C:
 			if pool.journal != nil {
 				pool.mu.Lock()
-				if err := pool.journal.rotate(pool.local()); err != nil {
+				if err := pool.journal.rotate(pool.local(), pool.localQi()); err != nil {
 					pool.logger.WithField("err", err).Warn("Failed to rotate local tx journal")
 				}
 				pool.mu.Unlock()
@@ -1230,7 +1259,7 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 		news = append(news, tx)
 	}
 	if len(qiNews) > 0 {
-		qiErrs := pool.addQiTxs(qiNews)
+		qiErrs := pool.addQiTxs(qiNews, nil, local)
 		var nilSlot = 0
 		for _, err := range qiErrs {
 			for errs[nilSlot] != nil {
@@ -1263,9 +1292,10 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 var txPoolFullErrs uint64
 var feesErrs uint64
 
-// addQiTxs adds Qi transactions to the Qi pool.
+// addQiTxs adds Qi transactions to the Qi pool, received at the given times or
+// now if there are none. Local transactions are journaled.
 // The qiMu lock must NOT be held by the caller.
-func (pool *TxPool) addQiTxs(txs types.Transactions) []error {
+func (pool *TxPool) addQiTxs(txs types.Transactions, received []time.Time, local bool) []error {
 	errs := make([]error, 0)
 	currentBlock := pool.chain.CurrentBlock()
 	etxRLimit := len(currentBlock.Transactions()) / params.ETXRegionMaxFraction
@@ -1278,7 +1308,7 @@ func (pool *TxPool) addQiTxs(txs types.Transactions) []error {
 	}
 	activeLocations := common.NewChainsAdded(pool.chain.CurrentBlock().ExpansionNumber())
 	transactionsWithoutErrors := make([]*types.TxWithMinerFee, 0, len(txs))
-	for _, tx := range txs {
+	for i, tx := range txs {
 		// Reject TX if it emits an output to an inactive chain
 		for _, txo := range tx.TxOut() {
 			found := false
@@ -1310,17 +1340,33 @@ func (pool *TxPool) addQiTxs(txs types.Transactions) []error {
 			errs = append(errs, err)
 			continue
 		}
-		txWithMinerFee, err := types.NewTxWithMinerFee(tx, txFee, time.Now())
+		receivedAt := time.Now()
+		if received != nil {
+			receivedAt = received[i]
+		}
+		txWithMinerFee, err := types.NewTxWithMinerFee(tx, txFee, receivedAt)
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
+		if pool.qiTxExpired(txWithMinerFee) {
+			errs = append(errs, ErrQiTxExpired)
+			continue
+		}
+		if local {
+			tx.SetLocal(true)
+		}
 		transactionsWithoutErrors = append(transactionsWithoutErrors, txWithMinerFee)
 	}
 	for _, txWithFee := range transactionsWithoutErrors {
-
+		if err := pool.replaceQiConflictsLocked(txWithFee); err != nil {
+			errs = append(errs, err)
+			continue
+		}
 		txHash := txWithFee.Tx().Hash()
 		pool.qiPool.Add(txHash, txWithFee)
+		pool.qiSpent.add(txWithFee.Tx())
+		pool.journalQiTx(txWithFee)
 		pool.queueTxEvent(txWithFee.Tx())
 		select {
 		case pool.sendersCh <- newSender{txHash, common.InternalAddress{}}: // There is no "sender" for Qi transactions, but the sig is good
@@ -1392,7 +1438,17 @@ func (pool *TxPool) addQiTxsWithoutValidationLocked(txs types.Transactions) {
 			pool.logger.Error("Error creating txWithMinerFee: " + err.Error())
 			continue
 		}
+		// A transaction of the pool spending the same outpoints was accepted
+		// while this one was in a block, so it is kept instead
+		if conflicts := pool.qiSpent.conflicts(tx); len(conflicts) > 0 {
+			pool.logger.WithFields(logrus.Fields{
+				"tx":        tx.Hash().String(),
+				"conflicts": len(conflicts),
+			}).Debug("Conflicting Qi transaction, skipping re-inject")
+			continue
+		}
 		pool.qiPool.Add(tx.Hash(), txWithMinerFee)
+		pool.qiSpent.add(tx)
 		select {
 		case pool.sendersCh <- newSender{tx.Hash(), common.InternalAddress{}}: // There is no "sender" for Qi transactions, but the sig is good
 		default:
@@ -1419,10 +1475,18 @@ func (pool *TxPool) RemoveQiTxs(txs []*common.Hash) {
 	qiTxGauge.Sub(float64(txsRemoved))
 }
 
+// removeQiTxsLocked removes the given Qi transactions, which were included in a
+// block, along with the transactions of the pool spending the same outpoints,
+// which can no longer be included.
 // Mempool lock must be held.
 func (pool *TxPool) removeQiTxsLocked(txs []*types.Transaction) {
 	txsRemoved := 0
 	for _, tx := range txs {
+		for _, conflict := range pool.qiSpent.conflicts(tx) {
+			if pool.qiPool.Remove(conflict) {
+				txsRemoved++
+			}
+		}
 		if _, exists := pool.qiPool.Get(tx.Hash()); exists {
 			pool.qiPool.Remove(tx.Hash())
 			txsRemoved++
@@ -1431,6 +1495,87 @@ func (pool *TxPool) removeQiTxsLocked(txs []*types.Transaction) {
 	qiTxGauge.Sub(float64(txsRemoved))
 }
 
+// replaceQiConflictsLocked makes room for a Qi transaction spending outpoints
+// already claimed by transactions of the pool, which are replaced if its fee
+// exceeds their total fee by the price bump, mirroring the replacement of Quai
+// transactions with the same nonce.
+// Mempool lock must be held.
+func (pool *TxPool) replaceQiConflictsLocked(tx *types.TxWithMinerFee) error {
+	conflicts := pool.qiSpent.conflicts(tx.Tx())
+	if len(conflicts) == 0 {
+		return nil
+	}
+	replacedFee := new(big.Int)
+	for _, hash := range conflicts {
+		if conflict, ok := pool.qiPool.Peek(hash); ok {
+			replacedFee.Add(replacedFee, conflict.MinerFee())
+		}
+	}
+	threshold := new(big.Int).Mul(replacedFee, big.NewInt(100+int64(pool.config.PriceBump)))
+	threshold.Div(threshold, big.NewInt(100))
+	if tx.MinerFee().Cmp(threshold) < 0 {
+		return fmt.Errorf("%w: fee %v is below %v, the fee of the %d pool transactions spending the same outpoints bumped by %d%%", ErrReplaceUnderpriced, tx.MinerFee(), threshold, len(conflicts), pool.config.PriceBump)
+	}
+	txsRemoved := 0
+	for _, hash := range conflicts {
+		if pool.qiPool.Remove(hash) {
+			txsRemoved++
+		}
+	}
+	qiTxGauge.Sub(float64(txsRemoved))
+	pool.logger.WithFields(logrus.Fields{
+		"tx":       tx.Tx().Hash().String(),
+		"fee":      tx.MinerFee(),
+		"replaced": conflicts,
+	}).Debug("Replaced conflicting qi txs")
+	return nil
+}
+
+// addJournaledQiTxs adds the local Qi transactions loaded from the journal,
+// revalidating them against the current UTXO set. Those which expired while the
+// node was down are dropped.
+func (pool *TxPool) addJournaledQiTxs(txs []*types.Transaction, received []time.Time) []error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.addQiTxs(txs, received, true)
+}
+
+// journalQiTx adds the specified Qi transaction to the local disk journal if it
+// was submitted locally.
+func (pool *TxPool) journalQiTx(tx *types.TxWithMinerFee) {
+	if pool.journal == nil || !tx.Tx().IsLocal() {
+		return
+	}
+	if err := pool.journal.insertQi(tx); err != nil {
+		pool.logger.WithField("err", err).Warn("Failed to journal local qi transaction")
+	}
+}
+
+// localQi retrieves the local Qi transactions of the pool, oldest first.
+func (pool *TxPool) localQi() []*types.TxWithMinerFee {
+	txs := make([]*types.TxWithMinerFee, 0)
+	for _, tx := range pool.qiPool.Values() {
+		if tx.Tx().IsLocal() {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// qiTxExpired reports whether the Qi transaction has been in the pool for longer
+// than the lifetime of Qi transactions.
+func (pool *TxPool) qiTxExpired(tx *types.TxWithMinerFee) bool {
+	return time.Since(tx.Received()) > pool.config.QiTxLifetime
+}
+
+// QiConflicts returns the transactions of the Qi pool spending the given
+// outpoints, or the spenders of all the outpoints claimed by the pool if none
+// is given.
+func (pool *TxPool) QiConflicts(outpoints []types.OutPoint) map[types.OutPoint]common.Hash {
+	return pool.qiSpent.claimed(outpoints)
+}
+
 func (pool *TxPool) AsyncRemoveQiTxs(invalidTxHashes []*common.Hash) {
 	select {
 	case pool.invalidQiTxsCh <- invalidTxHashes:
@@ -1507,6 +1652,42 @@ func (pool *TxPool) Has(hash common.Hash) bool {
 	return pool.all.Get(hash) != nil
 }
 
+// Sync resets the pool to the current head of the chain, promoting the queued
+// transactions, and waits for it to be done. Chains sealed faster than the
+// pool runs its reorgs, such as in the developer mode, use it so that the pool
+// no longer holds the transactions of the head, and validates the transactions
+// added next against its state.
+func (pool *TxPool) Sync() {
+	<-pool.requestReset(nil, pool.chain.CurrentBlock())
+}
+
+// Clear removes all the transactions from the pool, and waits for it to be
+// done. Chains generated by tests from an arbitrary parent use it, so that the
+// transactions of the blocks they reorg out are not included again.
+func (pool *TxPool) Clear() {
+	done := make(chan struct{})
+	select {
+	case pool.clearCh <- done:
+		<-done
+	case <-pool.reorgShutdownCh:
+	}
+}
+
+func (pool *TxPool) clear() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.all = newTxLookup()
+	pool.priced = newTxPricedList(pool.all)
+	pool.pending = make(map[common.InternalAddress]*txList)
+	pool.queue = make(map[common.InternalAddress]*txList)
+	pool.beats = make(map[common.InternalAddress]time.Time)
+	pool.pendingNonces = newTxNoncer(pool.currentState)
+	pool.qiPool.Purge()
+	pendingTxGauge.Set(0)
+	queuedGauge.Set(0)
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
@@ -1966,6 +2147,12 @@ func (pool *TxPool) reset(oldHead, newHead *types.WorkObject) {
 		}
 	} else {
 		block := pool.chain.GetBlock(newHead.Hash(), newHead.Number(pool.chainconfig.Location.Context()).Uint64())
+		if block == nil {
+			// The new head was already deleted by a rewind of the chain, whose
+			// own head event resets the pool again
+			pool.logger.WithField("hash", newHead.Hash()).Warn("Transaction pool reset with missing newhead")
+			return
+		}
 		pool.removeQiTxsLocked(block.QiTransactionsWithoutCoinbase())
 		pool.logger.WithField("count", len(block.QiTransactionsWithoutCoinbase())).Debug("Removed qi txs from pool")
 	}
@@ -2528,7 +2715,7 @@ func (pool *TxPool) qiTxExpirationGoroutine() {
 			// Grabbing lock is not necessary as LRU already has lock internally
 			for i := 0; i < pool.qiPool.Len()/qiExpirationCheckDivisor; i++ {
 				_, oldestTx, _ := pool.qiPool.GetOldest()
-				if time.Since(oldestTx.Received()) > pool.config.QiTxLifetime {
+				if pool.qiTxExpired(oldestTx) {
 					pool.qiPool.Remove(oldestTx.Tx().Hash())
 				}
 			}
@@ -1251,6 +1251,51 @@ func (x *ProtoWorkObjectHeaderView) GetWorkObject() *ProtoWorkObject {
 	return nil
 }
 
+type ProtoWorkObjectHeadersView struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkObjects []*ProtoWorkObjectHeaderView `protobuf:"bytes,1,rep,name=work_objects,json=workObjects,proto3" json:"work_objects,omitempty"`
+}
+
+func (x *ProtoWorkObjectHeadersView) Reset() {
+	*x = ProtoWorkObjectHeadersView{}
+	mi := &file_core_types_proto_block_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoWorkObjectHeadersView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoWorkObjectHeadersView) ProtoMessage() {}
+
+func (x *ProtoWorkObjectHeadersView) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_proto_block_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoWorkObjectHeadersView.ProtoReflect.Descriptor instead.
+func (*ProtoWorkObjectHeadersView) Descriptor() ([]byte, []int) {
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProtoWorkObjectHeadersView) GetWorkObjects() []*ProtoWorkObjectHeaderView {
+	if x != nil {
+		return x.WorkObjects
+	}
+	return nil
+}
+
 type ProtoWorkObjectShareView struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1261,7 +1306,7 @@ type ProtoWorkObjectShareView struct {
 
 func (x *ProtoWorkObjectShareView) Reset() {
 	*x = ProtoWorkObjectShareView{}
-	mi := &file_core_types_proto_block_proto_msgTypes[14]
+	mi := &file_core_types_proto_block_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1273,7 +1318,7 @@ func (x *ProtoWorkObjectShareView) String() string {
 func (*ProtoWorkObjectShareView) ProtoMessage() {}
 
 func (x *ProtoWorkObjectShareView) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[14]
+	mi := &file_core_types_proto_block_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1286,7 +1331,7 @@ func (x *ProtoWorkObjectShareView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoWorkObjectShareView.ProtoReflect.Descriptor instead.
 func (*ProtoWorkObjectShareView) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{14}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ProtoWorkObjectShareView) GetWorkObject() *ProtoWorkObject {
@@ -1307,7 +1352,7 @@ type ProtoAccessTuple struct {
 
 func (x *ProtoAccessTuple) Reset() {
 	*x = ProtoAccessTuple{}
-	mi := &file_core_types_proto_block_proto_msgTypes[15]
+	mi := &file_core_types_proto_block_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1319,7 +1364,7 @@ func (x *ProtoAccessTuple) String() string {
 func (*ProtoAccessTuple) ProtoMessage() {}
 
 func (x *ProtoAccessTuple) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[15]
+	mi := &file_core_types_proto_block_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1332,7 +1377,7 @@ func (x *ProtoAccessTuple) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoAccessTuple.ProtoReflect.Descriptor instead.
 func (*ProtoAccessTuple) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{15}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ProtoAccessTuple) GetAddress() []byte {
@@ -1365,7 +1410,7 @@ type ProtoReceiptForStorage struct {
 
 func (x *ProtoReceiptForStorage) Reset() {
 	*x = ProtoReceiptForStorage{}
-	mi := &file_core_types_proto_block_proto_msgTypes[16]
+	mi := &file_core_types_proto_block_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1377,7 +1422,7 @@ func (x *ProtoReceiptForStorage) String() string {
 func (*ProtoReceiptForStorage) ProtoMessage() {}
 
 func (x *ProtoReceiptForStorage) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[16]
+	mi := &file_core_types_proto_block_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1390,7 +1435,7 @@ func (x *ProtoReceiptForStorage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoReceiptForStorage.ProtoReflect.Descriptor instead.
 func (*ProtoReceiptForStorage) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{16}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ProtoReceiptForStorage) GetPostStateOrStatus() []byte {
@@ -1452,7 +1497,7 @@ type ProtoReceiptsForStorage struct {
 
 func (x *ProtoReceiptsForStorage) Reset() {
 	*x = ProtoReceiptsForStorage{}
-	mi := &file_core_types_proto_block_proto_msgTypes[17]
+	mi := &file_core_types_proto_block_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1464,7 +1509,7 @@ func (x *ProtoReceiptsForStorage) String() string {
 func (*ProtoReceiptsForStorage) ProtoMessage() {}
 
 func (x *ProtoReceiptsForStorage) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[17]
+	mi := &file_core_types_proto_block_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1477,7 +1522,7 @@ func (x *ProtoReceiptsForStorage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoReceiptsForStorage.ProtoReflect.Descriptor instead.
 func (*ProtoReceiptsForStorage) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{17}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ProtoReceiptsForStorage) GetReceipts() []*ProtoReceiptForStorage {
@@ -1499,7 +1544,7 @@ type ProtoLogForStorage struct {
 
 func (x *ProtoLogForStorage) Reset() {
 	*x = ProtoLogForStorage{}
-	mi := &file_core_types_proto_block_proto_msgTypes[18]
+	mi := &file_core_types_proto_block_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1511,7 +1556,7 @@ func (x *ProtoLogForStorage) String() string {
 func (*ProtoLogForStorage) ProtoMessage() {}
 
 func (x *ProtoLogForStorage) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[18]
+	mi := &file_core_types_proto_block_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1524,7 +1569,7 @@ func (x *ProtoLogForStorage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoLogForStorage.ProtoReflect.Descriptor instead.
 func (*ProtoLogForStorage) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{18}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ProtoLogForStorage) GetAddress() *common.ProtoAddress {
@@ -1558,7 +1603,7 @@ type ProtoLogsForStorage struct {
 
 func (x *ProtoLogsForStorage) Reset() {
 	*x = ProtoLogsForStorage{}
-	mi := &file_core_types_proto_block_proto_msgTypes[19]
+	mi := &file_core_types_proto_block_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1570,7 +1615,7 @@ func (x *ProtoLogsForStorage) String() string {
 func (*ProtoLogsForStorage) ProtoMessage() {}
 
 func (x *ProtoLogsForStorage) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[19]
+	mi := &file_core_types_proto_block_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1583,7 +1628,7 @@ func (x *ProtoLogsForStorage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoLogsForStorage.ProtoReflect.Descriptor instead.
 func (*ProtoLogsForStorage) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{19}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ProtoLogsForStorage) GetLogs() []*ProtoLogForStorage {
@@ -1604,7 +1649,7 @@ type ProtoPendingHeader struct {
 
 func (x *ProtoPendingHeader) Reset() {
 	*x = ProtoPendingHeader{}
-	mi := &file_core_types_proto_block_proto_msgTypes[20]
+	mi := &file_core_types_proto_block_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1616,7 +1661,7 @@ func (x *ProtoPendingHeader) String() string {
 func (*ProtoPendingHeader) ProtoMessage() {}
 
 func (x *ProtoPendingHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[20]
+	mi := &file_core_types_proto_block_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1629,7 +1674,7 @@ func (x *ProtoPendingHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoPendingHeader.ProtoReflect.Descriptor instead.
 func (*ProtoPendingHeader) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{20}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *ProtoPendingHeader) GetWo() *ProtoWorkObject {
@@ -1657,7 +1702,7 @@ type ProtoTermini struct {
 
 func (x *ProtoTermini) Reset() {
 	*x = ProtoTermini{}
-	mi := &file_core_types_proto_block_proto_msgTypes[21]
+	mi := &file_core_types_proto_block_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1669,7 +1714,7 @@ func (x *ProtoTermini) String() string {
 func (*ProtoTermini) ProtoMessage() {}
 
 func (x *ProtoTermini) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[21]
+	mi := &file_core_types_proto_block_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1682,7 +1727,7 @@ func (x *ProtoTermini) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTermini.ProtoReflect.Descriptor instead.
 func (*ProtoTermini) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{21}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *ProtoTermini) GetDomTermini() []*common.ProtoHash {
@@ -1709,7 +1754,7 @@ type ProtoEtxSet struct {
 
 func (x *ProtoEtxSet) Reset() {
 	*x = ProtoEtxSet{}
-	mi := &file_core_types_proto_block_proto_msgTypes[22]
+	mi := &file_core_types_proto_block_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1721,7 +1766,7 @@ func (x *ProtoEtxSet) String() string {
 func (*ProtoEtxSet) ProtoMessage() {}
 
 func (x *ProtoEtxSet) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[22]
+	mi := &file_core_types_proto_block_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1734,7 +1779,7 @@ func (x *ProtoEtxSet) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoEtxSet.ProtoReflect.Descriptor instead.
 func (*ProtoEtxSet) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{22}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *ProtoEtxSet) GetEtxHashes() []byte {
@@ -1755,7 +1800,7 @@ type ProtoPendingEtxs struct {
 
 func (x *ProtoPendingEtxs) Reset() {
 	*x = ProtoPendingEtxs{}
-	mi := &file_core_types_proto_block_proto_msgTypes[23]
+	mi := &file_core_types_proto_block_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1767,7 +1812,7 @@ func (x *ProtoPendingEtxs) String() string {
 func (*ProtoPendingEtxs) ProtoMessage() {}
 
 func (x *ProtoPendingEtxs) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[23]
+	mi := &file_core_types_proto_block_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1780,7 +1825,7 @@ func (x *ProtoPendingEtxs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoPendingEtxs.ProtoReflect.Descriptor instead.
 func (*ProtoPendingEtxs) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{23}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ProtoPendingEtxs) GetHeader() *ProtoWorkObject {
@@ -1808,7 +1853,7 @@ type ProtoPendingEtxsRollup struct {
 
 func (x *ProtoPendingEtxsRollup) Reset() {
 	*x = ProtoPendingEtxsRollup{}
-	mi := &file_core_types_proto_block_proto_msgTypes[24]
+	mi := &file_core_types_proto_block_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1820,7 +1865,7 @@ func (x *ProtoPendingEtxsRollup) String() string {
 func (*ProtoPendingEtxsRollup) ProtoMessage() {}
 
 func (x *ProtoPendingEtxsRollup) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[24]
+	mi := &file_core_types_proto_block_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1833,7 +1878,7 @@ func (x *ProtoPendingEtxsRollup) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoPendingEtxsRollup.ProtoReflect.Descriptor instead.
 func (*ProtoPendingEtxsRollup) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{24}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *ProtoPendingEtxsRollup) GetHeader() *ProtoWorkObject {
@@ -1860,7 +1905,7 @@ type ProtoTxIns struct {
 
 func (x *ProtoTxIns) Reset() {
 	*x = ProtoTxIns{}
-	mi := &file_core_types_proto_block_proto_msgTypes[25]
+	mi := &file_core_types_proto_block_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1872,7 +1917,7 @@ func (x *ProtoTxIns) String() string {
 func (*ProtoTxIns) ProtoMessage() {}
 
 func (x *ProtoTxIns) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[25]
+	mi := &file_core_types_proto_block_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1885,7 +1930,7 @@ func (x *ProtoTxIns) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTxIns.ProtoReflect.Descriptor instead.
 func (*ProtoTxIns) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{25}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ProtoTxIns) GetTxIns() []*ProtoTxIn {
@@ -1905,7 +1950,7 @@ type ProtoTxOuts struct {
 
 func (x *ProtoTxOuts) Reset() {
 	*x = ProtoTxOuts{}
-	mi := &file_core_types_proto_block_proto_msgTypes[26]
+	mi := &file_core_types_proto_block_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1917,7 +1962,7 @@ func (x *ProtoTxOuts) String() string {
 func (*ProtoTxOuts) ProtoMessage() {}
 
 func (x *ProtoTxOuts) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[26]
+	mi := &file_core_types_proto_block_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1930,7 +1975,7 @@ func (x *ProtoTxOuts) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTxOuts.ProtoReflect.Descriptor instead.
 func (*ProtoTxOuts) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{26}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *ProtoTxOuts) GetTxOuts() []*ProtoTxOut {
@@ -1951,7 +1996,7 @@ type ProtoTxIn struct {
 
 func (x *ProtoTxIn) Reset() {
 	*x = ProtoTxIn{}
-	mi := &file_core_types_proto_block_proto_msgTypes[27]
+	mi := &file_core_types_proto_block_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1963,7 +2008,7 @@ func (x *ProtoTxIn) String() string {
 func (*ProtoTxIn) ProtoMessage() {}
 
 func (x *ProtoTxIn) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[27]
+	mi := &file_core_types_proto_block_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1976,7 +2021,7 @@ func (x *ProtoTxIn) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTxIn.ProtoReflect.Descriptor instead.
 func (*ProtoTxIn) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{27}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ProtoTxIn) GetPreviousOutPoint() *ProtoOutPoint {
@@ -2004,7 +2049,7 @@ type ProtoOutPoint struct {
 
 func (x *ProtoOutPoint) Reset() {
 	*x = ProtoOutPoint{}
-	mi := &file_core_types_proto_block_proto_msgTypes[28]
+	mi := &file_core_types_proto_block_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2016,7 +2061,7 @@ func (x *ProtoOutPoint) String() string {
 func (*ProtoOutPoint) ProtoMessage() {}
 
 func (x *ProtoOutPoint) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[28]
+	mi := &file_core_types_proto_block_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2029,7 +2074,7 @@ func (x *ProtoOutPoint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoOutPoint.ProtoReflect.Descriptor instead.
 func (*ProtoOutPoint) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{28}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ProtoOutPoint) GetHash() *common.ProtoHash {
@@ -2058,7 +2103,7 @@ type ProtoTxOut struct {
 
 func (x *ProtoTxOut) Reset() {
 	*x = ProtoTxOut{}
-	mi := &file_core_types_proto_block_proto_msgTypes[29]
+	mi := &file_core_types_proto_block_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2070,7 +2115,7 @@ func (x *ProtoTxOut) String() string {
 func (*ProtoTxOut) ProtoMessage() {}
 
 func (x *ProtoTxOut) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[29]
+	mi := &file_core_types_proto_block_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2083,7 +2128,7 @@ func (x *ProtoTxOut) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTxOut.ProtoReflect.Descriptor instead.
 func (*ProtoTxOut) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{29}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *ProtoTxOut) GetDenomination() uint32 {
@@ -2120,7 +2165,7 @@ type ProtoOutPointAndDenomination struct {
 
 func (x *ProtoOutPointAndDenomination) Reset() {
 	*x = ProtoOutPointAndDenomination{}
-	mi := &file_core_types_proto_block_proto_msgTypes[30]
+	mi := &file_core_types_proto_block_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2132,7 +2177,7 @@ func (x *ProtoOutPointAndDenomination) String() string {
 func (*ProtoOutPointAndDenomination) ProtoMessage() {}
 
 func (x *ProtoOutPointAndDenomination) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[30]
+	mi := &file_core_types_proto_block_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2145,7 +2190,7 @@ func (x *ProtoOutPointAndDenomination) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoOutPointAndDenomination.ProtoReflect.Descriptor instead.
 func (*ProtoOutPointAndDenomination) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{30}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *ProtoOutPointAndDenomination) GetHash() *common.ProtoHash {
@@ -2186,7 +2231,7 @@ type ProtoAddressOutPoints struct {
 
 func (x *ProtoAddressOutPoints) Reset() {
 	*x = ProtoAddressOutPoints{}
-	mi := &file_core_types_proto_block_proto_msgTypes[31]
+	mi := &file_core_types_proto_block_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2198,7 +2243,7 @@ func (x *ProtoAddressOutPoints) String() string {
 func (*ProtoAddressOutPoints) ProtoMessage() {}
 
 func (x *ProtoAddressOutPoints) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[31]
+	mi := &file_core_types_proto_block_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2211,7 +2256,7 @@ func (x *ProtoAddressOutPoints) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoAddressOutPoints.ProtoReflect.Descriptor instead.
 func (*ProtoAddressOutPoints) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{31}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *ProtoAddressOutPoints) GetOutPoints() []*ProtoOutPointAndDenomination {
@@ -2232,7 +2277,7 @@ type ProtoSpentUTXO struct {
 
 func (x *ProtoSpentUTXO) Reset() {
 	*x = ProtoSpentUTXO{}
-	mi := &file_core_types_proto_block_proto_msgTypes[32]
+	mi := &file_core_types_proto_block_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2244,7 +2289,7 @@ func (x *ProtoSpentUTXO) String() string {
 func (*ProtoSpentUTXO) ProtoMessage() {}
 
 func (x *ProtoSpentUTXO) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[32]
+	mi := &file_core_types_proto_block_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2257,7 +2302,7 @@ func (x *ProtoSpentUTXO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoSpentUTXO.ProtoReflect.Descriptor instead.
 func (*ProtoSpentUTXO) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{32}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *ProtoSpentUTXO) GetOutpoint() *ProtoOutPoint {
@@ -2284,7 +2329,7 @@ type ProtoSpentUTXOs struct {
 
 func (x *ProtoSpentUTXOs) Reset() {
 	*x = ProtoSpentUTXOs{}
-	mi := &file_core_types_proto_block_proto_msgTypes[33]
+	mi := &file_core_types_proto_block_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2296,7 +2341,7 @@ func (x *ProtoSpentUTXOs) String() string {
 func (*ProtoSpentUTXOs) ProtoMessage() {}
 
 func (x *ProtoSpentUTXOs) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[33]
+	mi := &file_core_types_proto_block_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2309,7 +2354,7 @@ func (x *ProtoSpentUTXOs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoSpentUTXOs.ProtoReflect.Descriptor instead.
 func (*ProtoSpentUTXOs) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{33}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ProtoSpentUTXOs) GetSutxos() []*ProtoSpentUTXO {
@@ -2329,7 +2374,7 @@ type ProtoKeys struct {
 
 func (x *ProtoKeys) Reset() {
 	*x = ProtoKeys{}
-	mi := &file_core_types_proto_block_proto_msgTypes[34]
+	mi := &file_core_types_proto_block_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2341,7 +2386,7 @@ func (x *ProtoKeys) String() string {
 func (*ProtoKeys) ProtoMessage() {}
 
 func (x *ProtoKeys) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[34]
+	mi := &file_core_types_proto_block_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2354,7 +2399,7 @@ func (x *ProtoKeys) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoKeys.ProtoReflect.Descriptor instead.
 func (*ProtoKeys) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{34}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *ProtoKeys) GetKeys() [][]byte {
@@ -2374,7 +2419,7 @@ type ProtoTrimDepths struct {
 
 func (x *ProtoTrimDepths) Reset() {
 	*x = ProtoTrimDepths{}
-	mi := &file_core_types_proto_block_proto_msgTypes[35]
+	mi := &file_core_types_proto_block_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2386,7 +2431,7 @@ func (x *ProtoTrimDepths) String() string {
 func (*ProtoTrimDepths) ProtoMessage() {}
 
 func (x *ProtoTrimDepths) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[35]
+	mi := &file_core_types_proto_block_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2399,7 +2444,7 @@ func (x *ProtoTrimDepths) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTrimDepths.ProtoReflect.Descriptor instead.
 func (*ProtoTrimDepths) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{35}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *ProtoTrimDepths) GetTrimDepths() map[uint32]uint64 {
@@ -2419,7 +2464,7 @@ type ProtoTokenChoiceSet struct {
 
 func (x *ProtoTokenChoiceSet) Reset() {
 	*x = ProtoTokenChoiceSet{}
-	mi := &file_core_types_proto_block_proto_msgTypes[36]
+	mi := &file_core_types_proto_block_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2431,7 +2476,7 @@ func (x *ProtoTokenChoiceSet) String() string {
 func (*ProtoTokenChoiceSet) ProtoMessage() {}
 
 func (x *ProtoTokenChoiceSet) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[36]
+	mi := &file_core_types_proto_block_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2444,7 +2489,7 @@ func (x *ProtoTokenChoiceSet) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTokenChoiceSet.ProtoReflect.Descriptor instead.
 func (*ProtoTokenChoiceSet) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{36}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *ProtoTokenChoiceSet) GetTokenChoiceArray() []*ProtoTokenChoiceArray {
@@ -2464,7 +2509,7 @@ type ProtoTokenChoiceArray struct {
 
 func (x *ProtoTokenChoiceArray) Reset() {
 	*x = ProtoTokenChoiceArray{}
-	mi := &file_core_types_proto_block_proto_msgTypes[37]
+	mi := &file_core_types_proto_block_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2476,7 +2521,7 @@ func (x *ProtoTokenChoiceArray) String() string {
 func (*ProtoTokenChoiceArray) ProtoMessage() {}
 
 func (x *ProtoTokenChoiceArray) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[37]
+	mi := &file_core_types_proto_block_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2489,7 +2534,7 @@ func (x *ProtoTokenChoiceArray) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTokenChoiceArray.ProtoReflect.Descriptor instead.
 func (*ProtoTokenChoiceArray) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{37}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *ProtoTokenChoiceArray) GetTokenChoices() *ProtoTokenChoice {
@@ -2511,7 +2556,7 @@ type ProtoTokenChoice struct {
 
 func (x *ProtoTokenChoice) Reset() {
 	*x = ProtoTokenChoice{}
-	mi := &file_core_types_proto_block_proto_msgTypes[38]
+	mi := &file_core_types_proto_block_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2523,7 +2568,7 @@ func (x *ProtoTokenChoice) String() string {
 func (*ProtoTokenChoice) ProtoMessage() {}
 
 func (x *ProtoTokenChoice) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[38]
+	mi := &file_core_types_proto_block_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2536,7 +2581,7 @@ func (x *ProtoTokenChoice) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoTokenChoice.ProtoReflect.Descriptor instead.
 func (*ProtoTokenChoice) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{38}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *ProtoTokenChoice) GetQuai() uint64 {
@@ -2571,7 +2616,7 @@ type ProtoBetas struct {
 
 func (x *ProtoBetas) Reset() {
 	*x = ProtoBetas{}
-	mi := &file_core_types_proto_block_proto_msgTypes[39]
+	mi := &file_core_types_proto_block_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2583,7 +2628,7 @@ func (x *ProtoBetas) String() string {
 func (*ProtoBetas) ProtoMessage() {}
 
 func (x *ProtoBetas) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[39]
+	mi := &file_core_types_proto_block_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2596,7 +2641,7 @@ func (x *ProtoBetas) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoBetas.ProtoReflect.Descriptor instead.
 func (*ProtoBetas) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{39}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ProtoBetas) GetBeta0() []byte {
@@ -2624,7 +2669,7 @@ type ProtoLockup struct {
 
 func (x *ProtoLockup) Reset() {
 	*x = ProtoLockup{}
-	mi := &file_core_types_proto_block_proto_msgTypes[40]
+	mi := &file_core_types_proto_block_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2636,7 +2681,7 @@ func (x *ProtoLockup) String() string {
 func (*ProtoLockup) ProtoMessage() {}
 
 func (x *ProtoLockup) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[40]
+	mi := &file_core_types_proto_block_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2649,7 +2694,7 @@ func (x *ProtoLockup) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoLockup.ProtoReflect.Descriptor instead.
 func (*ProtoLockup) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{40}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *ProtoLockup) GetValue() []byte {
@@ -2676,7 +2721,7 @@ type ProtoLockups struct {
 
 func (x *ProtoLockups) Reset() {
 	*x = ProtoLockups{}
-	mi := &file_core_types_proto_block_proto_msgTypes[41]
+	mi := &file_core_types_proto_block_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2688,7 +2733,7 @@ func (x *ProtoLockups) String() string {
 func (*ProtoLockups) ProtoMessage() {}
 
 func (x *ProtoLockups) ProtoReflect() protoreflect.Message {
-	mi := &file_core_types_proto_block_proto_msgTypes[41]
+	mi := &file_core_types_proto_block_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2701,7 +2746,7 @@ func (x *ProtoLockups) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProtoLockups.ProtoReflect.Descriptor instead.
 func (*ProtoLockups) Descriptor() ([]byte, []int) {
-	return file_core_types_proto_block_proto_rawDescGZIP(), []int{41}
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *ProtoLockups) GetLockups() []*ProtoLockup {
@@ -2711,6 +2756,327 @@ func (x *ProtoLockups) GetLockups() []*ProtoLockup {
 	return nil
 }
 
+type ProtoUtxoRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockHash *common.ProtoHash `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3,oneof" json:"block_hash,omitempty"`
+	Start     *ProtoOutPoint    `protobuf:"bytes,2,opt,name=start,proto3,oneof" json:"start,omitempty"`
+}
+
+func (x *ProtoUtxoRangeRequest) Reset() {
+	*x = ProtoUtxoRangeRequest{}
+	mi := &file_core_types_proto_block_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoUtxoRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoUtxoRangeRequest) ProtoMessage() {}
+
+func (x *ProtoUtxoRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_proto_block_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoUtxoRangeRequest.ProtoReflect.Descriptor instead.
+func (*ProtoUtxoRangeRequest) Descriptor() ([]byte, []int) {
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ProtoUtxoRangeRequest) GetBlockHash() *common.ProtoHash {
+	if x != nil {
+		return x.BlockHash
+	}
+	return nil
+}
+
+func (x *ProtoUtxoRangeRequest) GetStart() *ProtoOutPoint {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+type ProtoUtxoRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Utxos []*ProtoSpentUTXO `protobuf:"bytes,1,rep,name=utxos,proto3" json:"utxos,omitempty"`
+	Next  *ProtoOutPoint    `protobuf:"bytes,2,opt,name=next,proto3,oneof" json:"next,omitempty"`
+}
+
+func (x *ProtoUtxoRange) Reset() {
+	*x = ProtoUtxoRange{}
+	mi := &file_core_types_proto_block_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoUtxoRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoUtxoRange) ProtoMessage() {}
+
+func (x *ProtoUtxoRange) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_proto_block_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoUtxoRange.ProtoReflect.Descriptor instead.
+func (*ProtoUtxoRange) Descriptor() ([]byte, []int) {
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ProtoUtxoRange) GetUtxos() []*ProtoSpentUTXO {
+	if x != nil {
+		return x.Utxos
+	}
+	return nil
+}
+
+func (x *ProtoUtxoRange) GetNext() *ProtoOutPoint {
+	if x != nil {
+		return x.Next
+	}
+	return nil
+}
+
+type ProtoSyncBlockState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TokenChoiceSet  *ProtoTokenChoiceSet `protobuf:"bytes,1,opt,name=token_choice_set,json=tokenChoiceSet,proto3,oneof" json:"token_choice_set,omitempty"`
+	Betas           *ProtoBetas          `protobuf:"bytes,2,opt,name=betas,proto3,oneof" json:"betas,omitempty"`
+	CreatedUtxoKeys *ProtoKeys           `protobuf:"bytes,3,opt,name=created_utxo_keys,json=createdUtxoKeys,proto3,oneof" json:"created_utxo_keys,omitempty"`
+}
+
+func (x *ProtoSyncBlockState) Reset() {
+	*x = ProtoSyncBlockState{}
+	mi := &file_core_types_proto_block_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoSyncBlockState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoSyncBlockState) ProtoMessage() {}
+
+func (x *ProtoSyncBlockState) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_proto_block_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoSyncBlockState.ProtoReflect.Descriptor instead.
+func (*ProtoSyncBlockState) Descriptor() ([]byte, []int) {
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ProtoSyncBlockState) GetTokenChoiceSet() *ProtoTokenChoiceSet {
+	if x != nil {
+		return x.TokenChoiceSet
+	}
+	return nil
+}
+
+func (x *ProtoSyncBlockState) GetBetas() *ProtoBetas {
+	if x != nil {
+		return x.Betas
+	}
+	return nil
+}
+
+func (x *ProtoSyncBlockState) GetCreatedUtxoKeys() *ProtoKeys {
+	if x != nil {
+		return x.CreatedUtxoKeys
+	}
+	return nil
+}
+
+type ProtoWorkShareReward struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockHash    *common.ProtoHash `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3,oneof" json:"block_hash,omitempty"`
+	BlockNumber  *uint64           `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3,oneof" json:"block_number,omitempty"`
+	WorkShare    *common.ProtoHash `protobuf:"bytes,3,opt,name=work_share,json=workShare,proto3,oneof" json:"work_share,omitempty"`
+	Distance     *uint64           `protobuf:"varint,4,opt,name=distance,proto3,oneof" json:"distance,omitempty"`
+	Secondary    *bool             `protobuf:"varint,5,opt,name=secondary,proto3,oneof" json:"secondary,omitempty"`
+	Lock         *uint32           `protobuf:"varint,6,opt,name=lock,proto3,oneof" json:"lock,omitempty"`
+	Reward       []byte            `protobuf:"bytes,7,opt,name=reward,proto3,oneof" json:"reward,omitempty"`
+	LockedValue  []byte            `protobuf:"bytes,8,opt,name=locked_value,json=lockedValue,proto3,oneof" json:"locked_value,omitempty"`
+	UnlockHeight *uint64           `protobuf:"varint,9,opt,name=unlock_height,json=unlockHeight,proto3,oneof" json:"unlock_height,omitempty"`
+}
+
+func (x *ProtoWorkShareReward) Reset() {
+	*x = ProtoWorkShareReward{}
+	mi := &file_core_types_proto_block_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoWorkShareReward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoWorkShareReward) ProtoMessage() {}
+
+func (x *ProtoWorkShareReward) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_proto_block_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoWorkShareReward.ProtoReflect.Descriptor instead.
+func (*ProtoWorkShareReward) Descriptor() ([]byte, []int) {
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ProtoWorkShareReward) GetBlockHash() *common.ProtoHash {
+	if x != nil {
+		return x.BlockHash
+	}
+	return nil
+}
+
+func (x *ProtoWorkShareReward) GetBlockNumber() uint64 {
+	if x != nil && x.BlockNumber != nil {
+		return *x.BlockNumber
+	}
+	return 0
+}
+
+func (x *ProtoWorkShareReward) GetWorkShare() *common.ProtoHash {
+	if x != nil {
+		return x.WorkShare
+	}
+	return nil
+}
+
+func (x *ProtoWorkShareReward) GetDistance() uint64 {
+	if x != nil && x.Distance != nil {
+		return *x.Distance
+	}
+	return 0
+}
+
+func (x *ProtoWorkShareReward) GetSecondary() bool {
+	if x != nil && x.Secondary != nil {
+		return *x.Secondary
+	}
+	return false
+}
+
+func (x *ProtoWorkShareReward) GetLock() uint32 {
+	if x != nil && x.Lock != nil {
+		return *x.Lock
+	}
+	return 0
+}
+
+func (x *ProtoWorkShareReward) GetReward() []byte {
+	if x != nil {
+		return x.Reward
+	}
+	return nil
+}
+
+func (x *ProtoWorkShareReward) GetLockedValue() []byte {
+	if x != nil {
+		return x.LockedValue
+	}
+	return nil
+}
+
+func (x *ProtoWorkShareReward) GetUnlockHeight() uint64 {
+	if x != nil && x.UnlockHeight != nil {
+		return *x.UnlockHeight
+	}
+	return 0
+}
+
+type ProtoWorkShareRewards struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rewards []*ProtoWorkShareReward `protobuf:"bytes,1,rep,name=rewards,proto3" json:"rewards,omitempty"`
+}
+
+func (x *ProtoWorkShareRewards) Reset() {
+	*x = ProtoWorkShareRewards{}
+	mi := &file_core_types_proto_block_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoWorkShareRewards) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoWorkShareRewards) ProtoMessage() {}
+
+func (x *ProtoWorkShareRewards) ProtoReflect() protoreflect.Message {
+	mi := &file_core_types_proto_block_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoWorkShareRewards.ProtoReflect.Descriptor instead.
+func (*ProtoWorkShareRewards) Descriptor() ([]byte, []int) {
+	return file_core_types_proto_block_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ProtoWorkShareRewards) GetRewards() []*ProtoWorkShareReward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
 var File_core_types_proto_block_proto protoreflect.FileDescriptor
 
 var file_core_types_proto_block_proto_rawDesc = []byte{
@@ -3065,213 +3431,290 @@ var file_core_types_proto_block_proto_rawDesc = []byte{
 	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
 	0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x77, 0x6f,
 	0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f,
-	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x68, 0x0a, 0x18, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x68,
-	0x61, 0x72, 0x65, 0x56, 0x69, 0x65, 0x77, 0x12, 0x3c, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
-	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65,
-	0x63, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x60, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x54, 0x75, 0x70, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x6b,
-	0x65, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0a, 0x73, 0x74, 0x6f,
-	0x72, 0x61, 0x67, 0x65, 0x4b, 0x65, 0x79, 0x22, 0xf0, 0x02, 0x0a, 0x16, 0x50, 0x72, 0x6f, 0x74,
-	0x6f, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x46, 0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61,
-	0x67, 0x65, 0x12, 0x2f, 0x0a, 0x14, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x5f, 0x6f, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x11, 0x70, 0x6f, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4f, 0x72, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76,
-	0x65, 0x5f, 0x67, 0x61, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x11, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x47, 0x61, 0x73, 0x55,
-	0x73, 0x65, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c,
-	0x6f, 0x67, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x04, 0x6c,
-	0x6f, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x06, 0x74, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12,
-	0x3f, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
-	0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52,
-	0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x12, 0x19, 0x0a, 0x08, 0x67, 0x61, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x07, 0x67, 0x61, 0x73, 0x55, 0x73, 0x65, 0x64, 0x12, 0x3d, 0x0a, 0x0d, 0x6f,
-	0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x65, 0x74, 0x78, 0x73, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0c, 0x6f, 0x75,
-	0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x45, 0x74, 0x78, 0x73, 0x22, 0x54, 0x0a, 0x17, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x74,
-	0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x46, 0x6f, 0x72, 0x53,
-	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73,
-	0x22, 0x83, 0x01, 0x0a, 0x12, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x67, 0x46, 0x6f, 0x72,
-	0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
-	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x07,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x29, 0x0a, 0x06, 0x74, 0x6f, 0x70, 0x69, 0x63,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
-	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x06, 0x74, 0x6f, 0x70, 0x69,
-	0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x44, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c,
-	0x6f, 0x67, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x2d, 0x0a,
-	0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x53,
-	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x22, 0x88, 0x01, 0x0a,
-	0x12, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x48, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x02, 0x77, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x61, 0x0a, 0x1a, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x56, 0x69, 0x65, 0x77, 0x12, 0x43, 0x0a, 0x0c, 0x77, 0x6f, 0x72,
+	0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72,
+	0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x69, 0x65,
+	0x77, 0x52, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x22, 0x68,
+	0x0a, 0x18, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x56, 0x69, 0x65, 0x77, 0x12, 0x3c, 0x0a, 0x0b, 0x77, 0x6f,
+	0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72,
-	0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x02, 0x77, 0x6f, 0x88, 0x01, 0x01,
-	0x12, 0x32, 0x0a, 0x07, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54,
-	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x48, 0x01, 0x52, 0x07, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e,
-	0x69, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x77, 0x6f, 0x42, 0x0a, 0x0a, 0x08, 0x5f,
-	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x22, 0x76, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x12, 0x32, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x5f, 0x74,
-	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63,
-	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52,
-	0x0a, 0x64, 0x6f, 0x6d, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x12, 0x32, 0x0a, 0x0b, 0x73,
-	0x75, 0x62, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48,
-	0x61, 0x73, 0x68, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x22,
-	0x40, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x45, 0x74, 0x78, 0x53, 0x65, 0x74, 0x12, 0x22,
-	0x0a, 0x0a, 0x65, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x65, 0x74, 0x78, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x88,
-	0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x65, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65,
-	0x73, 0x22, 0xa8, 0x01, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x6e, 0x64, 0x69,
-	0x6e, 0x67, 0x45, 0x74, 0x78, 0x73, 0x12, 0x33, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x00,
-	0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x42, 0x0a, 0x0d, 0x6f,
-	0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x65, 0x74, 0x78, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x01, 0x52, 0x0c,
-	0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x45, 0x74, 0x78, 0x73, 0x88, 0x01, 0x01, 0x42,
-	0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6f,
-	0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x65, 0x74, 0x78, 0x73, 0x22, 0xa8, 0x01, 0x0a,
-	0x16, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x45, 0x74, 0x78,
-	0x73, 0x52, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x12, 0x33, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48,
-	0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x3e, 0x0a, 0x0b,
-	0x65, 0x74, 0x78, 0x73, 0x5f, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x01, 0x52, 0x0a, 0x65,
-	0x74, 0x78, 0x73, 0x52, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07,
-	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x65, 0x74, 0x78, 0x73,
-	0x5f, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x22, 0x35, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x54, 0x78, 0x49, 0x6e, 0x73, 0x12, 0x27, 0x0a, 0x06, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x54, 0x78, 0x49, 0x6e, 0x52, 0x05, 0x74, 0x78, 0x49, 0x6e, 0x73, 0x22, 0x39,
-	0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x4f, 0x75, 0x74, 0x73, 0x12, 0x2a, 0x0a,
-	0x07, 0x74, 0x78, 0x5f, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
-	0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x4f, 0x75,
-	0x74, 0x52, 0x06, 0x74, 0x78, 0x4f, 0x75, 0x74, 0x73, 0x22, 0x95, 0x01, 0x0a, 0x09, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x54, 0x78, 0x49, 0x6e, 0x12, 0x47, 0x0a, 0x12, 0x70, 0x72, 0x65, 0x76, 0x69,
-	0x6f, 0x75, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74,
-	0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x70, 0x72, 0x65,
-	0x76, 0x69, 0x6f, 0x75, 0x73, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01,
-	0x12, 0x1c, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x48, 0x01, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x42, 0x15,
-	0x0a, 0x13, 0x5f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x5f,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65,
-	0x79, 0x22, 0x69, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69,
-	0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48,
-	0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x19,
-	0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52,
-	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68, 0x61,
-	0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x93, 0x01, 0x0a,
-	0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x4f, 0x75, 0x74, 0x12, 0x27, 0x0a, 0x0c, 0x64,
-	0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0d, 0x48, 0x00, 0x52, 0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x01, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0c, 0x48, 0x02, 0x52, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d,
-	0x5f, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0a, 0x0a,
-	0x08, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6c, 0x6f,
-	0x63, 0x6b, 0x22, 0xd4, 0x01, 0x0a, 0x1c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50,
-	0x6f, 0x69, 0x6e, 0x74, 0x41, 0x6e, 0x64, 0x44, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12,
-	0x19, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01,
-	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x64, 0x65,
-	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
-	0x48, 0x02, 0x52, 0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0c, 0x48, 0x03, 0x52, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05,
-	0x5f, 0x68, 0x61, 0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x42,
+	0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x4f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x77, 0x6f, 0x72,
+	0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x60, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x75, 0x70, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0a,
+	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x4b, 0x65, 0x79, 0x22, 0xf0, 0x02, 0x0a, 0x16, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x46, 0x6f, 0x72, 0x53, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x14, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x5f, 0x6f, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x11, 0x70, 0x6f, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4f, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x5f, 0x67, 0x61, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x11, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x47,
+	0x61, 0x73, 0x55, 0x73, 0x65, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x4c, 0x6f, 0x67, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x52, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
+	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x06, 0x74, 0x78, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x3f, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x61, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x67, 0x61, 0x73, 0x55, 0x73, 0x65, 0x64, 0x12, 0x3d,
+	0x0a, 0x0d, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x65, 0x74, 0x78, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x0c, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x45, 0x74, 0x78, 0x73, 0x22, 0x54, 0x0a,
+	0x17, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x46, 0x6f,
+	0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x46,
+	0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x73, 0x22, 0x83, 0x01, 0x0a, 0x12, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x67,
+	0x46, 0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x29, 0x0a, 0x06, 0x74, 0x6f,
+	0x70, 0x69, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x06, 0x74,
+	0x6f, 0x70, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x44, 0x0a, 0x13, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x4c, 0x6f, 0x67, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x12, 0x2d, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x67, 0x46,
+	0x6f, 0x72, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x22,
+	0x88, 0x01, 0x0a, 0x12, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x02, 0x77, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x02, 0x77, 0x6f,
+	0x88, 0x01, 0x01, 0x12, 0x32, 0x0a, 0x07, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x48, 0x01, 0x52, 0x07, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x69, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x77, 0x6f, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x22, 0x76, 0x0a, 0x0c, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x12, 0x32, 0x0a, 0x0b, 0x64, 0x6f,
+	0x6d, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61,
+	0x73, 0x68, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x12, 0x32,
+	0x0a, 0x0b, 0x73, 0x75, 0x62, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x54, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x69, 0x22, 0x40, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x45, 0x74, 0x78, 0x53, 0x65,
+	0x74, 0x12, 0x22, 0x0a, 0x0a, 0x65, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x65, 0x74, 0x78, 0x48, 0x61, 0x73, 0x68,
+	0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x65, 0x74, 0x78, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x65, 0x73, 0x22, 0xa8, 0x01, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x45, 0x74, 0x78, 0x73, 0x12, 0x33, 0x0a, 0x06, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x42,
+	0x0a, 0x0d, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x65, 0x74, 0x78, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48,
+	0x01, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x45, 0x74, 0x78, 0x73, 0x88,
+	0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x65, 0x74, 0x78, 0x73, 0x22,
+	0xa8, 0x01, 0x0a, 0x16, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x45, 0x74, 0x78, 0x73, 0x52, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x12, 0x33, 0x0a, 0x06, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x3e, 0x0a, 0x0b, 0x65, 0x74, 0x78, 0x73, 0x5f, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x01,
+	0x52, 0x0a, 0x65, 0x74, 0x78, 0x73, 0x52, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x88, 0x01, 0x01, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x65,
+	0x74, 0x78, 0x73, 0x5f, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x22, 0x35, 0x0a, 0x0a, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x78, 0x49, 0x6e, 0x73, 0x12, 0x27, 0x0a, 0x06, 0x74, 0x78, 0x5f, 0x69,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x49, 0x6e, 0x52, 0x05, 0x74, 0x78, 0x49, 0x6e,
+	0x73, 0x22, 0x39, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x4f, 0x75, 0x74, 0x73,
+	0x12, 0x2a, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54,
+	0x78, 0x4f, 0x75, 0x74, 0x52, 0x06, 0x74, 0x78, 0x4f, 0x75, 0x74, 0x73, 0x22, 0x95, 0x01, 0x0a,
+	0x09, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x49, 0x6e, 0x12, 0x47, 0x0a, 0x12, 0x70, 0x72,
+	0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10,
+	0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x01, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x88, 0x01,
+	0x01, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x6f,
+	0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x75, 0x62,
+	0x5f, 0x6b, 0x65, 0x79, 0x22, 0x69, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01,
+	0x01, 0x12, 0x19, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x01, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22,
+	0x93, 0x01, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x4f, 0x75, 0x74, 0x12, 0x27,
+	0x0a, 0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x01, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x02, 0x52, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42,
 	0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x5b, 0x0a, 0x15, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e,
-	0x74, 0x73, 0x12, 0x42, 0x0a, 0x0a, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x41, 0x6e, 0x64, 0x44,
-	0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6f, 0x75, 0x74,
-	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x8c, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x53, 0x70, 0x65, 0x6e, 0x74, 0x55, 0x54, 0x58, 0x4f, 0x12, 0x35, 0x0a, 0x08, 0x6f, 0x75, 0x74,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e,
-	0x74, 0x48, 0x00, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01,
-	0x12, 0x2c, 0x0a, 0x05, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x78, 0x4f,
-	0x75, 0x74, 0x48, 0x01, 0x52, 0x05, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x88, 0x01, 0x01, 0x42, 0x0b,
-	0x0a, 0x09, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f,
-	0x73, 0x75, 0x74, 0x78, 0x6f, 0x22, 0x40, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x70,
-	0x65, 0x6e, 0x74, 0x55, 0x54, 0x58, 0x4f, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x75, 0x74, 0x78,
-	0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x55, 0x54, 0x58, 0x4f, 0x52,
-	0x06, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x73, 0x22, 0x1f, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x4b, 0x65, 0x79, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0c, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x99, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x54, 0x72, 0x69, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x12, 0x47, 0x0a, 0x0b,
-	0x74, 0x72, 0x69, 0x6d, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x26, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54,
-	0x72, 0x69, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x2e, 0x54, 0x72, 0x69, 0x6d, 0x44, 0x65,
-	0x70, 0x74, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x72, 0x69, 0x6d, 0x44,
-	0x65, 0x70, 0x74, 0x68, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x54, 0x72, 0x69, 0x6d, 0x44, 0x65, 0x70,
-	0x74, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x3a, 0x02, 0x38, 0x01, 0x22, 0x61, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b,
-	0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x12, 0x4a, 0x0a, 0x12, 0x74,
-	0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x5f, 0x61, 0x72, 0x72, 0x61,
-	0x79, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65,
-	0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x10, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69,
-	0x63, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x22, 0x6c, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79,
-	0x12, 0x41, 0x0a, 0x0d, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65,
-	0x48, 0x00, 0x52, 0x0c, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73,
-	0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68,
-	0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x4a, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f,
-	0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x71, 0x75, 0x61,
-	0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x71, 0x75, 0x61, 0x69, 0x12, 0x0e, 0x0a,
-	0x02, 0x71, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x71, 0x69, 0x12, 0x12, 0x0a,
-	0x04, 0x64, 0x69, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x69, 0x66,
-	0x66, 0x22, 0x38, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x42, 0x65, 0x74, 0x61, 0x73, 0x12,
-	0x14, 0x0a, 0x05, 0x62, 0x65, 0x74, 0x61, 0x30, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
-	0x62, 0x65, 0x74, 0x61, 0x30, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x65, 0x74, 0x61, 0x31, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x62, 0x65, 0x74, 0x61, 0x31, 0x22, 0x48, 0x0a, 0x0b, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x12, 0x23, 0x0a, 0x0d, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x48,
-	0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x3c, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f,
-	0x63, 0x6b, 0x75, 0x70, 0x73, 0x12, 0x2c, 0x0a, 0x07, 0x6c, 0x6f, 0x63, 0x6b, 0x75, 0x70, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x07, 0x6c, 0x6f, 0x63, 0x6b,
-	0x75, 0x70, 0x73, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6e, 0x74, 0x2d, 0x73, 0x74, 0x72, 0x61, 0x74,
-	0x65, 0x67, 0x69, 0x65, 0x73, 0x2f, 0x67, 0x6f, 0x2d, 0x71, 0x75, 0x61, 0x69, 0x2f, 0x63, 0x6f,
-	0x72, 0x65, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0xd4, 0x01, 0x0a, 0x1c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f,
+	0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x41, 0x6e, 0x64, 0x44, 0x65, 0x6e, 0x6f, 0x6d, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88,
+	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x01, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a,
+	0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0c, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x03, 0x52, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x5b, 0x0a, 0x15,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x4f, 0x75, 0x74, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x42, 0x0a, 0x0a, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x41,
+	0x6e, 0x64, 0x44, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09,
+	0x6f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x8c, 0x01, 0x0a, 0x0e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x55, 0x54, 0x58, 0x4f, 0x12, 0x35, 0x0a, 0x08,
+	0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x54, 0x78, 0x4f, 0x75, 0x74, 0x48, 0x01, 0x52, 0x05, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x88, 0x01,
+	0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x22, 0x40, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x55, 0x54, 0x58, 0x4f, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x73,
+	0x75, 0x74, 0x78, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x55, 0x54,
+	0x58, 0x4f, 0x52, 0x06, 0x73, 0x75, 0x74, 0x78, 0x6f, 0x73, 0x22, 0x1f, 0x0a, 0x09, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x99, 0x01, 0x0a, 0x0f,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x72, 0x69, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x12,
+	0x47, 0x0a, 0x0b, 0x74, 0x72, 0x69, 0x6d, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x54, 0x72, 0x69, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x2e, 0x54, 0x72, 0x69,
+	0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x72,
+	0x69, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x54, 0x72, 0x69, 0x6d,
+	0x44, 0x65, 0x70, 0x74, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x61, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x12, 0x4a,
+	0x0a, 0x12, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x5f, 0x61,
+	0x72, 0x72, 0x61, 0x79, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f,
+	0x69, 0x63, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x10, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x43,
+	0x68, 0x6f, 0x69, 0x63, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x22, 0x6c, 0x0a, 0x15, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x12, 0x41, 0x0a, 0x0d, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68, 0x6f,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f,
+	0x69, 0x63, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69,
+	0x63, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x4a, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x71, 0x75, 0x61, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x71, 0x75, 0x61, 0x69,
+	0x12, 0x0e, 0x0a, 0x02, 0x71, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x71, 0x69,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x69, 0x66, 0x66, 0x22, 0x38, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x42, 0x65, 0x74,
+	0x61, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x65, 0x74, 0x61, 0x30, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x62, 0x65, 0x74, 0x61, 0x30, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x65, 0x74, 0x61,
+	0x31, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x62, 0x65, 0x74, 0x61, 0x31, 0x22, 0x48,
+	0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x75, 0x6e, 0x6c, 0x6f,
+	0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x3c, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x12, 0x2c, 0x0a, 0x07, 0x6c, 0x6f, 0x63, 0x6b,
+	0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x07, 0x6c,
+	0x6f, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x22, 0x98, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x55, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x35, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x48, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x48, 0x01, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x22, 0x75, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x74, 0x78, 0x6f, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x75, 0x74, 0x78, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x53, 0x70, 0x65, 0x6e, 0x74, 0x55, 0x54, 0x58, 0x4f, 0x52, 0x05, 0x75, 0x74, 0x78, 0x6f, 0x73,
+	0x12, 0x2d, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4f, 0x75, 0x74, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x65, 0x78, 0x74, 0x22, 0x86, 0x02, 0x0a, 0x13, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x49, 0x0a, 0x10, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65,
+	0x5f, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43, 0x68, 0x6f,
+	0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x43,
+	0x68, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x05, 0x62,
+	0x65, 0x74, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x42, 0x65, 0x74, 0x61, 0x73, 0x48, 0x01, 0x52,
+	0x05, 0x62, 0x65, 0x74, 0x61, 0x73, 0x88, 0x01, 0x01, 0x12, 0x41, 0x0a, 0x11, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x78, 0x6f, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x4b, 0x65, 0x79, 0x73, 0x48, 0x02, 0x52, 0x0f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x55, 0x74, 0x78, 0x6f, 0x4b, 0x65, 0x79, 0x73, 0x88, 0x01, 0x01, 0x42, 0x13, 0x0a, 0x11,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x65,
+	0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x62, 0x65, 0x74, 0x61, 0x73, 0x42, 0x14, 0x0a, 0x12, 0x5f,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x78, 0x6f, 0x5f, 0x6b, 0x65, 0x79,
+	0x73, 0x22, 0xf9, 0x03, 0x0a, 0x14, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x35, 0x0a, 0x0a, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73,
+	0x68, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x88, 0x01,
+	0x01, 0x12, 0x26, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x0a, 0x77, 0x6f, 0x72,
+	0x6b, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68,
+	0x48, 0x02, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x68, 0x61, 0x72, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x1f, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x48, 0x03, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x21, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x09, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x61, 0x72,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x05, 0x52, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a,
+	0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x06, 0x52,
+	0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6c, 0x6f,
+	0x63, 0x6b, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x07, 0x52, 0x0b, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x48, 0x08, 0x52, 0x0c, 0x75, 0x6e, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c,
+	0x6f, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
+	0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x4e, 0x0a,
+	0x15, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12, 0x35, 0x0a, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65,
+	0x77, 0x61, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x42, 0x33, 0x5a,
+	0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69,
+	0x6e, 0x61, 0x6e, 0x74, 0x2d, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x2f,
+	0x67, 0x6f, 0x2d, 0x71, 0x75, 0x61, 0x69, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -3286,7 +3729,7 @@ func file_core_types_proto_block_proto_rawDescGZIP() []byte {
 	return file_core_types_proto_block_proto_rawDescData
 }
 
-var file_core_types_proto_block_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
+var file_core_types_proto_block_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_core_types_proto_block_proto_goTypes = []any{
 	(*ProtoHeader)(nil),                  // 0: block.ProtoHeader
 	(*ProtoTransaction)(nil),             // 1: block.ProtoTransaction
@@ -3302,79 +3745,85 @@ var file_core_types_proto_block_proto_goTypes = []any{
 	(*ProtoWorkObjectBlockView)(nil),     // 11: block.ProtoWorkObjectBlockView
 	(*ProtoWorkObjectBlocksView)(nil),    // 12: block.ProtoWorkObjectBlocksView
 	(*ProtoWorkObjectHeaderView)(nil),    // 13: block.ProtoWorkObjectHeaderView
-	(*ProtoWorkObjectShareView)(nil),     // 14: block.ProtoWorkObjectShareView
-	(*ProtoAccessTuple)(nil),             // 15: block.ProtoAccessTuple
-	(*ProtoReceiptForStorage)(nil),       // 16: block.ProtoReceiptForStorage
-	(*ProtoReceiptsForStorage)(nil),      // 17: block.ProtoReceiptsForStorage
-	(*ProtoLogForStorage)(nil),           // 18: block.ProtoLogForStorage
-	(*ProtoLogsForStorage)(nil),          // 19: block.ProtoLogsForStorage
-	(*ProtoPendingHeader)(nil),           // 20: block.ProtoPendingHeader
-	(*ProtoTermini)(nil),                 // 21: block.ProtoTermini
-	(*ProtoEtxSet)(nil),                  // 22: block.ProtoEtxSet
-	(*ProtoPendingEtxs)(nil),             // 23: block.ProtoPendingEtxs
-	(*ProtoPendingEtxsRollup)(nil),       // 24: block.ProtoPendingEtxsRollup
-	(*ProtoTxIns)(nil),                   // 25: block.ProtoTxIns
-	(*ProtoTxOuts)(nil),                  // 26: block.ProtoTxOuts
-	(*ProtoTxIn)(nil),                    // 27: block.ProtoTxIn
-	(*ProtoOutPoint)(nil),                // 28: block.ProtoOutPoint
-	(*ProtoTxOut)(nil),                   // 29: block.ProtoTxOut
-	(*ProtoOutPointAndDenomination)(nil), // 30: block.ProtoOutPointAndDenomination
-	(*ProtoAddressOutPoints)(nil),        // 31: block.ProtoAddressOutPoints
-	(*ProtoSpentUTXO)(nil),               // 32: block.ProtoSpentUTXO
-	(*ProtoSpentUTXOs)(nil),              // 33: block.ProtoSpentUTXOs
-	(*ProtoKeys)(nil),                    // 34: block.ProtoKeys
-	(*ProtoTrimDepths)(nil),              // 35: block.ProtoTrimDepths
-	(*ProtoTokenChoiceSet)(nil),          // 36: block.ProtoTokenChoiceSet
-	(*ProtoTokenChoiceArray)(nil),        // 37: block.ProtoTokenChoiceArray
-	(*ProtoTokenChoice)(nil),             // 38: block.ProtoTokenChoice
-	(*ProtoBetas)(nil),                   // 39: block.ProtoBetas
-	(*ProtoLockup)(nil),                  // 40: block.ProtoLockup
-	(*ProtoLockups)(nil),                 // 41: block.ProtoLockups
-	nil,                                  // 42: block.ProtoTrimDepths.TrimDepthsEntry
-	(*common.ProtoHash)(nil),             // 43: common.ProtoHash
-	(*common.ProtoLocation)(nil),         // 44: common.ProtoLocation
-	(*common.ProtoAddress)(nil),          // 45: common.ProtoAddress
-	(*common.ProtoHashes)(nil),           // 46: common.ProtoHashes
+	(*ProtoWorkObjectHeadersView)(nil),   // 14: block.ProtoWorkObjectHeadersView
+	(*ProtoWorkObjectShareView)(nil),     // 15: block.ProtoWorkObjectShareView
+	(*ProtoAccessTuple)(nil),             // 16: block.ProtoAccessTuple
+	(*ProtoReceiptForStorage)(nil),       // 17: block.ProtoReceiptForStorage
+	(*ProtoReceiptsForStorage)(nil),      // 18: block.ProtoReceiptsForStorage
+	(*ProtoLogForStorage)(nil),           // 19: block.ProtoLogForStorage
+	(*ProtoLogsForStorage)(nil),          // 20: block.ProtoLogsForStorage
+	(*ProtoPendingHeader)(nil),           // 21: block.ProtoPendingHeader
+	(*ProtoTermini)(nil),                 // 22: block.ProtoTermini
+	(*ProtoEtxSet)(nil),                  // 23: block.ProtoEtxSet
+	(*ProtoPendingEtxs)(nil),             // 24: block.ProtoPendingEtxs
+	(*ProtoPendingEtxsRollup)(nil),       // 25: block.ProtoPendingEtxsRollup
+	(*ProtoTxIns)(nil),                   // 26: block.ProtoTxIns
+	(*ProtoTxOuts)(nil),                  // 27: block.ProtoTxOuts
+	(*ProtoTxIn)(nil),                    // 28: block.ProtoTxIn
+	(*ProtoOutPoint)(nil),                // 29: block.ProtoOutPoint
+	(*ProtoTxOut)(nil),                   // 30: block.ProtoTxOut
+	(*ProtoOutPointAndDenomination)(nil), // 31: block.ProtoOutPointAndDenomination
+	(*ProtoAddressOutPoints)(nil),        // 32: block.ProtoAddressOutPoints
+	(*ProtoSpentUTXO)(nil),               // 33: block.ProtoSpentUTXO
+	(*ProtoSpentUTXOs)(nil),              // 34: block.ProtoSpentUTXOs
+	(*ProtoKeys)(nil),                    // 35: block.ProtoKeys
+	(*ProtoTrimDepths)(nil),              // 36: block.ProtoTrimDepths
+	(*ProtoTokenChoiceSet)(nil),          // 37: block.ProtoTokenChoiceSet
+	(*ProtoTokenChoiceArray)(nil),        // 38: block.ProtoTokenChoiceArray
+	(*ProtoTokenChoice)(nil),             // 39: block.ProtoTokenChoice
+	(*ProtoBetas)(nil),                   // 40: block.ProtoBetas
+	(*ProtoLockup)(nil),                  // 41: block.ProtoLockup
+	(*ProtoLockups)(nil),                 // 42: block.ProtoLockups
+	(*ProtoUtxoRangeRequest)(nil),        // 43: block.ProtoUtxoRangeRequest
+	(*ProtoUtxoRange)(nil),               // 44: block.ProtoUtxoRange
+	(*ProtoSyncBlockState)(nil),          // 45: block.ProtoSyncBlockState
+	(*ProtoWorkShareReward)(nil),         // 46: block.ProtoWorkShareReward
+	(*ProtoWorkShareRewards)(nil),        // 47: block.ProtoWorkShareRewards
+	nil,                                  // 48: block.ProtoTrimDepths.TrimDepthsEntry
+	(*common.ProtoHash)(nil),             // 49: common.ProtoHash
+	(*common.ProtoLocation)(nil),         // 50: common.ProtoLocation
+	(*common.ProtoAddress)(nil),          // 51: common.ProtoAddress
+	(*common.ProtoHashes)(nil),           // 52: common.ProtoHashes
 }
 var file_core_types_proto_block_proto_depIdxs = []int32{
-	43, // 0: block.ProtoHeader.parent_hash:type_name -> common.ProtoHash
-	43, // 1: block.ProtoHeader.uncle_hash:type_name -> common.ProtoHash
-	43, // 2: block.ProtoHeader.evm_root:type_name -> common.ProtoHash
-	43, // 3: block.ProtoHeader.tx_hash:type_name -> common.ProtoHash
-	43, // 4: block.ProtoHeader.outbound_etx_hash:type_name -> common.ProtoHash
-	43, // 5: block.ProtoHeader.etx_rollup_hash:type_name -> common.ProtoHash
-	43, // 6: block.ProtoHeader.manifest_hash:type_name -> common.ProtoHash
-	43, // 7: block.ProtoHeader.receipt_hash:type_name -> common.ProtoHash
-	44, // 8: block.ProtoHeader.location:type_name -> common.ProtoLocation
-	43, // 9: block.ProtoHeader.mix_hash:type_name -> common.ProtoHash
-	43, // 10: block.ProtoHeader.utxo_root:type_name -> common.ProtoHash
-	43, // 11: block.ProtoHeader.etx_set_root:type_name -> common.ProtoHash
-	43, // 12: block.ProtoHeader.etx_eligible_slices:type_name -> common.ProtoHash
-	43, // 13: block.ProtoHeader.prime_terminus_hash:type_name -> common.ProtoHash
-	43, // 14: block.ProtoHeader.interlink_root_hash:type_name -> common.ProtoHash
+	49, // 0: block.ProtoHeader.parent_hash:type_name -> common.ProtoHash
+	49, // 1: block.ProtoHeader.uncle_hash:type_name -> common.ProtoHash
+	49, // 2: block.ProtoHeader.evm_root:type_name -> common.ProtoHash
+	49, // 3: block.ProtoHeader.tx_hash:type_name -> common.ProtoHash
+	49, // 4: block.ProtoHeader.outbound_etx_hash:type_name -> common.ProtoHash
+	49, // 5: block.ProtoHeader.etx_rollup_hash:type_name -> common.ProtoHash
+	49, // 6: block.ProtoHeader.manifest_hash:type_name -> common.ProtoHash
+	49, // 7: block.ProtoHeader.receipt_hash:type_name -> common.ProtoHash
+	50, // 8: block.ProtoHeader.location:type_name -> common.ProtoLocation
+	49, // 9: block.ProtoHeader.mix_hash:type_name -> common.ProtoHash
+	49, // 10: block.ProtoHeader.utxo_root:type_name -> common.ProtoHash
+	49, // 11: block.ProtoHeader.etx_set_root:type_name -> common.ProtoHash
+	49, // 12: block.ProtoHeader.etx_eligible_slices:type_name -> common.ProtoHash
+	49, // 13: block.ProtoHeader.prime_terminus_hash:type_name -> common.ProtoHash
+	49, // 14: block.ProtoHeader.interlink_root_hash:type_name -> common.ProtoHash
 	5,  // 15: block.ProtoTransaction.access_list:type_name -> block.ProtoAccessList
-	43, // 16: block.ProtoTransaction.originating_tx_hash:type_name -> common.ProtoHash
-	25, // 17: block.ProtoTransaction.tx_ins:type_name -> block.ProtoTxIns
-	26, // 18: block.ProtoTransaction.tx_outs:type_name -> block.ProtoTxOuts
-	43, // 19: block.ProtoTransaction.parent_hash:type_name -> common.ProtoHash
-	43, // 20: block.ProtoTransaction.mix_hash:type_name -> common.ProtoHash
+	49, // 16: block.ProtoTransaction.originating_tx_hash:type_name -> common.ProtoHash
+	26, // 17: block.ProtoTransaction.tx_ins:type_name -> block.ProtoTxIns
+	27, // 18: block.ProtoTransaction.tx_outs:type_name -> block.ProtoTxOuts
+	49, // 19: block.ProtoTransaction.parent_hash:type_name -> common.ProtoHash
+	49, // 20: block.ProtoTransaction.mix_hash:type_name -> common.ProtoHash
 	1,  // 21: block.ProtoTransactions.transactions:type_name -> block.ProtoTransaction
 	0,  // 22: block.ProtoHeaders.headers:type_name -> block.ProtoHeader
-	43, // 23: block.ProtoManifest.manifest:type_name -> common.ProtoHash
-	15, // 24: block.ProtoAccessList.access_tuples:type_name -> block.ProtoAccessTuple
-	43, // 25: block.ProtoWorkObjectHeader.header_hash:type_name -> common.ProtoHash
-	43, // 26: block.ProtoWorkObjectHeader.parent_hash:type_name -> common.ProtoHash
-	43, // 27: block.ProtoWorkObjectHeader.tx_hash:type_name -> common.ProtoHash
-	44, // 28: block.ProtoWorkObjectHeader.location:type_name -> common.ProtoLocation
-	43, // 29: block.ProtoWorkObjectHeader.mix_hash:type_name -> common.ProtoHash
-	45, // 30: block.ProtoWorkObjectHeader.primary_coinbase:type_name -> common.ProtoAddress
+	49, // 23: block.ProtoManifest.manifest:type_name -> common.ProtoHash
+	16, // 24: block.ProtoAccessList.access_tuples:type_name -> block.ProtoAccessTuple
+	49, // 25: block.ProtoWorkObjectHeader.header_hash:type_name -> common.ProtoHash
+	49, // 26: block.ProtoWorkObjectHeader.parent_hash:type_name -> common.ProtoHash
+	49, // 27: block.ProtoWorkObjectHeader.tx_hash:type_name -> common.ProtoHash
+	50, // 28: block.ProtoWorkObjectHeader.location:type_name -> common.ProtoLocation
+	49, // 29: block.ProtoWorkObjectHeader.mix_hash:type_name -> common.ProtoHash
+	51, // 30: block.ProtoWorkObjectHeader.primary_coinbase:type_name -> common.ProtoAddress
 	6,  // 31: block.ProtoWorkObjectHeaders.wo_headers:type_name -> block.ProtoWorkObjectHeader
 	0,  // 32: block.ProtoWorkObjectBody.header:type_name -> block.ProtoHeader
 	2,  // 33: block.ProtoWorkObjectBody.transactions:type_name -> block.ProtoTransactions
 	7,  // 34: block.ProtoWorkObjectBody.uncles:type_name -> block.ProtoWorkObjectHeaders
 	2,  // 35: block.ProtoWorkObjectBody.outbound_etxs:type_name -> block.ProtoTransactions
 	4,  // 36: block.ProtoWorkObjectBody.manifest:type_name -> block.ProtoManifest
-	46, // 37: block.ProtoWorkObjectBody.interlink_hashes:type_name -> common.ProtoHashes
+	52, // 37: block.ProtoWorkObjectBody.interlink_hashes:type_name -> common.ProtoHashes
 	6,  // 38: block.ProtoWorkObject.wo_header:type_name -> block.ProtoWorkObjectHeader
 	8,  // 39: block.ProtoWorkObject.wo_body:type_name -> block.ProtoWorkObjectBody
 	1,  // 40: block.ProtoWorkObject.tx:type_name -> block.ProtoTransaction
@@ -3382,42 +3831,53 @@ var file_core_types_proto_block_proto_depIdxs = []int32{
 	9,  // 42: block.ProtoWorkObjectBlockView.work_object:type_name -> block.ProtoWorkObject
 	11, // 43: block.ProtoWorkObjectBlocksView.work_objects:type_name -> block.ProtoWorkObjectBlockView
 	9,  // 44: block.ProtoWorkObjectHeaderView.work_object:type_name -> block.ProtoWorkObject
-	9,  // 45: block.ProtoWorkObjectShareView.work_object:type_name -> block.ProtoWorkObject
-	43, // 46: block.ProtoAccessTuple.storage_key:type_name -> common.ProtoHash
-	19, // 47: block.ProtoReceiptForStorage.logs:type_name -> block.ProtoLogsForStorage
-	43, // 48: block.ProtoReceiptForStorage.tx_hash:type_name -> common.ProtoHash
-	45, // 49: block.ProtoReceiptForStorage.contract_address:type_name -> common.ProtoAddress
-	2,  // 50: block.ProtoReceiptForStorage.outbound_etxs:type_name -> block.ProtoTransactions
-	16, // 51: block.ProtoReceiptsForStorage.receipts:type_name -> block.ProtoReceiptForStorage
-	45, // 52: block.ProtoLogForStorage.address:type_name -> common.ProtoAddress
-	43, // 53: block.ProtoLogForStorage.topics:type_name -> common.ProtoHash
-	18, // 54: block.ProtoLogsForStorage.logs:type_name -> block.ProtoLogForStorage
-	9,  // 55: block.ProtoPendingHeader.wo:type_name -> block.ProtoWorkObject
-	21, // 56: block.ProtoPendingHeader.termini:type_name -> block.ProtoTermini
-	43, // 57: block.ProtoTermini.dom_termini:type_name -> common.ProtoHash
-	43, // 58: block.ProtoTermini.sub_termini:type_name -> common.ProtoHash
-	9,  // 59: block.ProtoPendingEtxs.header:type_name -> block.ProtoWorkObject
-	2,  // 60: block.ProtoPendingEtxs.outbound_etxs:type_name -> block.ProtoTransactions
-	9,  // 61: block.ProtoPendingEtxsRollup.header:type_name -> block.ProtoWorkObject
-	2,  // 62: block.ProtoPendingEtxsRollup.etxs_rollup:type_name -> block.ProtoTransactions
-	27, // 63: block.ProtoTxIns.tx_ins:type_name -> block.ProtoTxIn
-	29, // 64: block.ProtoTxOuts.tx_outs:type_name -> block.ProtoTxOut
-	28, // 65: block.ProtoTxIn.previous_out_point:type_name -> block.ProtoOutPoint
-	43, // 66: block.ProtoOutPoint.hash:type_name -> common.ProtoHash
-	43, // 67: block.ProtoOutPointAndDenomination.hash:type_name -> common.ProtoHash
-	30, // 68: block.ProtoAddressOutPoints.out_points:type_name -> block.ProtoOutPointAndDenomination
-	28, // 69: block.ProtoSpentUTXO.outpoint:type_name -> block.ProtoOutPoint
-	29, // 70: block.ProtoSpentUTXO.sutxo:type_name -> block.ProtoTxOut
-	32, // 71: block.ProtoSpentUTXOs.sutxos:type_name -> block.ProtoSpentUTXO
-	42, // 72: block.ProtoTrimDepths.trim_depths:type_name -> block.ProtoTrimDepths.TrimDepthsEntry
-	37, // 73: block.ProtoTokenChoiceSet.token_choice_array:type_name -> block.ProtoTokenChoiceArray
-	38, // 74: block.ProtoTokenChoiceArray.token_choices:type_name -> block.ProtoTokenChoice
-	40, // 75: block.ProtoLockups.lockups:type_name -> block.ProtoLockup
-	76, // [76:76] is the sub-list for method output_type
-	76, // [76:76] is the sub-list for method input_type
-	76, // [76:76] is the sub-list for extension type_name
-	76, // [76:76] is the sub-list for extension extendee
-	0,  // [0:76] is the sub-list for field type_name
+	13, // 45: block.ProtoWorkObjectHeadersView.work_objects:type_name -> block.ProtoWorkObjectHeaderView
+	9,  // 46: block.ProtoWorkObjectShareView.work_object:type_name -> block.ProtoWorkObject
+	49, // 47: block.ProtoAccessTuple.storage_key:type_name -> common.ProtoHash
+	20, // 48: block.ProtoReceiptForStorage.logs:type_name -> block.ProtoLogsForStorage
+	49, // 49: block.ProtoReceiptForStorage.tx_hash:type_name -> common.ProtoHash
+	51, // 50: block.ProtoReceiptForStorage.contract_address:type_name -> common.ProtoAddress
+	2,  // 51: block.ProtoReceiptForStorage.outbound_etxs:type_name -> block.ProtoTransactions
+	17, // 52: block.ProtoReceiptsForStorage.receipts:type_name -> block.ProtoReceiptForStorage
+	51, // 53: block.ProtoLogForStorage.address:type_name -> common.ProtoAddress
+	49, // 54: block.ProtoLogForStorage.topics:type_name -> common.ProtoHash
+	19, // 55: block.ProtoLogsForStorage.logs:type_name -> block.ProtoLogForStorage
+	9,  // 56: block.ProtoPendingHeader.wo:type_name -> block.ProtoWorkObject
+	22, // 57: block.ProtoPendingHeader.termini:type_name -> block.ProtoTermini
+	49, // 58: block.ProtoTermini.dom_termini:type_name -> common.ProtoHash
+	49, // 59: block.ProtoTermini.sub_termini:type_name -> common.ProtoHash
+	9,  // 60: block.ProtoPendingEtxs.header:type_name -> block.ProtoWorkObject
+	2,  // 61: block.ProtoPendingEtxs.outbound_etxs:type_name -> block.ProtoTransactions
+	9,  // 62: block.ProtoPendingEtxsRollup.header:type_name -> block.ProtoWorkObject
+	2,  // 63: block.ProtoPendingEtxsRollup.etxs_rollup:type_name -> block.ProtoTransactions
+	28, // 64: block.ProtoTxIns.tx_ins:type_name -> block.ProtoTxIn
+	30, // 65: block.ProtoTxOuts.tx_outs:type_name -> block.ProtoTxOut
+	29, // 66: block.ProtoTxIn.previous_out_point:type_name -> block.ProtoOutPoint
+	49, // 67: block.ProtoOutPoint.hash:type_name -> common.ProtoHash
+	49, // 68: block.ProtoOutPointAndDenomination.hash:type_name -> common.ProtoHash
+	31, // 69: block.ProtoAddressOutPoints.out_points:type_name -> block.ProtoOutPointAndDenomination
+	29, // 70: block.ProtoSpentUTXO.outpoint:type_name -> block.ProtoOutPoint
+	30, // 71: block.ProtoSpentUTXO.sutxo:type_name -> block.ProtoTxOut
+	33, // 72: block.ProtoSpentUTXOs.sutxos:type_name -> block.ProtoSpentUTXO
+	48, // 73: block.ProtoTrimDepths.trim_depths:type_name -> block.ProtoTrimDepths.TrimDepthsEntry
+	38, // 74: block.ProtoTokenChoiceSet.token_choice_array:type_name -> block.ProtoTokenChoiceArray
+	39, // 75: block.ProtoTokenChoiceArray.token_choices:type_name -> block.ProtoTokenChoice
+	41, // 76: block.ProtoLockups.lockups:type_name -> block.ProtoLockup
+	49, // 77: block.ProtoUtxoRangeRequest.block_hash:type_name -> common.ProtoHash
+	29, // 78: block.ProtoUtxoRangeRequest.start:type_name -> block.ProtoOutPoint
+	33, // 79: block.ProtoUtxoRange.utxos:type_name -> block.ProtoSpentUTXO
+	29, // 80: block.ProtoUtxoRange.next:type_name -> block.ProtoOutPoint
+	37, // 81: block.ProtoSyncBlockState.token_choice_set:type_name -> block.ProtoTokenChoiceSet
+	40, // 82: block.ProtoSyncBlockState.betas:type_name -> block.ProtoBetas
+	35, // 83: block.ProtoSyncBlockState.created_utxo_keys:type_name -> block.ProtoKeys
+	49, // 84: block.ProtoWorkShareReward.block_hash:type_name -> common.ProtoHash
+	49, // 85: block.ProtoWorkShareReward.work_share:type_name -> common.ProtoHash
+	46, // 86: block.ProtoWorkShareRewards.rewards:type_name -> block.ProtoWorkShareReward
+	87, // [87:87] is the sub-list for method output_type
+	87, // [87:87] is the sub-list for method input_type
+	87, // [87:87] is the sub-list for extension type_name
+	87, // [87:87] is the sub-list for extension extendee
+	0,  // [0:87] is the sub-list for field type_name
 }
 
 func init() { file_core_types_proto_block_proto_init() }
@@ -3432,24 +3892,28 @@ func file_core_types_proto_block_proto_init() {
 	file_core_types_proto_block_proto_msgTypes[9].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[11].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[13].OneofWrappers = []any{}
-	file_core_types_proto_block_proto_msgTypes[14].OneofWrappers = []any{}
-	file_core_types_proto_block_proto_msgTypes[20].OneofWrappers = []any{}
-	file_core_types_proto_block_proto_msgTypes[22].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[15].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[21].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[23].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[24].OneofWrappers = []any{}
-	file_core_types_proto_block_proto_msgTypes[27].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[25].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[28].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[29].OneofWrappers = []any{}
 	file_core_types_proto_block_proto_msgTypes[30].OneofWrappers = []any{}
-	file_core_types_proto_block_proto_msgTypes[32].OneofWrappers = []any{}
-	file_core_types_proto_block_proto_msgTypes[37].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[31].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[33].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[38].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[43].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[44].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[45].OneofWrappers = []any{}
+	file_core_types_proto_block_proto_msgTypes[46].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_core_types_proto_block_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   43,
+			NumMessages:   49,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dominant-strategies/go-quai/common"
+)
+
+// UtxoRangeRequest requests the UTXOs of the set of a block starting at the
+// given outpoint, in the order of their outpoints.
+type UtxoRangeRequest struct {
+	BlockHash common.Hash
+	Start     OutPoint
+}
+
+// ProtoEncode converts the request into its protobuf representation.
+func (req *UtxoRangeRequest) ProtoEncode() (*ProtoUtxoRangeRequest, error) {
+	start, err := req.Start.ProtoEncode()
+	if err != nil {
+		return nil, err
+	}
+	return &ProtoUtxoRangeRequest{
+		BlockHash: req.BlockHash.ProtoEncode(),
+		Start:     start,
+	}, nil
+}
+
+// ProtoDecode sets the request from its protobuf representation.
+func (req *UtxoRangeRequest) ProtoDecode(protoReq *ProtoUtxoRangeRequest) error {
+	if protoReq.GetBlockHash() == nil {
+		return errors.New("missing block hash in utxo range request")
+	}
+	if protoReq.GetStart() == nil || protoReq.GetStart().Index == nil {
+		return errors.New("missing start in utxo range request")
+	}
+	req.BlockHash.ProtoDecode(protoReq.GetBlockHash())
+	return req.Start.ProtoDecode(protoReq.GetStart())
+}
+
+// UtxoRange is a page of the UTXO set of a block, sorted by outpoint. Next is
+// the outpoint the following page starts at, or nil if the page is the last
+// one. The page holds every UTXO of the set between the start it was
+// requested at and Next.
+type UtxoRange struct {
+	Utxos []*SpentUtxoEntry
+	Next  *OutPoint
+}
+
+// ProtoEncode converts the range into its protobuf representation.
+func (r *UtxoRange) ProtoEncode() (*ProtoUtxoRange, error) {
+	protoRange := &ProtoUtxoRange{Utxos: make([]*ProtoSpentUTXO, 0, len(r.Utxos))}
+	for _, utxo := range r.Utxos {
+		protoUtxo, err := utxo.ProtoEncode()
+		if err != nil {
+			return nil, err
+		}
+		protoRange.Utxos = append(protoRange.Utxos, protoUtxo)
+	}
+	if r.Next != nil {
+		next, err := r.Next.ProtoEncode()
+		if err != nil {
+			return nil, err
+		}
+		protoRange.Next = next
+	}
+	return protoRange, nil
+}
+
+// ProtoDecode sets the range from its protobuf representation.
+func (r *UtxoRange) ProtoDecode(protoRange *ProtoUtxoRange) error {
+	r.Utxos = make([]*SpentUtxoEntry, 0, len(protoRange.GetUtxos()))
+	for _, protoUtxo := range protoRange.GetUtxos() {
+		if protoUtxo.GetOutpoint() == nil || protoUtxo.GetOutpoint().Index == nil || protoUtxo.GetSutxo() == nil || protoUtxo.GetSutxo().Denomination == nil {
+			return errors.New("incomplete utxo in utxo range")
+		}
+		utxo := new(SpentUtxoEntry)
+		if err := utxo.ProtoDecode(protoUtxo); err != nil {
+			return err
+		}
+		r.Utxos = append(r.Utxos, utxo)
+	}
+	r.Next = nil
+	if protoRange.GetNext() != nil {
+		if protoRange.GetNext().Index == nil {
+			return errors.New("incomplete next outpoint in utxo range")
+		}
+		r.Next = new(OutPoint)
+		if err := r.Next.ProtoDecode(protoRange.GetNext()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks that the range served for a request starting at the given
+// outpoint is sorted by outpoint, starts at or after it and ends before Next.
+func (r *UtxoRange) Verify(start OutPoint) error {
+	prev := &start
+	for i, utxo := range r.Utxos {
+		if cmp := CompareOutPoints(utxo.OutPoint, *prev); cmp < 0 || (cmp == 0 && i > 0) {
+			return errors.New("utxo range is not sorted")
+		}
+		prev = &utxo.OutPoint
+	}
+	if r.Next != nil && CompareOutPoints(*r.Next, *prev) <= 0 {
+		return errors.New("utxo range overlaps the next range")
+	}
+	return nil
+}
+
+// CompareOutPoints compares two outpoints in the order their UTXOs are stored
+// in the database, by transaction hash and then by index.
+func CompareOutPoints(a, b OutPoint) int {
+	if cmp := bytes.Compare(a.TxHash[:], b.TxHash[:]); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case a.Index < b.Index:
+		return -1
+	case a.Index > b.Index:
+		return 1
+	}
+	return 0
+}
+
+// SyncBlockState is the state a zone keeps for a block outside of its tries
+// and UTXO set. A node snap syncing the state at a block needs it for that
+// block, and the created UTXO keys of the blocks within the trim depths below
+// it to trim the UTXOs they created.
+type SyncBlockState struct {
+	TokenChoiceSet  *TokenChoiceSet
+	Betas           *Betas
+	CreatedUtxoKeys [][]byte
+}
+
+// ProtoEncode converts the block state into its protobuf representation.
+func (s *SyncBlockState) ProtoEncode() (*ProtoSyncBlockState, error) {
+	protoState := &ProtoSyncBlockState{CreatedUtxoKeys: &ProtoKeys{Keys: s.CreatedUtxoKeys}}
+	if s.TokenChoiceSet != nil {
+		tokenChoiceSet, err := s.TokenChoiceSet.ProtoEncode()
+		if err != nil {
+			return nil, err
+		}
+		protoState.TokenChoiceSet = tokenChoiceSet
+	}
+	if s.Betas != nil {
+		betas, err := s.Betas.ProtoEncode()
+		if err != nil {
+			return nil, err
+		}
+		protoState.Betas = betas
+	}
+	return protoState, nil
+}
+
+// ProtoDecode sets the block state from its protobuf representation.
+func (s *SyncBlockState) ProtoDecode(protoState *ProtoSyncBlockState) error {
+	s.TokenChoiceSet = nil
+	if protoState.GetTokenChoiceSet() != nil {
+		choices := protoState.GetTokenChoiceSet().GetTokenChoiceArray()
+		if len(choices) != C_tokenChoiceSetSize {
+			return errors.New("invalid token choice set size")
+		}
+		for _, choice := range choices {
+			if choice.GetTokenChoices() == nil {
+				return errors.New("missing token choices in token choice set")
+			}
+		}
+		s.TokenChoiceSet = new(TokenChoiceSet)
+		if err := s.TokenChoiceSet.ProtoDecode(protoState.GetTokenChoiceSet()); err != nil {
+			return err
+		}
+	}
+	s.Betas = nil
+	if protoState.GetBetas() != nil {
+		s.Betas = new(Betas)
+		if err := s.Betas.ProtoDecode(protoState.GetBetas()); err != nil {
+			return err
+		}
+	}
+	s.CreatedUtxoKeys = protoState.GetCreatedUtxoKeys().GetKeys()
+	return nil
+}
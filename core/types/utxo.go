@@ -353,6 +353,18 @@ type RPCTxOut struct {
 	Lock         *hexutil.Big            `json:"lock"`
 }
 
+// RPCTrimWarning is a UTXO which is going to be trimmed from the UTXO set
+// unless it is spent before the trim height.
+type RPCTrimWarning struct {
+	TxHash       common.Hash    `json:"txHash"`
+	Index        hexutil.Uint64 `json:"index"`
+	Address      string         `json:"address"`
+	Denomination hexutil.Uint64 `json:"denomination"`
+	Lock         *hexutil.Big   `json:"lock"`
+	CreatedAt    hexutil.Uint64 `json:"createdAt"`
+	TrimHeight   hexutil.Uint64 `json:"trimHeight"`
+}
+
 // UtxoEntry houses details about an individual transaction output in a utxo
 // view such as whether or not it was contained in a coinbase tx, the height of
 // the block that contains the tx, whether or not it is spent, its public key
@@ -466,6 +478,38 @@ func (utxo *UtxoEntry) UnmarshalJSON(input []byte) error {
 	return nil
 }
 
+// TrimHeight returns the number of the block which trims the UTXO, if it was
+// created at the given height. Only the UTXOs of the trimmed denominations
+// with a lockup, which are the coinbase and conversion outputs, are trimmed.
+func (utxo *UtxoEntry) TrimHeight(createdAt uint64) (uint64, bool) {
+	if utxo.Denomination > MaxTrimDenomination || utxo.Lock == nil || utxo.Lock.Sign() == 0 {
+		return 0, false
+	}
+	depth, ok := TrimDepths[utxo.Denomination]
+	if !ok || createdAt == 0 {
+		return 0, false
+	}
+	return createdAt + depth, true
+}
+
+// NewRPCTrimWarning returns the trim warning of the UTXO created at the given
+// height, or nil if the UTXO is never trimmed.
+func NewRPCTrimWarning(outpoint OutPoint, utxo *UtxoEntry, createdAt uint64, location common.Location) *RPCTrimWarning {
+	trimHeight, ok := utxo.TrimHeight(createdAt)
+	if !ok {
+		return nil
+	}
+	return &RPCTrimWarning{
+		TxHash:       outpoint.TxHash,
+		Index:        hexutil.Uint64(outpoint.Index),
+		Address:      common.BytesToAddress(utxo.Address, location).Hex(),
+		Denomination: hexutil.Uint64(utxo.Denomination),
+		Lock:         (*hexutil.Big)(utxo.Lock),
+		CreatedAt:    hexutil.Uint64(createdAt),
+		TrimHeight:   hexutil.Uint64(trimHeight),
+	}
+}
+
 func UTXOHash(txHash common.Hash, index uint16, utxo *UtxoEntry) common.Hash {
 	indexBytes := make([]byte, 2)
 	binary.BigEndian.PutUint16(indexBytes, index)
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"strings"
 	"sync"
 	"testing"
@@ -891,3 +892,28 @@ func TestMuHash(t *testing.T) {
 		t.Fatalf("muhash mismatch!")
 	}
 }
+
+func TestUtxoTrimHeight(t *testing.T) {
+	lock := new(big.Int).SetUint64(100)
+	tests := []struct {
+		utxo      *UtxoEntry
+		createdAt uint64
+		trimmed   bool
+	}{
+		{&UtxoEntry{Denomination: 0, Lock: lock}, 10, true},
+		{&UtxoEntry{Denomination: MaxTrimDenomination, Lock: lock}, 10, true},
+		{&UtxoEntry{Denomination: MaxTrimDenomination + 1, Lock: lock}, 10, false},
+		{&UtxoEntry{Denomination: 0, Lock: new(big.Int)}, 10, false},
+		{&UtxoEntry{Denomination: 0}, 10, false},
+		{&UtxoEntry{Denomination: 0, Lock: lock}, 0, false},
+	}
+	for i, test := range tests {
+		height, trimmed := test.utxo.TrimHeight(test.createdAt)
+		if trimmed != test.trimmed {
+			t.Fatalf("test %d: got trimmed %v, want %v", i, trimmed, test.trimmed)
+		}
+		if trimmed && height != test.createdAt+TrimDepths[test.utxo.Denomination] {
+			t.Fatalf("test %d: got trim height %d, want %d", i, height, test.createdAt+TrimDepths[test.utxo.Denomination])
+		}
+	}
+}
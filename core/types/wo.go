@@ -66,6 +66,7 @@ const (
 	HeaderObject
 	WorkShareObject
 	WorkShareTxObject
+	HeaderObjects
 )
 
 type WorkShareValidity int
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dominant-strategies/go-quai/common"
+)
+
+// WorkShareReward is an entry of the workshare index, which records a reward
+// paid to a coinbase by a canonical block, either for a workshare the block
+// included or for the block itself.
+type WorkShareReward struct {
+	BlockHash   common.Hash
+	BlockNumber uint64
+
+	// WorkShare is the hash of the rewarded workshare, or the hash of the
+	// block for the rewards of its own coinbases.
+	WorkShare common.Hash
+
+	// Distance is the distance of the workshare to the block, which is zero
+	// for the rewards of the block.
+	Distance uint64
+
+	// Secondary is set for the fees paid to the secondary coinbase of the
+	// block.
+	Secondary bool
+
+	// Lock is the lockup byte chosen by the miner, which sets the depth at
+	// which the reward unlocks and its lockup multiple.
+	Lock uint8
+
+	// Reward is the value of the coinbase transaction paying the reward, and
+	// LockedValue the value it unlocks with once the lockup multiple of the
+	// lockup byte is applied.
+	Reward       *big.Int
+	LockedValue  *big.Int
+	UnlockHeight uint64
+}
+
+// ProtoEncode converts the reward into its protobuf representation.
+func (r *WorkShareReward) ProtoEncode() *ProtoWorkShareReward {
+	blockNumber := r.BlockNumber
+	distance := r.Distance
+	secondary := r.Secondary
+	lock := uint32(r.Lock)
+	unlockHeight := r.UnlockHeight
+	return &ProtoWorkShareReward{
+		BlockHash:    r.BlockHash.ProtoEncode(),
+		BlockNumber:  &blockNumber,
+		WorkShare:    r.WorkShare.ProtoEncode(),
+		Distance:     &distance,
+		Secondary:    &secondary,
+		Lock:         &lock,
+		Reward:       r.Reward.Bytes(),
+		LockedValue:  r.LockedValue.Bytes(),
+		UnlockHeight: &unlockHeight,
+	}
+}
+
+// ProtoDecode sets the reward from its protobuf representation.
+func (r *WorkShareReward) ProtoDecode(protoReward *ProtoWorkShareReward) error {
+	if protoReward.GetBlockHash() == nil || protoReward.GetWorkShare() == nil {
+		return errors.New("missing hash in workshare reward")
+	}
+	if protoReward.GetLock() > 0xff {
+		return errors.New("invalid lockup byte in workshare reward")
+	}
+	r.BlockHash.ProtoDecode(protoReward.GetBlockHash())
+	r.BlockNumber = protoReward.GetBlockNumber()
+	r.WorkShare.ProtoDecode(protoReward.GetWorkShare())
+	r.Distance = protoReward.GetDistance()
+	r.Secondary = protoReward.GetSecondary()
+	r.Lock = uint8(protoReward.GetLock())
+	r.Reward = new(big.Int).SetBytes(protoReward.GetReward())
+	r.LockedValue = new(big.Int).SetBytes(protoReward.GetLockedValue())
+	r.UnlockHeight = protoReward.GetUnlockHeight()
+	return nil
+}
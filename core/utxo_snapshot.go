@@ -0,0 +1,427 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/crypto/multiset"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rlp"
+)
+
+const (
+	// UtxoSnapshotVersion is the version of the UTXO snapshot format
+	UtxoSnapshotVersion = 1
+	// c_utxoSnapshotChunkSize is the number of UTXOs of a snapshot chunk
+	c_utxoSnapshotChunkSize = 4096
+)
+
+var (
+	// ErrUtxoSnapshotRoot is returned if the UTXOs of a snapshot do not match
+	// the UTXO root of the block it was taken at
+	ErrUtxoSnapshotRoot = errors.New("utxo snapshot does not match the utxo root of its block")
+)
+
+// UtxoSnapshotHeader is the first entry of a UTXO snapshot, describing the UTXO
+// set it holds. The UTXO root is the multiset hash committed to by the header
+// of the block the snapshot was taken at.
+type UtxoSnapshotHeader struct {
+	Version   uint64
+	Location  common.Location
+	BlockHash common.Hash
+	Number    uint64
+	UtxoRoot  common.Hash
+	Size      uint64
+}
+
+// UtxoSnapshotSummary holds the contents of a verified UTXO snapshot.
+type UtxoSnapshotSummary struct {
+	Header        *UtxoSnapshotHeader
+	Denominations [types.MaxDenomination + 1]uint64 // Number of UTXOs of each denomination
+	Locked        uint64                            // Number of UTXOs with a lock
+	Supply        *big.Int                          // Total value of the UTXOs
+}
+
+// utxoSnapshotChunk is an entry of a UTXO snapshot following its header. The
+// checksum is the hash of the RLP encoding of the UTXOs of the chunk.
+type utxoSnapshotChunk struct {
+	Utxos    []utxoSnapshotEntry
+	Checksum common.Hash
+}
+
+// utxoSnapshotEntry is a UTXO of a snapshot, with the UTXO protobuf encoded the
+// same way it is stored in the database.
+type utxoSnapshotEntry struct {
+	TxHash common.Hash
+	Index  uint16
+	Utxo   []byte
+}
+
+func (c *utxoSnapshotChunk) checksum() (common.Hash, error) {
+	data, err := rlp.EncodeToBytes(c.Utxos)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// ExportUtxoSnapshot writes the UTXO set at the given canonical block of the
+// zone database to the given writer. The set is read at the head of the
+// database and rolled back to the block, so the block can be any block below
+// the head whose UTXO changes have not been pruned yet. The exported UTXOs are
+// checked against the UTXO root of the block before the snapshot is complete.
+func ExportUtxoSnapshot(db ethdb.Database, w io.Writer, blockHash common.Hash, location common.Location, logger *log.Logger) (*UtxoSnapshotHeader, error) {
+	nodeCtx := location.Context()
+	if nodeCtx != common.ZONE_CTX {
+		return nil, errNoUtxoSet
+	}
+	number := rawdb.ReadHeaderNumber(db, blockHash)
+	if number == nil || rawdb.ReadCanonicalHash(db, *number) != blockHash {
+		return nil, errors.New("block is not canonical")
+	}
+	block := rawdb.ReadHeader(db, *number, blockHash)
+	if block == nil {
+		return nil, fmt.Errorf("header of block %s not found", blockHash)
+	}
+	headHash := rawdb.ReadHeadBlockHash(db)
+	headNumber := rawdb.ReadHeaderNumber(db, headHash)
+	if headNumber == nil || *headNumber < *number {
+		return nil, errors.New("block is ahead of the head")
+	}
+	head := rawdb.ReadHeader(db, *headNumber, headHash)
+	if head == nil {
+		return nil, errors.New("head header not found")
+	}
+	rollback, err := readUtxoRollback(db, head, blockHash, nodeCtx)
+	if err != nil {
+		return nil, err
+	}
+	// The restored UTXOs are not part of the current set, so they are merged
+	// into it in order
+	restored := make([]string, 0, len(rollback))
+	for key, utxo := range rollback {
+		if utxo != nil {
+			restored = append(restored, key)
+		}
+	}
+	sort.Strings(restored)
+
+	header := &UtxoSnapshotHeader{
+		Version:   UtxoSnapshotVersion,
+		Location:  location,
+		BlockHash: blockHash,
+		Number:    *number,
+		UtxoRoot:  block.UTXORoot(),
+		Size:      rawdb.ReadUTXOSetSize(db, blockHash),
+	}
+	if err := rlp.Encode(w, header); err != nil {
+		return nil, err
+	}
+
+	var (
+		multiSet = multiset.New()
+		chunk    = new(utxoSnapshotChunk)
+		size     uint64
+		start    = time.Now()
+		reported = time.Now()
+	)
+	add := func(key []byte, utxo *types.UtxoEntry) error {
+		txHash, index, err := rawdb.ReverseUtxoKey(key)
+		if err != nil {
+			return err
+		}
+		utxoProto, err := utxo.ProtoEncode()
+		if err != nil {
+			return err
+		}
+		data, err := proto.Marshal(utxoProto)
+		if err != nil {
+			return err
+		}
+		multiSet.Add(types.UTXOHash(txHash, index, utxo).Bytes())
+		size++
+		chunk.Utxos = append(chunk.Utxos, utxoSnapshotEntry{TxHash: txHash, Index: index, Utxo: data})
+		if len(chunk.Utxos) < c_utxoSnapshotChunkSize {
+			return nil
+		}
+		if err := writeUtxoSnapshotChunk(w, chunk); err != nil {
+			return err
+		}
+		chunk = new(utxoSnapshotChunk)
+		if time.Since(reported) >= exportReportInterval {
+			logger.WithFields(log.Fields{
+				"exported": size,
+				"elapsed":  common.PrettyDuration(time.Since(start)),
+			}).Info("Exporting utxo snapshot")
+			reported = time.Now()
+		}
+		return nil
+	}
+
+	it := db.NewIterator(rawdb.UtxoPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != rawdb.UtxoKeyLength {
+			continue
+		}
+		for len(restored) > 0 && restored[0] < string(key) {
+			if err := add([]byte(restored[0]), rollback[restored[0]]); err != nil {
+				return nil, err
+			}
+			restored = restored[1:]
+		}
+		if _, exists := rollback[string(key)]; exists {
+			continue
+		}
+		utxoProto := new(types.ProtoTxOut)
+		if err := proto.Unmarshal(it.Value(), utxoProto); err != nil {
+			return nil, err
+		}
+		utxo := new(types.UtxoEntry)
+		if err := utxo.ProtoDecode(utxoProto); err != nil {
+			return nil, err
+		}
+		if err := add(key, utxo); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	for _, key := range restored {
+		if err := add([]byte(key), rollback[key]); err != nil {
+			return nil, err
+		}
+	}
+	if len(chunk.Utxos) > 0 {
+		if err := writeUtxoSnapshotChunk(w, chunk); err != nil {
+			return nil, err
+		}
+	}
+	if multiSet.Hash() != header.UtxoRoot {
+		return nil, ErrUtxoSnapshotRoot
+	}
+	if size != header.Size {
+		return nil, fmt.Errorf("exported %d utxos, but the utxo set size of the block is %d", size, header.Size)
+	}
+	logger.WithFields(log.Fields{
+		"number":   header.Number,
+		"hash":     header.BlockHash,
+		"exported": size,
+		"elapsed":  common.PrettyDuration(time.Since(start)),
+	}).Info("Exported utxo snapshot")
+	return header, nil
+}
+
+func writeUtxoSnapshotChunk(w io.Writer, chunk *utxoSnapshotChunk) error {
+	checksum, err := chunk.checksum()
+	if err != nil {
+		return err
+	}
+	chunk.Checksum = checksum
+	return rlp.Encode(w, chunk)
+}
+
+// readUtxoSnapshot reads the UTXO snapshot of the zone at the given location
+// from the reader, calling fn for each of its UTXOs in order. The checksum of
+// every chunk is checked as it is read, and the UTXOs are checked against the
+// UTXO root and the size in the header of the snapshot once all are read, and
+// the multiset of the UTXOs is returned with the header.
+func readUtxoSnapshot(r io.Reader, location common.Location, fn func(utxo *types.SpentUtxoEntry) error) (*UtxoSnapshotHeader, *multiset.MultiSet, error) {
+	stream := rlp.NewStream(r, 0)
+	header := new(UtxoSnapshotHeader)
+	if err := stream.Decode(header); err != nil {
+		return nil, nil, fmt.Errorf("invalid utxo snapshot header: %v", err)
+	}
+	if header.Version != UtxoSnapshotVersion {
+		return nil, nil, fmt.Errorf("unsupported utxo snapshot version %d", header.Version)
+	}
+	if !header.Location.Equal(location) {
+		return nil, nil, fmt.Errorf("utxo snapshot of %s cannot be used in %s", header.Location.Name(), location.Name())
+	}
+
+	var (
+		multiSet = multiset.New()
+		size     uint64
+		last     *types.OutPoint
+	)
+	for chunks := 0; ; chunks++ {
+		chunk := new(utxoSnapshotChunk)
+		if err := stream.Decode(chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("utxo snapshot chunk %d: %v", chunks, err)
+		}
+		if checksum, err := chunk.checksum(); err != nil || checksum != chunk.Checksum {
+			return nil, nil, fmt.Errorf("utxo snapshot chunk %d: checksum mismatch", chunks)
+		}
+		for _, entry := range chunk.Utxos {
+			outPoint := types.OutPoint{TxHash: entry.TxHash, Index: entry.Index}
+			if last != nil && types.CompareOutPoints(*last, outPoint) >= 0 {
+				return nil, nil, fmt.Errorf("utxo snapshot chunk %d: utxos out of order", chunks)
+			}
+			last = &outPoint
+
+			utxoProto := new(types.ProtoTxOut)
+			if err := proto.Unmarshal(entry.Utxo, utxoProto); err != nil {
+				return nil, nil, fmt.Errorf("utxo snapshot chunk %d: %v", chunks, err)
+			}
+			utxo := new(types.UtxoEntry)
+			if err := utxo.ProtoDecode(utxoProto); err != nil {
+				return nil, nil, fmt.Errorf("utxo snapshot chunk %d: %v", chunks, err)
+			}
+			if utxo.Denomination > types.MaxDenomination {
+				return nil, nil, fmt.Errorf("utxo snapshot chunk %d: invalid denomination %d", chunks, utxo.Denomination)
+			}
+			multiSet.Add(types.UTXOHash(entry.TxHash, entry.Index, utxo).Bytes())
+			size++
+			if err := fn(&types.SpentUtxoEntry{OutPoint: outPoint, UtxoEntry: utxo}); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if root := multiSet.Hash(); root != header.UtxoRoot {
+		return nil, nil, fmt.Errorf("%w (have %x, want %x)", ErrUtxoSnapshotRoot, root, header.UtxoRoot)
+	}
+	if size != header.Size {
+		return nil, nil, fmt.Errorf("utxo snapshot holds %d utxos, but its header has %d", size, header.Size)
+	}
+	return header, multiSet, nil
+}
+
+// VerifyUtxoSnapshot reads the UTXO snapshot of the zone at the given location
+// from the reader and checks it against the UTXO root in its header, without
+// writing it anywhere. It returns the header and the supply held by the UTXOs.
+func VerifyUtxoSnapshot(r io.Reader, location common.Location) (*UtxoSnapshotSummary, error) {
+	summary := &UtxoSnapshotSummary{Supply: new(big.Int)}
+	header, _, err := readUtxoSnapshot(r, location, func(utxo *types.SpentUtxoEntry) error {
+		summary.Denominations[utxo.Denomination]++
+		if utxo.Lock != nil && utxo.Lock.Sign() > 0 {
+			summary.Locked++
+		}
+		summary.Supply.Add(summary.Supply, types.Denominations[utxo.Denomination])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary.Header = header
+	return summary, nil
+}
+
+// ImportUtxoSnapshot loads the UTXO snapshot of the zone at the given location
+// from the reader into the database, which must not have processed any block
+// past genesis. The snapshot is rejected, and the UTXOs written so far are
+// deleted again, unless the UTXOs match the UTXO root in its header. If the
+// header of the snapshot block is known, the root also has to match it.
+//
+// The block of the snapshot becomes the snap sync pivot of the database, so a
+// node started on it appends the blocks up to it without processing them, and
+// only downloads the state tries at the block from its peers.
+func ImportUtxoSnapshot(db ethdb.Database, r io.Reader, location common.Location, logger *log.Logger) (*UtxoSnapshotHeader, error) {
+	if location.Context() != common.ZONE_CTX {
+		return nil, errNoUtxoSet
+	}
+	if head := rawdb.ReadHeadBlockHash(db); head != (common.Hash{}) {
+		if number := rawdb.ReadHeaderNumber(db, head); number != nil && *number != 0 {
+			return nil, errors.New("cannot import a utxo snapshot into a chain that has processed blocks")
+		}
+	}
+	if err := DeleteUtxoSet(db); err != nil {
+		return nil, err
+	}
+
+	var (
+		batch    = db.NewBatch()
+		imported uint64
+		start    = time.Now()
+		reported = time.Now()
+	)
+	header, multiSet, err := readUtxoSnapshot(r, location, func(utxo *types.SpentUtxoEntry) error {
+		if err := rawdb.CreateUTXO(batch, utxo.TxHash, utxo.Index, utxo.UtxoEntry); err != nil {
+			return err
+		}
+		imported++
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(reported) >= exportReportInterval {
+			logger.WithFields(log.Fields{
+				"imported": imported,
+				"elapsed":  common.PrettyDuration(time.Since(start)),
+			}).Info("Importing utxo snapshot")
+			reported = time.Now()
+		}
+		return nil
+	})
+	if err == nil {
+		err = batch.Write()
+	}
+	if err == nil {
+		if block := rawdb.ReadHeader(db, header.Number, header.BlockHash); block != nil && block.UTXORoot() != header.UtxoRoot {
+			err = fmt.Errorf("%w (have %x, header has %x)", ErrUtxoSnapshotRoot, header.UtxoRoot, block.UTXORoot())
+		}
+	}
+	if err != nil {
+		if delErr := DeleteUtxoSet(db); delErr != nil {
+			logger.WithField("err", delErr).Error("Failed to delete the partially imported utxo set")
+		}
+		return nil, err
+	}
+
+	batch = db.NewBatch()
+	rawdb.WriteMultiSet(batch, header.BlockHash, multiSet)
+	rawdb.WriteUTXOSetSize(batch, header.BlockHash, header.Size)
+	rawdb.WriteSnapSyncPivot(batch, header.Number)
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	logger.WithFields(log.Fields{
+		"number":   header.Number,
+		"hash":     header.BlockHash,
+		"imported": imported,
+		"elapsed":  common.PrettyDuration(time.Since(start)),
+	}).Info("Imported utxo snapshot")
+	return header, nil
+}
+
+// DeleteUtxoSet deletes all the UTXOs of the database.
+func DeleteUtxoSet(db ethdb.Database) error {
+	it := db.NewIterator(rawdb.UtxoPrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		if len(it.Key()) != rawdb.UtxoKeyLength {
+			continue
+		}
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
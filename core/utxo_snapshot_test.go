@@ -0,0 +1,112 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto/multiset"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rlp"
+)
+
+// writeUtxoBlock stores a canonical zone block committing to the given UTXO
+// set.
+func writeUtxoBlock(t *testing.T, db ethdb.Database, parent *types.WorkObject, utxos []*types.SpentUtxoEntry) *types.WorkObject {
+	multiSet := multiset.New()
+	for _, utxo := range utxos {
+		multiSet.Add(types.UTXOHash(utxo.TxHash, utxo.Index, utxo.UtxoEntry).Bytes())
+	}
+	block := types.EmptyWorkObject(common.ZONE_CTX)
+	block.WorkObjectHeader().SetLocation(common.Location{0, 0})
+	block.WorkObjectHeader().SetPrimaryCoinbase(common.ZeroAddress(common.Location{0, 0}))
+	block.Header().SetUTXORoot(multiSet.Hash())
+	if parent != nil {
+		block.SetNumber(new(big.Int).Add(parent.Number(common.ZONE_CTX), common.Big1), common.ZONE_CTX)
+		block.SetParentHash(parent.Hash(), common.ZONE_CTX)
+	}
+	number := block.NumberU64(common.ZONE_CTX)
+	rawdb.WriteWorkObject(db, block.Hash(), block, types.BlockObject, common.ZONE_CTX)
+	rawdb.WriteHeaderNumber(db, block.Hash(), number)
+	rawdb.WriteCanonicalHash(db, block.Hash(), number)
+	rawdb.WriteHeadBlockHash(db, block.Hash())
+	rawdb.WriteUTXOSetSize(db, block.Hash(), uint64(len(utxos)))
+	require.NotNil(t, rawdb.ReadHeader(db, number, block.Hash()))
+	return block
+}
+
+func testUtxo(i int) *types.SpentUtxoEntry {
+	entry := types.NewUtxoEntry(types.NewTxOut(uint8(i%3), common.ZeroAddress(common.Location{0, 0}).Bytes(), big.NewInt(0)))
+	return &types.SpentUtxoEntry{OutPoint: types.OutPoint{TxHash: common.Hash{byte(i)}, Index: uint16(i)}, UtxoEntry: entry}
+}
+
+func TestUtxoSnapshotExportImport(t *testing.T) {
+	location := common.Location{0, 0}
+	db := rawdb.NewMemoryDatabase(log.Global)
+
+	// Block 1 holds utxos 1-3, and block 2 spends utxo 2 and creates utxo 4
+	genesis := writeUtxoBlock(t, db, nil, nil)
+	block1 := writeUtxoBlock(t, db, genesis, []*types.SpentUtxoEntry{testUtxo(1), testUtxo(2), testUtxo(3)})
+	block2 := writeUtxoBlock(t, db, block1, []*types.SpentUtxoEntry{testUtxo(1), testUtxo(3), testUtxo(4)})
+	for _, i := range []int{1, 3, 4} {
+		utxo := testUtxo(i)
+		require.NoError(t, rawdb.CreateUTXO(db, utxo.TxHash, utxo.Index, utxo.UtxoEntry))
+	}
+	require.NoError(t, rawdb.WriteSpentUTXOs(db, block2.Hash(), []*types.SpentUtxoEntry{testUtxo(2)}))
+	utxo4 := testUtxo(4)
+	require.NoError(t, rawdb.WriteCreatedUTXOKeys(db, block2.Hash(), [][]byte{rawdb.UtxoKeyWithDenomination(utxo4.TxHash, utxo4.Index, utxo4.Denomination)}))
+
+	// Export the set of block 1 from below the head
+	var snapshot bytes.Buffer
+	header, err := ExportUtxoSnapshot(db, &snapshot, block1.Hash(), location, log.Global)
+	require.NoError(t, err)
+	require.Equal(t, block1.UTXORoot(), header.UtxoRoot)
+	require.Equal(t, uint64(3), header.Size)
+
+	summary, err := VerifyUtxoSnapshot(bytes.NewReader(snapshot.Bytes()), location)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), summary.Denominations[0]+summary.Denominations[1]+summary.Denominations[2])
+	_, err = VerifyUtxoSnapshot(bytes.NewReader(snapshot.Bytes()), common.Location{0, 1})
+	require.Error(t, err)
+
+	// A corrupted chunk is rejected by its checksum
+	corrupted := common.CopyBytes(snapshot.Bytes())
+	corrupted[len(corrupted)-40] ^= 0xff
+	_, err = VerifyUtxoSnapshot(bytes.NewReader(corrupted), location)
+	require.Error(t, err)
+
+	// Import into a fresh database
+	fresh := rawdb.NewMemoryDatabase(log.Global)
+	_, err = ImportUtxoSnapshot(fresh, bytes.NewReader(snapshot.Bytes()), location, log.Global)
+	require.NoError(t, err)
+	for _, i := range []int{1, 2, 3} {
+		utxo := testUtxo(i)
+		require.NotNil(t, rawdb.GetUTXO(fresh, utxo.TxHash, utxo.Index))
+	}
+	require.Nil(t, rawdb.GetUTXO(fresh, utxo4.TxHash, utxo4.Index))
+	require.Equal(t, block1.UTXORoot(), rawdb.ReadMultiSet(fresh, block1.Hash()).Hash())
+	require.Equal(t, uint64(3), rawdb.ReadUTXOSetSize(fresh, block1.Hash()))
+	require.Equal(t, uint64(1), rawdb.ReadSnapSyncPivot(fresh))
+
+	// A snapshot whose utxos do not match its root is rejected, and leaves no
+	// utxo behind
+	var forged bytes.Buffer
+	forgedHeader := *header
+	forgedHeader.UtxoRoot = block2.UTXORoot()
+	require.NoError(t, rlp.Encode(&forged, &forgedHeader))
+	_, _, chunks, err := rlp.Split(snapshot.Bytes())
+	require.NoError(t, err)
+	forged.Write(chunks)
+	fresh = rawdb.NewMemoryDatabase(log.Global)
+	_, err = ImportUtxoSnapshot(fresh, &forged, location, log.Global)
+	require.True(t, errors.Is(err, ErrUtxoSnapshotRoot))
+	utxo1 := testUtxo(1)
+	require.Nil(t, rawdb.GetUTXO(fresh, utxo1.TxHash, utxo1.Index))
+}
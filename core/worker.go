@@ -717,7 +717,7 @@ func (w *worker) GeneratePendingHeader(block *types.WorkObject, fill bool, txs t
 		work.wo.Header().SetBaseFee(big.NewInt(0))
 	}
 
-	if block.NumberU64(common.ZONE_CTX) < params.TimeToStartTx {
+	if block.NumberU64(common.ZONE_CTX) < w.chainConfig.StartTxNumber() {
 		work.wo.Header().SetGasUsed(0)
 	}
 
@@ -1085,7 +1085,7 @@ func (w *worker) commitTransaction(env *environment, parent *types.WorkObject, t
 			return nil, false, fmt.Errorf("invalid coinbase address %v: %v", tx.To(), err)
 		}
 		lockupByte := tx.Data()[0]
-		if parent.NumberU64(common.ZONE_CTX) >= params.TimeToStartTx {
+		if parent.NumberU64(common.ZONE_CTX) >= w.chainConfig.StartTxNumber() {
 			if err := env.gasPool.SubGas(params.TxGas); err != nil {
 				// etxs are taking more gas
 				w.logger.Info("Stopped the etx processing because we crossed the block gas limit processing coinbase etxs")
@@ -1120,7 +1120,7 @@ func (w *worker) commitTransaction(env *environment, parent *types.WorkObject, t
 			}
 		}
 		gasUsed := env.wo.GasUsed()
-		if parent.NumberU64(common.ZONE_CTX) >= params.TimeToStartTx {
+		if parent.NumberU64(common.ZONE_CTX) >= w.chainConfig.StartTxNumber() {
 			gasUsed += params.TxGas
 		}
 		env.wo.Header().SetGasUsed(gasUsed)
@@ -1253,11 +1253,11 @@ func (w *worker) commitTransactions(env *environment, primeTerminus *types.WorkO
 				etxCount++
 			}
 		}
-		if parent.NumberU64(common.ZONE_CTX) < params.TimeToStartTx && etxCount > params.MinEtxCount {
+		if parent.NumberU64(common.ZONE_CTX) < w.chainConfig.StartTxNumber() && etxCount > params.MinEtxCount {
 			break
 		}
 		// Add ETXs until minimum gas is used
-		if parent.NumberU64(common.ZONE_CTX) >= params.TimeToStartTx && env.wo.GasUsed() >= minEtxGas {
+		if parent.NumberU64(common.ZONE_CTX) >= w.chainConfig.StartTxNumber() && env.wo.GasUsed() >= minEtxGas {
 			// included etxs more than min etx gas
 			break
 		}
@@ -1613,7 +1613,7 @@ func (w *worker) prepareWork(genParams *generateParams, wo *types.WorkObject) (*
 	// Only zone should calculate state
 	if nodeCtx == common.ZONE_CTX && w.hc.ProcessingState() {
 		newWo.Header().SetExtra(w.extra)
-		newWo.Header().SetStateLimit(misc.CalcStateLimit(parent, w.config.GasCeil))
+		newWo.Header().SetStateLimit(misc.CalcStateLimit(w.chainConfig, parent, w.config.GasCeil))
 		if w.isRunning() {
 			if w.GetPrimaryCoinbase().Equal(common.Zero) {
 				w.logger.Error("Refusing to mine without primary coinbase")
@@ -1882,7 +1882,7 @@ func (w *worker) fillTransactions(env *environment, primeTerminus *types.WorkObj
 // into the given sealing block. The transaction selection and ordering strategy can
 // be customized with the plugin in the future.
 func (w *worker) adjustGasLimit(env *environment, parent *types.WorkObject) {
-	env.wo.Header().SetGasLimit(CalcGasLimit(parent, w.config.GasCeil))
+	env.wo.Header().SetGasLimit(CalcGasLimit(w.chainConfig, parent, w.config.GasCeil))
 }
 
 // ComputeManifestHash given a header computes the manifest hash for the header
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+// workShareDistanceFunc returns the distance of a workshare to the block
+// including it.
+type workShareDistanceFunc func(wo *types.WorkObject, ws *types.WorkObjectHeader) (*big.Int, error)
+
+// coinbaseEtxs returns the coinbase transactions emitted by a block. The block
+// pays its primary coinbase first and then its secondary coinbase if the other
+// ledger collected fees, followed by one transaction for each included
+// workshare in the order of the uncles.
+func coinbaseEtxs(block *types.WorkObject) types.Transactions {
+	etxs := make(types.Transactions, 0, len(block.Uncles())+2)
+	for _, etx := range block.OutboundEtxs() {
+		if etx.EtxType() == types.CoinbaseType {
+			etxs = append(etxs, etx)
+		}
+	}
+	return etxs
+}
+
+// workShareRewards returns the rewards paid by a block, grouped by coinbase in
+// the order the block pays them.
+func workShareRewards(block *types.WorkObject, distance workShareDistanceFunc) ([]common.Address, map[common.AddressBytes][]*types.WorkShareReward, error) {
+	etxs := coinbaseEtxs(block)
+	uncles := block.Uncles()
+	blockRewards := len(etxs) - len(uncles)
+	if blockRewards < 1 || blockRewards > 2 {
+		return nil, nil, fmt.Errorf("block pays %d coinbase transactions for %d workshares", len(etxs), len(uncles))
+	}
+	number := block.NumberU64(common.ZONE_CTX)
+	coinbases := make([]common.Address, 0, len(etxs))
+	rewards := make(map[common.AddressBytes][]*types.WorkShareReward)
+	for i, etx := range etxs {
+		if etx.To() == nil || len(etx.Data()) == 0 {
+			return nil, nil, fmt.Errorf("invalid coinbase transaction %s", etx.Hash())
+		}
+		reward := &types.WorkShareReward{
+			BlockHash:   block.Hash(),
+			BlockNumber: number,
+			WorkShare:   block.Hash(),
+			Secondary:   i == 1 && blockRewards == 2,
+			Lock:        etx.Data()[0],
+			Reward:      new(big.Int).Set(etx.Value()),
+		}
+		if i >= blockRewards {
+			uncle := uncles[i-blockRewards]
+			if !uncle.PrimaryCoinbase().Equal(*etx.To()) {
+				return nil, nil, fmt.Errorf("coinbase transaction %s does not pay workshare %s", etx.Hash(), uncle.Hash())
+			}
+			reward.WorkShare = uncle.Hash()
+			shareDistance, err := distance(block, uncle)
+			if err != nil {
+				return nil, nil, err
+			}
+			reward.Distance = shareDistance.Uint64()
+		}
+		reward.LockedValue = params.CalculateCoinbaseValueWithLockup(reward.Reward, reward.Lock, number)
+		reward.UnlockHeight = number
+		if int(reward.Lock) < len(params.LockupByteToBlockDepth) {
+			reward.UnlockHeight += params.LockupByteToBlockDepth[reward.Lock]
+		}
+
+		coinbase := etx.To().Bytes20()
+		if _, exists := rewards[coinbase]; !exists {
+			coinbases = append(coinbases, *etx.To())
+		}
+		rewards[coinbase] = append(rewards[coinbase], reward)
+	}
+	return coinbases, rewards, nil
+}
+
+// indexWorkShares writes the rewards paid by a canonical block to the
+// workshare index of its coinbases.
+func indexWorkShares(db ethdb.Database, block *types.WorkObject, distance workShareDistanceFunc) error {
+	coinbases, rewards, err := workShareRewards(block, distance)
+	if err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	for _, coinbase := range coinbases {
+		rawdb.WriteWorkShareRewards(batch, coinbase, block.NumberU64(common.ZONE_CTX), rewards[coinbase.Bytes20()])
+	}
+	return batch.Write()
+}
+
+// unindexWorkShares deletes the rewards indexed for the given blocks, which
+// still have to be stored.
+func unindexWorkShares(db ethdb.Database, headers []*types.WorkObject, nodeCtx int, logger *log.Logger) error {
+	batch := db.NewBatch()
+	for _, header := range headers {
+		block := rawdb.ReadWorkObject(db, header.NumberU64(nodeCtx), header.Hash(), types.BlockObject)
+		if block == nil {
+			logger.WithField("hash", header.Hash()).Error("ChainIndexer: Error reading block to unindex workshares")
+			continue
+		}
+		for _, etx := range coinbaseEtxs(block) {
+			if etx.To() != nil {
+				rawdb.DeleteWorkShareRewards(batch, *etx.To(), block.NumberU64(nodeCtx))
+			}
+		}
+	}
+	return batch.Write()
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+// newTestWorkShare returns a workshare mined by the coinbase with the lockup
+// byte, with a nonce to tell it apart from the other workshares.
+func newTestWorkShare(coinbase common.Address, lock uint8, nonce uint64) *types.WorkObjectHeader {
+	workShare := types.CopyWorkObjectHeader(types.EmptyZoneWorkObject().WorkObjectHeader())
+	workShare.SetPrimaryCoinbase(coinbase)
+	workShare.SetLock(lock)
+	workShare.SetNonce(types.EncodeNonce(nonce))
+	return workShare
+}
+
+func newTestCoinbaseEtx(to common.Address, value int64, lock uint8) *types.Transaction {
+	return types.NewTx(&types.ExternalTx{To: &to, Value: big.NewInt(value), EtxType: types.CoinbaseType, Sender: to, Data: []byte{lock}})
+}
+
+func TestWorkShareIndex(t *testing.T) {
+	location := common.Location{0, 0}
+	db := rawdb.NewMemoryDatabase(log.Global)
+	primary := common.HexToAddress("0x0011111111111111111111111111111111111111", location)
+	secondary := common.HexToAddress("0x0091111111111111111111111111111111111111", location)
+	miner := common.HexToAddress("0x0022222222222222222222222222222222222222", location)
+
+	number := 2*params.BlocksPerMonth + 10
+	share1 := newTestWorkShare(miner, 0, 1)
+	share2 := newTestWorkShare(primary, 2, 2)
+	header := types.EmptyZoneWorkObject()
+	header.WorkObjectHeader().SetNumber(new(big.Int).SetUint64(number))
+	header.WorkObjectHeader().SetPrimaryCoinbase(primary)
+	etxs := []*types.Transaction{
+		newTestCoinbaseEtx(primary, 5000, 1),
+		newTestCoinbaseEtx(secondary, 30, 1),
+		types.NewTx(&types.ExternalTx{To: &miner, Value: big.NewInt(7)}),
+		newTestCoinbaseEtx(miner, 1000, 0),
+		newTestCoinbaseEtx(primary, 1000, 2),
+	}
+	block := header.WithBody(header.Body().Header(), nil, etxs, []*types.WorkObjectHeader{share1, share2}, nil, nil)
+	distances := map[common.Hash]int64{share1.Hash(): 1, share2.Hash(): 3}
+	distance := func(wo *types.WorkObject, ws *types.WorkObjectHeader) (*big.Int, error) {
+		require.Equal(t, block.Hash(), wo.Hash())
+		return big.NewInt(distances[ws.Hash()]), nil
+	}
+
+	require.NoError(t, indexWorkShares(db, block, distance))
+	newReward := func(workShare common.Hash, distance uint64, secondary bool, lock uint8, value int64) *types.WorkShareReward {
+		return &types.WorkShareReward{
+			BlockHash:    block.Hash(),
+			BlockNumber:  number,
+			WorkShare:    workShare,
+			Distance:     distance,
+			Secondary:    secondary,
+			Lock:         lock,
+			Reward:       big.NewInt(value),
+			LockedValue:  params.CalculateCoinbaseValueWithLockup(big.NewInt(value), lock, number),
+			UnlockHeight: number + params.LockupByteToBlockDepth[lock],
+		}
+	}
+	expected := map[common.Address][]*types.WorkShareReward{
+		primary:   {newReward(block.Hash(), 0, false, 1, 5000), newReward(share2.Hash(), 3, false, 2, 1000)},
+		secondary: {newReward(block.Hash(), 0, true, 1, 30)},
+		miner:     {newReward(share1.Hash(), 1, false, 0, 1000)},
+	}
+	for coinbase, rewards := range expected {
+		indexed, err := rawdb.ReadWorkShareRewards(db, coinbase, number, number)
+		require.NoError(t, err)
+		require.Equal(t, rewards, indexed, "coinbase %s", coinbase.Hex())
+	}
+	require.NotEqual(t, expected[primary][0].Reward, expected[primary][0].LockedValue, "lockup multiple not applied")
+
+	// The rewards of a block which is no longer canonical are deleted
+	rawdb.WriteWorkObject(db, block.Hash(), block, types.BlockObject, common.ZONE_CTX)
+	require.NoError(t, unindexWorkShares(db, []*types.WorkObject{block}, common.ZONE_CTX, log.Global))
+	for coinbase := range expected {
+		indexed, err := rawdb.ReadWorkShareRewards(db, coinbase, 0, number+1)
+		require.NoError(t, err)
+		require.Empty(t, indexed)
+	}
+
+	// Every workshare has to be paid by its own coinbase transaction
+	unpaid := header.WithBody(header.Body().Header(), nil, etxs[:3], []*types.WorkObjectHeader{share1, share2}, nil, nil)
+	require.Error(t, indexWorkShares(db, unpaid, distance))
+	misordered := header.WithBody(header.Body().Header(), nil, etxs, []*types.WorkObjectHeader{share2, share1}, nil, nil)
+	require.Error(t, indexWorkShares(db, misordered, distance))
+	require.Error(t, indexWorkShares(db, block, func(*types.WorkObject, *types.WorkObjectHeader) (*big.Int, error) {
+		return nil, errors.New("unknown parent")
+	}))
+}
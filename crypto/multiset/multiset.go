@@ -36,6 +36,11 @@ func (m MultiSet) Remove(data []byte) {
 	m.ms.Remove(data)
 }
 
+// Combine adds the elements of the other multiset to the multiset.
+func (m MultiSet) Combine(other *MultiSet) {
+	m.ms.Combine(other.ms)
+}
+
 func (m MultiSet) Hash() common.Hash {
 	finalizedHash := m.ms.Finalize()
 	return common.Hash(finalizedHash)
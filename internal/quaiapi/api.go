@@ -17,11 +17,13 @@
 package quaiapi
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"time"
 
@@ -169,6 +171,41 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// RPCQiClaim is an outpoint spent by a transaction of the Qi pool.
+type RPCQiClaim struct {
+	TxHash  common.Hash    `json:"txHash"`
+	Index   hexutil.Uint64 `json:"index"`
+	Spender common.Hash    `json:"spender"`
+}
+
+// QiConflicts returns the transactions of the Qi pool spending the given
+// outpoints, or all the outpoints claimed by the pool if none is given. A Qi
+// transaction spending a claimed outpoint only replaces its spender if it pays
+// a higher fee by the price bump of the pool.
+func (s *PublicTxPoolAPI) QiConflicts(outpoints []types.OutpointJSON) ([]*RPCQiClaim, error) {
+	if s.b.NodeCtx() != common.ZONE_CTX {
+		return nil, errors.New("qiConflicts can only be called in a zone chain")
+	}
+	query := make([]types.OutPoint, 0, len(outpoints))
+	for _, outpoint := range outpoints {
+		if uint64(outpoint.Index) > types.MaxOutputIndex {
+			return nil, fmt.Errorf("outpoint index %d exceeds the maximum output index %d", outpoint.Index, types.MaxOutputIndex)
+		}
+		query = append(query, types.OutPoint{TxHash: outpoint.TxHash, Index: uint16(outpoint.Index)})
+	}
+	claims := make([]*RPCQiClaim, 0)
+	for outpoint, spender := range s.b.TxPoolQiConflicts(query) {
+		claims = append(claims, &RPCQiClaim{TxHash: outpoint.TxHash, Index: hexutil.Uint64(outpoint.Index), Spender: spender})
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].TxHash != claims[j].TxHash {
+			return bytes.Compare(claims[i].TxHash[:], claims[j].TxHash[:]) < 0
+		}
+		return claims[i].Index < claims[j].Index
+	})
+	return claims, nil
+}
+
 // GetRollingFeeInfo returns an array of rolling values according to a 100 block peak filter.
 // []*hexutil.Big{min, max, avg}
 func (s *PublicTxPoolAPI) GetRollingFeeInfo() ([]*hexutil.Big, error) {
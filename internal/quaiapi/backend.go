@@ -21,6 +21,8 @@ import (
 	"context"
 	"math/big"
 
+	quai "github.com/dominant-strategies/go-quai"
+	"github.com/dominant-strategies/go-quai/accounts/keystore"
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/consensus"
 	"github.com/dominant-strategies/go-quai/core"
@@ -32,6 +34,7 @@ import (
 	"github.com/dominant-strategies/go-quai/event"
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/gasprice"
 	"github.com/dominant-strategies/go-quai/rpc"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 )
@@ -40,11 +43,14 @@ import (
 // both full and light clients) with access to necessary functions.
 type Backend interface {
 	// General Quai API
+	SyncProgress() *quai.SyncProgress
 	EventMux() *event.TypeMux
 
 	// General Quai API
 	ChainDb() ethdb.Database
 	ExtRPCEnabled() bool
+	InsecureUnlockAllowed() bool
+	KeyStore() *keystore.KeyStore
 	RPCGasCap() uint64    // global gas cap for eth_call over rpc: DoS protection
 	RPCTxFeeCap() float64 // global tx fee cap for all transaction related APIs
 
@@ -69,6 +75,7 @@ type Backend interface {
 	AddressLockups(ctx context.Context, address common.Address) ([]*types.Lockup, error)
 	GetOutpointsByAddressAndRange(ctx context.Context, address common.Address, start, end uint32) ([]*types.OutpointAndDenomination, error)
 	GetLockupsByAddressAndRange(ctx context.Context, address common.Address, start, end uint32) ([]*types.Lockup, error)
+	GetWorkSharesByCoinbase(ctx context.Context, address common.Address, start, end uint64) ([]*types.WorkShareReward, error)
 	UTXOsByAddress(ctx context.Context, address common.Address) ([]*types.UtxoEntry, error)
 	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
 	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.WorkObject, parent *types.WorkObject, vmConfig *vm.Config) (*vm.EVM, func() error, error)
@@ -93,9 +100,14 @@ type Backend interface {
 	AddPendingEtxsRollup(pEtxsRollup types.PendingEtxsRollup) error
 	PendingBlockAndReceipts() (*types.WorkObject, types.Receipts)
 	GenerateRecoveryPendingHeader(pendingHeader *types.WorkObject, checkpointHashes types.Termini) error
+	SetHead(hash common.Hash) error
+	SetHeadFromDom(domTerminus common.Hash, domPendingHeader *types.WorkObject) error
 	GetPendingEtxsRollupFromSub(hash common.Hash, location common.Location) (types.PendingEtxsRollup, error)
 	GetPendingEtxsFromSub(hash common.Hash, location common.Location) (types.PendingEtxs, error)
 	ProcessingState() bool
+	StateTrieNode(hash common.Hash) ([]byte, error)
+	UtxoRange(blockHash common.Hash, start types.OutPoint) (*types.UtxoRange, error)
+	SyncBlockState(hash common.Hash) *types.SyncBlockState
 	GetSlicesRunning() []common.Location
 	SetSubInterface(subInterface core.CoreBackend, location common.Location)
 	AddGenesisPendingEtxs(block *types.WorkObject)
@@ -133,17 +145,22 @@ type Backend interface {
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
 	SendRemoteTxs(txs types.Transactions) []error
+	AddPeerTxs(txs types.Transactions) []error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
 	Stats() (pending int, queued int, qi int)
+	SyncTxPool()
 	TxPoolContent() (map[common.InternalAddress]types.Transactions, map[common.InternalAddress]types.Transactions)
 	TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions)
+	TxPoolQiConflicts(outpoints []types.OutPoint) map[types.OutPoint]common.Hash
 	GetMinGasPrice() *big.Int
 	GetPoolGasPrice() *big.Int
 	SendTxToSharingClients(tx *types.Transaction)
 	GetRollingFeeInfo() (min, max, avg *big.Int)
+	FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*gasprice.FeeHistory, error)
+	SuggestFees(ctx context.Context) (*gasprice.FeeSuggestions, error)
 
 	// Filter API
 	BloomStatus() (uint64, uint64)
@@ -231,6 +248,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Service:   NewPublicWorkSharesAPI(apis[7].Service.(*PublicTransactionPoolAPI), apiBackend),
 			Public:    true,
 		})
+		apis = append(apis, rpc.API{
+			Namespace: "personal",
+			Version:   "1.0",
+			Service:   NewPrivateAccountAPI(apiBackend, nonceLock),
+		})
 	}
 
 	return apis
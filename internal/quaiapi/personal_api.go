@@ -0,0 +1,229 @@
+package quaiapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+// PrivateAccountAPI provides an API to access the accounts of the keystore of
+// the node, and to sign and send transactions with their keys. It is only
+// exposed over HTTP and WebSocket if the "personal" module is enabled.
+type PrivateAccountAPI struct {
+	b         Backend
+	nonceLock *AddrLocker
+}
+
+// NewPrivateAccountAPI creates a new PrivateAccountAPI.
+func NewPrivateAccountAPI(b Backend, nonceLock *AddrLocker) *PrivateAccountAPI {
+	return &PrivateAccountAPI{b, nonceLock}
+}
+
+// ListAccounts returns the addresses of the accounts of the keystore, which
+// may be located in any zone.
+func (s *PrivateAccountAPI) ListAccounts() []common.Address {
+	accs := s.b.KeyStore().Accounts()
+	addresses := make([]common.Address, len(accs))
+	for i, a := range accs {
+		addresses[i] = a.Address
+	}
+	return addresses
+}
+
+// NewAccount creates an account in the zone of the node, protected by the
+// given password. The ledger is "quai" unless "qi" is given.
+func (s *PrivateAccountAPI) NewAccount(password string, ledger *string) (common.Address, error) {
+	l := accounts.QuaiLedger
+	if ledger != nil {
+		var err error
+		if l, err = accounts.ParseLedger(*ledger); err != nil {
+			return common.Address{}, err
+		}
+	}
+	acc, err := s.b.KeyStore().NewAccount(password, s.b.NodeLocation(), l)
+	if err != nil {
+		return common.Address{}, err
+	}
+	s.b.Logger().WithFields(log.Fields{
+		"address": acc.Address.Hex(),
+		"ledger":  l,
+	}).Info("Created account")
+	return acc.Address, nil
+}
+
+// ImportRawKey stores the given hex encoded private key in the keystore,
+// protected by the given password.
+func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (common.Address, error) {
+	key, err := crypto.HexToECDSA(privkey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	acc, err := s.b.KeyStore().ImportECDSA(key, password)
+	return acc.Address, err
+}
+
+// UnlockAccount unlocks the account of an address with the given password for
+// the given number of seconds, 300 by default and indefinitely if zero.
+// Unlocking is refused while the RPC APIs are exposed over HTTP or WebSocket,
+// unless insecure unlocking is allowed.
+func (s *PrivateAccountAPI) UnlockAccount(ctx context.Context, addr common.Address, password string, duration *uint64) (bool, error) {
+	if s.b.ExtRPCEnabled() && !s.b.InsecureUnlockAllowed() {
+		return false, errors.New("account unlock with HTTP access is forbidden")
+	}
+	const max = uint64(time.Duration(1<<63-1) / time.Second)
+	var d time.Duration
+	if duration == nil {
+		d = 300 * time.Second
+	} else if *duration > max {
+		return false, errors.New("unlock duration too large")
+	} else {
+		d = time.Duration(*duration) * time.Second
+	}
+	if err := s.b.KeyStore().TimedUnlock(addr, password, d); err != nil {
+		s.b.Logger().WithFields(log.Fields{
+			"address": addr.Hex(),
+			"err":     err,
+		}).Warn("Failed account unlock attempt")
+		return false, err
+	}
+	return true, nil
+}
+
+// LockAccount locks the account of an address.
+func (s *PrivateAccountAPI) LockAccount(addr common.Address) bool {
+	return s.b.KeyStore().Lock(addr) == nil
+}
+
+// SignTransactionResult is a transaction signed by SignTransaction, both in
+// its protobuf encoding accepted by SendRawTransaction and in JSON.
+type SignTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// SignTransaction signs the transaction of the arguments with the keys of its
+// sender, or for a Qi transaction the keys of its inputs, decrypted with the
+// given password. A Quai transaction without a nonce is given the next nonce
+// of the sender in the pool.
+func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args TransactionArgs, passwd string) (*SignTransactionResult, error) {
+	tx, err := s.signTransaction(ctx, &args, passwd)
+	if err != nil {
+		return nil, err
+	}
+	protoTx, err := tx.ProtoEncode()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := proto.Marshal(protoTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{raw, tx}, nil
+}
+
+// SendTransaction signs the transaction of the arguments like SignTransaction
+// and submits it to the transaction pool.
+func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args TransactionArgs, passwd string) (common.Hash, error) {
+	// Hold the nonce of the sender until the transaction is in the pool, so
+	// that concurrent transactions get distinct nonces
+	if args.TxType != types.QiTxType && args.From != nil && args.Nonce == nil {
+		s.nonceLock.LockAddr(*args.From)
+		defer s.nonceLock.UnlockAddr(*args.From)
+	}
+	tx, err := s.signTransaction(ctx, &args, passwd)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	s.b.SendTxToSharingClients(tx)
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
+func (s *PrivateAccountAPI) signTransaction(ctx context.Context, args *TransactionArgs, passwd string) (*types.Transaction, error) {
+	if s.b.NodeCtx() != common.ZONE_CTX {
+		return nil, errors.New("transactions can only be signed in a zone chain")
+	}
+	if args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(s.b.ChainConfig().ChainID)
+	}
+	chainID := args.ChainID.ToInt()
+	if args.TxType == types.QiTxType {
+		tx, err := args.toTransaction()
+		if err != nil {
+			return nil, err
+		}
+		return s.b.KeyStore().SignQiTxWithPassphrase(passwd, tx, chainID)
+	}
+	if args.From == nil {
+		return nil, errors.New("sender not specified")
+	}
+	from := *args.From
+	if !from.Location().Equal(s.b.NodeLocation()) {
+		return nil, fmt.Errorf("sender %s is not in zone %s", from.Hex(), s.b.NodeLocation().Name())
+	}
+	db, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if db == nil || err != nil {
+		return nil, err
+	}
+	nonce := args.Nonce
+	if err := args.setDefaults(ctx, s.b, db); err != nil {
+		return nil, err
+	}
+	// setDefaults takes the nonce of the latest state, while the pool may
+	// already hold transactions of the sender
+	if nonce == nil {
+		poolNonce, err := s.b.GetPoolNonce(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		nonce = (*hexutil.Uint64)(&poolNonce)
+	}
+	args.Nonce = nonce
+	tx, err := args.toTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return s.b.KeyStore().SignTxWithPassphrase(from, passwd, tx, chainID)
+}
+
+// Sign calculates a signature of the message
+// keccak256("\x19Quai Signed Message:\n" + len(message) + message) by the key
+// of an address, decrypted with the given password. The recovery id of the
+// signature is 27 or 28, as expected by EcRecover.
+func (s *PrivateAccountAPI) Sign(ctx context.Context, data hexutil.Bytes, addr common.Address, passwd string) (hexutil.Bytes, error) {
+	sig, err := s.b.KeyStore().SignHashWithPassphrase(addr, passwd, accounts.TextHash(data))
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28
+	return sig, nil
+}
+
+// EcRecover returns the address of the key that created a signature with
+// Sign.
+func (s *PrivateAccountAPI) EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+	}
+	if sig[crypto.RecoveryIDOffset] != 27 && sig[crypto.RecoveryIDOffset] != 28 {
+		return common.Address{}, errors.New("invalid signature (V is not 27 or 28)")
+	}
+	sig = common.CopyBytes(sig)
+	sig[crypto.RecoveryIDOffset] -= 27 // Transform V from 27/28 to 0/1
+
+	rpk, err := crypto.SigToPub(accounts.TextHash(data), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return accounts.AddressOf(crypto.PubkeyToAddress(*rpk, s.b.NodeLocation()).Bytes20()), nil
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/metrics_config"
 	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/gasprice"
 	"github.com/dominant-strategies/go-quai/rpc"
 	"github.com/dominant-strategies/go-quai/trie"
 	"google.golang.org/protobuf/proto"
@@ -43,6 +44,7 @@ var (
 	txPropagationMetrics = metrics_config.NewCounterVec("TxPropagation", "Transaction propagation counter")
 	txEgressCounter      = txPropagationMetrics.WithLabelValues("egress")
 	maxOutpointsRange    = uint32(1000)
+	maxWorkSharesRange   = uint64(1000)
 )
 
 // PublicQuaiAPI provides an API to access Quai related information.
@@ -72,6 +74,100 @@ func (s *PublicQuaiAPI) MinerTip(ctx context.Context) *hexutil.Big {
 	return (*hexutil.Big)(s.b.GetPoolGasPrice())
 }
 
+type feeHistoryResult struct {
+	OldestBlock    *hexutil.Big     `json:"oldestBlock"`
+	Reward         [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee        []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio   []float64        `json:"gasUsedRatio"`
+	QiReward       [][]*hexutil.Big `json:"qiReward,omitempty"`
+	QiGasUsedRatio []float64        `json:"qiGasUsedRatio"`
+}
+
+func encodeBigs(values []*big.Int) []*hexutil.Big {
+	encoded := make([]*hexutil.Big, len(values))
+	for i, v := range values {
+		encoded[i] = (*hexutil.Big)(v)
+	}
+	return encoded
+}
+
+// FeeHistory returns the fee market history of the blocks up to lastBlock:
+// their base fees, the share of their gas limit used by all transactions and
+// by Qi transactions, and the gas prices paid by Quai and Qi transactions at
+// the given percentiles. The gas prices of Qi transactions are their fee
+// converted to Quai and divided by their gas.
+func (s *PublicQuaiAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	history, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:    (*hexutil.Big)(history.OldestBlock),
+		GasUsedRatio:   history.GasUsedRatio,
+		QiGasUsedRatio: history.QiGasUsedRatio,
+	}
+	if history.Reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(history.Reward))
+		results.QiReward = make([][]*hexutil.Big, len(history.QiReward))
+		for i := range history.Reward {
+			results.Reward[i] = encodeBigs(history.Reward[i])
+			results.QiReward[i] = encodeBigs(history.QiReward[i])
+		}
+	}
+	if history.BaseFee != nil {
+		results.BaseFee = encodeBigs(history.BaseFee)
+	}
+	return results, nil
+}
+
+// SuggestFees returns low, medium and high gas prices for Quai and Qi
+// transactions, taken from the gas prices paid in recent blocks, with the
+// time in seconds a transaction paying them is expected to take to be
+// included.
+func (s *PublicQuaiAPI) SuggestFees(ctx context.Context) (map[string]interface{}, error) {
+	suggestions, err := s.b.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	encodeLedger := func(ledger gasprice.LedgerFeeSuggestions) map[string]interface{} {
+		fields := make(map[string]interface{}, 3)
+		for name, suggestion := range map[string]gasprice.FeeSuggestion{"low": ledger.Low, "medium": ledger.Medium, "high": ledger.High} {
+			fields[name] = map[string]interface{}{
+				"gasPrice":      (*hexutil.Big)(suggestion.Fee),
+				"inclusionTime": hexutil.Uint64(suggestion.InclusionTime / time.Second),
+			}
+		}
+		return fields
+	}
+	return map[string]interface{}{
+		"baseFee": (*hexutil.Big)(suggestions.BaseFee),
+		"quai":    encodeLedger(suggestions.Quai),
+		"qi":      encodeLedger(suggestions.Qi),
+	}, nil
+}
+
+// Syncing returns false in case the node is not syncing with the network, as it
+// is up to date or has not yet received headers higher than its chain from its
+// peers. In case it is syncing it returns:
+// - startingBlock: block number this node started to sync from
+// - currentBlock:  block number this node is currently at
+// - highestBlock:  block number of the highest verified header received from peers
+// - pulledStates:  number of state entries processed until now
+// - knownStates:   number of known state entries that still need to be pulled
+func (s *PublicQuaiAPI) Syncing() (interface{}, error) {
+	progress := s.b.SyncProgress()
+	if progress == nil {
+		return false, nil
+	}
+	return map[string]interface{}{
+		"startingBlock": hexutil.Uint64(progress.StartingBlock),
+		"currentBlock":  hexutil.Uint64(progress.CurrentBlock),
+		"highestBlock":  hexutil.Uint64(progress.HighestBlock),
+		"pulledStates":  hexutil.Uint64(progress.PulledStates),
+		"knownStates":   hexutil.Uint64(progress.KnownStates),
+	}, nil
+}
+
 // PublicBlockChainQuaiAPI provides an API to access the Quai blockchain.
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicBlockChainQuaiAPI struct {
@@ -271,6 +367,39 @@ func (s *PublicBlockChainQuaiAPI) GetLockupsByAddressAndRange(ctx context.Contex
 	return jsonLockups, nil
 }
 
+// GetWorkSharesByCoinbase returns the rewards paid to a coinbase by the
+// canonical blocks from the start to the end number, for the blocks it mined
+// and for the workshares they included. Each reward is split under the lockup
+// byte of the miner into the value that unlocks and the block it unlocks at.
+func (s *PublicBlockChainQuaiAPI) GetWorkSharesByCoinbase(ctx context.Context, address common.Address, start, end hexutil.Uint64) ([]interface{}, error) {
+	if start > end {
+		return nil, fmt.Errorf("start is greater than end")
+	}
+	if uint64(end)-uint64(start) > maxWorkSharesRange {
+		return nil, fmt.Errorf("range is too large, max range is %d", maxWorkSharesRange)
+	}
+	rewards, err := s.b.GetWorkSharesByCoinbase(ctx, address, uint64(start), uint64(end))
+	if err != nil {
+		return nil, err
+	}
+	jsonRewards := make([]interface{}, 0, len(rewards))
+	for _, reward := range rewards {
+		jsonReward := map[string]interface{}{
+			"blockHash":    reward.BlockHash,
+			"blockNumber":  hexutil.Uint64(reward.BlockNumber),
+			"workShare":    reward.WorkShare,
+			"distance":     hexutil.Uint64(reward.Distance),
+			"secondary":    reward.Secondary,
+			"lock":         hexutil.Uint64(reward.Lock),
+			"reward":       (*hexutil.Big)(reward.Reward),
+			"lockedValue":  (*hexutil.Big)(reward.LockedValue),
+			"unlockHeight": hexutil.Uint64(reward.UnlockHeight),
+		}
+		jsonRewards = append(jsonRewards, jsonReward)
+	}
+	return jsonRewards, nil
+}
+
 func (s *PublicBlockChainQuaiAPI) GetOutPointsByAddressAndRange(ctx context.Context, address common.Address, start, end hexutil.Uint64) (map[string][]interface{}, error) {
 	if start > end {
 		return nil, fmt.Errorf("start is greater than end")
@@ -531,6 +660,70 @@ func (s *PublicBlockChainQuaiAPI) GetUTXO(ctx context.Context, txHash common.Has
 	return jsonOutpoint, nil
 }
 
+// GetTrimWarningsByAddress returns the UTXOs of the address which are going to
+// be trimmed from the UTXO set, with the block each of them is trimmed at.
+// The UTXOs have to be spent before then for the funds not to be lost.
+func (s *PublicBlockChainQuaiAPI) GetTrimWarningsByAddress(ctx context.Context, address common.Address) ([]*types.RPCTrimWarning, error) {
+	if address.IsInQuaiLedgerScope() {
+		return nil, fmt.Errorf("address %s is in Quai ledger scope", address.Hex())
+	}
+	outpoints, err := s.b.AddressOutpoints(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	query := make([]types.OutPoint, 0, len(outpoints))
+	for _, outpoint := range outpoints {
+		if outpoint == nil {
+			continue
+		}
+		query = append(query, types.OutPoint{TxHash: outpoint.TxHash, Index: outpoint.Index})
+	}
+	return s.trimWarnings(query), nil
+}
+
+// GetTrimWarnings returns the given outpoints which are going to be trimmed
+// from the UTXO set, with the block each of them is trimmed at.
+func (s *PublicBlockChainQuaiAPI) GetTrimWarnings(ctx context.Context, outpoints []types.OutpointJSON) ([]*types.RPCTrimWarning, error) {
+	if uint32(len(outpoints)) > maxOutpointsRange {
+		return nil, fmt.Errorf("too many outpoints, max is %d", maxOutpointsRange)
+	}
+	query := make([]types.OutPoint, 0, len(outpoints))
+	for _, outpoint := range outpoints {
+		if uint64(outpoint.Index) > types.MaxOutputIndex {
+			return nil, fmt.Errorf("outpoint index %d exceeds the maximum output index %d", outpoint.Index, types.MaxOutputIndex)
+		}
+		query = append(query, types.OutPoint{TxHash: outpoint.TxHash, Index: uint16(outpoint.Index)})
+	}
+	return s.trimWarnings(query), nil
+}
+
+// trimWarnings returns the trim warnings of the unspent outpoints which are
+// going to be trimmed.
+func (s *PublicBlockChainQuaiAPI) trimWarnings(outpoints []types.OutPoint) []*types.RPCTrimWarning {
+	db := s.b.Database()
+	warnings := make([]*types.RPCTrimWarning, 0)
+	for _, outpoint := range outpoints {
+		utxo := rawdb.GetUTXO(db, outpoint.TxHash, outpoint.Index)
+		if utxo == nil {
+			continue
+		}
+		// The creation height is indexed along with the address outpoints,
+		// otherwise it is the block which included the transaction
+		createdAt := uint64(rawdb.ReadUtxoToBlockHeight(db, outpoint.TxHash, outpoint.Index))
+		if createdAt == 0 {
+			number := rawdb.ReadTxLookupEntry(db, outpoint.TxHash)
+			if number == nil {
+				continue
+			}
+			createdAt = *number
+		}
+		if warning := types.NewRPCTrimWarning(outpoint, utxo, createdAt, s.b.NodeLocation()); warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
 // GetProof returns the Merkle-proof for a given account and optionally some storage keys.
 func (s *PublicBlockChainQuaiAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
 	nodeCtx := s.b.NodeCtx()
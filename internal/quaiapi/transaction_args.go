@@ -186,20 +186,31 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int, no
 
 // CalculateQiTxGas calculates the gas usage of a Qi transaction.
 func (args *TransactionArgs) CalculateQiTxGas(qiScalingFactor float64, location common.Location) (hexutil.Uint64, error) {
+	qiTx, err := args.toQiTx()
+	if err != nil {
+		return 0, err
+	}
+	tx := types.NewTx(qiTx)
+	return hexutil.Uint64(types.CalculateQiTxGas(tx, qiScalingFactor, location)), nil
+}
+
+// toQiTx converts the inputs and outputs of the arguments to an unsigned Qi
+// transaction.
+func (args *TransactionArgs) toQiTx() (*types.QiTx, error) {
 	if args.TxType != types.QiTxType {
-		return 0, errors.New("not a Qi transaction")
+		return nil, errors.New("not a Qi transaction")
 	}
 
 	if len(args.TxIn) == 0 || len(args.TxOut) == 0 {
-		return 0, errors.New("Qi transaction must have at least one input and one output")
+		return nil, errors.New("Qi transaction must have at least one input and one output")
 	} else if len(args.TxIn) > types.MaxOutputIndex {
-		return 0, fmt.Errorf("Qi transaction has too many inputs: %d", len(args.TxIn))
+		return nil, fmt.Errorf("Qi transaction has too many inputs: %d", len(args.TxIn))
 	}
 	ins := make([]types.TxIn, len(args.TxIn))
 	outs := make([]types.TxOut, len(args.TxOut))
 	for i, in := range args.TxIn {
 		if in.PreviousOutPoint.Index > types.MaxOutputIndex {
-			return 0, fmt.Errorf("Qi transaction has an input with an index too large: %d", in.PreviousOutPoint.Index)
+			return nil, fmt.Errorf("Qi transaction has an input with an index too large: %d", in.PreviousOutPoint.Index)
 		}
 		ins[i] = types.TxIn{
 			PreviousOutPoint: types.OutPoint{
@@ -217,11 +228,40 @@ func (args *TransactionArgs) CalculateQiTxGas(qiScalingFactor float64, location
 		}
 	}
 
-	qiTx := &types.QiTx{
+	return &types.QiTx{
 		TxIn:  ins,
 		TxOut: outs,
-	}
+	}, nil
+}
 
-	tx := types.NewTx(qiTx)
-	return hexutil.Uint64(types.CalculateQiTxGas(tx, qiScalingFactor, location)), nil
+// toTransaction converts the arguments to an unsigned transaction, a Qi
+// transaction if the Qi transaction type is given and a Quai transaction with
+// the defaults of setDefaults otherwise.
+func (args *TransactionArgs) toTransaction() (*types.Transaction, error) {
+	if args.TxType == types.QiTxType {
+		qiTx, err := args.toQiTx()
+		if err != nil {
+			return nil, err
+		}
+		qiTx.ChainID = (*big.Int)(args.ChainID)
+		return types.NewTx(qiTx), nil
+	}
+	if args.Nonce == nil || args.Gas == nil || args.GasPrice == nil || args.MinerTip == nil || args.Value == nil || args.ChainID == nil {
+		return nil, errors.New("transaction arguments are missing defaults")
+	}
+	var accessList types.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+	return types.NewTx(&types.QuaiTx{
+		ChainID:    (*big.Int)(args.ChainID),
+		Nonce:      uint64(*args.Nonce),
+		MinerTip:   (*big.Int)(args.MinerTip),
+		GasPrice:   (*big.Int)(args.GasPrice),
+		Gas:        uint64(*args.Gas),
+		To:         args.To,
+		Value:      (*big.Int)(args.Value),
+		Data:       args.data(),
+		AccessList: accessList,
+	}), nil
 }
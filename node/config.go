@@ -35,6 +35,7 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirJWTKey          = "jwtsecret"          // Path within the datadir to the jwt secret
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -82,6 +83,12 @@ type Config struct {
 	// USB enables hardware wallet monitoring and connectivity.
 	USB bool `toml:",omitempty"`
 
+	// IPCPath is the requested location to place the IPC endpoint. If the path is
+	// a simple file name, it is placed inside the data directory, or in the
+	// temporary directory without a data directory. An absolute path is used as
+	// is. If this field is empty, no IPC endpoint will be started.
+	IPCPath string
+
 	// HTTPHost is the host interface on which to start the HTTP RPC server. If this
 	// field is empty, no HTTP API endpoint will be started.
 	HTTPHost string
@@ -146,6 +153,24 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// AuthAddr is the host interface on which to start the authenticated RPC
+	// server, serving both HTTP and websocket requests. If this field is empty,
+	// no authenticated endpoint will be started.
+	AuthAddr string `toml:",omitempty"`
+
+	// AuthPort is the TCP port number on which to start the authenticated RPC
+	// server.
+	AuthPort int `toml:",omitempty"`
+
+	// AuthVirtualHosts is the list of virtual hostnames which are allowed on
+	// incoming requests to the authenticated RPC server.
+	AuthVirtualHosts []string `toml:",omitempty"`
+
+	// AuthModules is a list of API modules to expose via the authenticated RPC
+	// server. If the module list is empty, all API modules are exposed, and the
+	// tokens restrict the modules each client may call.
+	AuthModules []string `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger *log.Logger `toml:",omitempty"`
 
@@ -156,7 +181,8 @@ type Config struct {
 	// AllowUnprotectedTxs allows non EIP-155 protected transactions to be send over RPC.
 	AllowUnprotectedTxs bool `toml:",omitempty"`
 
-	// JWTSecret is the path to the hex-encoded jwt secret.
+	// JWTSecret is the path to the hex-encoded jwt secret of the authenticated
+	// RPC server. The secret is generated if the file does not exist.
 	JWTSecret string `toml:",omitempty"`
 
 	// EnablePersonal enables the deprecated personal namespace.
@@ -164,10 +190,32 @@ type Config struct {
 
 	DBEngine string `toml:",omitempty"`
 
+	// FreezerDepth is the depth after which finalized zone blocks are moved
+	// to the ancient store. Zero disables freezing.
+	FreezerDepth uint64 `toml:",omitempty"`
+
 	// NodeLocation is the location value for a particular chain
 	NodeLocation common.Location
 }
 
+// IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
+// account the set data folders as well as the designated platform we're currently
+// running on.
+func (c *Config) IPCEndpoint() string {
+	// Short circuit if IPC has not been enabled
+	if c.IPCPath == "" {
+		return ""
+	}
+	// Resolve names into the data directory full paths otherwise
+	if filepath.Base(c.IPCPath) == c.IPCPath {
+		if c.DataDir == "" {
+			return filepath.Join(os.TempDir(), c.IPCPath)
+		}
+		return filepath.Join(c.DataDir, c.IPCPath)
+	}
+	return c.IPCPath
+}
+
 // NodeDB returns the path to the discovery node database.
 func (c *Config) NodeDB() string {
 	if c.DataDir == "" {
@@ -207,9 +255,18 @@ func DefaultWSEndpoint() string {
 }
 
 // ExtRPCEnabled returns the indicator whether node enables the external
-// RPC(http, ws).
+// RPC(http, ws, authenticated).
 func (c *Config) ExtRPCEnabled() bool {
-	return c.HTTPHost != "" || c.WSHost != ""
+	return c.HTTPHost != "" || c.WSHost != "" || c.AuthAddr != ""
+}
+
+// AuthEndpoint resolves the endpoint of the authenticated RPC server based on
+// the configured host interface and port parameters.
+func (c *Config) AuthEndpoint() string {
+	if c.AuthAddr == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.AuthAddr, c.AuthPort)
 }
 
 // NodeName returns the devp2p node identifier.
@@ -276,3 +333,33 @@ func (c *Config) warnOnce(w *bool, format string, args ...interface{}) {
 	log.Global.Warn(fmt.Sprintf(format, args...))
 	*w = true
 }
+
+// getKeyStoreDir returns the directory of the keystore, which is created if it
+// does not exist. Without a data directory an ephemeral one is created, which
+// is reported so that it can be removed when the node is closed.
+func getKeyStoreDir(conf *Config) (string, bool, error) {
+	var (
+		keydir string
+		err    error
+	)
+	switch {
+	case filepath.IsAbs(conf.KeyStoreDir):
+		keydir = conf.KeyStoreDir
+	case conf.DataDir != "" && conf.KeyStoreDir == "":
+		keydir = filepath.Join(conf.DataDir, datadirDefaultKeyStore)
+	case conf.KeyStoreDir != "":
+		keydir, err = filepath.Abs(conf.KeyStoreDir)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if keydir == "" {
+		// There is no datadir.
+		keydir, err = os.MkdirTemp("", "go-quai-keystore")
+		return keydir, true, err
+	}
+	if err := os.MkdirAll(keydir, 0700); err != nil {
+		return "", false, err
+	}
+	return keydir, false, nil
+}
@@ -0,0 +1,166 @@
+package node
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+const (
+	jwtSecretLength  = 32               // Length in bytes of the HS256 secret
+	jwtIssuedAtDrift = 60 * time.Second // Tolerated clock drift, and lifetime of tokens without expiry
+)
+
+var (
+	errJWTMissingToken  = errors.New("missing bearer token")
+	errJWTIssuedAt      = errors.New("token issued-at is missing or in the future")
+	errJWTStale         = errors.New("token without expiry is stale")
+	errJWTExpired       = errors.New("token is expired")
+	errJWTSigningMethod = errors.New("token is not signed with HS256")
+)
+
+// jwtClaims are the claims of the tokens accepted by the authenticated RPC
+// endpoint. A token without an expiry has to be issued within the allowed drift
+// of the request, and a token without namespaces may call every namespace served
+// by the endpoint.
+type jwtClaims struct {
+	IssuedAt   int64    `json:"iat"`
+	ExpiresAt  int64    `json:"exp,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Valid implements jwt.Claims, checking the token times against the local clock.
+func (c *jwtClaims) Valid() error {
+	now := time.Now()
+	issued := time.Unix(c.IssuedAt, 0)
+	if c.IssuedAt == 0 || issued.After(now.Add(jwtIssuedAtDrift)) {
+		return errJWTIssuedAt
+	}
+	if c.ExpiresAt == 0 {
+		if now.Sub(issued) > jwtIssuedAtDrift {
+			return errJWTStale
+		}
+		return nil
+	}
+	if !now.Before(time.Unix(c.ExpiresAt, 0)) {
+		return errJWTExpired
+	}
+	return nil
+}
+
+// jwtHandler authenticates the requests with a HS256 bearer token before
+// passing them to the next handler, restricted to the namespaces of the token.
+type jwtHandler struct {
+	keyFunc func(token *jwt.Token) (interface{}, error)
+	next    http.Handler
+}
+
+func newJWTHandler(secret []byte, next http.Handler) http.Handler {
+	return &jwtHandler{
+		keyFunc: func(token *jwt.Token) (interface{}, error) {
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, errJWTSigningMethod
+			}
+			return secret, nil
+		},
+		next: next,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *jwtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		http.Error(w, errJWTMissingToken.Error(), http.StatusUnauthorized)
+		return
+	}
+	claims := new(jwtClaims)
+	token, err := jwt.ParseWithClaims(strings.TrimPrefix(auth, "Bearer "), claims, h.keyFunc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if !token.Valid {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	if len(claims.Namespaces) > 0 {
+		r = r.WithContext(rpc.WithNamespaces(r.Context(), claims.Namespaces))
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// NewJWTToken creates a token signed with the secret for the authenticated RPC
+// endpoint. The token is restricted to the given namespaces if there are any,
+// and is valid until the expiry, or for a short while if the expiry is zero.
+func NewJWTToken(secret []byte, namespaces []string, expiry time.Duration) (string, error) {
+	now := time.Now()
+	claims := &jwtClaims{IssuedAt: now.Unix(), Namespaces: namespaces}
+	if expiry > 0 {
+		claims.ExpiresAt = now.Add(expiry).Unix()
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// ReadJWTSecret reads the hex encoded secret of the authenticated RPC endpoint
+// from the file.
+func ReadJWTSecret(fileName string) ([]byte, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := hexutil.Decode("0x" + strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt secret in %s: %v", fileName, err)
+	}
+	if len(secret) != jwtSecretLength {
+		return nil, fmt.Errorf("invalid jwt secret in %s: want %d bytes, have %d", fileName, jwtSecretLength, len(secret))
+	}
+	return secret, nil
+}
+
+// obtainJWTSecret reads the secret from the file, or generates it into the file
+// if it does not exist. The slices of a node can share the file, so it is
+// created atomically and a secret generated concurrently by another slice wins.
+func obtainJWTSecret(fileName string) ([]byte, bool, error) {
+	if secret, err := ReadJWTSecret(fileName); !os.IsNotExist(err) {
+		return secret, false, err
+	}
+	secret := make([]byte, jwtSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return nil, false, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".tmp")
+	if err != nil {
+		return nil, false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(hexutil.Encode(secret)); err != nil {
+		tmp.Close()
+		return nil, false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, false, err
+	}
+	if err := os.Link(tmp.Name(), fileName); err != nil {
+		if os.IsExist(err) {
+			secret, err := ReadJWTSecret(fileName)
+			return secret, false, err
+		}
+		return nil, false, err
+	}
+	return secret, true, nil
+}
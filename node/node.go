@@ -28,6 +28,7 @@ import (
 
 	"github.com/prometheus/tsdb/fileutil"
 
+	"github.com/dominant-strategies/go-quai/accounts/keystore"
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core/rawdb"
 	"github.com/dominant-strategies/go-quai/ethdb"
@@ -51,8 +52,13 @@ type Node struct {
 	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
 	http          *httpServer //
 	ws            *httpServer //
+	httpAuth      *httpServer // Authenticated HTTP and WebSocket server
+	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 	location      []byte
+	keyStore      *keystore.KeyStore // Keys of the accounts of the node
+	keyDir        string             // Directory of the keystore
+	keyDirTemp    bool               // Whether the keystore directory is ephemeral
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -99,6 +105,17 @@ func New(conf *Config, logger *log.Logger) (*Node, error) {
 	if err := node.openDataDir(); err != nil {
 		return nil, err
 	}
+	keyDir, isEphem, err := getKeyStoreDir(conf)
+	if err != nil {
+		return nil, err
+	}
+	node.keyDir = keyDir
+	node.keyDirTemp = isEphem
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if conf.UseLightweightKDF {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	node.keyStore = keystore.NewKeyStore(keyDir, scryptN, scryptP)
 
 	// Check HTTP/WS prefixes are valid.
 	if err := validatePrefix("HTTP", conf.HTTPPathPrefix); err != nil {
@@ -111,6 +128,8 @@ func New(conf *Config, logger *log.Logger) (*Node, error) {
 	// Configure RPC servers.
 	node.http = newHTTPServer(node.logger, conf.HTTPTimeouts)
 	node.ws = newHTTPServer(node.logger, rpc.DefaultHTTPTimeouts)
+	node.httpAuth = newHTTPServer(node.logger, conf.HTTPTimeouts)
+	node.ipc = newIPCServer(node.logger, conf.IPCEndpoint())
 
 	return node, nil
 }
@@ -197,6 +216,14 @@ func (n *Node) doClose(errs []error) error {
 	// Release instance directory lock.
 	n.closeDataDir()
 
+	// Lock all accounts and remove an ephemeral keystore.
+	n.keyStore.Close()
+	if n.keyDirTemp {
+		if err := os.RemoveAll(n.keyDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// Unblock n.Wait.
 	close(n.stop)
 
@@ -287,6 +314,11 @@ func (n *Node) startRPC() error {
 		return err
 	}
 
+	// Configure IPC.
+	if err := n.ipc.start(n.rpcAPIs); err != nil {
+		return err
+	}
+
 	// Configure HTTP.
 	if n.config.HTTPHost != "" {
 		config := httpConfig{
@@ -319,10 +351,71 @@ func (n *Node) startRPC() error {
 		}
 	}
 
+	// Configure the authenticated endpoint, serving both HTTP and WebSocket.
+	if n.config.AuthAddr != "" {
+		secret, err := n.obtainJWTSecret()
+		if err != nil {
+			return err
+		}
+		modules := n.config.AuthModules
+		if len(modules) == 0 {
+			modules = apiNamespaces(n.rpcAPIs)
+		}
+		if err := n.httpAuth.setListenAddr(n.config.AuthAddr, n.config.AuthPort); err != nil {
+			return err
+		}
+		if err := n.httpAuth.enableHTTP(n.rpcAPIs, httpConfig{
+			Vhosts:    n.config.AuthVirtualHosts,
+			Modules:   modules,
+			jwtSecret: secret,
+		}); err != nil {
+			return err
+		}
+		if err := n.httpAuth.enableWS(n.rpcAPIs, wsConfig{
+			Modules:   modules,
+			jwtSecret: secret,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if err := n.http.start(); err != nil {
 		return err
 	}
-	return n.ws.start()
+	if err := n.ws.start(); err != nil {
+		return err
+	}
+	return n.httpAuth.start()
+}
+
+// obtainJWTSecret returns the secret of the authenticated endpoint, generating
+// it if the configured file does not exist.
+func (n *Node) obtainJWTSecret() ([]byte, error) {
+	fileName := n.config.JWTSecret
+	if fileName == "" {
+		fileName = n.ResolvePath(datadirJWTKey)
+	}
+	secret, generated, err := obtainJWTSecret(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if generated {
+		n.logger.WithField("path", fileName).Info("Generated JWT secret")
+	}
+	return secret, nil
+}
+
+// apiNamespaces returns the distinct namespaces of the APIs.
+func apiNamespaces(apis []rpc.API) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, api := range apis {
+		if !seen[api.Namespace] {
+			seen[api.Namespace] = true
+			namespaces = append(namespaces, api.Namespace)
+		}
+	}
+	return namespaces
 }
 
 func (n *Node) wsServerForPort(port int) *httpServer {
@@ -335,6 +428,8 @@ func (n *Node) wsServerForPort(port int) *httpServer {
 func (n *Node) stopRPC() {
 	n.http.stop()
 	n.ws.stop()
+	n.httpAuth.stop()
+	n.ipc.stop()
 	n.stopInProc()
 }
 
@@ -431,12 +526,28 @@ func (n *Node) InstanceDir() string {
 	return n.config.instanceDir()
 }
 
+// KeyStore retrieves the keystore holding the keys of the accounts of the node.
+func (n *Node) KeyStore() *keystore.KeyStore {
+	return n.keyStore
+}
+
 // HTTPEndpoint returns the URL of the HTTP server. Note that this URL does not
 // contain the JSON-RPC path prefix set by HTTPPathPrefix.
 func (n *Node) HTTPEndpoint() string {
 	return "http://" + n.http.listenAddr()
 }
 
+// IPCEndpoint retrieves the current IPC endpoint used by the protocol stack.
+func (n *Node) IPCEndpoint() string {
+	return n.ipc.endpoint
+}
+
+// AuthEndpoint returns the URL of the authenticated RPC server, which serves
+// both HTTP and WebSocket requests.
+func (n *Node) AuthEndpoint() string {
+	return "http://" + n.httpAuth.listenAddr()
+}
+
 // WSEndpoint returns the current JSON-RPC over WebSocket endpoint.
 func (n *Node) WSEndpoint() string {
 	if n.http.wsAllowed() {
@@ -502,6 +613,7 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient,
 			Type:              n.config.DBEngine,
 			Directory:         n.ResolvePath(name),
 			AncientsDirectory: n.ResolveAncient(name, ancient),
+			FreezerThreshold:  n.config.FreezerDepth,
 			Namespace:         namespace,
 			Cache:             cache,
 			Handles:           handles,
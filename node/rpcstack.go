@@ -41,13 +41,15 @@ type httpConfig struct {
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
+	jwtSecret          []byte // optional JWT secret authenticating the requests
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
-	Origins []string
-	Modules []string
-	prefix  string // path prefix on which to mount ws handler
+	Origins   []string
+	Modules   []string
+	prefix    string // path prefix on which to mount ws handler
+	jwtSecret []byte // optional JWT secret authenticating the connections
 }
 
 type rpcHandler struct {
@@ -300,7 +302,7 @@ func (h *httpServer) enableHTTP(apis []rpc.API, config httpConfig) error {
 	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts),
+		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -332,7 +334,7 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: srv.WebsocketHandler(config.Origins),
+		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -377,13 +379,24 @@ func isWebsocket(r *http.Request) bool {
 }
 
 // NewHTTPHandlerStack returns wrapped http-related handlers
-func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string) http.Handler {
+func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, jwtSecret []byte) http.Handler {
 	// Wrap the CORS-handler within a host-handler
 	handler := newCorsHandler(srv, cors)
 	handler = newVHostHandler(vhosts, handler)
+	if len(jwtSecret) != 0 {
+		handler = newJWTHandler(jwtSecret, handler)
+	}
 	return newGzipHandler(handler)
 }
 
+// NewWSHandlerStack returns a wrapped ws-related handler.
+func NewWSHandlerStack(srv http.Handler, jwtSecret []byte) http.Handler {
+	if len(jwtSecret) != 0 {
+		return newJWTHandler(jwtSecret, srv)
+	}
+	return srv
+}
+
 func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
@@ -485,6 +498,71 @@ func newGzipHandler(next http.Handler) http.Handler {
 	})
 }
 
+// ipcServer serves JSON-RPC, including subscriptions, on a local socket. All
+// the APIs are exposed, the socket being only accessible to the node's user.
+type ipcServer struct {
+	logger   *log.Logger
+	endpoint string
+
+	mu       sync.Mutex
+	listener net.Listener
+	srv      *rpc.Server
+}
+
+func newIPCServer(logger *log.Logger, endpoint string) *ipcServer {
+	return &ipcServer{logger: logger, endpoint: endpoint}
+}
+
+// start starts the IPC server if it is configured and not already running.
+func (is *ipcServer) start(apis []rpc.API) error {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if is.endpoint == "" || is.listener != nil {
+		return nil // already running or not configured
+	}
+	srv := rpc.NewServer(is.logger)
+	if err := RegisterApis(apis, nil, srv, true, is.logger); err != nil {
+		return err
+	}
+	listener, err := rpc.IPCListen(is.endpoint)
+	if err != nil {
+		is.logger.WithFields(log.Fields{
+			"endpoint": is.endpoint,
+			"err":      err,
+		}).Warn("IPC opening failed")
+		return err
+	}
+	is.listener, is.srv = listener, srv
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Global.WithFields(log.Fields{
+					"error":      r,
+					"stacktrace": string(debug.Stack()),
+				}).Error("Go-Quai Panicked")
+			}
+		}()
+		srv.ServeListener(listener)
+	}()
+	is.logger.WithField("url", is.endpoint).Info("IPC endpoint opened")
+	return nil
+}
+
+// stop shuts down the IPC server.
+func (is *ipcServer) stop() {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if is.listener == nil {
+		return // not running
+	}
+	is.listener.Close()
+	is.srv.Stop()
+	is.listener, is.srv = nil, nil
+	is.logger.WithField("url", is.endpoint).Info("IPC endpoint closed")
+}
+
 // RegisterApis checks the given modules' availability, generates an allowlist based on the allowed modules,
 // and then registers all of the APIs exposed by the services.
 func RegisterApis(apis []rpc.API, modules []string, srv *rpc.Server, exposeAll bool, logger *log.Logger) error {
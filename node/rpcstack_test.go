@@ -24,7 +24,9 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/rpc"
 	"github.com/gorilla/websocket"
@@ -290,3 +292,93 @@ func rpcRequest(t *testing.T, url string, extraHeaders ...string) *http.Response
 	}
 	return resp
 }
+
+type jwtTestService struct{}
+
+func (jwtTestService) Ping() string { return "pong" }
+
+// TestJWT makes sure the authenticated endpoint checks the tokens and restricts
+// them to their namespaces.
+func TestJWT(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x42}, jwtSecretLength)
+	apis := []rpc.API{
+		{Namespace: "miner", Service: jwtTestService{}},
+		{Namespace: "admin", Service: jwtTestService{}},
+	}
+	srv := newHTTPServer(log.Global, rpc.DefaultHTTPTimeouts)
+	assert.NoError(t, srv.enableHTTP(apis, httpConfig{Modules: []string{"miner", "admin"}, jwtSecret: secret}))
+	assert.NoError(t, srv.enableWS(apis, wsConfig{Modules: []string{"miner", "admin"}, jwtSecret: secret}))
+	assert.NoError(t, srv.setListenAddr("localhost", 0))
+	assert.NoError(t, srv.start())
+	defer srv.stop()
+	url := "http://" + srv.listenAddr()
+
+	call := func(method string, token string) (int, string) {
+		body := bytes.NewReader([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"%s","params":[]}`, method)))
+		req, err := http.NewRequest("POST", url, body)
+		assert.NoError(t, err)
+		req.Header.Set("content-type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		var buf bytes.Buffer
+		buf.ReadFrom(resp.Body)
+		return resp.StatusCode, buf.String()
+	}
+	sign := func(claims *jwtClaims, key []byte) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		assert.NoError(t, err)
+		return token
+	}
+	now := time.Now()
+
+	// Requests without a valid token are rejected
+	status, _ := call("miner_ping", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = call("miner_ping", sign(&jwtClaims{IssuedAt: now.Unix()}, bytes.Repeat([]byte{0x43}, jwtSecretLength)))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = call("miner_ping", sign(&jwtClaims{}, secret))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = call("miner_ping", sign(&jwtClaims{IssuedAt: now.Add(-2 * jwtIssuedAtDrift).Unix()}, secret))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = call("miner_ping", sign(&jwtClaims{IssuedAt: now.Add(-time.Hour).Unix(), ExpiresAt: now.Add(-time.Minute).Unix()}, secret))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, &jwtClaims{IssuedAt: now.Unix()}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	status, _ = call("miner_ping", none)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// A fresh token may call every namespace
+	token, err := NewJWTToken(secret, nil, 0)
+	assert.NoError(t, err)
+	status, body := call("admin_ping", token)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "pong")
+
+	// A long lived token is restricted to its namespaces
+	token, err = NewJWTToken(secret, []string{"miner"}, time.Hour)
+	assert.NoError(t, err)
+	_, body = call("miner_ping", token)
+	assert.Contains(t, body, "pong")
+	_, body = call("admin_ping", token)
+	assert.Contains(t, body, "not authorized")
+	_, body = call("rpc_modules", token)
+	assert.Contains(t, body, "miner")
+
+	// The restriction holds for the lifetime of a websocket connection
+	wsURL := "ws://" + srv.listenAddr()
+	_, _, err = websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.Error(t, err)
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": {"Bearer " + token}})
+	assert.NoError(t, err)
+	defer conn.Close()
+	for method, want := range map[string]string{"miner_ping": "pong", "admin_ping": "not authorized"} {
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"%s","params":[]}`, method))))
+		_, msg, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Contains(t, string(msg), want)
+	}
+}
@@ -2,8 +2,10 @@ package node
 
 import (
 	"math/big"
+	"net"
 	"reflect"
 	"runtime/debug"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/dominant-strategies/go-quai/p2p/protocol"
 	quaiprotocol "github.com/dominant-strategies/go-quai/p2p/protocol"
 	"github.com/dominant-strategies/go-quai/quai"
+	"github.com/dominant-strategies/go-quai/trie"
 
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -41,6 +44,7 @@ func (p *P2PNode) Start() error {
 	log.Global.Debugf("starting node processes...")
 	go p.eventLoop()
 	go p.statsLoop()
+	go p.staticPeersLoop()
 
 	// Register the Quai protocol handler
 	p.peerManager.GetHost().SetStreamHandler(quaiprotocol.ProtocolVersion, func(s network.Stream) {
@@ -289,6 +293,16 @@ func (p *P2PNode) Request(location common.Location, requestData interface{}, res
 	return resultChan
 }
 
+// RequestFromPeer requests the data from the given peer only, and waits for
+// its response
+func (p *P2PNode) RequestFromPeer(peerID p2p.PeerID, location common.Location, requestData interface{}, responseDataType interface{}) (interface{}, error) {
+	topic, err := pubsubManager.NewTopic(p.pubsub.GetGenesis(), location, responseDataType)
+	if err != nil {
+		return nil, err
+	}
+	return p.requestFromPeer(peerID, topic, requestData, responseDataType)
+}
+
 func (p *P2PNode) AdjustPeerQuality(peer p2p.PeerID, topic string, adjFn func(int) int) {
 	p.peerManager.AdjustPeerQuality(peer, topic, adjFn)
 }
@@ -314,10 +328,100 @@ func (p *P2PNode) BanPeer(peer p2p.PeerID) {
 		"peer": peer,
 	}).Warn("Banning peer for misbehaving")
 
-	p.peerManager.BanPeer(peer)
+	if err := p.peerManager.BanPeer(peer, "misbehaving", c_misbehaviourBanDuration); err != nil {
+		log.Global.WithFields(log.Fields{
+			"peer": peer,
+			"err":  err,
+		}).Error("Error banning peer")
+	}
 	p.peerManager.GetHost().Network().ClosePeer(peer)
 }
 
+// Peers returns the connected peers, with their quality and the traffic
+// exchanged with them
+func (p *P2PNode) Peers() []p2p.PeerInfo {
+	host := p.peerManager.GetHost()
+	peerTopics := make(map[peer.ID][]string)
+	for _, topic := range p.pubsub.GetTopics() {
+		for _, id := range p.pubsub.ListPeers(topic) {
+			peerTopics[id] = append(peerTopics[id], topic)
+		}
+	}
+
+	peers := host.Network().Peers()
+	infos := make([]p2p.PeerInfo, 0, len(peers))
+	for _, id := range peers {
+		info := p2p.PeerInfo{
+			ID:        id,
+			Protected: p.peerManager.IsProtected(id, ""),
+			Quality:   p.peerManager.GetPeerQuality(id),
+			Buckets:   make(map[string]string),
+			Topics:    peerTopics[id],
+			Latency:   host.Peerstore().LatencyEWMA(id),
+			Bandwidth: p.bandwidthCounter.GetBandwidthForPeer(id),
+		}
+		for topic, bucket := range p.peerManager.GetPeerBuckets(id) {
+			info.Buckets[topic] = bucket.String()
+		}
+		for _, conn := range host.Network().ConnsToPeer(id) {
+			info.Addrs = append(info.Addrs, conn.RemoteMultiaddr())
+			if conn.Stat().Direction == network.DirInbound {
+				info.Inbound = true
+			}
+		}
+		sort.Strings(info.Topics)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// AddPeer connects to the peer at the given multiaddress, which has to
+// include the ID of the peer
+func (p *P2PNode) AddPeer(addr string) error {
+	info, err := peer.AddrInfoFromString(addr)
+	if err != nil {
+		return err
+	}
+	log.Global.WithFields(log.Fields{
+		"peer":  info.ID,
+		"addrs": info.Addrs,
+	}).Info("Connecting to peer")
+	return p.Connect(*info)
+}
+
+// BlockPeer disconnects the peer and refuses any further connection with it
+func (p *P2PNode) BlockPeer(peer p2p.PeerID) error {
+	log.Global.WithFields(log.Fields{
+		"peer": peer,
+	}).Warn("Blocking peer")
+
+	if err := p.peerManager.BanPeer(peer, "blocked by operator", 0); err != nil {
+		return err
+	}
+	return p.peerManager.GetHost().Network().ClosePeer(peer)
+}
+
+// UnblockSubnet allows connections with the addresses of the subnet again
+func (p *P2PNode) UnblockSubnet(subnet *net.IPNet) error {
+	log.Global.WithFields(log.Fields{
+		"subnet": subnet,
+	}).Info("Unblocking subnet")
+
+	return p.peerManager.UnblockSubnet(subnet)
+}
+
+// ListBlocked returns the blocked peers, addresses and subnets
+func (p *P2PNode) ListBlocked() p2p.BlockedPeers {
+	return p2p.BlockedPeers{
+		Peers:   p.peerManager.ListBlockedPeers(),
+		Addrs:   p.peerManager.ListBlockedAddrs(),
+		Subnets: p.peerManager.ListBlockedSubnets(),
+	}
+}
+
 // Opens a new stream to the given peer using the given protocol ID
 func (p *P2PNode) GetStream(peerID peer.ID) (network.Stream, error) {
 	return p.peerManager.GetStream(peerID)
@@ -357,6 +461,26 @@ func (p *P2PNode) GetWorkObjectsFrom(hash common.Hash, location common.Location,
 	return response
 }
 
+// Search for the block in the data base and get the header views of up to count
+// blocks starting with it. The range stops early at the first block that is
+// not known or does not extend the previous one.
+func (p *P2PNode) GetWorkObjectHeadersFrom(hash common.Hash, location common.Location, count int) []*types.WorkObjectHeaderView {
+	block := p.consensus.LookupBlock(hash, location)
+	if block == nil {
+		return nil
+	}
+	response := []*types.WorkObjectHeaderView{block.ConvertToHeaderView()}
+	for i := 1; i < count; i++ {
+		nextNumber := block.NumberU64(location.Context()) + uint64(i)
+		next := p.consensus.LookupBlockByNumber(new(big.Int).SetUint64(nextNumber), location)
+		if next == nil || next.ParentHash(location.Context()) != response[i-1].Hash() {
+			break
+		}
+		response = append(response, next.ConvertToHeaderView())
+	}
+	return response
+}
+
 func (p *P2PNode) GetHeight(location common.Location) uint64 {
 	return p.consensus.GetHeight(location)
 }
@@ -365,6 +489,22 @@ func (p *P2PNode) GetBlockHashByNumber(number *big.Int, location common.Location
 	return p.consensus.LookupBlockHashByNumber(number, location)
 }
 
+func (p *P2PNode) GetTrieNode(hash common.Hash, location common.Location) *trie.TrieNodeResponse {
+	return p.consensus.GetTrieNode(hash, location)
+}
+
+func (p *P2PNode) GetUtxoRange(req *types.UtxoRangeRequest, location common.Location) *types.UtxoRange {
+	return p.consensus.GetUtxoRange(req, location)
+}
+
+func (p *P2PNode) GetSyncBlockState(hash common.Hash, location common.Location) *types.SyncBlockState {
+	return p.consensus.GetSyncBlockState(hash, location)
+}
+
+func (p *P2PNode) GetTransactions(hashes common.Hashes, location common.Location) types.Transactions {
+	return p.consensus.LookupTransactions(hashes, location)
+}
+
 func (p *P2PNode) GetBlockByNumber(number *big.Int, location common.Location) *types.WorkObject {
 	return p.consensus.LookupBlockByNumber(number, location)
 }
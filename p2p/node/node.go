@@ -39,6 +39,10 @@ const (
 	// c_defaultCacheSize is the default size for the p2p cache
 	c_defaultCacheSize    = 32
 	c_streamPeerThreshold = 25
+	// c_misbehaviourBanDuration is how long a misbehaving peer is banned for
+	c_misbehaviourBanDuration = 24 * time.Hour
+	// c_staticPeerRedialInterval is how often the disconnected static peers are redialed
+	c_staticPeerRedialInterval = 30 * time.Second
 )
 
 // P2PNode represents a libp2p node
@@ -103,7 +107,22 @@ func NewNode(ctx context.Context, quitCh chan struct{}) (*P2PNode, error) {
 		log.Global.Fatal(err)
 	}
 
-	rmgr, err := rcmgr.NewResourceManager(rcmgr.NewFixedLimiter(rcmgr.DefaultLimits.AutoScale()), rcmgr.WithTraceReporter(str))
+	// Trusted peers are allowlisted, which lets them connect above the
+	// connection limits
+	var trustedAddrs []multiaddr.Multiaddr
+	for _, info := range peerMgr.GetTrustedPeers() {
+		addrs, err := peer.AddrInfoToP2pAddrs(&info)
+		if err != nil {
+			log.Global.WithFields(log.Fields{
+				"peer": info.ID,
+				"err":  err,
+			}).Error("Invalid trusted peer address")
+			continue
+		}
+		trustedAddrs = append(trustedAddrs, addrs...)
+	}
+
+	rmgr, err := rcmgr.NewResourceManager(rcmgr.NewFixedLimiter(rcmgr.DefaultLimits.AutoScale()), rcmgr.WithTraceReporter(str), rcmgr.WithAllowlistedMultiaddrs(trustedAddrs))
 	if err != nil {
 		log.Global.Fatal(err)
 	}
@@ -275,6 +294,7 @@ var acceptableTypes = map[reflect.Type]struct{}{
 	reflect.TypeOf(types.WorkObjectShareView{}):  {},
 	reflect.TypeOf(types.WorkObjectBlockView{}):  {},
 	reflect.TypeOf(types.WorkObjectHeaderView{}): {},
+	reflect.TypeOf(common.Hashes{}):              {},
 }
 
 func initializeCaches(locations []common.Location) map[string]map[reflect.Type]*lru.Cache[common.Hash, interface{}] {
@@ -316,5 +336,9 @@ func (p *P2PNode) cacheAdd(hash common.Hash, data interface{}, location common.L
 // Get a datagram from the corresponding cache
 func (p *P2PNode) cacheGet(hash common.Hash, datatype interface{}, location common.Location) (interface{}, bool) {
 	cache := p.pickCache(datatype, location)
+	if cache == nil {
+		// Only the work object views are cached
+		return nil, false
+	}
 	return cache.Get(hash)
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/p2p"
 	"github.com/dominant-strategies/go-quai/p2p/node/peerManager"
@@ -19,6 +20,7 @@ import (
 	"github.com/dominant-strategies/go-quai/p2p/node/requestManager"
 	"github.com/dominant-strategies/go-quai/p2p/pb"
 	"github.com/dominant-strategies/go-quai/p2p/protocol"
+	"github.com/dominant-strategies/go-quai/trie"
 )
 
 // Opens a stream to the given peer and request some data for the given hash at the given location
@@ -94,7 +96,7 @@ func (p *P2PNode) requestFromPeer(peerID peer.ID, topic *pubsubManager.Topic, re
 	// Check the received data type & hash matches the request
 	switch respDataType.(type) {
 	// First, check that the recvdType is the same as the expected type
-	case *types.WorkObjectBlockView, *types.WorkObjectHeaderView, []*types.WorkObjectBlockView:
+	case *types.WorkObjectBlockView, *types.WorkObjectHeaderView, []*types.WorkObjectBlockView, []*types.WorkObjectHeaderView:
 		switch reqData := reqData.(type) {
 		case common.Hash:
 			// Next, if it was a requestByHash, verify the hash matches
@@ -126,6 +128,21 @@ func (p *P2PNode) requestFromPeer(peerID peer.ID, topic *pubsubManager.Topic, re
 				}
 			case []*types.WorkObjectBlockView:
 				return recvdType, nil
+			case []*types.WorkObjectHeaderView:
+				// A range of headers has to start at the requested number
+				// and form a continuous chain
+				if len(block) == 0 || len(block) > protocol.C_NumHeadersToDownload {
+					return nil, errors.Errorf("invalid response: got %d headers", len(block))
+				}
+				if block[0] == nil || block[0].Number(nodeCtx).Cmp(reqData) != 0 {
+					break
+				}
+				for i := 1; i < len(block); i++ {
+					if block[i] == nil || block[i].ParentHash(nodeCtx) != block[i-1].Hash() {
+						return nil, errors.New("invalid response: got non continuous headers")
+					}
+				}
+				return recvdType, nil
 			default:
 				return nil, errors.New("invalid response")
 			}
@@ -136,6 +153,45 @@ func (p *P2PNode) requestFromPeer(peerID peer.ID, topic *pubsubManager.Topic, re
 		if hash, ok := recvdType.(common.Hash); ok {
 			return hash, nil
 		}
+	case *trie.TrieNodeResponse:
+		// Trie nodes and code are addressed by the hash of their data
+		if trieNode, ok := recvdType.(*trie.TrieNodeResponse); ok {
+			if hash, ok := reqData.(common.Hash); ok && crypto.Keccak256Hash(trieNode.NodeData) == hash {
+				return trieNode, nil
+			}
+		}
+	case *types.UtxoRange:
+		if utxoRange, ok := recvdType.(*types.UtxoRange); ok {
+			if req, ok := reqData.(*types.UtxoRangeRequest); ok && utxoRange.Verify(req.Start) == nil {
+				return utxoRange, nil
+			}
+		}
+	case *types.SyncBlockState:
+		// The block state is verified against the pivot once the sync completes
+		if state, ok := recvdType.(*types.SyncBlockState); ok {
+			return state, nil
+		}
+	case types.Transactions:
+		// The peer may only return the requested transactions it has
+		if txs, ok := recvdType.(types.Transactions); ok {
+			if hashes, ok := reqData.(common.Hashes); ok && len(txs) <= len(hashes) {
+				requested := make(map[common.Hash]struct{}, len(hashes))
+				for _, hash := range hashes {
+					requested[hash] = struct{}{}
+				}
+				valid := true
+				for _, tx := range txs {
+					if _, ok := requested[tx.Hash()]; !ok {
+						valid = false
+						break
+					}
+					delete(requested, tx.Hash())
+				}
+				if valid {
+					return txs, nil
+				}
+			}
+		}
 	default:
 		log.Global.Warn("peer returned unexpected type")
 	}
@@ -55,6 +55,16 @@ const (
 
 	// c_maxBootNodes is the maximum number of bootnodes to connect to when bootstrapping
 	c_maxBootNodes = 10
+
+	// c_banDBName is the name of the database the peer bans are persisted in
+	c_banDBName = "bannedPeersDB"
+	// c_banExpiryCheckInterval is how often expired bans are lifted
+	c_banExpiryCheckInterval = 1 * time.Minute
+
+	// Connection manager tags protecting the static and trusted peers from
+	// being pruned
+	c_staticPeerTag  = "static"
+	c_trustedPeerTag = "trusted"
 )
 
 type PeerQuality int
@@ -67,9 +77,17 @@ const (
 )
 
 var (
-	dbNames = [3]string{"bestPeersDB", "responsivePeersDB", "lastResortPeersDB"}
+	dbNames      = [3]string{"bestPeersDB", "responsivePeersDB", "lastResortPeersDB"}
+	qualityNames = [4]string{"Best", "Responsive", "LastResort", "All"}
 )
 
+func (q PeerQuality) String() string {
+	if q < Best || q > All {
+		return "Unknown"
+	}
+	return qualityNames[q]
+}
+
 // PeerManager is an interface that extends libp2p Connection Manager and Gater
 type PeerManager interface {
 	connmgr.ConnManager
@@ -111,13 +129,25 @@ type PeerManager interface {
 
 	// Adjust the quality score of a peer by applying the given adjustment function
 	AdjustPeerQuality(p2p.PeerID, string, func(int) int)
+	// Returns the quality score of a peer
+	GetPeerQuality(p2p.PeerID) int
+	// Returns the quality bucket a peer is in for each topic it was scored on
+	GetPeerBuckets(p2p.PeerID) map[string]PeerQuality
 
 	// Protects the peer's connection from being disconnected
 	ProtectPeer(p2p.PeerID)
 	// Remove protection from the peer's connection
 	UnprotectPeer(p2p.PeerID)
-	// Bans the peer's connection from being re-established
-	BanPeer(p2p.PeerID)
+	// Bans the peer's connection from being re-established for the given
+	// duration, or forever if it is zero. The ban persists across restarts.
+	BanPeer(p2p.PeerID, string, time.Duration) error
+	// Returns the bans of the peers which have not expired yet
+	GetBans() (map[p2p.PeerID]peerdb.BanInfo, error)
+
+	// Returns the peers the node always stays connected to
+	GetStaticPeers() []peer.AddrInfo
+	// Returns the peers allowed to connect above the connection limits
+	GetTrustedPeers() []peer.AddrInfo
 
 	// Stops the peer manager
 	Stop() error
@@ -136,6 +166,13 @@ type BasicPeerManager struct {
 	// Initial bootpeers passed via config
 	bootpeers []peer.AddrInfo
 
+	// Static and trusted peers passed via config
+	staticPeers  []peer.AddrInfo
+	trustedPeers []peer.AddrInfo
+
+	// Persisted bans of the peers blocked by the connection gater
+	banDB *peerdb.PeerDB
+
 	// DHT instance
 	dht *kaddht.IpfsDHT
 
@@ -166,11 +203,26 @@ func NewManager(ctx context.Context, low int, high int, datastore datastore.Data
 		return nil, err
 	}
 
+	staticPeers, err := loadPeerAddrs(utils.StaticPeersFlag.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedPeers, err := loadPeerAddrs(utils.TrustedPeersFlag.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	peerDBs, err := loadPeerDBs()
 	if err != nil {
 		return nil, err
 	}
 
+	banDB, err := peerdb.NewPeerDB(c_banDBName, "")
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	logger := log.NewLogger("peers.log", viper.GetString(utils.PeersLogLevelFlag.Name), viper.GetInt(utils.LogSizeFlag.Name))
@@ -230,16 +282,35 @@ func NewManager(ctx context.Context, low int, high int, datastore datastore.Data
 		}
 	}()
 
-	return &BasicPeerManager{
+	pm := &BasicPeerManager{
 		ctx:                  ctx,
 		cancel:               cancel,
 		BasicConnMgr:         mgr,
 		BasicConnectionGater: gater,
 		genesis:              utils.MakeGenesis().ToBlock(0).Hash(),
 		bootpeers:            bootpeers,
+		staticPeers:          staticPeers,
+		trustedPeers:         trustedPeers,
 		peerDBs:              peerDBs,
+		banDB:                banDB,
 		logger:               logger,
-	}, nil
+	}
+
+	for _, info := range staticPeers {
+		mgr.Protect(info.ID, c_staticPeerTag)
+	}
+	for _, info := range trustedPeers {
+		mgr.Protect(info.ID, c_trustedPeerTag)
+	}
+
+	// Restore the bans of the previous runs, lifting the ones which expired
+	// in the meantime
+	if err := pm.expireBans(true); err != nil {
+		return nil, err
+	}
+	go pm.banExpiryLoop()
+
+	return pm, nil
 }
 
 func loadPeerDBs() (map[string][]*peerdb.PeerDB, error) {
@@ -247,6 +318,7 @@ func loadPeerDBs() (map[string][]*peerdb.PeerDB, error) {
 		&types.WorkObjectHeaderView{},
 		&types.WorkObjectBlockView{},
 		&types.WorkObjectShareView{},
+		common.Hashes{},
 	}
 
 	generateLocations := func() []common.Location {
@@ -302,9 +374,14 @@ func loadBootPeers() ([]peer.AddrInfo, error) {
 	if viper.GetBool(utils.SoloFlag.Name) || viper.GetString(utils.EnvironmentFlag.Name) == params.LocalName {
 		return nil, nil
 	}
+	return loadPeerAddrs(utils.BootPeersFlag.Name)
+}
 
-	var bootpeers []peer.AddrInfo
-	for _, p := range viper.GetStringSlice(utils.BootPeersFlag.Name) {
+// Loads the peer addresses of the given flag from the config and returns a
+// list of peer.AddrInfo
+func loadPeerAddrs(flag string) ([]peer.AddrInfo, error) {
+	var peers []peer.AddrInfo
+	for _, p := range viper.GetStringSlice(flag) {
 		addr, err := multiaddr.NewMultiaddr(p)
 		if err != nil {
 			return nil, err
@@ -313,10 +390,10 @@ func loadBootPeers() ([]peer.AddrInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		bootpeers = append(bootpeers, *info)
+		peers = append(peers, *info)
 	}
 
-	return bootpeers, nil
+	return peers, nil
 }
 
 func queryAllPeers(peerDBs map[string][]*peerdb.PeerDB, quality PeerQuality, peerCount int) ([]peer.AddrInfo, error) {
@@ -415,6 +492,20 @@ func (pm *BasicPeerManager) getPeerTopics(peerID p2p.PeerID) map[string]struct{}
 	return topics
 }
 
+func (pm *BasicPeerManager) GetPeerBuckets(peerID p2p.PeerID) map[string]PeerQuality {
+	key := datastore.NewKey(peerID.String())
+	buckets := map[string]PeerQuality{}
+	for topic, dbs := range pm.peerDBs {
+		for _, quality := range []PeerQuality{Best, Responsive, LastResort} {
+			if exists, _ := dbs[quality].Has(pm.ctx, key); exists {
+				buckets[topic] = quality
+				break
+			}
+		}
+	}
+	return buckets
+}
+
 func (pm *BasicPeerManager) SetSelfID(selfID p2p.PeerID) {
 	pm.selfID = selfID
 }
@@ -622,11 +713,135 @@ func (pm *BasicPeerManager) UnprotectPeer(peer p2p.PeerID) {
 	pm.Unprotect(peer, "gen_protection")
 }
 
-func (pm *BasicPeerManager) BanPeer(peer p2p.PeerID) {
-	pm.BlockPeer(peer)
+func (pm *BasicPeerManager) BanPeer(peerID p2p.PeerID, reason string, duration time.Duration) error {
+	ban := peerdb.BanInfo{Reason: reason}
+	if duration > 0 {
+		ban.Expiry = time.Now().Add(duration)
+	}
+	banInfo, err := proto.Marshal(ban.ProtoEncode())
+	if err != nil {
+		return errors.Wrap(err, "error marshaling ban info")
+	}
+	if err := pm.banDB.Put(pm.ctx, datastore.NewKey(peerID.String()), banInfo); err != nil {
+		return errors.Wrap(err, "error putting peer in bannedPeersDB")
+	}
+	return pm.BasicConnectionGater.BlockPeer(peerID)
+}
+
+// UnblockPeer lifts the ban of the peer, including its persisted ban
+func (pm *BasicPeerManager) UnblockPeer(peerID p2p.PeerID) error {
+	key := datastore.NewKey(peerID.String())
+	if exists, _ := pm.banDB.Has(pm.ctx, key); exists {
+		if err := pm.banDB.Delete(pm.ctx, key); err != nil {
+			return err
+		}
+	}
+	return pm.BasicConnectionGater.UnblockPeer(peerID)
+}
+
+func (pm *BasicPeerManager) GetBans() (map[p2p.PeerID]peerdb.BanInfo, error) {
+	bans, err := pm.readBans()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for peerID, ban := range bans {
+		if ban.Expired(now) {
+			delete(bans, peerID)
+		}
+	}
+	return bans, nil
+}
+
+// readBans returns the persisted bans, including the expired ones which have
+// not been lifted yet
+func (pm *BasicPeerManager) readBans() (map[p2p.PeerID]peerdb.BanInfo, error) {
+	entries, err := pm.banDB.All(pm.ctx)
+	if err != nil {
+		return nil, err
+	}
+	bans := make(map[p2p.PeerID]peerdb.BanInfo, len(entries))
+	for _, entry := range entries {
+		peerID, err := peer.Decode(strings.TrimPrefix(entry.Key, "/"))
+		if err != nil {
+			pm.logger.Errorf("Error decoding banned peer ID: %s", err)
+			continue
+		}
+		protoBan := &peerdb.ProtoBanInfo{}
+		if err := proto.Unmarshal(entry.Value, protoBan); err != nil {
+			pm.logger.Errorf("Error unmarshaling ban info: %s", err)
+			continue
+		}
+		var ban peerdb.BanInfo
+		ban.ProtoDecode(protoBan)
+		bans[peerID] = ban
+	}
+	return bans, nil
+}
+
+// expireBans lifts the bans which have expired. If restore is set, the bans
+// which have not expired are applied to the connection gater, which only
+// holds them in memory.
+func (pm *BasicPeerManager) expireBans(restore bool) error {
+	bans, err := pm.readBans()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for peerID, ban := range bans {
+		if ban.Expired(now) {
+			pm.logger.WithFields(log.Fields{
+				"peer":   peerID,
+				"reason": ban.Reason,
+			}).Info("Lifting expired peer ban")
+			if err := pm.UnblockPeer(peerID); err != nil {
+				return err
+			}
+		} else if restore {
+			if err := pm.BasicConnectionGater.BlockPeer(peerID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// banExpiryLoop periodically lifts the bans which have expired
+func (pm *BasicPeerManager) banExpiryLoop() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Global.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Error("Go-Quai Panicked")
+		}
+	}()
+	ticker := time.NewTicker(c_banExpiryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pm.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := pm.expireBans(false); err != nil {
+				pm.logger.WithField("err", err).Error("Error lifting expired peer bans")
+			}
+		}
+	}
+}
+
+func (pm *BasicPeerManager) GetStaticPeers() []peer.AddrInfo {
+	return pm.staticPeers
+}
+
+func (pm *BasicPeerManager) GetTrustedPeers() []peer.AddrInfo {
+	return pm.trustedPeers
 }
 
 func (pm *BasicPeerManager) Stop() error {
+	// Stop the background loops before closing the databases they use
+	pm.cancel()
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var closeErrors []string
@@ -634,6 +849,7 @@ func (pm *BasicPeerManager) Stop() error {
 	closeFuncs := []func() error{
 		pm.BasicConnMgr.Close,
 		pm.dht.Close,
+		pm.banDB.Close,
 	}
 
 	wg.Add(len(closeFuncs))
@@ -0,0 +1,123 @@
+package peerManager
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	basicConnGater "github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/cmd/utils"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/p2p/node/peerManager/peerdb"
+)
+
+// c_testBanDuration is the duration of the bans expiring during the tests. The
+// bans are persisted to the second, so it has to be longer than one.
+const c_testBanDuration = 2 * time.Second
+
+// openBanManager opens a peer manager on the bannedPeersDB of the data
+// directory with a fresh connection gater, and restores the persisted bans into
+// it as NewManager does.
+func openBanManager(t *testing.T) *BasicPeerManager {
+	t.Helper()
+	gater, err := basicConnGater.NewBasicConnectionGater(nil)
+	require.NoError(t, err)
+	banDB, err := peerdb.NewPeerDB(c_banDBName, "")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	pm := &BasicPeerManager{
+		BasicConnectionGater: gater,
+		banDB:                banDB,
+		ctx:                  ctx,
+		cancel:               cancel,
+		logger:               log.Global,
+	}
+	require.NoError(t, pm.expireBans(true))
+	return pm
+}
+
+// closeBanManager closes the bannedPeersDB of the peer manager, so that it can
+// be opened again.
+func closeBanManager(t *testing.T, pm *BasicPeerManager) {
+	t.Helper()
+	pm.cancel()
+	require.NoError(t, pm.banDB.Close())
+}
+
+func newPeerID(t *testing.T) peer.ID {
+	_, pubkey, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	peerID, err := peer.IDFromPublicKey(pubkey)
+	require.NoError(t, err)
+	return peerID
+}
+
+// TestBansRestored verifies the bans persisted by BanPeer are restored into the
+// connection gater once the bannedPeersDB is opened again, except the ones
+// which expired in the meantime.
+func TestBansRestored(t *testing.T) {
+	viper.GetViper().Set(utils.DataDirFlag.Name, t.TempDir())
+	permanent, temporary, expiring := newPeerID(t), newPeerID(t), newPeerID(t)
+
+	pm := openBanManager(t)
+	require.NoError(t, pm.BanPeer(permanent, "blocked by operator", 0))
+	require.NoError(t, pm.BanPeer(temporary, "misbehaving", time.Hour))
+	require.NoError(t, pm.BanPeer(expiring, "misbehaving", c_testBanDuration))
+	for _, peerID := range []peer.ID{permanent, temporary, expiring} {
+		require.False(t, pm.InterceptPeerDial(peerID))
+	}
+	closeBanManager(t, pm)
+	time.Sleep(c_testBanDuration)
+
+	pm = openBanManager(t)
+	defer closeBanManager(t, pm)
+	require.False(t, pm.InterceptPeerDial(permanent))
+	require.False(t, pm.InterceptPeerDial(temporary))
+	require.True(t, pm.InterceptPeerDial(expiring))
+	require.ElementsMatch(t, []peer.ID{permanent, temporary}, pm.ListBlockedPeers())
+
+	bans, err := pm.readBans()
+	require.NoError(t, err)
+	require.Len(t, bans, 2)
+	require.Equal(t, "blocked by operator", bans[permanent].Reason)
+	require.True(t, bans[permanent].Expiry.IsZero())
+	require.Equal(t, "misbehaving", bans[temporary].Reason)
+	require.False(t, bans[temporary].Expiry.IsZero())
+}
+
+// TestBanExpiry verifies an expired ban is lifted from the connection gater and
+// the bannedPeersDB, while the bans which have not expired are kept.
+func TestBanExpiry(t *testing.T) {
+	viper.GetViper().Set(utils.DataDirFlag.Name, t.TempDir())
+	permanent, expiring := newPeerID(t), newPeerID(t)
+
+	pm := openBanManager(t)
+	defer closeBanManager(t, pm)
+	require.NoError(t, pm.BanPeer(permanent, "blocked by operator", 0))
+	require.NoError(t, pm.BanPeer(expiring, "misbehaving", c_testBanDuration))
+	bans, err := pm.GetBans()
+	require.NoError(t, err)
+	require.Len(t, bans, 2)
+
+	time.Sleep(c_testBanDuration)
+	// The expired ban is no longer reported, but is only lifted by the expiry
+	// check
+	bans, err = pm.GetBans()
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.False(t, pm.InterceptPeerDial(expiring))
+
+	require.NoError(t, pm.expireBans(false))
+	require.True(t, pm.InterceptPeerDial(expiring))
+	require.False(t, pm.InterceptPeerDial(permanent))
+	bans, err = pm.readBans()
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.Contains(t, bans, permanent)
+}
@@ -117,6 +117,29 @@ func (p *PeerDB) Query(ctx context.Context, q query.Query) (query.Results, error
 	return result, nil
 }
 
+// All returns every entry of the datastore, unlike Query which returns a
+// random sample of at most c_maxPeerDbLookup entries.
+func (p *PeerDB) All(ctx context.Context) ([]query.Entry, error) {
+	iter := p.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	var entries []query.Entry
+	for iter.Next() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// The iterator reuses its buffers between entries
+		entries = append(entries, query.Entry{
+			Key:   string(iter.Key()),
+			Value: append([]byte{}, iter.Value()...),
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // Put stores the object `value` named by `key`.
 //
 // The generalized Datastore interface does not impose a value type,
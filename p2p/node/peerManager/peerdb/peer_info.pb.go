@@ -142,6 +142,59 @@ func (x *ProtoAddrInfo) GetAddrs() []string {
 	return nil
 }
 
+type ProtoBanInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Expiry int64  `protobuf:"varint,2,opt,name=expiry,proto3" json:"expiry,omitempty"`
+}
+
+func (x *ProtoBanInfo) Reset() {
+	*x = ProtoBanInfo{}
+	mi := &file_p2p_node_peerManager_peerdb_peer_info_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoBanInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoBanInfo) ProtoMessage() {}
+
+func (x *ProtoBanInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_node_peerManager_peerdb_peer_info_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoBanInfo.ProtoReflect.Descriptor instead.
+func (*ProtoBanInfo) Descriptor() ([]byte, []int) {
+	return file_p2p_node_peerManager_peerdb_peer_info_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProtoBanInfo) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProtoBanInfo) GetExpiry() int64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
 var File_p2p_node_peerManager_peerdb_peer_info_proto protoreflect.FileDescriptor
 
 var file_p2p_node_peerManager_peerdb_peer_info_proto_rawDesc = []byte{
@@ -161,7 +214,11 @@ var file_p2p_node_peerManager_peerdb_peer_info_proto_rawDesc = []byte{
 	0x6f, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x49,
 	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x41,
 	0x64, 0x64, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x41, 0x64, 0x64, 0x72,
-	0x73, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x22, 0x3e, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x42, 0x61, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x79, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
 	0x64, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6e, 0x74, 0x2d, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67,
 	0x69, 0x65, 0x73, 0x2f, 0x67, 0x6f, 0x2d, 0x71, 0x75, 0x61, 0x69, 0x2f, 0x70, 0x65, 0x65, 0x72,
 	0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x64, 0x62, 0x62, 0x06,
@@ -180,10 +237,11 @@ func file_p2p_node_peerManager_peerdb_peer_info_proto_rawDescGZIP() []byte {
 	return file_p2p_node_peerManager_peerdb_peer_info_proto_rawDescData
 }
 
-var file_p2p_node_peerManager_peerdb_peer_info_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_p2p_node_peerManager_peerdb_peer_info_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_p2p_node_peerManager_peerdb_peer_info_proto_goTypes = []any{
 	(*ProtoPeerInfo)(nil), // 0: peerdb.ProtoPeerInfo
 	(*ProtoAddrInfo)(nil), // 1: peerdb.ProtoAddrInfo
+	(*ProtoBanInfo)(nil),  // 2: peerdb.ProtoBanInfo
 }
 var file_p2p_node_peerManager_peerdb_peer_info_proto_depIdxs = []int32{
 	1, // 0: peerdb.ProtoPeerInfo.addrInfo:type_name -> peerdb.ProtoAddrInfo
@@ -205,7 +263,7 @@ func file_p2p_node_peerManager_peerdb_peer_info_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_p2p_node_peerManager_peerdb_peer_info_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
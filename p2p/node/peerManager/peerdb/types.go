@@ -2,6 +2,7 @@ package peerdb
 
 import (
 	sync "sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/multiformats/go-multiaddr"
@@ -21,6 +22,13 @@ type AddrInfo struct {
 	peer.AddrInfo
 }
 
+// contains the reason a peer was banned for and when
+// the ban expires, a zero expiry meaning it never does
+type BanInfo struct {
+	Reason string
+	Expiry time.Time
+}
+
 // PeerDB implements the ipfs Datastore interface
 // and exposes an API for storing and retrieving peers
 // using levelDB as the underlying database
@@ -72,3 +80,27 @@ func (addr *AddrInfo) ProtoDecode(protoAddr *ProtoAddrInfo) error {
 	}
 	return nil
 }
+
+func (bi *BanInfo) ProtoEncode() *ProtoBanInfo {
+	var expiry int64
+	if !bi.Expiry.IsZero() {
+		expiry = bi.Expiry.Unix()
+	}
+	return &ProtoBanInfo{
+		Reason: bi.Reason,
+		Expiry: expiry,
+	}
+}
+
+func (bi *BanInfo) ProtoDecode(pbi *ProtoBanInfo) {
+	bi.Reason = pbi.Reason
+	bi.Expiry = time.Time{}
+	if pbi.Expiry != 0 {
+		bi.Expiry = time.Unix(pbi.Expiry, 0)
+	}
+}
+
+// Expired returns whether the ban has expired at the given time
+func (bi *BanInfo) Expired(now time.Time) bool {
+	return !bi.Expiry.IsZero() && !now.Before(bi.Expiry)
+}
@@ -0,0 +1,41 @@
+package peerdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBanInfoProtoEncodeDecode(t *testing.T) {
+	now := time.Unix(time.Now().Unix(), 0)
+	bans := []BanInfo{
+		{Reason: "misbehaving", Expiry: now.Add(time.Hour)},
+		{Reason: "blocked by operator"},
+	}
+
+	for _, ban := range bans {
+		data, err := proto.Marshal(ban.ProtoEncode())
+		require.NoError(t, err)
+
+		protoBan := new(ProtoBanInfo)
+		require.NoError(t, proto.Unmarshal(data, protoBan))
+
+		decoded := BanInfo{}
+		decoded.ProtoDecode(protoBan)
+		require.Equal(t, ban.Reason, decoded.Reason)
+		require.True(t, ban.Expiry.Equal(decoded.Expiry))
+	}
+}
+
+func TestBanInfoExpired(t *testing.T) {
+	now := time.Now()
+
+	permanent := BanInfo{Reason: "blocked by operator"}
+	require.False(t, permanent.Expired(now.Add(100*365*24*time.Hour)))
+
+	temporary := BanInfo{Reason: "misbehaving", Expiry: now.Add(time.Hour)}
+	require.False(t, temporary.Expired(now))
+	require.True(t, temporary.Expired(now.Add(time.Hour)))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/dominant-strategies/go-quai/log"
 	p2p "github.com/dominant-strategies/go-quai/p2p"
 	"github.com/dominant-strategies/go-quai/p2p/pb"
+	"github.com/dominant-strategies/go-quai/p2p/protocol"
 	"github.com/dominant-strategies/go-quai/params"
 	"github.com/dominant-strategies/go-quai/quai"
 )
@@ -425,6 +426,29 @@ func (g *PubsubManager) ValidatorFunc() func(ctx context.Context, id p2p.PeerID,
 			if backend.NodeCtx() == common.ZONE_CTX && workShareIntrinsicEntropy.Cmp(new(big.Int).Div(currentHeaderIntrinsic, big.NewInt(2))) < 0 {
 				return pubsub.ValidationIgnore
 			}
+
+		case common.Hashes:
+
+			protoHashes := new(common.ProtoHashes)
+			err := proto.Unmarshal(protoData, protoHashes)
+			if err != nil {
+				log.Global.WithField("err", err).Error("Error unmarshalling proto tx announcement")
+				return pubsub.ValidationReject
+			}
+
+			hashes := common.Hashes{}
+			hashes.ProtoDecode(protoHashes)
+			if len(hashes) == 0 || len(hashes) > protocol.C_MaxTxAnnouncementHashes {
+				log.Global.WithFields(log.Fields{
+					"peer":   id,
+					"hashes": len(hashes),
+				}).Warn("Invalid number of hashes in tx announcement")
+				return pubsub.ValidationReject
+			}
+
+			// Announcements are relayed once their transactions are in the
+			// pool, and the missing ones are fetched in the background
+			return g.consensus.ValidateTxAnnouncement(id, hashes, topic.location)
 		}
 		return pubsub.ValidationAccept
 	}
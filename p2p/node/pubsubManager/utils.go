@@ -7,6 +7,7 @@ import (
 
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/trie"
 	"github.com/ipfs/go-cid"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -41,12 +42,14 @@ func (t *Topic) buildTopicString() string {
 	encodedLocation := strings.Join(parts, ",")
 	baseTopic := strings.Join([]string{t.genesis.String(), encodedLocation}, "/")
 	switch t.data.(type) {
-	case *types.WorkObjectHeaderView:
+	case *types.WorkObjectHeaderView, []*types.WorkObjectHeaderView:
 		return strings.Join([]string{baseTopic, C_headerType}, "/")
-	case *types.WorkObjectBlockView, []*types.WorkObjectBlockView:
+	case *types.WorkObjectBlockView, []*types.WorkObjectBlockView, *trie.TrieNodeResponse, *types.UtxoRange, *types.SyncBlockState:
 		return strings.Join([]string{baseTopic, C_workObjectType}, "/")
 	case *types.WorkObjectShareView:
 		return strings.Join([]string{baseTopic, C_workObjectShareType}, "/")
+	case common.Hashes, types.Transactions:
+		return strings.Join([]string{baseTopic, C_transactionType}, "/")
 	default:
 		panic(ErrUnsupportedType)
 	}
@@ -74,10 +77,17 @@ func NewTopic(genesis common.Hash, location common.Location, data interface{}) (
 	switch data.(type) {
 	case *types.WorkObjectShareView:
 		requestDegree = C_defaultRequestDegree
-	case *types.WorkObjectHeaderView:
+	case common.Hashes, types.Transactions:
+		// Transactions are announced by hash and fetched from the
+		// announcing peers
+		requestDegree = C_defaultRequestDegree
+	case *types.WorkObjectHeaderView, []*types.WorkObjectHeaderView:
 		requestDegree = C_workObjectHeaderTypeRequestDegree
 	case *types.WorkObjectBlockView, []*types.WorkObjectBlockView:
 		requestDegree = C_workObjectRequestDegree
+	case *trie.TrieNodeResponse, *types.UtxoRange, *types.SyncBlockState:
+		// State is requested from the peers of the zone serving its blocks
+		requestDegree = C_workObjectRequestDegree
 	default:
 		return nil, ErrUnsupportedType
 	}
@@ -135,6 +145,8 @@ func TopicFromString(topic string) (*Topic, error) {
 		return NewTopic(genHash, location, &types.WorkObjectBlockView{})
 	case C_workObjectShareType:
 		return NewTopic(genHash, location, &types.WorkObjectShareView{})
+	case C_transactionType:
+		return NewTopic(genHash, location, common.Hashes{})
 	default:
 		return nil, ErrUnsupportedType
 	}
@@ -15,6 +15,7 @@ func TestTopicFromString(t *testing.T) {
 		{"0x0011223344556677889900112233445566778899001122334455667788990011/0,0/blocks", true},
 		{"0x0011223344556677889900112233445566778899001122334455667788990011/0,0/headers", true},
 		{"0x0011223344556677889900112233445566778899001122334455667788990011/1,0/worksharev2", true},
+		{"0x0011223344556677889900112233445566778899001122334455667788990011/1,2/transactions", true},
 		{"0x0011223344556677889900112233445566778899001122334455667788990011/7,0/blocks", true},
 		{"0x0011223344556677889900112233445566778899001122334455667788990011/15,0/headers", true},
 		{"0x0011223344556677889900112233445566778899001122334455667788990011/15,15/headers", true},
@@ -7,6 +7,7 @@ import (
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/metrics_config"
+	"github.com/libp2p/go-libp2p/core/network"
 )
 
 var (
@@ -68,3 +69,39 @@ func (p *P2PNode) statsLoop() {
 		}
 	}
 }
+
+// staticPeersLoop redials the static peers the node is not connected to
+func (p *P2PNode) staticPeersLoop() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Global.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Error("Go-Quai Panicked")
+		}
+	}()
+	staticPeers := p.peerManager.GetStaticPeers()
+	if len(staticPeers) == 0 {
+		return
+	}
+	ticker := time.NewTicker(c_staticPeerRedialInterval)
+	defer ticker.Stop()
+	for {
+		for _, info := range staticPeers {
+			if p.peerManager.GetHost().Network().Connectedness(info.ID) == network.Connected {
+				continue
+			}
+			if err := p.Connect(info); err != nil {
+				log.Global.WithFields(log.Fields{
+					"peer": info.ID,
+					"err":  err,
+				}).Warn("Failed to connect to static peer")
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/trie"
 )
 
 var EmptyResponse = errors.New("received empty reponse from peer")
@@ -34,6 +35,14 @@ func EncodeQuaiRequest(id uint32, location common.Location, reqData interface{},
 		reqMsg.Data = &QuaiRequestMessage_Hash{Hash: d.ProtoEncode()}
 	case *big.Int:
 		reqMsg.Data = &QuaiRequestMessage_Number{Number: d.Bytes()}
+	case *types.UtxoRangeRequest:
+		protoReq, err := d.ProtoEncode()
+		if err != nil {
+			return nil, err
+		}
+		reqMsg.Data = &QuaiRequestMessage_UtxoRangeRequest{UtxoRangeRequest: protoReq}
+	case common.Hashes:
+		reqMsg.Data = &QuaiRequestMessage_Hashes{Hashes: d.ProtoEncode()}
 	default:
 		return nil, errors.Errorf("unsupported request input data field type: %T", reqData)
 	}
@@ -45,8 +54,18 @@ func EncodeQuaiRequest(id uint32, location common.Location, reqData interface{},
 		reqMsg.Request = &QuaiRequestMessage_WorkObjectBlocks{}
 	case *types.WorkObjectHeaderView:
 		reqMsg.Request = &QuaiRequestMessage_WorkObjectHeader{}
+	case []*types.WorkObjectHeaderView:
+		reqMsg.Request = &QuaiRequestMessage_WorkObjectHeaders{}
 	case common.Hash:
 		reqMsg.Request = &QuaiRequestMessage_BlockHash{}
+	case *trie.TrieNodeResponse:
+		reqMsg.Request = &QuaiRequestMessage_TrieNode{}
+	case *types.UtxoRange:
+		reqMsg.Request = &QuaiRequestMessage_UtxoRange{}
+	case *types.SyncBlockState:
+		reqMsg.Request = &QuaiRequestMessage_SyncBlockState{}
+	case types.Transactions:
+		reqMsg.Request = &QuaiRequestMessage_Transactions{}
 	default:
 		return nil, errors.Errorf("unsupported request data type: %T", respDataType)
 	}
@@ -77,6 +96,16 @@ func DecodeQuaiRequest(reqMsg *QuaiRequestMessage) (uint32, interface{}, common.
 		reqData = hash
 	case *QuaiRequestMessage_Number:
 		reqData = new(big.Int).SetBytes(d.Number)
+	case *QuaiRequestMessage_UtxoRangeRequest:
+		req := &types.UtxoRangeRequest{}
+		if err := req.ProtoDecode(d.UtxoRangeRequest); err != nil {
+			return reqMsg.Id, nil, common.Location{}, common.Hash{}, err
+		}
+		reqData = req
+	case *QuaiRequestMessage_Hashes:
+		hashes := common.Hashes{}
+		hashes.ProtoDecode(d.Hashes)
+		reqData = hashes
 	}
 
 	// Decode the request type
@@ -88,8 +117,18 @@ func DecodeQuaiRequest(reqMsg *QuaiRequestMessage) (uint32, interface{}, common.
 		reqType = []*types.WorkObjectBlockView{}
 	case *QuaiRequestMessage_WorkObjectHeader:
 		reqType = &types.WorkObjectHeaderView{}
+	case *QuaiRequestMessage_WorkObjectHeaders:
+		reqType = []*types.WorkObjectHeaderView{}
 	case *QuaiRequestMessage_BlockHash:
 		reqType = &common.Hash{}
+	case *QuaiRequestMessage_TrieNode:
+		reqType = &trie.TrieNodeResponse{}
+	case *QuaiRequestMessage_UtxoRange:
+		reqType = &types.UtxoRange{}
+	case *QuaiRequestMessage_SyncBlockState:
+		reqType = &types.SyncBlockState{}
+	case *QuaiRequestMessage_Transactions:
+		reqType = types.Transactions{}
 	default:
 		return reqMsg.Id, nil, common.Location{}, common.Hash{}, errors.Errorf("unsupported request type: %T", reqMsg.Request)
 	}
@@ -147,12 +186,62 @@ func EncodeQuaiResponse(id uint32, location common.Location, respDataType interf
 			}
 			respMsg.Response = &QuaiResponseMessage_WorkObjectBlocksView{WorkObjectBlocksView: protoWorkObjectBlocks}
 		}
+	case []*types.WorkObjectHeaderView:
+		if data == nil {
+			respMsg.Response = &QuaiResponseMessage_WorkObjectHeadersView{}
+		} else {
+			protoWorkObjectHeaders := &types.ProtoWorkObjectHeadersView{}
+			for _, wo := range data.([]*types.WorkObjectHeaderView) {
+				protoWo, err := wo.ProtoEncode()
+				if err != nil {
+					return nil, err
+				}
+				protoWorkObjectHeaders.WorkObjects = append(protoWorkObjectHeaders.WorkObjects, protoWo)
+			}
+			respMsg.Response = &QuaiResponseMessage_WorkObjectHeadersView{WorkObjectHeadersView: protoWorkObjectHeaders}
+		}
 	case *common.Hash:
 		if data == nil {
 			respMsg.Response = &QuaiResponseMessage_BlockHash{}
 		} else {
 			respMsg.Response = &QuaiResponseMessage_BlockHash{BlockHash: data.(common.Hash).ProtoEncode()}
 		}
+	case *trie.TrieNodeResponse:
+		if data == nil {
+			respMsg.Response = &QuaiResponseMessage_TrieNode{}
+		} else {
+			respMsg.Response = &QuaiResponseMessage_TrieNode{TrieNode: &trie.ProtoTrieNode{ProtoNodeData: data.(*trie.TrieNodeResponse).NodeData}}
+		}
+	case *types.UtxoRange:
+		if data == nil {
+			respMsg.Response = &QuaiResponseMessage_UtxoRange{}
+		} else {
+			protoUtxoRange, err := data.(*types.UtxoRange).ProtoEncode()
+			if err != nil {
+				return nil, err
+			}
+			respMsg.Response = &QuaiResponseMessage_UtxoRange{UtxoRange: protoUtxoRange}
+		}
+	case *types.SyncBlockState:
+		if data == nil {
+			respMsg.Response = &QuaiResponseMessage_SyncBlockState{}
+		} else {
+			protoSyncBlockState, err := data.(*types.SyncBlockState).ProtoEncode()
+			if err != nil {
+				return nil, err
+			}
+			respMsg.Response = &QuaiResponseMessage_SyncBlockState{SyncBlockState: protoSyncBlockState}
+		}
+	case types.Transactions:
+		if data == nil {
+			respMsg.Response = &QuaiResponseMessage_Transactions{}
+		} else {
+			protoTransactions, err := data.(types.Transactions).ProtoEncode()
+			if err != nil {
+				return nil, err
+			}
+			respMsg.Response = &QuaiResponseMessage_Transactions{Transactions: protoTransactions}
+		}
 
 	default:
 		return nil, errors.Errorf("unsupported response data type: %T", data)
@@ -237,6 +326,29 @@ func DecodeQuaiResponse(respMsg *QuaiResponseMessage) (uint32, interface{}, erro
 			messageMetrics.WithLabelValues("blocks").Inc()
 		}
 		return id, blocks, nil
+	case *QuaiResponseMessage_WorkObjectHeadersView:
+		protoWorkObjects := respMsg.GetWorkObjectHeadersView()
+		if protoWorkObjects == nil {
+			return id, nil, errors.New("nil response, and is not valid")
+		}
+		if protoWorkObjects.WorkObjects == nil {
+			return id, nil, EmptyResponse
+		}
+		headers := []*types.WorkObjectHeaderView{}
+		for _, wo := range protoWorkObjects.WorkObjects {
+			header := &types.WorkObjectHeaderView{
+				WorkObject: &types.WorkObject{},
+			}
+			err := header.ProtoDecode(wo, *sourceLocation)
+			if err != nil {
+				return id, nil, err
+			}
+			headers = append(headers, header)
+		}
+		if messageMetrics != nil {
+			messageMetrics.WithLabelValues("headers").Inc()
+		}
+		return id, headers, nil
 	case *QuaiResponseMessage_BlockHash:
 		blockHash := respMsg.GetBlockHash()
 		if blockHash == nil {
@@ -245,6 +357,51 @@ func DecodeQuaiResponse(respMsg *QuaiResponseMessage) (uint32, interface{}, erro
 		hash := common.Hash{}
 		hash.ProtoDecode(blockHash)
 		return id, hash, nil
+	case *QuaiResponseMessage_TrieNode:
+		trieNode := respMsg.GetTrieNode()
+		if trieNode == nil || len(trieNode.ProtoNodeData) == 0 {
+			return id, nil, EmptyResponse
+		}
+		if messageMetrics != nil {
+			messageMetrics.WithLabelValues("trienodes").Inc()
+		}
+		return id, &trie.TrieNodeResponse{NodeData: trieNode.ProtoNodeData}, nil
+	case *QuaiResponseMessage_UtxoRange:
+		protoUtxoRange := respMsg.GetUtxoRange()
+		if protoUtxoRange == nil {
+			return id, nil, EmptyResponse
+		}
+		utxoRange := &types.UtxoRange{}
+		if err := utxoRange.ProtoDecode(protoUtxoRange); err != nil {
+			return id, nil, err
+		}
+		if messageMetrics != nil {
+			messageMetrics.WithLabelValues("utxoranges").Inc()
+		}
+		return id, utxoRange, nil
+	case *QuaiResponseMessage_SyncBlockState:
+		protoSyncBlockState := respMsg.GetSyncBlockState()
+		if protoSyncBlockState == nil {
+			return id, nil, EmptyResponse
+		}
+		syncBlockState := &types.SyncBlockState{}
+		if err := syncBlockState.ProtoDecode(protoSyncBlockState); err != nil {
+			return id, nil, err
+		}
+		return id, syncBlockState, nil
+	case *QuaiResponseMessage_Transactions:
+		protoTransactions := respMsg.GetTransactions()
+		if protoTransactions == nil || len(protoTransactions.Transactions) == 0 {
+			return id, nil, EmptyResponse
+		}
+		txs := types.Transactions{}
+		if err := txs.ProtoDecode(protoTransactions, *sourceLocation); err != nil {
+			return id, nil, err
+		}
+		if messageMetrics != nil {
+			messageMetrics.WithLabelValues("transactions").Inc()
+		}
+		return id, txs, nil
 	default:
 		return id, nil, errors.Errorf("unsupported response type: %T", respMsg.Response)
 	}
@@ -274,6 +431,8 @@ func ConvertAndMarshal(data interface{}) ([]byte, error) {
 	case common.Hash:
 		protoBlock := data.ProtoEncode()
 		return proto.Marshal(protoBlock)
+	case common.Hashes:
+		return proto.Marshal(data.ProtoEncode())
 	default:
 		return nil, errors.New("unsupported data type")
 	}
@@ -335,6 +494,16 @@ func UnmarshalAndConvert(data []byte, sourceLocation common.Location, dataPtr *i
 		hash.ProtoDecode(protoHash)
 		*dataPtr = hash
 		return nil
+	case common.Hashes:
+		protoHashes := &common.ProtoHashes{}
+		err := proto.Unmarshal(data, protoHashes)
+		if err != nil {
+			return err
+		}
+		hashes := common.Hashes{}
+		hashes.ProtoDecode(protoHashes)
+		*dataPtr = hashes
+		return nil
 	default:
 		return errors.New("unsupported data type")
 	}
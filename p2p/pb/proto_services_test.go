@@ -1,10 +1,12 @@
 package pb
 
 import (
+	"math/big"
 	reflect "reflect"
 	"testing"
 
 	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -51,3 +53,151 @@ func TestEncodeDecodeRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeDecodeHeaderRange(t *testing.T) {
+	loc := common.Location{0, 0}
+	id := uint32(1)
+	number := big.NewInt(42)
+
+	data, err := EncodeQuaiRequest(id, loc, number, []*types.WorkObjectHeaderView{})
+	require.NoError(t, err)
+	quaiMsg, err := DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	decodedId, decodedType, decodedLocation, decodedNumber, err := DecodeQuaiRequest(quaiMsg.GetRequest())
+	require.NoError(t, err)
+	assert.Equal(t, id, decodedId)
+	assert.Equal(t, loc, decodedLocation)
+	assert.Equal(t, number, decodedNumber)
+	assert.IsType(t, []*types.WorkObjectHeaderView{}, decodedType)
+
+	headers := make([]*types.WorkObjectHeaderView, 3)
+	for i := range headers {
+		header := types.EmptyWorkObject(common.ZONE_CTX)
+		header.WorkObjectHeader().SetLocation(loc)
+		header.WorkObjectHeader().SetPrimaryCoinbase(common.ZeroAddress(loc))
+		header.SetNumber(big.NewInt(int64(42+i)), common.ZONE_CTX)
+		if i > 0 {
+			header.SetParentHash(headers[i-1].Hash(), common.ZONE_CTX)
+		}
+		headers[i] = header.ConvertToHeaderView()
+	}
+	data, err = EncodeQuaiResponse(id, loc, []*types.WorkObjectHeaderView{}, headers)
+	require.NoError(t, err)
+	quaiMsg, err = DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	decodedId, decoded, err := DecodeQuaiResponse(quaiMsg.GetResponse())
+	require.NoError(t, err)
+	assert.Equal(t, id, decodedId)
+	decodedHeaders, ok := decoded.([]*types.WorkObjectHeaderView)
+	require.True(t, ok)
+	require.Len(t, decodedHeaders, len(headers))
+	for i := range headers {
+		assert.Equal(t, headers[i].Hash(), decodedHeaders[i].Hash())
+	}
+}
+
+func TestEncodeDecodeUtxoRange(t *testing.T) {
+	loc := common.Location{0, 0}
+	id := uint32(1)
+	req := &types.UtxoRangeRequest{BlockHash: common.Hash{1}, Start: types.OutPoint{TxHash: common.Hash{2}, Index: 3}}
+
+	data, err := EncodeQuaiRequest(id, loc, req, &types.UtxoRange{})
+	require.NoError(t, err)
+	quaiMsg, err := DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	decodedId, decodedType, decodedLocation, decodedReq, err := DecodeQuaiRequest(quaiMsg.GetRequest())
+	require.NoError(t, err)
+	assert.Equal(t, id, decodedId)
+	assert.Equal(t, loc, decodedLocation)
+	assert.Equal(t, req, decodedReq)
+	assert.IsType(t, &types.UtxoRange{}, decodedType)
+
+	entry := types.NewUtxoEntry(types.NewTxOut(2, common.ZeroAddress(loc).Bytes(), big.NewInt(0)))
+	utxoRange := &types.UtxoRange{
+		Utxos: []*types.SpentUtxoEntry{
+			{OutPoint: types.OutPoint{TxHash: common.Hash{2}, Index: 3}, UtxoEntry: entry},
+			{OutPoint: types.OutPoint{TxHash: common.Hash{2}, Index: 4}, UtxoEntry: entry},
+		},
+		Next: &types.OutPoint{TxHash: common.Hash{3}, Index: 0},
+	}
+	data, err = EncodeQuaiResponse(id, loc, &types.UtxoRange{}, utxoRange)
+	require.NoError(t, err)
+	quaiMsg, err = DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	decodedId, decoded, err := DecodeQuaiResponse(quaiMsg.GetResponse())
+	require.NoError(t, err)
+	assert.Equal(t, id, decodedId)
+	decodedRange, ok := decoded.(*types.UtxoRange)
+	require.True(t, ok)
+	require.NoError(t, decodedRange.Verify(req.Start))
+	require.Len(t, decodedRange.Utxos, 2)
+	assert.Equal(t, utxoRange.Utxos[1].OutPoint, decodedRange.Utxos[1].OutPoint)
+	assert.Equal(t, *utxoRange.Next, *decodedRange.Next)
+
+	// Ranges out of order are rejected
+	utxoRange.Utxos[0], utxoRange.Utxos[1] = utxoRange.Utxos[1], utxoRange.Utxos[0]
+	assert.Error(t, utxoRange.Verify(req.Start))
+}
+
+func TestEncodeDecodeTransactions(t *testing.T) {
+	loc := common.Location{0, 0}
+	id := uint32(1)
+	to := common.HexToAddress("0x0094f5ea0ba39494ce83a213fffba74279579268", loc)
+	txs := make(types.Transactions, 2)
+	for i := range txs {
+		txs[i] = types.NewTx(&types.QuaiTx{
+			ChainID:    big.NewInt(1),
+			Nonce:      uint64(i),
+			MinerTip:   big.NewInt(1),
+			GasPrice:   big.NewInt(2),
+			Gas:        21000,
+			To:         &to,
+			Value:      big.NewInt(3),
+			AccessList: types.AccessList{},
+			V:          big.NewInt(0),
+			R:          big.NewInt(0),
+			S:          big.NewInt(0),
+		})
+	}
+	hashes := common.Hashes{txs[0].Hash(), txs[1].Hash()}
+
+	data, err := EncodeQuaiRequest(id, loc, hashes, types.Transactions{})
+	require.NoError(t, err)
+	quaiMsg, err := DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	decodedId, decodedType, decodedLocation, decodedHashes, err := DecodeQuaiRequest(quaiMsg.GetRequest())
+	require.NoError(t, err)
+	assert.Equal(t, id, decodedId)
+	assert.Equal(t, loc, decodedLocation)
+	assert.Equal(t, hashes, decodedHashes)
+	assert.IsType(t, types.Transactions{}, decodedType)
+
+	data, err = EncodeQuaiResponse(id, loc, types.Transactions{}, txs)
+	require.NoError(t, err)
+	quaiMsg, err = DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	decodedId, decoded, err := DecodeQuaiResponse(quaiMsg.GetResponse())
+	require.NoError(t, err)
+	assert.Equal(t, id, decodedId)
+	decodedTxs, ok := decoded.(types.Transactions)
+	require.True(t, ok)
+	require.Len(t, decodedTxs, len(txs))
+	for i := range txs {
+		assert.Equal(t, hashes[i], decodedTxs[i].Hash())
+	}
+
+	// A peer without any of the transactions sends an empty response
+	data, err = EncodeQuaiResponse(id, loc, types.Transactions{}, nil)
+	require.NoError(t, err)
+	quaiMsg, err = DecodeQuaiMessage(data)
+	require.NoError(t, err)
+	_, _, err = DecodeQuaiResponse(quaiMsg.GetResponse())
+	assert.ErrorIs(t, err, EmptyResponse)
+
+	// Announcements are gossiped as the hashes alone
+	data, err = ConvertAndMarshal(hashes)
+	require.NoError(t, err)
+	var announced interface{}
+	require.NoError(t, UnmarshalAndConvert(data, loc, &announced, common.Hashes{}))
+	assert.Equal(t, hashes, announced)
+}
@@ -9,6 +9,7 @@ package pb
 import (
 	common "github.com/dominant-strategies/go-quai/common"
 	types "github.com/dominant-strategies/go-quai/core/types"
+	trie "github.com/dominant-strategies/go-quai/trie"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -122,16 +123,21 @@ type QuaiRequestMessage struct {
 	Id       uint32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Location *common.ProtoLocation `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
 	// Types that are assignable to Data:
-	//
 	//	*QuaiRequestMessage_Hash
 	//	*QuaiRequestMessage_Number
+	//	*QuaiRequestMessage_UtxoRangeRequest
+	//	*QuaiRequestMessage_Hashes
 	Data isQuaiRequestMessage_Data `protobuf_oneof:"data"`
 	// Types that are assignable to Request:
-	//
 	//	*QuaiRequestMessage_WorkObjectBlock
 	//	*QuaiRequestMessage_WorkObjectBlocks
 	//	*QuaiRequestMessage_WorkObjectHeader
 	//	*QuaiRequestMessage_BlockHash
+	//	*QuaiRequestMessage_WorkObjectHeaders
+	//	*QuaiRequestMessage_TrieNode
+	//	*QuaiRequestMessage_UtxoRange
+	//	*QuaiRequestMessage_SyncBlockState
+	//	*QuaiRequestMessage_Transactions
 	Request isQuaiRequestMessage_Request `protobuf_oneof:"request"`
 }
 
@@ -200,6 +206,20 @@ func (x *QuaiRequestMessage) GetNumber() []byte {
 	return nil
 }
 
+func (x *QuaiRequestMessage) GetUtxoRangeRequest() *types.ProtoUtxoRangeRequest {
+	if x, ok := x.GetData().(*QuaiRequestMessage_UtxoRangeRequest); ok {
+		return x.UtxoRangeRequest
+	}
+	return nil
+}
+
+func (x *QuaiRequestMessage) GetHashes() *common.ProtoHashes {
+	if x, ok := x.GetData().(*QuaiRequestMessage_Hashes); ok {
+		return x.Hashes
+	}
+	return nil
+}
+
 func (m *QuaiRequestMessage) GetRequest() isQuaiRequestMessage_Request {
 	if m != nil {
 		return m.Request
@@ -235,6 +255,41 @@ func (x *QuaiRequestMessage) GetBlockHash() *common.ProtoHash {
 	return nil
 }
 
+func (x *QuaiRequestMessage) GetWorkObjectHeaders() *types.ProtoWorkObjectHeadersView {
+	if x, ok := x.GetRequest().(*QuaiRequestMessage_WorkObjectHeaders); ok {
+		return x.WorkObjectHeaders
+	}
+	return nil
+}
+
+func (x *QuaiRequestMessage) GetTrieNode() *trie.ProtoTrieNode {
+	if x, ok := x.GetRequest().(*QuaiRequestMessage_TrieNode); ok {
+		return x.TrieNode
+	}
+	return nil
+}
+
+func (x *QuaiRequestMessage) GetUtxoRange() *types.ProtoUtxoRange {
+	if x, ok := x.GetRequest().(*QuaiRequestMessage_UtxoRange); ok {
+		return x.UtxoRange
+	}
+	return nil
+}
+
+func (x *QuaiRequestMessage) GetSyncBlockState() *types.ProtoSyncBlockState {
+	if x, ok := x.GetRequest().(*QuaiRequestMessage_SyncBlockState); ok {
+		return x.SyncBlockState
+	}
+	return nil
+}
+
+func (x *QuaiRequestMessage) GetTransactions() *types.ProtoTransactions {
+	if x, ok := x.GetRequest().(*QuaiRequestMessage_Transactions); ok {
+		return x.Transactions
+	}
+	return nil
+}
+
 type isQuaiRequestMessage_Data interface {
 	isQuaiRequestMessage_Data()
 }
@@ -247,10 +302,22 @@ type QuaiRequestMessage_Number struct {
 	Number []byte `protobuf:"bytes,4,opt,name=number,proto3,oneof"`
 }
 
+type QuaiRequestMessage_UtxoRangeRequest struct {
+	UtxoRangeRequest *types.ProtoUtxoRangeRequest `protobuf:"bytes,10,opt,name=utxo_range_request,json=utxoRangeRequest,proto3,oneof"`
+}
+
+type QuaiRequestMessage_Hashes struct {
+	Hashes *common.ProtoHashes `protobuf:"bytes,14,opt,name=hashes,proto3,oneof"`
+}
+
 func (*QuaiRequestMessage_Hash) isQuaiRequestMessage_Data() {}
 
 func (*QuaiRequestMessage_Number) isQuaiRequestMessage_Data() {}
 
+func (*QuaiRequestMessage_UtxoRangeRequest) isQuaiRequestMessage_Data() {}
+
+func (*QuaiRequestMessage_Hashes) isQuaiRequestMessage_Data() {}
+
 type isQuaiRequestMessage_Request interface {
 	isQuaiRequestMessage_Request()
 }
@@ -271,6 +338,26 @@ type QuaiRequestMessage_BlockHash struct {
 	BlockHash *common.ProtoHash `protobuf:"bytes,8,opt,name=block_hash,json=blockHash,proto3,oneof"`
 }
 
+type QuaiRequestMessage_WorkObjectHeaders struct {
+	WorkObjectHeaders *types.ProtoWorkObjectHeadersView `protobuf:"bytes,9,opt,name=work_object_headers,json=workObjectHeaders,proto3,oneof"`
+}
+
+type QuaiRequestMessage_TrieNode struct {
+	TrieNode *trie.ProtoTrieNode `protobuf:"bytes,11,opt,name=trie_node,json=trieNode,proto3,oneof"`
+}
+
+type QuaiRequestMessage_UtxoRange struct {
+	UtxoRange *types.ProtoUtxoRange `protobuf:"bytes,12,opt,name=utxo_range,json=utxoRange,proto3,oneof"`
+}
+
+type QuaiRequestMessage_SyncBlockState struct {
+	SyncBlockState *types.ProtoSyncBlockState `protobuf:"bytes,13,opt,name=sync_block_state,json=syncBlockState,proto3,oneof"`
+}
+
+type QuaiRequestMessage_Transactions struct {
+	Transactions *types.ProtoTransactions `protobuf:"bytes,15,opt,name=transactions,proto3,oneof"`
+}
+
 func (*QuaiRequestMessage_WorkObjectBlock) isQuaiRequestMessage_Request() {}
 
 func (*QuaiRequestMessage_WorkObjectBlocks) isQuaiRequestMessage_Request() {}
@@ -279,6 +366,16 @@ func (*QuaiRequestMessage_WorkObjectHeader) isQuaiRequestMessage_Request() {}
 
 func (*QuaiRequestMessage_BlockHash) isQuaiRequestMessage_Request() {}
 
+func (*QuaiRequestMessage_WorkObjectHeaders) isQuaiRequestMessage_Request() {}
+
+func (*QuaiRequestMessage_TrieNode) isQuaiRequestMessage_Request() {}
+
+func (*QuaiRequestMessage_UtxoRange) isQuaiRequestMessage_Request() {}
+
+func (*QuaiRequestMessage_SyncBlockState) isQuaiRequestMessage_Request() {}
+
+func (*QuaiRequestMessage_Transactions) isQuaiRequestMessage_Request() {}
+
 // QuaiResponseMessage is the main 'envelope' for QuaiProtocol response messages
 type QuaiResponseMessage struct {
 	state         protoimpl.MessageState
@@ -288,11 +385,15 @@ type QuaiResponseMessage struct {
 	Id       uint32                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Location *common.ProtoLocation `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
 	// Types that are assignable to Response:
-	//
 	//	*QuaiResponseMessage_WorkObjectHeaderView
 	//	*QuaiResponseMessage_WorkObjectBlockView
 	//	*QuaiResponseMessage_WorkObjectBlocksView
 	//	*QuaiResponseMessage_BlockHash
+	//	*QuaiResponseMessage_WorkObjectHeadersView
+	//	*QuaiResponseMessage_TrieNode
+	//	*QuaiResponseMessage_UtxoRange
+	//	*QuaiResponseMessage_SyncBlockState
+	//	*QuaiResponseMessage_Transactions
 	Response isQuaiResponseMessage_Response `protobuf_oneof:"response"`
 }
 
@@ -375,6 +476,41 @@ func (x *QuaiResponseMessage) GetBlockHash() *common.ProtoHash {
 	return nil
 }
 
+func (x *QuaiResponseMessage) GetWorkObjectHeadersView() *types.ProtoWorkObjectHeadersView {
+	if x, ok := x.GetResponse().(*QuaiResponseMessage_WorkObjectHeadersView); ok {
+		return x.WorkObjectHeadersView
+	}
+	return nil
+}
+
+func (x *QuaiResponseMessage) GetTrieNode() *trie.ProtoTrieNode {
+	if x, ok := x.GetResponse().(*QuaiResponseMessage_TrieNode); ok {
+		return x.TrieNode
+	}
+	return nil
+}
+
+func (x *QuaiResponseMessage) GetUtxoRange() *types.ProtoUtxoRange {
+	if x, ok := x.GetResponse().(*QuaiResponseMessage_UtxoRange); ok {
+		return x.UtxoRange
+	}
+	return nil
+}
+
+func (x *QuaiResponseMessage) GetSyncBlockState() *types.ProtoSyncBlockState {
+	if x, ok := x.GetResponse().(*QuaiResponseMessage_SyncBlockState); ok {
+		return x.SyncBlockState
+	}
+	return nil
+}
+
+func (x *QuaiResponseMessage) GetTransactions() *types.ProtoTransactions {
+	if x, ok := x.GetResponse().(*QuaiResponseMessage_Transactions); ok {
+		return x.Transactions
+	}
+	return nil
+}
+
 type isQuaiResponseMessage_Response interface {
 	isQuaiResponseMessage_Response()
 }
@@ -395,6 +531,26 @@ type QuaiResponseMessage_BlockHash struct {
 	BlockHash *common.ProtoHash `protobuf:"bytes,6,opt,name=block_hash,json=blockHash,proto3,oneof"`
 }
 
+type QuaiResponseMessage_WorkObjectHeadersView struct {
+	WorkObjectHeadersView *types.ProtoWorkObjectHeadersView `protobuf:"bytes,7,opt,name=work_object_headers_view,json=workObjectHeadersView,proto3,oneof"`
+}
+
+type QuaiResponseMessage_TrieNode struct {
+	TrieNode *trie.ProtoTrieNode `protobuf:"bytes,8,opt,name=trie_node,json=trieNode,proto3,oneof"`
+}
+
+type QuaiResponseMessage_UtxoRange struct {
+	UtxoRange *types.ProtoUtxoRange `protobuf:"bytes,9,opt,name=utxo_range,json=utxoRange,proto3,oneof"`
+}
+
+type QuaiResponseMessage_SyncBlockState struct {
+	SyncBlockState *types.ProtoSyncBlockState `protobuf:"bytes,10,opt,name=sync_block_state,json=syncBlockState,proto3,oneof"`
+}
+
+type QuaiResponseMessage_Transactions struct {
+	Transactions *types.ProtoTransactions `protobuf:"bytes,11,opt,name=transactions,proto3,oneof"`
+}
+
 func (*QuaiResponseMessage_WorkObjectHeaderView) isQuaiResponseMessage_Response() {}
 
 func (*QuaiResponseMessage_WorkObjectBlockView) isQuaiResponseMessage_Response() {}
@@ -403,13 +559,22 @@ func (*QuaiResponseMessage_WorkObjectBlocksView) isQuaiResponseMessage_Response(
 
 func (*QuaiResponseMessage_BlockHash) isQuaiResponseMessage_Response() {}
 
+func (*QuaiResponseMessage_WorkObjectHeadersView) isQuaiResponseMessage_Response() {}
+
+func (*QuaiResponseMessage_TrieNode) isQuaiResponseMessage_Response() {}
+
+func (*QuaiResponseMessage_UtxoRange) isQuaiResponseMessage_Response() {}
+
+func (*QuaiResponseMessage_SyncBlockState) isQuaiResponseMessage_Response() {}
+
+func (*QuaiResponseMessage_Transactions) isQuaiResponseMessage_Response() {}
+
 type QuaiMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// Types that are assignable to Payload:
-	//
 	//	*QuaiMessage_Request
 	//	*QuaiMessage_Response
 	Payload isQuaiMessage_Payload `protobuf_oneof:"payload"`
@@ -491,86 +656,137 @@ var file_p2p_pb_quai_messages_proto_rawDesc = []byte{
 	0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x74, 0x79, 0x70, 0x65,
 	0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x4b, 0x0a, 0x10, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x57, 0x6f, 0x72,
-	0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x37, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
-	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
-	0x22, 0x4e, 0x0a, 0x11, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6c, 0x6f,
-	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x22, 0xd4, 0x03, 0x0a, 0x12, 0x51, 0x75, 0x61, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x31, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
-	0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x04, 0x68, 0x61,
-	0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68,
-	0x61, 0x73, 0x68, 0x12, 0x18, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x4d, 0x0a,
-	0x11, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f,
-	0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x69, 0x65, 0x77, 0x48, 0x01, 0x52, 0x0f, 0x77, 0x6f, 0x72,
-	0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x50, 0x0a, 0x12,
-	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63,
-	0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x56, 0x69, 0x65, 0x77, 0x48, 0x01, 0x52, 0x10, 0x77, 0x6f,
-	0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x50,
-	0x0a, 0x12, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x68, 0x65,
-	0x61, 0x64, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f,
-	0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65,
-	0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x48, 0x01, 0x52, 0x10,
+	0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x74, 0x72, 0x69, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x5f,
+	0x74, 0x72, 0x69, 0x65, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4b,
+	0x0a, 0x10, 0x47, 0x6f, 0x73, 0x73, 0x69, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x12, 0x37, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52,
+	0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x4e, 0x0a, 0x11, 0x47,
+	0x6f, 0x73, 0x73, 0x69, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x39, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9a, 0x07, 0x0a, 0x12,
+	0x51, 0x75, 0x61, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x31, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x18,
+	0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00,
+	0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x4c, 0x0a, 0x12, 0x75, 0x74, 0x78, 0x6f,
+	0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x55, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x48, 0x00, 0x52, 0x10, 0x75, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x48, 0x00, 0x52, 0x06, 0x68,
+	0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x11, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f,
+	0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x69, 0x65,
+	0x77, 0x48, 0x01, 0x52, 0x0f, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x50, 0x0a, 0x12, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f,
+	0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x56, 0x69,
+	0x65, 0x77, 0x48, 0x01, 0x52, 0x10, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x50, 0x0a, 0x12, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x56, 0x69, 0x65, 0x77, 0x48, 0x01, 0x52, 0x10, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48,
+	0x01, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x53, 0x0a, 0x13,
+	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x56, 0x69, 0x65, 0x77, 0x48, 0x01, 0x52, 0x11,
 	0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x12, 0x32, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x01, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x48, 0x61, 0x73, 0x68, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x09, 0x0a, 0x07,
-	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xa6, 0x03, 0x0a, 0x13, 0x51, 0x75, 0x61, 0x69,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x31, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x17, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63,
-	0x74, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x76, 0x69, 0x65, 0x77, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74,
-	0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65,
-	0x72, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00, 0x52, 0x14, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a,
-	0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x12, 0x56, 0x0a,
-	0x16, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f,
-	0x63, 0x6b, 0x5f, 0x76, 0x69, 0x65, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
-	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00,
-	0x52, 0x13, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x56, 0x69, 0x65, 0x77, 0x12, 0x59, 0x0a, 0x17, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x76, 0x69, 0x65, 0x77,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x73, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00, 0x52, 0x14, 0x77, 0x6f, 0x72, 0x6b,
-	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x56, 0x69, 0x65, 0x77,
-	0x12, 0x32, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x48, 0x61, 0x73, 0x68, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x97, 0x01, 0x0a, 0x0b, 0x51, 0x75, 0x61, 0x69, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x3c, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x20, 0x2e, 0x71, 0x75, 0x61, 0x69, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
-	0x2e, 0x51, 0x75, 0x61, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f,
-	0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x21, 0x2e, 0x71, 0x75, 0x61, 0x69, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e,
-	0x51, 0x75, 0x61, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
-	0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6e,
-	0x74, 0x2d, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x2f, 0x67, 0x6f, 0x2d,
-	0x71, 0x75, 0x61, 0x69, 0x2f, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x73, 0x12, 0x32, 0x0a, 0x09, 0x74, 0x72, 0x69, 0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x54, 0x72, 0x69, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x01, 0x52, 0x08, 0x74, 0x72, 0x69,
+	0x65, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x0a, 0x75, 0x74, 0x78, 0x6f, 0x5f, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x48, 0x01, 0x52, 0x09, 0x75, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x46, 0x0a,
+	0x10, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x48, 0x01, 0x52, 0x0e, 0x73, 0x79, 0x6e, 0x63, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x01, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x09, 0x0a,
+	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xf8, 0x05, 0x0a, 0x13, 0x51, 0x75, 0x61,
+	0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x31, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x17, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x76, 0x69, 0x65, 0x77, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00, 0x52, 0x14, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x69, 0x65, 0x77, 0x12, 0x56,
+	0x0a, 0x16, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x5f, 0x76, 0x69, 0x65, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x69, 0x65, 0x77, 0x48,
+	0x00, 0x52, 0x13, 0x77, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x56, 0x69, 0x65, 0x77, 0x12, 0x59, 0x0a, 0x17, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x76, 0x69, 0x65,
+	0x77, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00, 0x52, 0x14, 0x77, 0x6f, 0x72,
+	0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x56, 0x69, 0x65,
+	0x77, 0x12, 0x32, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x48, 0x61, 0x73, 0x68, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x5c, 0x0a, 0x18, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x76, 0x69, 0x65,
+	0x77, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x57, 0x6f, 0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x56, 0x69, 0x65, 0x77, 0x48, 0x00, 0x52, 0x15, 0x77, 0x6f,
+	0x72, 0x6b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x56,
+	0x69, 0x65, 0x77, 0x12, 0x32, 0x0a, 0x09, 0x74, 0x72, 0x69, 0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x54, 0x72, 0x69, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x08, 0x74,
+	0x72, 0x69, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x0a, 0x75, 0x74, 0x78, 0x6f, 0x5f,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x55, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x48, 0x00, 0x52, 0x09, 0x75, 0x74, 0x78, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x46, 0x0a, 0x10, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x79, 0x6e, 0x63, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x97, 0x01, 0x0a, 0x0b, 0x51, 0x75, 0x61, 0x69, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x71, 0x75, 0x61, 0x69, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x51, 0x75, 0x61, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x3f, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x71, 0x75, 0x61, 0x69, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x2e, 0x51, 0x75, 0x61, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x2f, 0x5a,
+	0x2d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f, 0x6d, 0x69,
+	0x6e, 0x61, 0x6e, 0x74, 0x2d, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x2f,
+	0x67, 0x6f, 0x2d, 0x71, 0x75, 0x61, 0x69, 0x2f, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -587,40 +803,59 @@ func file_p2p_pb_quai_messages_proto_rawDescGZIP() []byte {
 
 var file_p2p_pb_quai_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_p2p_pb_quai_messages_proto_goTypes = []any{
-	(*GossipWorkObject)(nil),                // 0: quaiprotocol.GossipWorkObject
-	(*GossipTransaction)(nil),               // 1: quaiprotocol.GossipTransaction
-	(*QuaiRequestMessage)(nil),              // 2: quaiprotocol.QuaiRequestMessage
-	(*QuaiResponseMessage)(nil),             // 3: quaiprotocol.QuaiResponseMessage
-	(*QuaiMessage)(nil),                     // 4: quaiprotocol.QuaiMessage
-	(*types.ProtoWorkObject)(nil),           // 5: block.ProtoWorkObject
-	(*types.ProtoTransaction)(nil),          // 6: block.ProtoTransaction
-	(*common.ProtoLocation)(nil),            // 7: common.ProtoLocation
-	(*common.ProtoHash)(nil),                // 8: common.ProtoHash
-	(*types.ProtoWorkObjectBlockView)(nil),  // 9: block.ProtoWorkObjectBlockView
-	(*types.ProtoWorkObjectBlocksView)(nil), // 10: block.ProtoWorkObjectBlocksView
-	(*types.ProtoWorkObjectHeaderView)(nil), // 11: block.ProtoWorkObjectHeaderView
+	(*GossipWorkObject)(nil),                 // 0: quaiprotocol.GossipWorkObject
+	(*GossipTransaction)(nil),                // 1: quaiprotocol.GossipTransaction
+	(*QuaiRequestMessage)(nil),               // 2: quaiprotocol.QuaiRequestMessage
+	(*QuaiResponseMessage)(nil),              // 3: quaiprotocol.QuaiResponseMessage
+	(*QuaiMessage)(nil),                      // 4: quaiprotocol.QuaiMessage
+	(*types.ProtoWorkObject)(nil),            // 5: block.ProtoWorkObject
+	(*types.ProtoTransaction)(nil),           // 6: block.ProtoTransaction
+	(*common.ProtoLocation)(nil),             // 7: common.ProtoLocation
+	(*common.ProtoHash)(nil),                 // 8: common.ProtoHash
+	(*types.ProtoUtxoRangeRequest)(nil),      // 9: block.ProtoUtxoRangeRequest
+	(*common.ProtoHashes)(nil),               // 10: common.ProtoHashes
+	(*types.ProtoWorkObjectBlockView)(nil),   // 11: block.ProtoWorkObjectBlockView
+	(*types.ProtoWorkObjectBlocksView)(nil),  // 12: block.ProtoWorkObjectBlocksView
+	(*types.ProtoWorkObjectHeaderView)(nil),  // 13: block.ProtoWorkObjectHeaderView
+	(*types.ProtoWorkObjectHeadersView)(nil), // 14: block.ProtoWorkObjectHeadersView
+	(*trie.ProtoTrieNode)(nil),               // 15: trie.ProtoTrieNode
+	(*types.ProtoUtxoRange)(nil),             // 16: block.ProtoUtxoRange
+	(*types.ProtoSyncBlockState)(nil),        // 17: block.ProtoSyncBlockState
+	(*types.ProtoTransactions)(nil),          // 18: block.ProtoTransactions
 }
 var file_p2p_pb_quai_messages_proto_depIdxs = []int32{
 	5,  // 0: quaiprotocol.GossipWorkObject.work_object:type_name -> block.ProtoWorkObject
 	6,  // 1: quaiprotocol.GossipTransaction.transaction:type_name -> block.ProtoTransaction
 	7,  // 2: quaiprotocol.QuaiRequestMessage.location:type_name -> common.ProtoLocation
 	8,  // 3: quaiprotocol.QuaiRequestMessage.hash:type_name -> common.ProtoHash
-	9,  // 4: quaiprotocol.QuaiRequestMessage.work_object_block:type_name -> block.ProtoWorkObjectBlockView
-	10, // 5: quaiprotocol.QuaiRequestMessage.work_object_blocks:type_name -> block.ProtoWorkObjectBlocksView
-	11, // 6: quaiprotocol.QuaiRequestMessage.work_object_header:type_name -> block.ProtoWorkObjectHeaderView
-	8,  // 7: quaiprotocol.QuaiRequestMessage.block_hash:type_name -> common.ProtoHash
-	7,  // 8: quaiprotocol.QuaiResponseMessage.location:type_name -> common.ProtoLocation
-	11, // 9: quaiprotocol.QuaiResponseMessage.work_object_header_view:type_name -> block.ProtoWorkObjectHeaderView
-	9,  // 10: quaiprotocol.QuaiResponseMessage.work_object_block_view:type_name -> block.ProtoWorkObjectBlockView
-	10, // 11: quaiprotocol.QuaiResponseMessage.work_object_blocks_view:type_name -> block.ProtoWorkObjectBlocksView
-	8,  // 12: quaiprotocol.QuaiResponseMessage.block_hash:type_name -> common.ProtoHash
-	2,  // 13: quaiprotocol.QuaiMessage.request:type_name -> quaiprotocol.QuaiRequestMessage
-	3,  // 14: quaiprotocol.QuaiMessage.response:type_name -> quaiprotocol.QuaiResponseMessage
-	15, // [15:15] is the sub-list for method output_type
-	15, // [15:15] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	9,  // 4: quaiprotocol.QuaiRequestMessage.utxo_range_request:type_name -> block.ProtoUtxoRangeRequest
+	10, // 5: quaiprotocol.QuaiRequestMessage.hashes:type_name -> common.ProtoHashes
+	11, // 6: quaiprotocol.QuaiRequestMessage.work_object_block:type_name -> block.ProtoWorkObjectBlockView
+	12, // 7: quaiprotocol.QuaiRequestMessage.work_object_blocks:type_name -> block.ProtoWorkObjectBlocksView
+	13, // 8: quaiprotocol.QuaiRequestMessage.work_object_header:type_name -> block.ProtoWorkObjectHeaderView
+	8,  // 9: quaiprotocol.QuaiRequestMessage.block_hash:type_name -> common.ProtoHash
+	14, // 10: quaiprotocol.QuaiRequestMessage.work_object_headers:type_name -> block.ProtoWorkObjectHeadersView
+	15, // 11: quaiprotocol.QuaiRequestMessage.trie_node:type_name -> trie.ProtoTrieNode
+	16, // 12: quaiprotocol.QuaiRequestMessage.utxo_range:type_name -> block.ProtoUtxoRange
+	17, // 13: quaiprotocol.QuaiRequestMessage.sync_block_state:type_name -> block.ProtoSyncBlockState
+	18, // 14: quaiprotocol.QuaiRequestMessage.transactions:type_name -> block.ProtoTransactions
+	7,  // 15: quaiprotocol.QuaiResponseMessage.location:type_name -> common.ProtoLocation
+	13, // 16: quaiprotocol.QuaiResponseMessage.work_object_header_view:type_name -> block.ProtoWorkObjectHeaderView
+	11, // 17: quaiprotocol.QuaiResponseMessage.work_object_block_view:type_name -> block.ProtoWorkObjectBlockView
+	12, // 18: quaiprotocol.QuaiResponseMessage.work_object_blocks_view:type_name -> block.ProtoWorkObjectBlocksView
+	8,  // 19: quaiprotocol.QuaiResponseMessage.block_hash:type_name -> common.ProtoHash
+	14, // 20: quaiprotocol.QuaiResponseMessage.work_object_headers_view:type_name -> block.ProtoWorkObjectHeadersView
+	15, // 21: quaiprotocol.QuaiResponseMessage.trie_node:type_name -> trie.ProtoTrieNode
+	16, // 22: quaiprotocol.QuaiResponseMessage.utxo_range:type_name -> block.ProtoUtxoRange
+	17, // 23: quaiprotocol.QuaiResponseMessage.sync_block_state:type_name -> block.ProtoSyncBlockState
+	18, // 24: quaiprotocol.QuaiResponseMessage.transactions:type_name -> block.ProtoTransactions
+	2,  // 25: quaiprotocol.QuaiMessage.request:type_name -> quaiprotocol.QuaiRequestMessage
+	3,  // 26: quaiprotocol.QuaiMessage.response:type_name -> quaiprotocol.QuaiResponseMessage
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_p2p_pb_quai_messages_proto_init() }
@@ -631,16 +866,28 @@ func file_p2p_pb_quai_messages_proto_init() {
 	file_p2p_pb_quai_messages_proto_msgTypes[2].OneofWrappers = []any{
 		(*QuaiRequestMessage_Hash)(nil),
 		(*QuaiRequestMessage_Number)(nil),
+		(*QuaiRequestMessage_UtxoRangeRequest)(nil),
+		(*QuaiRequestMessage_Hashes)(nil),
 		(*QuaiRequestMessage_WorkObjectBlock)(nil),
 		(*QuaiRequestMessage_WorkObjectBlocks)(nil),
 		(*QuaiRequestMessage_WorkObjectHeader)(nil),
 		(*QuaiRequestMessage_BlockHash)(nil),
+		(*QuaiRequestMessage_WorkObjectHeaders)(nil),
+		(*QuaiRequestMessage_TrieNode)(nil),
+		(*QuaiRequestMessage_UtxoRange)(nil),
+		(*QuaiRequestMessage_SyncBlockState)(nil),
+		(*QuaiRequestMessage_Transactions)(nil),
 	}
 	file_p2p_pb_quai_messages_proto_msgTypes[3].OneofWrappers = []any{
 		(*QuaiResponseMessage_WorkObjectHeaderView)(nil),
 		(*QuaiResponseMessage_WorkObjectBlockView)(nil),
 		(*QuaiResponseMessage_WorkObjectBlocksView)(nil),
 		(*QuaiResponseMessage_BlockHash)(nil),
+		(*QuaiResponseMessage_WorkObjectHeadersView)(nil),
+		(*QuaiResponseMessage_TrieNode)(nil),
+		(*QuaiResponseMessage_UtxoRange)(nil),
+		(*QuaiResponseMessage_SyncBlockState)(nil),
+		(*QuaiResponseMessage_Transactions)(nil),
 	}
 	file_p2p_pb_quai_messages_proto_msgTypes[4].OneofWrappers = []any{
 		(*QuaiMessage_Request)(nil),
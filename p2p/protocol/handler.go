@@ -3,6 +3,7 @@ package protocol
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"runtime/debug"
@@ -20,6 +21,7 @@ import (
 	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/p2p/pb"
+	"github.com/dominant-strategies/go-quai/trie"
 )
 
 const (
@@ -29,6 +31,8 @@ const (
 	rateFilterAlphaPct         = 10 // alpha (in percent) for rate tracker filter
 	requestRateLimitPeriod_ms  = 20 // 20ms avg delay between requests = 50 requests/sec
 	C_NumPrimeBlocksToDownload = 10
+	C_NumHeadersToDownload     = 64   // Max number of headers served for a header range request
+	C_MaxTxAnnouncementHashes  = 1024 // Max number of transactions announced or requested at once
 )
 
 type rateTracker struct {
@@ -254,7 +258,7 @@ func handleRequest(quaiMsg *pb.QuaiRequestMessage, stream network.Stream, node Q
 		// TODO: handle error
 		return
 	}
-	switch query.(type) {
+	switch query := query.(type) {
 	case *common.Hash:
 		log.Global.WithFields(log.Fields{
 			"requestID":   id,
@@ -271,12 +275,29 @@ func handleRequest(quaiMsg *pb.QuaiRequestMessage, stream network.Stream, node Q
 			"number":      query,
 			"peer":        stream.Conn().RemotePeer(),
 		}).Debug("Received request by number to handle")
+	case *types.UtxoRangeRequest:
+		log.Global.WithFields(log.Fields{
+			"requestID":   id,
+			"decodedType": decodedType,
+			"location":    loc,
+			"block":       query.BlockHash,
+			"start":       query.Start,
+			"peer":        stream.Conn().RemotePeer(),
+		}).Debug("Received utxo range request to handle")
+	case common.Hashes:
+		log.Global.WithFields(log.Fields{
+			"requestID":   id,
+			"decodedType": decodedType,
+			"location":    loc,
+			"hashes":      len(query),
+			"peer":        stream.Conn().RemotePeer(),
+		}).Debug("Received request by hashes to handle")
 	default:
 		log.Global.Errorf("unsupported request input data field type: %T", query)
 	}
 
 	switch decodedType.(type) {
-	case *types.WorkObjectHeaderView, *types.WorkObjectBlockView, []*types.WorkObjectBlockView:
+	case *types.WorkObjectHeaderView, *types.WorkObjectBlockView, []*types.WorkObjectBlockView, []*types.WorkObjectHeaderView:
 		var requestedView types.WorkObjectView
 		switch decodedType.(type) {
 		case *types.WorkObjectHeaderView:
@@ -285,6 +306,8 @@ func handleRequest(quaiMsg *pb.QuaiRequestMessage, stream network.Stream, node Q
 			requestedView = types.BlockObject
 		case []*types.WorkObjectBlockView:
 			requestedView = types.BlockObjects
+		case []*types.WorkObjectHeaderView:
+			requestedView = types.HeaderObjects
 		}
 
 		requestedHash := &common.Hash{}
@@ -326,6 +349,18 @@ func handleRequest(quaiMsg *pb.QuaiRequestMessage, stream network.Stream, node Q
 			log.Global.WithField("err", err).Error("error handling block number request")
 			return
 		}
+	case *trie.TrieNodeResponse, *types.UtxoRange, *types.SyncBlockState:
+		err = handleStateRequest(id, loc, decodedType, query, stream, node)
+		if err != nil {
+			log.Global.WithField("err", err).Error("error handling state request")
+			return
+		}
+	case types.Transactions:
+		err = handleTransactionsRequest(id, loc, query, stream, node)
+		if err != nil {
+			log.Global.WithField("err", err).Error("error handling transactions request")
+			return
+		}
 	default:
 		log.Global.WithField("request type", decodedType).Error("unsupported request data type")
 		// TODO: handle error
@@ -379,6 +414,10 @@ func handleBlockRequest(id uint32, loc common.Location, hash common.Hash, stream
 		case types.BlockObjects:
 			// This is the case in which the Prime is asking for the next c_NumPrimeBlocksToDownload blocks
 			block = node.GetWorkObjectsFrom(hash, loc, C_NumPrimeBlocksToDownload)
+		case types.HeaderObjects:
+			// This is the case in which a syncing node is asking for the
+			// headers following the block, up to C_NumHeadersToDownload
+			block = node.GetWorkObjectHeadersFrom(hash, loc, C_NumHeadersToDownload)
 		}
 	}
 	var requestDataType interface{}
@@ -389,6 +428,8 @@ func handleBlockRequest(id uint32, loc common.Location, hash common.Hash, stream
 		requestDataType = &types.WorkObjectHeaderView{}
 	case types.BlockObjects:
 		requestDataType = []*types.WorkObjectBlockView{}
+	case types.HeaderObjects:
+		requestDataType = []*types.WorkObjectHeaderView{}
 	}
 	// create a Quai Message Response with the block
 	data, err = pb.EncodeQuaiResponse(id, loc, requestDataType, block)
@@ -421,3 +462,61 @@ func handleBlockNumberRequest(id uint32, loc common.Location, number *big.Int, s
 	log.Global.Tracef("Sent block hash %s to peer %s", blockHash, stream.Conn().RemotePeer())
 	return nil
 }
+
+// Looks up the state data a snap syncing peer requests and sends it to the peer
+// in a pb.QuaiResponseMessage, responding with empty data if it is not found
+func handleStateRequest(id uint32, loc common.Location, decodedType interface{}, query interface{}, stream network.Stream, node QuaiP2PNode) error {
+	var resp interface{}
+	switch decodedType.(type) {
+	case *trie.TrieNodeResponse:
+		hash, ok := query.(*common.Hash)
+		if !ok {
+			return fmt.Errorf("unsupported trie node query type: %T", query)
+		}
+		if trieNode := node.GetTrieNode(*hash, loc); trieNode != nil {
+			resp = trieNode
+		}
+	case *types.UtxoRange:
+		req, ok := query.(*types.UtxoRangeRequest)
+		if !ok {
+			return fmt.Errorf("unsupported utxo range query type: %T", query)
+		}
+		if utxoRange := node.GetUtxoRange(req, loc); utxoRange != nil {
+			resp = utxoRange
+		}
+	case *types.SyncBlockState:
+		hash, ok := query.(*common.Hash)
+		if !ok {
+			return fmt.Errorf("unsupported block state query type: %T", query)
+		}
+		if state := node.GetSyncBlockState(*hash, loc); state != nil {
+			resp = state
+		}
+	}
+	data, err := pb.EncodeQuaiResponse(id, loc, decodedType, resp)
+	if err != nil {
+		return err
+	}
+	return common.WriteMessageToStream(stream, data, ProtocolVersion, node.GetBandwidthCounter())
+}
+
+// Looks up the requested transactions in the pool and sends the ones found to
+// the peer in a pb.QuaiResponseMessage
+func handleTransactionsRequest(id uint32, loc common.Location, query interface{}, stream network.Stream, node QuaiP2PNode) error {
+	hashes, ok := query.(common.Hashes)
+	if !ok {
+		return fmt.Errorf("unsupported transactions query type: %T", query)
+	}
+	if len(hashes) > C_MaxTxAnnouncementHashes {
+		hashes = hashes[:C_MaxTxAnnouncementHashes]
+	}
+	var resp interface{}
+	if txs := node.GetTransactions(hashes, loc); len(txs) > 0 {
+		resp = txs
+	}
+	data, err := pb.EncodeQuaiResponse(id, loc, types.Transactions{}, resp)
+	if err != nil {
+		return err
+	}
+	return common.WriteMessageToStream(stream, data, ProtocolVersion, node.GetBandwidthCounter())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/dominant-strategies/go-quai/p2p/node/requestManager"
+	"github.com/dominant-strategies/go-quai/trie"
 )
 
 // interface required to join the quai protocol network
@@ -18,8 +19,16 @@ type QuaiP2PNode interface {
 	// Returns nil if the block is not found.
 	GetWorkObject(hash common.Hash, location common.Location) *types.WorkObject
 	GetWorkObjectsFrom(hash common.Hash, location common.Location, count int) []*types.WorkObjectBlockView
+	GetWorkObjectHeadersFrom(hash common.Hash, location common.Location, count int) []*types.WorkObjectHeaderView
 	GetHeight(location common.Location) uint64
 	GetBlockHashByNumber(number *big.Int, location common.Location) *common.Hash
+	// State served to snap syncing peers. Each returns nil if the data is not found.
+	GetTrieNode(hash common.Hash, location common.Location) *trie.TrieNodeResponse
+	GetUtxoRange(req *types.UtxoRangeRequest, location common.Location) *types.UtxoRange
+	GetSyncBlockState(hash common.Hash, location common.Location) *types.SyncBlockState
+	// Transactions of the pool served to the peers they were announced to.
+	// Returns the transactions found.
+	GetTransactions(hashes common.Hashes, location common.Location) types.Transactions
 	GetRequestManager() requestManager.RequestManager
 	GetBandwidthCounter() libp2pmetrics.Reporter
 
@@ -16,6 +16,7 @@ import (
 	common "github.com/dominant-strategies/go-quai/common"
 	types "github.com/dominant-strategies/go-quai/core/types"
 	requestManager "github.com/dominant-strategies/go-quai/p2p/node/requestManager"
+	trie "github.com/dominant-strategies/go-quai/trie"
 	metrics "github.com/libp2p/go-libp2p/core/metrics"
 	network "github.com/libp2p/go-libp2p/core/network"
 	peer "github.com/libp2p/go-libp2p/core/peer"
@@ -95,6 +96,62 @@ func (mr *MockQuaiP2PNodeMockRecorder) GetBlockHashByNumber(number, location any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHashByNumber", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetBlockHashByNumber), number, location)
 }
 
+// GetTrieNode mocks base method.
+func (m *MockQuaiP2PNode) GetTrieNode(hash common.Hash, location common.Location) *trie.TrieNodeResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrieNode", hash, location)
+	ret0, _ := ret[0].(*trie.TrieNodeResponse)
+	return ret0
+}
+
+// GetTrieNode indicates an expected call of GetTrieNode.
+func (mr *MockQuaiP2PNodeMockRecorder) GetTrieNode(hash, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrieNode", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetTrieNode), hash, location)
+}
+
+// GetUtxoRange mocks base method.
+func (m *MockQuaiP2PNode) GetUtxoRange(req *types.UtxoRangeRequest, location common.Location) *types.UtxoRange {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUtxoRange", req, location)
+	ret0, _ := ret[0].(*types.UtxoRange)
+	return ret0
+}
+
+// GetUtxoRange indicates an expected call of GetUtxoRange.
+func (mr *MockQuaiP2PNodeMockRecorder) GetUtxoRange(req, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUtxoRange", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetUtxoRange), req, location)
+}
+
+// GetSyncBlockState mocks base method.
+func (m *MockQuaiP2PNode) GetSyncBlockState(hash common.Hash, location common.Location) *types.SyncBlockState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncBlockState", hash, location)
+	ret0, _ := ret[0].(*types.SyncBlockState)
+	return ret0
+}
+
+// GetSyncBlockState indicates an expected call of GetSyncBlockState.
+func (mr *MockQuaiP2PNodeMockRecorder) GetSyncBlockState(hash, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncBlockState", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetSyncBlockState), hash, location)
+}
+
+// GetTransactions mocks base method.
+func (m *MockQuaiP2PNode) GetTransactions(hashes common.Hashes, location common.Location) types.Transactions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", hashes, location)
+	ret0, _ := ret[0].(types.Transactions)
+	return ret0
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockQuaiP2PNodeMockRecorder) GetTransactions(hashes, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetTransactions), hashes, location)
+}
+
 // GetRequestManager mocks base method.
 func (m *MockQuaiP2PNode) GetRequestManager() requestManager.RequestManager {
 	m.ctrl.T.Helper()
@@ -138,6 +195,20 @@ func (mr *MockQuaiP2PNodeMockRecorder) GetWorkObject(hash, location any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkObject", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetWorkObject), hash, location)
 }
 
+// GetWorkObjectHeadersFrom mocks base method.
+func (m *MockQuaiP2PNode) GetWorkObjectHeadersFrom(hash common.Hash, location common.Location, count int) []*types.WorkObjectHeaderView {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkObjectHeadersFrom", hash, location, count)
+	ret0, _ := ret[0].([]*types.WorkObjectHeaderView)
+	return ret0
+}
+
+// GetWorkObjectHeadersFrom indicates an expected call of GetWorkObjectHeadersFrom.
+func (mr *MockQuaiP2PNodeMockRecorder) GetWorkObjectHeadersFrom(hash, location, count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkObjectHeadersFrom", reflect.TypeOf((*MockQuaiP2PNode)(nil).GetWorkObjectHeadersFrom), hash, location, count)
+}
+
 // GetWorkObjectsFrom mocks base method.
 func (m *MockQuaiP2PNode) GetWorkObjectsFrom(hash common.Hash, location common.Location, count int) []*types.WorkObjectBlockView {
 	m.ctrl.T.Helper()
@@ -1,7 +1,11 @@
 package p2p
 
 import (
+	"net"
+	"time"
+
 	"github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/metrics"
 )
 
 // Multiaddr aliases the Multiaddr type from github.com/libp2p/core
@@ -13,3 +17,30 @@ type Multiaddr = core.Multiaddr
 //
 // Refer to the docs on that type for more info.
 type PeerID = core.PeerID
+
+// PeerInfo describes a connected peer
+type PeerInfo struct {
+	ID        PeerID
+	Addrs     []Multiaddr
+	Inbound   bool
+	Protected bool
+
+	// Quality is the quality score of the peer, and Buckets the quality
+	// bucket it is in for each topic it was scored on
+	Quality int
+	Buckets map[string]string
+
+	// Topics are the gossipsub topics the peer shares with the node
+	Topics []string
+
+	Latency   time.Duration
+	Bandwidth metrics.Stats
+}
+
+// BlockedPeers lists the peers, addresses and subnets the node refuses to
+// connect to
+type BlockedPeers struct {
+	Peers   []PeerID
+	Addrs   []net.IP
+	Subnets []*net.IPNet
+}
@@ -112,9 +112,9 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllProgpowProtocolChanges = &ChainConfig{big.NewInt(1337), "progpow", new(Blake3powConfig), new(ProgpowConfig), common.Location{}, common.Hash{}, false}
+	AllProgpowProtocolChanges = &ChainConfig{big.NewInt(1337), "progpow", new(Blake3powConfig), new(ProgpowConfig), common.Location{}, common.Hash{}, false, false, nil}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), "progpow", new(Blake3powConfig), new(ProgpowConfig), common.Location{}, common.Hash{}, false}
+	TestChainConfig = &ChainConfig{big.NewInt(1), "progpow", new(Blake3powConfig), new(ProgpowConfig), common.Location{}, common.Hash{}, false, false, nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -132,6 +132,10 @@ type ChainConfig struct {
 	Location           common.Location
 	DefaultGenesisHash common.Hash
 	IndexAddressUtxos  bool
+	IndexWorkShares    bool
+	// TimeToStartTx, if set, overrides the number of zone blocks which are
+	// given no gas for transactions
+	TimeToStartTx *uint64 `json:"timeToStartTx,omitempty"`
 }
 
 // SetLocation sets the location on the chain config
@@ -139,6 +143,15 @@ func (cfg *ChainConfig) SetLocation(location common.Location) {
 	cfg.Location = location
 }
 
+// StartTxNumber returns the number of zone blocks which are given no gas for
+// transactions, to give the miners time to join a new chain.
+func (cfg *ChainConfig) StartTxNumber() uint64 {
+	if cfg.TimeToStartTx != nil {
+		return *cfg.TimeToStartTx
+	}
+	return TimeToStartTx
+}
+
 // Blake3powConfig is the consensus engine configs for proof-of-work based sealing.
 type Blake3powConfig struct{}
 
@@ -0,0 +1,62 @@
+package bind
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/accounts/keystore"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+)
+
+var (
+	// ErrNoChainID is returned whenever the user failed to specify a chain id.
+	ErrNoChainID = errors.New("no chain id specified")
+
+	// ErrNotAuthorized is returned when an account is not properly unlocked.
+	ErrNotAuthorized = errors.New("not authorized to sign this account")
+)
+
+// NewKeyStoreTransactorWithChainID is a utility method to easily create a
+// transaction signer from an unlocked account of a keystore. The account signs
+// the Quai transactions of the zone its address is prefixed with.
+func NewKeyStoreTransactorWithChainID(ks *keystore.KeyStore, account accounts.Account, chainID *big.Int) (*TransactOpts, error) {
+	if chainID == nil {
+		return nil, ErrNoChainID
+	}
+	return &TransactOpts{
+		From: account.Address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if !address.Equal(account.Address) {
+				return nil, ErrNotAuthorized
+			}
+			return ks.SignTx(accounts.AddressOf(address.Bytes20()), tx, chainID)
+		},
+		Context: context.Background(),
+	}, nil
+}
+
+// NewKeyedTransactorWithChainID is a utility method to easily create a
+// transaction signer from a single private key. The key signs the Quai
+// transactions of the zone at location, in which its address is derived.
+func NewKeyedTransactorWithChainID(key *ecdsa.PrivateKey, chainID *big.Int, location common.Location) (*TransactOpts, error) {
+	if chainID == nil {
+		return nil, ErrNoChainID
+	}
+	keyAddr := crypto.PubkeyToAddress(key.PublicKey, location)
+	signer := types.LatestSignerForChainID(chainID, location)
+	return &TransactOpts{
+		From: keyAddr,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if !address.Equal(keyAddr) {
+				return nil, ErrNotAuthorized
+			}
+			return types.SignTx(tx, signer, key)
+		},
+		Context: context.Background(),
+	}, nil
+}
@@ -0,0 +1,805 @@
+// Package backends provides contract backends for the Go contract bindings.
+package backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	quai "github.com/dominant-strategies/go-quai"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/bloombits"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/state"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/core/vm"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/event"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/abi"
+	"github.com/dominant-strategies/go-quai/quai/abi/bind"
+	"github.com/dominant-strategies/go-quai/quai/filters"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+// This nil assignment ensures at compile time that SimulatedBackend implements
+// the interfaces of the contract bindings.
+var (
+	_ bind.ContractBackend       = (*SimulatedBackend)(nil)
+	_ bind.PendingContractCaller = (*SimulatedBackend)(nil)
+	_ bind.DeployBackend         = (*SimulatedBackend)(nil)
+	_ filters.Backend            = (*filterBackend)(nil)
+)
+
+var errBlockDoesNotExist = errors.New("block does not exist in blockchain")
+
+// SimulatedBackend implements bind.ContractBackend, simulating the zone of a
+// Quai network in memory. Its main purpose is to allow for easy testing of
+// contract bindings and of the services built on them, without running a node.
+//
+// The blocks are generated by a chaingen.Generator, which seals them with the
+// developer mode of blake3pow. Sent transactions are applied to a pending state
+// and only mined when Commit is called.
+type SimulatedBackend struct {
+	generator *chaingen.Generator
+	location  common.Location
+	core      *core.Core
+	database  ethdb.Database
+
+	mu              sync.Mutex
+	pendingHeader   *types.WorkObject // Header of the pending block the sent transactions are applied to
+	pendingState    *state.StateDB    // Currently pending state that will be the active on request
+	pendingTxs      types.Transactions
+	pendingReceipts types.Receipts
+	gasPool         *types.GasPool
+	usedGas         uint64
+	usedState       uint64
+
+	events        *filters.EventSystem // Event system for filtering log events live
+	filterBackend *filterBackend       // Backend of the range filters of past logs
+}
+
+// NewSimulatedBackend creates a new binding backend simulating the only zone of
+// a hierarchy, which funds the Quai ledger accounts of alloc. The accounts have
+// to be in the scope of zone-0-0. The allocations are applied by the first
+// block of the zone, which is committed before the backend is returned.
+func NewSimulatedBackend(alloc map[common.Address]*big.Int) (*SimulatedBackend, error) {
+	generator, err := chaingen.New(chaingen.Config{QuaiAlloc: alloc})
+	if err != nil {
+		return nil, err
+	}
+	location := generator.Locations()[0]
+	backend := &SimulatedBackend{
+		generator: generator,
+		location:  location,
+		core:      generator.Core(location),
+		database:  generator.Database(location),
+	}
+	backend.filterBackend = &filterBackend{backend.database, backend.core}
+	backend.events = filters.NewEventSystem(backend.filterBackend)
+
+	if err := backend.Commit(); err != nil {
+		generator.Stop()
+		return nil, err
+	}
+	return backend, nil
+}
+
+// Close terminates the underlying chains of the simulated backend.
+func (b *SimulatedBackend) Close() error {
+	b.generator.Stop()
+	return nil
+}
+
+// Location returns the location of the simulated zone.
+func (b *SimulatedBackend) Location() common.Location {
+	return b.location
+}
+
+// Core returns the core of the simulated zone.
+func (b *SimulatedBackend) Core() *core.Core {
+	return b.core
+}
+
+// Commit mines a block with the pending transactions, and resets the pending
+// state to the new head. The worker may leave transactions of a sender for the
+// next block, like it does on a node, in which case the following blocks are
+// mined as well until every pending transaction is included.
+func (b *SimulatedBackend) Commit() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending := make(map[common.Hash]bool)
+	for _, tx := range b.pendingTxs {
+		pending[tx.Hash()] = true
+	}
+	for i := 0; i == 0 || len(pending) > 0; i++ {
+		if i > len(b.pendingTxs) {
+			for hash := range pending {
+				return fmt.Errorf("transaction %s was not included", hash)
+			}
+		}
+		blocks, err := b.generator.GenerateBlocks(b.location, 1, nil)
+		if err != nil {
+			return err
+		}
+		for _, tx := range blocks[0].Transactions() {
+			delete(pending, tx.Hash())
+		}
+	}
+	return b.rollback()
+}
+
+// rollback resets the pending state to the head of the chain.
+func (b *SimulatedBackend) rollback() error {
+	head := b.core.CurrentBlock()
+	pendingHeader, err := b.core.GeneratePendingHeader(head, false)
+	if err != nil {
+		return err
+	}
+	statedb, err := b.core.StateAt(head.EVMRoot(), head.EtxSetRoot(), head.QuaiStateSize())
+	if err != nil {
+		return err
+	}
+	// Validate the transactions sent next against the state of the head
+	b.core.TxPool().Sync()
+	b.pendingHeader = pendingHeader
+	b.pendingState = statedb
+	b.pendingTxs = nil
+	b.pendingReceipts = nil
+	b.gasPool = new(types.GasPool).AddGas(pendingHeader.GasLimit())
+	b.usedGas = 0
+	b.usedState = 0
+	return nil
+}
+
+// stateByBlockNumber retrieves the state and the header of the block of the
+// given number, or of the head if it is nil.
+func (b *SimulatedBackend) stateByBlockNumber(blockNumber *big.Int) (*state.StateDB, *types.WorkObject, error) {
+	header := b.core.CurrentBlock()
+	if blockNumber != nil && blockNumber.Cmp(header.Number(common.ZONE_CTX)) != 0 {
+		header = b.core.GetHeaderByNumber(blockNumber.Uint64())
+		if header == nil {
+			return nil, nil, errBlockDoesNotExist
+		}
+	}
+	statedb, err := b.core.StateAt(header.EVMRoot(), header.EtxSetRoot(), header.QuaiStateSize())
+	return statedb, header, err
+}
+
+// internalAddress returns the internal address of the account in the simulated
+// zone, whatever the location it was read in.
+func (b *SimulatedBackend) internalAddress(account common.Address) (common.InternalAddress, error) {
+	return common.BytesToAddress(account.Bytes(), b.location).InternalAndQuaiAddress()
+}
+
+// CodeAt returns the code associated with a certain account in the blockchain.
+func (b *SimulatedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	internal, err := b.internalAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	statedb, _, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetCode(internal), nil
+}
+
+// BalanceAt returns the balance of a certain account in the blockchain.
+func (b *SimulatedBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	internal, err := b.internalAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	statedb, _, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetBalance(internal), nil
+}
+
+// NonceAt returns the nonce of a certain account in the blockchain.
+func (b *SimulatedBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	internal, err := b.internalAddress(account)
+	if err != nil {
+		return 0, err
+	}
+	statedb, _, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return statedb.GetNonce(internal), nil
+}
+
+// StorageAt returns the value of key in the storage of an account in the blockchain.
+func (b *SimulatedBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	internal, err := b.internalAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	statedb, _, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	val := statedb.GetState(internal, key)
+	return val[:], nil
+}
+
+// TransactionReceipt returns the receipt of a transaction, or quai.NotFound if
+// it is not mined yet.
+func (b *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	tx, blockHash, _, _ := rawdb.ReadTransaction(b.database, txHash)
+	if tx == nil {
+		return nil, quai.NotFound
+	}
+	for _, receipt := range b.core.GetReceiptsByHash(blockHash) {
+		if receipt.TxHash == txHash {
+			return receipt, nil
+		}
+	}
+	return nil, quai.NotFound
+}
+
+// TransactionByHash checks the pool of pending transactions in addition to the
+// blockchain. The isPending return value indicates whether the transaction has
+// been mined yet.
+func (b *SimulatedBackend) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, tx := range b.pendingTxs {
+		if tx.Hash() == txHash {
+			return tx, true, nil
+		}
+	}
+	tx, _, _, _ := rawdb.ReadTransaction(b.database, txHash)
+	if tx != nil {
+		return tx, false, nil
+	}
+	return nil, false, quai.NotFound
+}
+
+// BlockByNumber retrieves a block from the database by number, or the head of
+// the chain if number is nil.
+func (b *SimulatedBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.WorkObject, error) {
+	if number == nil {
+		return b.core.CurrentBlock(), nil
+	}
+	block := b.core.GetBlockByNumber(number.Uint64())
+	if block == nil {
+		return nil, errBlockDoesNotExist
+	}
+	return block, nil
+}
+
+// PendingCodeAt returns the code associated with an account in the pending state.
+func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	internal, err := b.internalAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	return b.pendingState.GetCode(internal), nil
+}
+
+// PendingNonceAt implements PendingStateReader.PendingNonceAt, retrieving
+// the nonce currently pending for the account.
+func (b *SimulatedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	internal, err := b.internalAddress(account)
+	if err != nil {
+		return 0, err
+	}
+	return b.pendingState.GetNonce(internal), nil
+}
+
+// ChainID returns the chain ID the transactions of the simulated zone are
+// signed for.
+func (b *SimulatedBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.core.Config().ChainID), nil
+}
+
+// SuggestGasPrice implements ContractTransactor.SuggestGasPrice with the
+// minimum gas price of the pool, like quai_gasPrice.
+func (b *SimulatedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return b.core.GetMinGasPrice(), nil
+}
+
+// SuggestMinerTip implements ContractTransactor.SuggestMinerTip with the gas
+// price of the pool, like quai_minerTip.
+func (b *SimulatedBackend) SuggestMinerTip(ctx context.Context) (*big.Int, error) {
+	return b.core.GetPoolGasPrice(), nil
+}
+
+// CallContract executes a contract call.
+func (b *SimulatedBackend) CallContract(ctx context.Context, call quai.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, header, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	res, err := b.callContract(ctx, call, header, statedb)
+	if err != nil {
+		return nil, err
+	}
+	// If the result contains a revert reason, try to unpack and return it.
+	if len(res.Revert()) > 0 {
+		return nil, newRevertError(res, b.location)
+	}
+	return res.Return(), res.Err
+}
+
+// PendingCallContract executes a contract call on the pending state.
+func (b *SimulatedBackend) PendingCallContract(ctx context.Context, call quai.CallMsg) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res, err := b.callContract(ctx, call, b.pendingHeader, b.pendingState.Copy())
+	if err != nil {
+		return nil, err
+	}
+	// If the result contains a revert reason, try to unpack and return it.
+	if len(res.Revert()) > 0 {
+		return nil, newRevertError(res, b.location)
+	}
+	return res.Return(), res.Err
+}
+
+// EstimateGas executes the requested code against the pending state and returns
+// the used amount of gas.
+func (b *SimulatedBackend) EstimateGas(ctx context.Context, call quai.CallMsg) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Determine the lowest and highest possible gas limits to binary search in between
+	var (
+		lo  uint64 = params.TxGas - 1
+		hi  uint64
+		cap uint64
+	)
+	if call.Gas >= params.TxGas {
+		hi = call.Gas
+	} else {
+		hi = b.pendingHeader.GasLimit()
+	}
+	// Recap the highest gas allowance with account's balance.
+	if call.GasPrice != nil && call.GasPrice.BitLen() != 0 {
+		internal, err := b.internalAddress(call.From)
+		if err != nil {
+			return 0, err
+		}
+		balance := b.pendingState.GetBalance(internal) // from can't be nil
+		available := new(big.Int).Set(balance)
+		if call.Value != nil {
+			if call.Value.Cmp(available) >= 0 {
+				return 0, errors.New("insufficient funds for transfer")
+			}
+			available.Sub(available, call.Value)
+		}
+		allowance := new(big.Int).Div(available, call.GasPrice)
+		if allowance.IsUint64() && hi > allowance.Uint64() {
+			hi = allowance.Uint64()
+		}
+	}
+	cap = hi
+
+	// Create a helper to check if a gas allowance results in an executable transaction
+	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
+		call.Gas = gas
+
+		res, err := b.callContract(ctx, call, b.pendingHeader, b.pendingState.Copy())
+		if err != nil {
+			if errors.Is(err, core.ErrIntrinsicGas) {
+				return true, nil, nil // Special case, raise gas limit
+			}
+			return true, nil, err // Bail out
+		}
+		return res.Failed(), res, nil
+	}
+	// Execute the binary search and hone in on an executable gas limit
+	for lo+1 < hi {
+		mid := (hi + lo) / 2
+		failed, _, err := executable(mid)
+
+		// If the error is not nil(consensus error), it means the provided message
+		// call or transaction will never be accepted no matter how much gas it is
+		// assigned. Return the error directly, don't struggle any more
+		if err != nil {
+			return 0, err
+		}
+		if failed {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	// Reject the transaction as invalid if it still fails at the highest allowance
+	if hi == cap {
+		failed, result, err := executable(hi)
+		if err != nil {
+			return 0, err
+		}
+		if failed {
+			if result != nil && result.Err != vm.ErrOutOfGas {
+				if len(result.Revert()) > 0 {
+					return 0, newRevertError(result, b.location)
+				}
+				return 0, result.Err
+			}
+			// Otherwise, the specified gas cap is too low
+			return 0, fmt.Errorf("gas required exceeds allowance (%d)", cap)
+		}
+	}
+	return hi, nil
+}
+
+// CreateAccessList creates the access list of a transaction against the pending
+// state, returning the gas used by the transaction with it and the error of its
+// execution, if any. Contracts only access the accounts and the storage slots
+// of the access list of their transaction.
+func (b *SimulatedBackend) CreateAccessList(ctx context.Context, call quai.CallMsg) (*types.AccessList, uint64, string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from, to := b.callAddresses(call)
+	internal, err := from.InternalAndQuaiAddress()
+	if err != nil {
+		return nil, 0, "", err
+	}
+	// The created contract is accessed like the recipient of the call
+	recipient := to
+	if recipient == nil {
+		address, err := bind.ContractAddress(from, b.pendingState.GetNonce(internal), call.Data)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		recipient = &address
+	}
+	precompiles := vm.ActivePrecompiles(b.core.Config().Rules(b.pendingHeader.Number(common.ZONE_CTX)), b.location)
+
+	// Expand the access list until the call accesses nothing new
+	prevTracer := vm.NewAccessListTracer(call.AccessList, from, *recipient, precompiles)
+	for {
+		call.AccessList = prevTracer.AccessList(b.location)
+		tracer := vm.NewAccessListTracer(call.AccessList, from, *recipient, precompiles)
+		res, err := b.applyCall(call, b.pendingHeader, b.pendingState.Copy(), tracer)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if tracer.Equal(prevTracer) {
+			var vmErr string
+			if res.Err != nil {
+				vmErr = res.Err.Error()
+			}
+			return &call.AccessList, res.UsedGas, vmErr, nil
+		}
+		prevTracer = tracer
+	}
+}
+
+// callAddresses returns the sender and the recipient of a call bound to the
+// simulated zone, the sender being the zero address if it is unspecified.
+func (b *SimulatedBackend) callAddresses(call quai.CallMsg) (common.Address, *common.Address) {
+	from := common.ZeroAddress(b.location)
+	if !call.From.Equal(common.Address{}) && !call.From.Equal(common.Zero) {
+		from = common.BytesToAddress(call.From.Bytes(), b.location)
+	}
+	var to *common.Address
+	if call.To != nil {
+		address := common.BytesToAddress(call.To.Bytes(), b.location)
+		to = &address
+	}
+	return from, to
+}
+
+// callContract implements common code between normal and pending contract calls.
+// The state is modified during execution, make sure to copy it if necessary.
+// Like the calls of the API, the calls are not restricted to their access list.
+func (b *SimulatedBackend) callContract(ctx context.Context, call quai.CallMsg, header *types.WorkObject, statedb *state.StateDB) (*core.ExecutionResult, error) {
+	precompiles := vm.ActivePrecompiles(b.core.Config().Rules(header.Number(common.ZONE_CTX)), b.location)
+	tracer := vm.NewAccessListTracer(types.AccessList{}, common.ZeroAddress(b.location), common.ZeroAddress(b.location), precompiles)
+	return b.applyCall(call, header, statedb, tracer)
+}
+
+// applyCall executes a call on top of the state with the block context of the
+// header, tracing the accesses of the call with the tracer.
+func (b *SimulatedBackend) applyCall(call quai.CallMsg, header *types.WorkObject, statedb *state.StateDB, tracer *vm.AccessListTracer) (*core.ExecutionResult, error) {
+	// Calls are not charged, and are executed with the gas limit of the block
+	// if no gas is given
+	if call.Gas == 0 {
+		call.Gas = header.GasLimit()
+	}
+	if call.Value == nil {
+		call.Value = new(big.Int)
+	}
+	from, to := b.callAddresses(call)
+	internal, err := from.InternalAndQuaiAddress()
+	if err != nil {
+		return nil, err
+	}
+	msg := types.NewMessage(from, to, statedb.GetNonce(internal), call.Value, call.Gas, new(big.Int), new(big.Int), call.Data, call.AccessList, false)
+
+	parent := b.core.GetBlockByHash(header.ParentHash(common.ZONE_CTX))
+	if parent == nil {
+		return nil, errBlockDoesNotExist
+	}
+	// The coinbase of the headers read from the database is not bound to the
+	// zone, so bind it to be paid the fees of the call
+	coinbase := common.BytesToAddress(header.PrimaryCoinbase().Bytes(), b.location)
+	blockContext, err := core.NewEVMBlockContext(header, parent, b.core, &coinbase)
+	if err != nil {
+		return nil, err
+	}
+	// Create a new environment which holds all relevant information
+	// about the transaction and calling mechanisms.
+	vmEnv := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), statedb, b.core.Config(), vm.Config{Tracer: tracer, Debug: true, NoBaseFee: true})
+	gasPool := new(types.GasPool).AddGas(math.MaxUint64)
+
+	return core.ApplyMessage(vmEnv, msg, gasPool)
+}
+
+// SendTransaction updates the pending block to include the given transaction,
+// which is added to the pool for the next block committed.
+func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Check transaction validity
+	config := b.core.Config()
+	signer := types.MakeSigner(config, b.pendingHeader.Number(common.ZONE_CTX))
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return fmt.Errorf("invalid transaction: %v", err)
+	}
+	internal, err := sender.InternalAndQuaiAddress()
+	if err != nil {
+		return fmt.Errorf("invalid transaction: %v", err)
+	}
+	nonce := b.pendingState.GetNonce(internal)
+	if tx.Nonce() != nonce {
+		return fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce)
+	}
+	// Include tx in the pending state
+	parent := b.core.CurrentBlock()
+	_, parentOrder, err := b.core.CalcOrder(parent)
+	if err != nil {
+		return err
+	}
+	var (
+		coinbase  = b.pendingHeader.PrimaryCoinbase()
+		etxRLimit = params.ETXRLimitMin
+		etxPLimit = params.ETXPLimitMin
+		usedGas   = b.usedGas
+		usedState = b.usedState
+		gasPool   = *b.gasPool
+		statedb   = b.pendingState.Copy()
+	)
+	// The transaction is applied to a copy of the pending state, which replaces
+	// it once the pool accepted the transaction as well
+	statedb.Prepare(tx.Hash(), len(b.pendingTxs))
+	receipt, _, err := core.ApplyTransaction(config, parent, parentOrder, b.core, &coinbase, &gasPool, statedb, b.pendingHeader, tx, &usedGas, &usedState, *b.core.GetVMConfig(), &etxRLimit, &etxPLimit, log.Global)
+	if err != nil {
+		return err
+	}
+	if err := b.core.TxPool().AddLocal(tx); err != nil {
+		return err
+	}
+	b.pendingState = statedb
+	*b.gasPool = gasPool
+	b.usedGas = usedGas
+	b.usedState = usedState
+	b.pendingTxs = append(b.pendingTxs, tx)
+	b.pendingReceipts = append(b.pendingReceipts, receipt)
+	return nil
+}
+
+// FilterLogs executes a log filter operation, blocking during execution and
+// returning all the results in one batch.
+func (b *SimulatedBackend) FilterLogs(ctx context.Context, query quai.FilterQuery) ([]types.Log, error) {
+	var addresses []common.Address
+	for _, address := range query.Addresses {
+		addresses = append(addresses, common.Bytes20ToAddress(address, b.location))
+	}
+	var filter *filters.Filter
+	if query.BlockHash != nil {
+		// Block filter requested, construct a single-shot filter
+		filter = filters.NewBlockFilter(b.filterBackend, *query.BlockHash, addresses, query.Topics)
+	} else {
+		// Initialize unset filter boundaries to run from genesis to chain head
+		from := int64(0)
+		if query.FromBlock != nil {
+			from = query.FromBlock.Int64()
+		}
+		to := int64(-1)
+		if query.ToBlock != nil {
+			to = query.ToBlock.Int64()
+		}
+		// Construct the range filter
+		filter = filters.NewRangeFilter(b.filterBackend, from, to, addresses, query.Topics, log.Global)
+	}
+	// Run the filter and return all the logs
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.Log, len(logs))
+	for i, nLog := range logs {
+		res[i] = *nLog
+	}
+	return res, nil
+}
+
+// SubscribeFilterLogs creates a background log filtering operation, returning a
+// subscription immediately, which can be used to stream the found events.
+func (b *SimulatedBackend) SubscribeFilterLogs(ctx context.Context, query quai.FilterQuery, ch chan<- types.Log) (quai.Subscription, error) {
+	// Subscribe to contract events
+	sink := make(chan []*types.Log)
+
+	sub, err := b.events.SubscribeLogs(query, sink)
+	if err != nil {
+		return nil, err
+	}
+	// Since we're getting logs in batches, we need to flatten them into a plain stream
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case logs := <-sink:
+				for _, nlog := range logs {
+					select {
+					case ch <- *nlog:
+					case err := <-sub.Err():
+						return err
+					case <-quit:
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// revertError is an API error that encompasses an EVM revert with JSON error
+// code and a binary data blob.
+type revertError struct {
+	error
+	reason string // revert reason hex encoded
+}
+
+func newRevertError(result *core.ExecutionResult, nodeLocation common.Location) *revertError {
+	reason, errUnpack := abi.UnpackRevert(result.Revert(), nodeLocation)
+	err := errors.New("execution reverted")
+	if errUnpack == nil {
+		err = fmt.Errorf("execution reverted: %v", reason)
+	}
+	return &revertError{
+		error:  err,
+		reason: hexutil.Encode(result.Revert()),
+	}
+}
+
+// ErrorCode returns the JSON error code for a revert.
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData returns the hex encoded revert reason.
+func (e *revertError) ErrorData() interface{} {
+	return e.reason
+}
+
+// filterBackend implements filters.Backend to support filtering for logs
+// without taking bloom-bits acceleration structures into account.
+type filterBackend struct {
+	db   ethdb.Database
+	core *core.Core
+}
+
+func (fb *filterBackend) ChainDb() ethdb.Database { return fb.db }
+
+func (fb *filterBackend) HeaderByNumber(ctx context.Context, block rpc.BlockNumber) (*types.WorkObject, error) {
+	if block == rpc.LatestBlockNumber || block == rpc.PendingBlockNumber {
+		return fb.core.CurrentBlock(), nil
+	}
+	return fb.core.GetHeaderByNumber(uint64(block.Int64())), nil
+}
+
+func (fb *filterBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.WorkObject, error) {
+	return fb.core.GetHeaderByHash(hash), nil
+}
+
+func (fb *filterBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return fb.core.GetReceiptsByHash(hash), nil
+}
+
+func (fb *filterBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
+	receipts := fb.core.GetReceiptsByHash(hash)
+	if receipts == nil {
+		return nil, nil
+	}
+	logs := make([][]*types.Log, len(receipts))
+	for i, receipt := range receipts {
+		logs[i] = receipt.Logs
+	}
+	return logs, nil
+}
+
+func (fb *filterBackend) GetBloom(hash common.Hash) (*types.Bloom, error) {
+	return fb.core.Slice().HeaderChain().GetBloom(hash)
+}
+
+func (fb *filterBackend) GetBlock(hash common.Hash, number uint64) (*types.WorkObject, error) {
+	return fb.core.GetBlock(hash, number), nil
+}
+
+func (fb *filterBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return fb.core.SubscribeChainEvent(ch)
+}
+
+func (fb *filterBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return fb.core.SubscribeChainHeadEvent(ch)
+}
+
+func (fb *filterBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
+	return fb.core.SubscribeRemovedLogsEvent(ch)
+}
+
+func (fb *filterBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return fb.core.SubscribeLogsEvent(ch)
+}
+
+func (fb *filterBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return fb.core.SubscribePendingLogs(ch)
+}
+
+func (fb *filterBackend) SubscribePendingHeaderEvent(ch chan<- *types.WorkObject) event.Subscription {
+	return fb.core.SubscribePendingHeader(ch)
+}
+
+func (fb *filterBackend) SubscribeUnlocksEvent(ch chan<- core.UnlocksEvent) event.Subscription {
+	return fb.core.SubscribeUnlocks(ch)
+}
+
+func (fb *filterBackend) ProcessingState() bool { return true }
+
+func (fb *filterBackend) NodeLocation() common.Location { return fb.core.NodeLocation() }
+
+func (fb *filterBackend) NodeCtx() int { return common.ZONE_CTX }
+
+func (fb *filterBackend) BloomStatus() (uint64, uint64) { return params.BloomBitsBlocks, 0 }
+
+func (fb *filterBackend) ServiceFilter(ctx context.Context, ms *bloombits.MatcherSession) {
+	panic("not supported")
+}
+
+func (fb *filterBackend) Logger() *log.Logger { return log.Global }
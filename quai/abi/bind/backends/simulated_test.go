@@ -0,0 +1,190 @@
+package backends
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	quai "github.com/dominant-strategies/go-quai"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/abi"
+	"github.com/dominant-strategies/go-quai/quai/abi/bind"
+	"github.com/stretchr/testify/require"
+)
+
+const storeABI = `[
+	{"type":"function","name":"set","stateMutability":"nonpayable","inputs":[{"name":"value","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"get","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"event","name":"Stored","anonymous":false,"inputs":[{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+// storeBin stores the value given to set, emitting it in a Stored event, and
+// returns it from get. Other calls revert.
+const storeBin = "605c80600c6000396000f30060003560e01c806360fe47b114601d57636d4ce63c14605057600080fd5b50600435806000556000527fc6d8c0af6d21f291e7c359603aa97e0ed500f04db6e983b9fce75a91c6b8da6b60206000a1005b60005460005260206000f3"
+
+func newTestBackend(t *testing.T) (*SimulatedBackend, []chaingen.Account) {
+	accs := chaingen.Accounts(common.Location{0, 0}, false, 2)
+	alloc := make(map[common.Address]*big.Int)
+	for _, acc := range accs {
+		alloc[acc.Address] = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+	}
+	backend, err := NewSimulatedBackend(alloc)
+	require.NoError(t, err)
+	t.Cleanup(func() { backend.Close() })
+	return backend, accs
+}
+
+func newTransactor(t *testing.T, backend *SimulatedBackend, acc chaingen.Account) *bind.TransactOpts {
+	chainID, err := backend.ChainID(context.Background())
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(acc.Key, chainID, backend.Location())
+	require.NoError(t, err)
+	return opts
+}
+
+func deployStore(t *testing.T, backend *SimulatedBackend, opts *bind.TransactOpts) (common.Address, *bind.BoundContract) {
+	parsed, err := abi.JSON(strings.NewReader(storeABI))
+	require.NoError(t, err)
+	address, tx, contract, err := bind.DeployContract(opts, parsed, common.FromHex(storeBin), backend)
+	require.NoError(t, err)
+
+	// The contract only exists in the pending state until it is committed
+	code, err := backend.CodeAt(context.Background(), address, nil)
+	require.NoError(t, err)
+	require.Empty(t, code)
+	code, err = backend.PendingCodeAt(context.Background(), address)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+
+	require.NoError(t, backend.Commit())
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	require.True(t, receipt.ContractAddress.Equal(address))
+	return address, contract
+}
+
+func getStored(t *testing.T, contract *bind.BoundContract, opts *bind.CallOpts) *big.Int {
+	var out []interface{}
+	require.NoError(t, contract.Call(opts, &out, "get"))
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+}
+
+func TestSimulatedBackendDeployAndTransact(t *testing.T) {
+	backend, accs := newTestBackend(t)
+	opts := newTransactor(t, backend, accs[0])
+	_, contract := deployStore(t, backend, opts)
+	require.Zero(t, getStored(t, contract, nil).Sign())
+
+	tx, err := contract.Transact(opts, "set", big.NewInt(42))
+	require.NoError(t, err)
+	_, isPending, err := backend.TransactionByHash(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.True(t, isPending)
+	_, err = backend.TransactionReceipt(context.Background(), tx.Hash())
+	require.ErrorIs(t, err, quai.NotFound)
+
+	// The transaction is only applied to the pending state before the commit
+	require.Equal(t, big.NewInt(42), getStored(t, contract, &bind.CallOpts{Pending: true}))
+	require.Zero(t, getStored(t, contract, nil).Sign())
+
+	require.NoError(t, backend.Commit())
+	require.Equal(t, big.NewInt(42), getStored(t, contract, nil))
+	_, isPending, err = backend.TransactionByHash(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.False(t, isPending)
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	// The value of the previous block is still readable
+	block, err := backend.BlockByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	previous := new(big.Int).Sub(block.Number(common.ZONE_CTX), common.Big1)
+	require.Zero(t, getStored(t, contract, &bind.CallOpts{BlockNumber: previous}).Sign())
+}
+
+func TestSimulatedBackendNonces(t *testing.T) {
+	backend, accs := newTestBackend(t)
+	opts := newTransactor(t, backend, accs[0])
+	ctx := context.Background()
+
+	opts.Value = big.NewInt(params.Ether)
+	opts.GasLimit = params.TxGas
+	raw := bind.NewBoundContract(accs[1].Address, abi.ABI{}, backend, backend, backend)
+	for i := 0; i < 3; i++ {
+		_, err := raw.RawTransact(opts, nil)
+		require.NoError(t, err)
+	}
+	nonce, err := backend.PendingNonceAt(ctx, accs[0].Address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce)
+	nonce, err = backend.NonceAt(ctx, accs[0].Address, nil)
+	require.NoError(t, err)
+	require.Zero(t, nonce)
+
+	require.NoError(t, backend.Commit())
+	nonce, err = backend.NonceAt(ctx, accs[0].Address, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce)
+}
+
+func TestSimulatedBackendEstimateGas(t *testing.T) {
+	backend, accs := newTestBackend(t)
+	opts := newTransactor(t, backend, accs[0])
+	address, _ := deployStore(t, backend, opts)
+	parsed, err := abi.JSON(strings.NewReader(storeABI))
+	require.NoError(t, err)
+
+	input, err := parsed.Pack("set", big.NewInt(42))
+	require.NoError(t, err)
+	gas, err := backend.EstimateGas(context.Background(), quai.CallMsg{From: accs[0].Address, To: &address, Data: input})
+	require.NoError(t, err)
+	require.Greater(t, gas, params.TxGas)
+
+	// Calls of unknown methods revert whatever the gas allowance
+	_, err = backend.EstimateGas(context.Background(), quai.CallMsg{From: accs[0].Address, To: &address, Data: []byte{0xde, 0xad, 0xbe, 0xef}})
+	require.ErrorContains(t, err, "execution reverted")
+}
+
+func TestSimulatedBackendLogs(t *testing.T) {
+	backend, accs := newTestBackend(t)
+	opts := newTransactor(t, backend, accs[0])
+	_, contract := deployStore(t, backend, opts)
+
+	sink, sub, err := contract.WatchLogs(nil, "Stored")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	_, err = contract.Transact(opts, "set", big.NewInt(42))
+	require.NoError(t, err)
+	require.NoError(t, backend.Commit())
+
+	select {
+	case log := <-sink:
+		out := make(map[string]interface{})
+		require.NoError(t, contract.UnpackLogIntoMap(out, "Stored", log))
+		require.Equal(t, big.NewInt(42), out["value"])
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log received")
+	}
+
+	logs, sub2, err := contract.FilterLogs(nil, "Stored")
+	require.NoError(t, err)
+	defer sub2.Unsubscribe()
+	select {
+	case log := <-logs:
+		out := make(map[string]interface{})
+		require.NoError(t, contract.UnpackLogIntoMap(out, "Stored", log))
+		require.Equal(t, big.NewInt(42), out["value"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log filtered")
+	}
+}
@@ -0,0 +1,235 @@
+package bind_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	quai "github.com/dominant-strategies/go-quai"
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/quai/abi"
+	"github.com/dominant-strategies/go-quai/quai/abi/bind"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"owner","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"set","stateMutability":"nonpayable","inputs":[{"name":"value","type":"uint256"}],"outputs":[]},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+var zoneLocation = common.Location{0, 1}
+
+// mockBackend is a contract backend returning canned answers and recording
+// the transactions sent to it.
+type mockBackend struct {
+	code    []byte
+	output  []byte
+	chainID *big.Int
+	nonce   uint64
+	sent    []*types.Transaction
+}
+
+func (b *mockBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.code, nil
+}
+
+func (b *mockBackend) CallContract(ctx context.Context, call quai.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.output, nil
+}
+
+func (b *mockBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return b.chainID, nil
+}
+
+func (b *mockBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.code, nil
+}
+
+func (b *mockBackend) PendingCallContract(ctx context.Context, call quai.CallMsg) ([]byte, error) {
+	return b.output, nil
+}
+
+func (b *mockBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.nonce, nil
+}
+
+func (b *mockBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2000), nil
+}
+
+func (b *mockBackend) SuggestMinerTip(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(100), nil
+}
+
+func (b *mockBackend) EstimateGas(ctx context.Context, call quai.CallMsg) (uint64, error) {
+	return 50000, nil
+}
+
+func (b *mockBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *mockBackend) FilterLogs(ctx context.Context, query quai.FilterQuery) ([]types.Log, error) {
+	return nil, nil
+}
+
+func (b *mockBackend) SubscribeFilterLogs(ctx context.Context, query quai.FilterQuery, ch chan<- types.Log) (quai.Subscription, error) {
+	return nil, nil
+}
+
+func newTestContract(t *testing.T, address common.Address, backend *mockBackend) *bind.BoundContract {
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+	return bind.NewBoundContract(address, parsed, backend, backend, backend)
+}
+
+func TestBoundContractAddressInItsZone(t *testing.T) {
+	contract := chaingen.Accounts(zoneLocation, false, 1)[0].Address
+
+	// Addresses read from JSON are bound to the location of the prime chain
+	bound := newTestContract(t, common.BytesToAddress(contract.Bytes(), common.Location{0, 0}), &mockBackend{})
+	require.True(t, bound.Address().Equal(contract))
+	require.Equal(t, zoneLocation, bound.Location())
+	require.Equal(t, zoneLocation, *bound.Address().Location())
+}
+
+func TestCallUnpacksAddressInContractZone(t *testing.T) {
+	owner := chaingen.Accounts(zoneLocation, false, 2)[1].Address
+	backend := &mockBackend{output: common.LeftPadBytes(owner.Bytes(), 32)}
+	bound := newTestContract(t, chaingen.Accounts(zoneLocation, false, 1)[0].Address, backend)
+
+	var out []interface{}
+	require.NoError(t, bound.Call(nil, &out, "owner"))
+	require.Len(t, out, 1)
+	got := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	require.True(t, got.Equal(owner))
+	require.Equal(t, zoneLocation, *got.Location())
+}
+
+func TestCallWithoutCode(t *testing.T) {
+	bound := newTestContract(t, chaingen.Accounts(zoneLocation, false, 1)[0].Address, &mockBackend{})
+
+	var out []interface{}
+	require.ErrorIs(t, bound.Call(nil, &out, "owner"), bind.ErrNoCode)
+	require.ErrorIs(t, bound.Call(&bind.CallOpts{Pending: true}, &out, "owner"), bind.ErrNoCode)
+}
+
+func TestTransactSignsWithChainID(t *testing.T) {
+	accs := chaingen.Accounts(zoneLocation, false, 2)
+	backend := &mockBackend{code: []byte{0x1}, chainID: big.NewInt(1337), nonce: 7}
+	bound := newTestContract(t, accs[1].Address, backend)
+
+	opts, err := bind.NewKeyedTransactorWithChainID(accs[0].Key, backend.chainID, zoneLocation)
+	require.NoError(t, err)
+	tx, err := bound.Transact(opts, "set", big.NewInt(42))
+	require.NoError(t, err)
+	require.Len(t, backend.sent, 1)
+
+	require.Equal(t, backend.chainID, tx.ChainId())
+	require.Equal(t, uint64(7), tx.Nonce())
+	require.Equal(t, uint64(50000), tx.Gas())
+	require.Equal(t, big.NewInt(2000), tx.GasPrice())
+	require.Equal(t, big.NewInt(100), tx.MinerTip())
+	require.True(t, tx.To().Equal(accs[1].Address))
+
+	from, err := types.Sender(types.LatestSignerForChainID(backend.chainID, zoneLocation), tx)
+	require.NoError(t, err)
+	require.True(t, from.Equal(accs[0].Address))
+}
+
+func TestTransactNoSend(t *testing.T) {
+	accs := chaingen.Accounts(zoneLocation, false, 2)
+	backend := &mockBackend{code: []byte{0x1}, chainID: big.NewInt(1337)}
+	bound := newTestContract(t, accs[1].Address, backend)
+
+	opts, err := bind.NewKeyedTransactorWithChainID(accs[0].Key, backend.chainID, zoneLocation)
+	require.NoError(t, err)
+	opts.NoSend = true
+	tx, err := bound.Transact(opts, "set", big.NewInt(42))
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	require.Empty(t, backend.sent)
+}
+
+func TestTransactFromOtherZone(t *testing.T) {
+	other := common.Location{0, 0}
+	sender := chaingen.Accounts(other, false, 1)[0]
+	backend := &mockBackend{code: []byte{0x1}, chainID: big.NewInt(1337)}
+	bound := newTestContract(t, chaingen.Accounts(zoneLocation, false, 1)[0].Address, backend)
+
+	opts, err := bind.NewKeyedTransactorWithChainID(sender.Key, backend.chainID, other)
+	require.NoError(t, err)
+	_, err = bound.Transact(opts, "set", big.NewInt(42))
+	require.Error(t, err)
+	require.Empty(t, backend.sent)
+
+	// Accounts of the Qi ledger can't send contract transactions either
+	qi := chaingen.Accounts(zoneLocation, true, 1)[0]
+	opts, err = bind.NewKeyedTransactorWithChainID(qi.Key, backend.chainID, zoneLocation)
+	require.NoError(t, err)
+	_, err = bound.Transact(opts, "set", big.NewInt(42))
+	require.Error(t, err)
+	require.Empty(t, backend.sent)
+}
+
+func TestUnpackLog(t *testing.T) {
+	accs := chaingen.Accounts(zoneLocation, false, 3)
+	bound := newTestContract(t, accs[0].Address, &mockBackend{})
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+
+	log := types.Log{
+		Address: accs[0].Address,
+		Topics: []common.Hash{
+			parsed.Events["Transfer"].ID,
+			common.BytesToHash(accs[1].Address.Bytes()),
+			common.BytesToHash(accs[2].Address.Bytes()),
+		},
+		Data: common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+	}
+	var transfer struct {
+		From  common.Address
+		To    common.Address
+		Value *big.Int
+	}
+	require.NoError(t, bound.UnpackLog(&transfer, "Transfer", log))
+	require.True(t, transfer.From.Equal(accs[1].Address))
+	require.True(t, transfer.To.Equal(accs[2].Address))
+	require.Equal(t, zoneLocation, *transfer.To.Location())
+	require.Equal(t, big.NewInt(42), transfer.Value)
+
+	out := make(map[string]interface{})
+	require.NoError(t, bound.UnpackLogIntoMap(out, "Transfer", log))
+	require.Equal(t, big.NewInt(42), out["value"])
+
+	log.Topics[0] = common.Hash{}
+	require.Error(t, bound.UnpackLog(&transfer, "Transfer", log))
+}
+
+func TestContractAddressInSenderZone(t *testing.T) {
+	sender := chaingen.Accounts(zoneLocation, false, 1)[0].Address
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+	// Grinding may run out of attempts, in which case the creation fails in
+	// the EVM as well
+	found := 0
+	for nonce := uint64(0); nonce < 16; nonce++ {
+		address, err := bind.ContractAddress(sender, nonce, code)
+		if err != nil {
+			continue
+		}
+		require.True(t, accounts.InScope(address, zoneLocation, accounts.QuaiLedger))
+		found++
+	}
+	require.NotZero(t, found)
+	// The address doesn't depend on the location the sender was read in
+	address, err := bind.ContractAddress(common.BytesToAddress(sender.Bytes(), common.Location{0, 0}), 1, code)
+	expected, expectedErr := bind.ContractAddress(sender, 1, code)
+	require.Equal(t, expectedErr, err)
+	require.True(t, address.Equal(expected))
+}
@@ -0,0 +1,136 @@
+package bind
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const tokenABI = `[
+	{"type":"constructor","stateMutability":"nonpayable","inputs":[{"name":"supply","type":"uint256"},{"name":"owners","type":"address[]"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"info","stateMutability":"view","inputs":[],"outputs":[{"name":"name","type":"string"},{"name":"decimals","type":"uint8"}]},
+	{"type":"function","name":"position","stateMutability":"view","inputs":[{"name":"id","type":"bytes32"}],"outputs":[{"name":"","type":"tuple","internalType":"struct Token.Position","components":[{"name":"holder","type":"address"},{"name":"amount","type":"uint256"}]}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"_transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"range","type":"uint256"}],"outputs":[]},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"memo","type":"string","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Hidden","anonymous":true,"inputs":[]},
+	{"type":"fallback","stateMutability":"nonpayable"},
+	{"type":"receive","stateMutability":"payable"}
+]`
+
+// declarations returns the names of the declared types and functions, methods
+// being prefixed with the name of their receiver type.
+func declarations(t *testing.T, code string) map[string]bool {
+	file, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+	require.NoError(t, err)
+
+	decls := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name := decl.Name.Name
+			if decl.Recv != nil {
+				recv := decl.Recv.List[0].Type.(*ast.StarExpr).X.(*ast.Ident)
+				name = recv.Name + "." + name
+			}
+			decls[name] = true
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					decls[spec.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						decls[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// typeCheck type-checks the generated package against the sources of its
+// imports, which are resolved from the module of the test.
+func typeCheck(t *testing.T, code string) {
+	dir, err := os.Getwd()
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(dir, "generated.go"), code, 0)
+	require.NoError(t, err)
+
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("token", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+}
+
+func TestBindToken(t *testing.T) {
+	code, err := Bind([]string{"token"}, []string{tokenABI}, []string{"0x6080"}, nil, "token", nil, map[string]string{"_transfer": "transferInternal"})
+	require.NoError(t, err)
+	require.Contains(t, code, "package token")
+	require.Contains(t, code, `Bin: "0x6080"`)
+
+	decls := declarations(t, code)
+	for _, name := range []string{
+		"Token", "TokenCaller", "TokenTransactor", "TokenFilterer", "TokenSession", "TokenRaw",
+		"TokenMetaData", "NewToken", "NewTokenCaller", "NewTokenTransactor", "NewTokenFilterer", "DeployToken",
+		"TokenPosition",
+		"TokenCaller.BalanceOf", "TokenSession.BalanceOf", "TokenCallerSession.BalanceOf",
+		"TokenCaller.Info", "TokenCaller.Position",
+		"TokenTransactor.Transfer", "TokenSession.Transfer", "TokenTransactorSession.Transfer",
+		"TokenTransactor.TransferInternal",
+		"TokenTransactor.Fallback", "TokenTransactor.Receive",
+		"TokenTransfer", "TokenTransferIterator",
+		"TokenFilterer.FilterTransfer", "TokenFilterer.WatchTransfer", "TokenFilterer.ParseTransfer",
+	} {
+		require.True(t, decls[name], "missing declaration %s", name)
+	}
+	// Anonymous events can't be filtered
+	require.False(t, decls["TokenHidden"])
+
+	// Constructor inputs parametrize the deployer, multiple named outputs are
+	// returned as a struct and indexed strings are filtered by their hashes.
+	require.Contains(t, code, "func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend, supply *big.Int, owners []common.Address)")
+	require.Contains(t, code, "Decimals uint8")
+	require.Contains(t, code, "Memo  common.Hash")
+	require.Contains(t, code, "func (_Token *TokenTransactor) TransferInternal(opts *bind.TransactOpts, to common.Address, arg1 *big.Int)")
+
+	typeCheck(t, code)
+}
+
+func TestBindWithoutBytecode(t *testing.T) {
+	code, err := Bind([]string{"token"}, []string{tokenABI}, []string{""}, nil, "token", nil, map[string]string{"_transfer": "transferInternal"})
+	require.NoError(t, err)
+
+	decls := declarations(t, code)
+	require.True(t, decls["NewToken"])
+	require.False(t, decls["DeployToken"])
+}
+
+func TestBindDuplicateIdentifier(t *testing.T) {
+	// _transfer and transfer are both normalized to Transfer
+	_, err := Bind([]string{"token"}, []string{tokenABI}, []string{""}, nil, "token", nil, nil)
+	require.Error(t, err)
+}
+
+func TestBindLibraries(t *testing.T) {
+	libABI := `[{"type":"function","name":"add","stateMutability":"pure","inputs":[{"name":"a","type":"uint256"},{"name":"b","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}]`
+	code, err := Bind(
+		[]string{"math", "token"},
+		[]string{libABI, tokenABI},
+		[]string{"0x6000", "0x73__$abcdef$__"},
+		nil, "token",
+		map[string]string{"abcdef": "math"}, map[string]string{"_transfer": "transferInternal"},
+	)
+	require.NoError(t, err)
+	require.Contains(t, code, "mathAddr, _, _, _ := DeployMath(auth, backend)")
+	require.Contains(t, code, `"__$abcdef$__"`)
+}
@@ -0,0 +1,63 @@
+package bind
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dominant-strategies/go-quai/accounts"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+// WaitMined waits for tx to be mined on the blockchain.
+// It stops waiting when the context is canceled.
+func WaitMined(ctx context.Context, b DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
+	queryTicker := time.NewTicker(time.Second)
+	defer queryTicker.Stop()
+
+	logger := log.Global.WithField("hash", tx.Hash())
+	for {
+		receipt, err := b.TransactionReceipt(ctx, tx.Hash())
+		if err == nil && receipt != nil {
+			return receipt, nil
+		}
+		if err != nil {
+			logger.WithField("err", err).Trace("Receipt retrieval failed")
+		} else {
+			logger.Trace("Transaction not yet mined")
+		}
+		// Wait for the next round.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
+
+// WaitDeployed waits for a contract deployment transaction and returns the on-chain
+// contract address, bound to its zone, when it is mined. It stops waiting when ctx
+// is canceled.
+func WaitDeployed(ctx context.Context, b DeployBackend, tx *types.Transaction) (common.Address, error) {
+	if tx.To() != nil {
+		return common.Address{}, errors.New("tx is not contract creation")
+	}
+	receipt, err := WaitMined(ctx, b, tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if receipt.ContractAddress.Equal(common.Address{}) || receipt.ContractAddress.Equal(common.Zero) {
+		return common.Address{}, errors.New("zero address")
+	}
+	// Receipts are decoded without the location of the chain they come from
+	address := accounts.AddressOf(receipt.ContractAddress.Bytes20())
+	// Check that code has indeed been deployed at the address, as a constructor
+	// returning no code leaves an empty account behind.
+	code, err := b.CodeAt(ctx, address, nil)
+	if err == nil && len(code) == 0 {
+		err = ErrNoCodeAfterDeploy
+	}
+	return address, err
+}
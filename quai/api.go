@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -38,6 +39,7 @@ import (
 	"github.com/dominant-strategies/go-quai/rlp"
 	"github.com/dominant-strategies/go-quai/rpc"
 	"github.com/dominant-strategies/go-quai/trie"
+	"github.com/libp2p/go-libp2p/core/peer"
 )
 
 // PublicQuaiAPI provides an API to access Quai full node-related
@@ -176,20 +178,27 @@ func (api *PrivateAdminAPI) ExportChain(file string, first *uint64, last *uint64
 	if err != nil {
 		return false, err
 	}
-	defer out.Close()
-
-	var writer io.Writer = out
+	var writer io.WriteCloser = out
 	if strings.HasSuffix(file, ".gz") {
-		writer = gzip.NewWriter(writer)
-		defer writer.(*gzip.Writer).Close()
+		writer = gzip.NewWriter(out)
 	}
 
 	// Export the blockchain
 	if first != nil {
-		if err := api.quai.Core().ExportN(writer, *first, *last); err != nil {
-			return false, err
+		err = api.quai.Core().ExportN(writer, *first, *last)
+	} else {
+		err = api.quai.Core().Export(writer)
+	}
+	// The gzip writer flushes the end of the compressed data when closed
+	if writer != out {
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
 		}
-	} else if err := api.quai.Core().Export(writer); err != nil {
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
 		return false, err
 	}
 	return true, nil
@@ -227,8 +236,8 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	for batch := 0; ; batch++ {
 		// Load a batch of blocks from the input file
 		for len(blocks) < cap(blocks) {
-			block := new(types.WorkObject)
-			if err := stream.Decode(block); err == io.EOF {
+			block, err := core.ReadExportedWorkObject(stream, api.quai.core.NodeLocation())
+			if err == io.EOF {
 				break
 			} else if err != nil {
 				return false, fmt.Errorf("block %d: failed to parse: %v", index, err)
@@ -253,6 +262,122 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// PeerBandwidth is the traffic exchanged with a peer, in bytes and bytes per
+// second.
+type PeerBandwidth struct {
+	TotalIn  int64   `json:"totalIn"`
+	TotalOut int64   `json:"totalOut"`
+	RateIn   float64 `json:"rateIn"`
+	RateOut  float64 `json:"rateOut"`
+}
+
+// PeerResult describes a connected peer. Buckets holds the quality bucket of
+// the peer, Best, Responsive or LastResort, for each topic it was scored on.
+type PeerResult struct {
+	ID        string            `json:"id"`
+	Addrs     []string          `json:"addrs"`
+	Inbound   bool              `json:"inbound"`
+	Protected bool              `json:"protected"`
+	Quality   int               `json:"quality"`
+	Buckets   map[string]string `json:"buckets"`
+	Topics    []string          `json:"topics"`
+	Latency   string            `json:"latency"`
+	Bandwidth PeerBandwidth     `json:"bandwidth"`
+}
+
+// BlockedResult lists the peers, addresses and subnets the node refuses to
+// connect to.
+type BlockedResult struct {
+	Peers   []string `json:"peers"`
+	Addrs   []string `json:"addrs"`
+	Subnets []string `json:"subnets"`
+}
+
+// Peers returns the connected peers, with their quality and the traffic
+// exchanged with them.
+func (api *PrivateAdminAPI) Peers() []PeerResult {
+	peers := api.quai.p2p.Peers()
+	results := make([]PeerResult, 0, len(peers))
+	for _, info := range peers {
+		result := PeerResult{
+			ID:        info.ID.String(),
+			Addrs:     make([]string, 0, len(info.Addrs)),
+			Inbound:   info.Inbound,
+			Protected: info.Protected,
+			Quality:   info.Quality,
+			Buckets:   info.Buckets,
+			Topics:    info.Topics,
+			Latency:   info.Latency.String(),
+			Bandwidth: PeerBandwidth{
+				TotalIn:  info.Bandwidth.TotalIn,
+				TotalOut: info.Bandwidth.TotalOut,
+				RateIn:   info.Bandwidth.RateIn,
+				RateOut:  info.Bandwidth.RateOut,
+			},
+		}
+		for _, addr := range info.Addrs {
+			result.Addrs = append(result.Addrs, addr.String())
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// AddPeer connects to the peer at the given multiaddress, which has to end
+// with the ID of the peer, e.g. /ip4/1.2.3.4/tcp/4001/p2p/<id>.
+func (api *PrivateAdminAPI) AddPeer(addr string) (bool, error) {
+	if err := api.quai.p2p.AddPeer(addr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// BlockPeer disconnects the peer with the given ID and refuses any further
+// connection with it.
+func (api *PrivateAdminAPI) BlockPeer(id string) (bool, error) {
+	peerID, err := peer.Decode(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid peer id: %v", err)
+	}
+	if err := api.quai.p2p.BlockPeer(peerID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnblockSubnet allows connections with the addresses of the subnet, given
+// in CIDR notation, again.
+func (api *PrivateAdminAPI) UnblockSubnet(cidr string) (bool, error) {
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, fmt.Errorf("invalid subnet: %v", err)
+	}
+	if err := api.quai.p2p.UnblockSubnet(subnet); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListBlocked returns the blocked peers, addresses and subnets.
+func (api *PrivateAdminAPI) ListBlocked() BlockedResult {
+	blocked := api.quai.p2p.ListBlocked()
+	result := BlockedResult{
+		Peers:   make([]string, 0, len(blocked.Peers)),
+		Addrs:   make([]string, 0, len(blocked.Addrs)),
+		Subnets: make([]string, 0, len(blocked.Subnets)),
+	}
+	for _, id := range blocked.Peers {
+		result.Peers = append(result.Peers, id.String())
+	}
+	for _, addr := range blocked.Addrs {
+		result.Addrs = append(result.Addrs, addr.String())
+	}
+	for _, subnet := range blocked.Subnets {
+		result.Subnets = append(result.Subnets, subnet.String())
+	}
+	return result
+}
+
 // PublicDebugAPI is the collection of Quai full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
@@ -310,6 +435,21 @@ func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hex
 	return nil, errors.New("unknown preimage")
 }
 
+// SetHead rewinds the slice to the given canonical block, deleting the blocks
+// above it. The dom and sub slices are rewound with it where they share some
+// of the deleted blocks, and the blocks are synced again from the peers.
+func (api *PrivateDebugAPI) SetHead(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) error {
+	blockNrOrHash.RequireCanonical = true
+	header, err := api.quai.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return err
+	}
+	if header == nil {
+		return errors.New("block not found")
+	}
+	return api.quai.core.SetHead(header.Hash())
+}
+
 // AccountRangeMaxResults is the maximum number of results to be returned per call
 const AccountRangeMaxResults = 256
 
@@ -21,6 +21,8 @@ import (
 	"errors"
 	"math/big"
 
+	goquai "github.com/dominant-strategies/go-quai"
+	"github.com/dominant-strategies/go-quai/accounts/keystore"
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/consensus"
 	"github.com/dominant-strategies/go-quai/core"
@@ -33,6 +35,7 @@ import (
 	"github.com/dominant-strategies/go-quai/event"
 	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/gasprice"
 	"github.com/dominant-strategies/go-quai/rpc"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 )
@@ -40,7 +43,10 @@ import (
 // QuaiAPIBackend implements quaiapi.Backend for full nodes
 type QuaiAPIBackend struct {
 	extRPCEnabled bool
+	allowUnlock   bool
+	keyStore      *keystore.KeyStore
 	quai          *Quai
+	gpo           *gasprice.Oracle
 }
 
 // ChainConfig returns the active chain configuration.
@@ -218,6 +224,13 @@ func (b QuaiAPIBackend) GetLockupsByAddressAndRange(ctx context.Context, address
 	return b.quai.core.GetLockupsByAddressAndRange(address, start, end)
 }
 
+func (b *QuaiAPIBackend) GetWorkSharesByCoinbase(ctx context.Context, address common.Address, start, end uint64) ([]*types.WorkShareReward, error) {
+	if b.quai.core.NodeCtx() != common.ZONE_CTX {
+		return nil, errors.New("getWorkSharesByCoinbase can only be called in zone chain")
+	}
+	return b.quai.core.GetWorkSharesByCoinbase(address, start, end)
+}
+
 func (b *QuaiAPIBackend) AddressOutpoints(ctx context.Context, address common.Address) ([]*types.OutpointAndDenomination, error) {
 	return b.quai.core.GetOutpointsByAddress(address)
 }
@@ -345,7 +358,11 @@ func (b *QuaiAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction
 	if nodeCtx != common.ZONE_CTX {
 		return errors.New("sendTx can only be called in zone chain")
 	}
-	return b.quai.Core().AddLocal(signedTx)
+	if err := b.quai.Core().AddLocal(signedTx); err != nil {
+		return err
+	}
+	b.quai.handler.AnnounceTx(signedTx.Hash())
+	return nil
 }
 
 func (b *QuaiAPIBackend) SendRemoteTx(remoteTx *types.Transaction) error {
@@ -366,6 +383,14 @@ func (b *QuaiAPIBackend) SendRemoteTxs(remoteTxs types.Transactions) []error {
 	return nil
 }
 
+func (b *QuaiAPIBackend) AddPeerTxs(txs types.Transactions) []error {
+	nodeCtx := b.quai.core.NodeCtx()
+	if nodeCtx != common.ZONE_CTX {
+		return []error{errors.New("AddPeerTxs can only be called in zone chain")}
+	}
+	return b.quai.Core().AddPeerTxs(txs)
+}
+
 func (b *QuaiAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	nodeCtx := b.quai.core.NodeCtx()
 	if nodeCtx != common.ZONE_CTX {
@@ -418,6 +443,22 @@ func (b *QuaiAPIBackend) GetRollingFeeInfo() (min, max, avg *big.Int) {
 	return b.quai.core.GetRollingFeeInfo()
 }
 
+func (b *QuaiAPIBackend) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*gasprice.FeeHistory, error) {
+	nodeCtx := b.quai.core.NodeCtx()
+	if nodeCtx != common.ZONE_CTX {
+		return nil, errors.New("feeHistory can only be called in zone chain")
+	}
+	return b.gpo.FeeHistory(ctx, blocks, lastBlock, rewardPercentiles)
+}
+
+func (b *QuaiAPIBackend) SuggestFees(ctx context.Context) (*gasprice.FeeSuggestions, error) {
+	nodeCtx := b.quai.core.NodeCtx()
+	if nodeCtx != common.ZONE_CTX {
+		return nil, errors.New("suggestFees can only be called in zone chain")
+	}
+	return b.gpo.SuggestFees(ctx)
+}
+
 func (b *QuaiAPIBackend) GetMinGasPrice() *big.Int {
 	return b.quai.core.GetMinGasPrice()
 }
@@ -430,6 +471,10 @@ func (b *QuaiAPIBackend) Stats() (pending int, queued int, qi int) {
 	return b.quai.core.Stats()
 }
 
+func (b *QuaiAPIBackend) SyncTxPool() {
+	b.quai.core.SyncTxPool()
+}
+
 func (b *QuaiAPIBackend) TxPoolContent() (map[common.InternalAddress]types.Transactions, map[common.InternalAddress]types.Transactions) {
 	nodeCtx := b.quai.core.NodeCtx()
 	if nodeCtx != common.ZONE_CTX {
@@ -446,6 +491,14 @@ func (b *QuaiAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transacti
 	return b.quai.core.ContentFrom(addr)
 }
 
+func (b *QuaiAPIBackend) TxPoolQiConflicts(outpoints []types.OutPoint) map[types.OutPoint]common.Hash {
+	nodeCtx := b.quai.core.NodeCtx()
+	if nodeCtx != common.ZONE_CTX {
+		return nil
+	}
+	return b.quai.core.QiConflicts(outpoints)
+}
+
 func (b *QuaiAPIBackend) SuggestFinalityDepth(ctx context.Context, qiValue *big.Int, correlatedRisk *big.Int) (*big.Int, error) {
 	nodeCtx := b.quai.core.NodeCtx()
 	if nodeCtx != common.ZONE_CTX {
@@ -466,6 +519,23 @@ func (b *QuaiAPIBackend) ExtRPCEnabled() bool {
 	return b.extRPCEnabled
 }
 
+// SyncProgress returns the progress of the header-first sync of the chain, or
+// nil if it is not behind its peers.
+func (b *QuaiAPIBackend) SyncProgress() *goquai.SyncProgress {
+	if b.quai.handler == nil {
+		return nil
+	}
+	return b.quai.handler.SyncProgress()
+}
+
+func (b *QuaiAPIBackend) InsecureUnlockAllowed() bool {
+	return b.allowUnlock
+}
+
+func (b *QuaiAPIBackend) KeyStore() *keystore.KeyStore {
+	return b.keyStore
+}
+
 func (b *QuaiAPIBackend) RPCGasCap() uint64 {
 	nodeCtx := b.quai.core.NodeCtx()
 	if nodeCtx != common.ZONE_CTX {
@@ -557,6 +627,18 @@ func (b *QuaiAPIBackend) ProcessingState() bool {
 	return b.quai.core.ProcessingState()
 }
 
+func (b *QuaiAPIBackend) StateTrieNode(hash common.Hash) ([]byte, error) {
+	return b.quai.core.StateTrieNode(hash)
+}
+
+func (b *QuaiAPIBackend) UtxoRange(blockHash common.Hash, start types.OutPoint) (*types.UtxoRange, error) {
+	return b.quai.core.UtxoRange(blockHash, start)
+}
+
+func (b *QuaiAPIBackend) SyncBlockState(hash common.Hash) *types.SyncBlockState {
+	return b.quai.core.SyncBlockState(hash)
+}
+
 func (b *QuaiAPIBackend) NewGenesisPendingHeader(pendingHeader *types.WorkObject, domTerminus common.Hash, genesisHash common.Hash) error {
 	return b.quai.core.NewGenesisPendigHeader(pendingHeader, domTerminus, genesisHash)
 }
@@ -601,6 +683,14 @@ func (b *QuaiAPIBackend) GenerateRecoveryPendingHeader(pendingHeader *types.Work
 	return b.quai.core.GenerateRecoveryPendingHeader(pendingHeader, checkpointHashes)
 }
 
+func (b *QuaiAPIBackend) SetHead(hash common.Hash) error {
+	return b.quai.core.SetHead(hash)
+}
+
+func (b *QuaiAPIBackend) SetHeadFromDom(domTerminus common.Hash, domPendingHeader *types.WorkObject) error {
+	return b.quai.core.SetHeadFromDom(domTerminus, domPendingHeader)
+}
+
 func (b *QuaiAPIBackend) GetPendingEtxsRollupFromSub(hash common.Hash, location common.Location) (types.PendingEtxsRollup, error) {
 	return b.quai.core.GetPendingEtxsRollupFromSub(hash, location)
 }
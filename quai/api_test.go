@@ -20,9 +20,11 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"net"
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/dominant-strategies/go-quai/common"
@@ -30,6 +32,10 @@ import (
 	"github.com/dominant-strategies/go-quai/core/state"
 	"github.com/dominant-strategies/go-quai/crypto"
 	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/p2p"
+	"github.com/libp2p/go-libp2p/core/metrics"
+	libp2ptest "github.com/libp2p/go-libp2p/core/test"
+	"github.com/multiformats/go-multiaddr"
 )
 
 var dumper = spew.ConfigState{Indent: "    "}
@@ -220,3 +226,81 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+// adminNetwork records the admin calls made to the network.
+type adminNetwork struct {
+	NetworkingAPI
+	peers   []p2p.PeerInfo
+	blocked p2p.BlockedPeers
+}
+
+func (n *adminNetwork) Peers() []p2p.PeerInfo         { return n.peers }
+func (n *adminNetwork) ListBlocked() p2p.BlockedPeers { return n.blocked }
+func (n *adminNetwork) BlockPeer(id p2p.PeerID) error {
+	n.blocked.Peers = append(n.blocked.Peers, id)
+	return nil
+}
+func (n *adminNetwork) UnblockSubnet(subnet *net.IPNet) error {
+	for i, blocked := range n.blocked.Subnets {
+		if blocked.String() == subnet.String() {
+			n.blocked.Subnets = append(n.blocked.Subnets[:i], n.blocked.Subnets[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("subnet %s not blocked", subnet)
+}
+
+func TestAdminPeers(t *testing.T) {
+	id := libp2ptest.RandPeerIDFatal(t)
+	addr, err := multiaddr.NewMultiaddr("/ip4/10.0.0.1/tcp/4001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, subnet, _ := net.ParseCIDR("10.1.0.0/16")
+	network := &adminNetwork{
+		peers: []p2p.PeerInfo{{
+			ID:        id,
+			Addrs:     []p2p.Multiaddr{addr},
+			Inbound:   true,
+			Quality:   20,
+			Buckets:   map[string]string{"topic": "Best"},
+			Topics:    []string{"topic"},
+			Latency:   50 * time.Millisecond,
+			Bandwidth: metrics.Stats{TotalIn: 100, TotalOut: 200, RateIn: 1.5, RateOut: 2.5},
+		}},
+		blocked: p2p.BlockedPeers{Addrs: []net.IP{net.ParseIP("10.0.0.2")}, Subnets: []*net.IPNet{subnet}},
+	}
+	api := NewPrivateAdminAPI(&Quai{p2p: network})
+
+	want := []PeerResult{{
+		ID:        id.String(),
+		Addrs:     []string{"/ip4/10.0.0.1/tcp/4001"},
+		Inbound:   true,
+		Quality:   20,
+		Buckets:   map[string]string{"topic": "Best"},
+		Topics:    []string{"topic"},
+		Latency:   "50ms",
+		Bandwidth: PeerBandwidth{TotalIn: 100, TotalOut: 200, RateIn: 1.5, RateOut: 2.5},
+	}}
+	if peers := api.Peers(); !reflect.DeepEqual(peers, want) {
+		t.Fatalf("wrong peers:\ngot %s\nwant %s", dumper.Sdump(peers), dumper.Sdump(want))
+	}
+
+	if _, err := api.BlockPeer("not a peer id"); err == nil {
+		t.Fatal("blocked an invalid peer id")
+	}
+	if ok, err := api.BlockPeer(id.String()); !ok || err != nil {
+		t.Fatalf("failed to block peer: %v", err)
+	}
+	if _, err := api.UnblockSubnet("10.1.0.0"); err == nil {
+		t.Fatal("unblocked a subnet without a mask")
+	}
+	if ok, err := api.UnblockSubnet("10.1.2.3/16"); !ok || err != nil {
+		t.Fatalf("failed to unblock subnet: %v", err)
+	}
+
+	wantBlocked := BlockedResult{Peers: []string{id.String()}, Addrs: []string{"10.0.0.2"}, Subnets: []string{}}
+	if blocked := api.ListBlocked(); !reflect.DeepEqual(blocked, wantBlocked) {
+		t.Fatalf("wrong blocked list:\ngot %s\nwant %s", dumper.Sdump(blocked), dumper.Sdump(wantBlocked))
+	}
+}
@@ -39,7 +39,10 @@ import (
 	"github.com/dominant-strategies/go-quai/node"
 	"github.com/dominant-strategies/go-quai/params"
 	"github.com/dominant-strategies/go-quai/quai/filters"
+	"github.com/dominant-strategies/go-quai/quai/gasprice"
 	"github.com/dominant-strategies/go-quai/quai/quaiconfig"
+	"github.com/dominant-strategies/go-quai/quai/tracers"
+	_ "github.com/dominant-strategies/go-quai/quai/tracers/native" // register the native tracers
 	"github.com/dominant-strategies/go-quai/rpc"
 )
 
@@ -151,7 +154,7 @@ func New(stack *node.Node, p2p NetworkingAPI, config *quaiconfig.Config, nodeCtx
 
 	logger.WithField("location", &chainConfig).Warn("Memory location of chainConfig")
 
-	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal), config.NodeLocation, logger); err != nil {
+	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, []string{stack.ResolvePath(config.TrieCleanCacheJournal), stack.ResolvePath(config.ETXTrieCleanCacheJournal)}, config.NodeLocation, logger); err != nil {
 		logger.WithField("err", err).Error("Failed to recover state")
 	}
 	quai := &Quai{
@@ -181,6 +184,8 @@ func New(stack *node.Node, p2p NetworkingAPI, config *quaiconfig.Config, nodeCtx
 	chainConfig.Location = config.NodeLocation // TODO: See why this is necessary
 	chainConfig.DefaultGenesisHash = config.DefaultGenesisHash
 	chainConfig.IndexAddressUtxos = config.IndexAddressUtxos
+	chainConfig.IndexWorkShares = config.IndexWorkShares
+	chainConfig.TimeToStartTx = config.TimeToStartTx
 	logger.WithFields(log.Fields{
 		"Ctx":          nodeCtx,
 		"NodeLocation": config.NodeLocation,
@@ -253,18 +258,32 @@ func New(stack *node.Node, p2p NetworkingAPI, config *quaiconfig.Config, nodeCtx
 
 	// Only index bloom if processing state
 	if quai.core.ProcessingState() && nodeCtx == common.ZONE_CTX {
-		quai.bloomIndexer = core.NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms, chainConfig.Location.Context(), logger, config.IndexAddressUtxos)
+		quai.bloomIndexer = core.NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms, chainConfig.Location.Context(), logger, config.IndexAddressUtxos, config.IndexWorkShares)
 		quai.bloomIndexer.Start(quai.Core().Slice().HeaderChain(), newChainConfig)
 	}
 
 	// Set the p2p Networking API
 	quai.p2p = p2p
 
-	quai.handler = newHandler(quai.p2p, quai.core, config.NodeLocation, logger)
+	snapSync := config.SnapSync
+	if !snapSync && quai.core.SnapSyncPivot() != 0 {
+		// A snap sync was started before, or a utxo snapshot was imported, so
+		// the blocks up to the pivot are not processed
+		logger.WithField("pivot", quai.core.SnapSyncPivot()).Info("Resuming the snap sync of the state")
+		snapSync = true
+	}
+	if snapSync && config.IndexAddressUtxos {
+		// The address utxo index is built while processing blocks, so it
+		// would miss the utxos of the snap synced state
+		logger.Warn("Snap sync is disabled while indexing address utxos")
+		snapSync = false
+	}
+	quai.handler = newHandler(quai.p2p, quai.core, config.NodeLocation, snapSync, logger)
 	// Start the handler
 	quai.handler.Start()
 
-	quai.APIBackend = &QuaiAPIBackend{stack.Config().ExtRPCEnabled(), quai}
+	quai.APIBackend = &QuaiAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().InsecureUnlockAllowed, stack.KeyStore(), quai, nil}
+	quai.APIBackend.gpo = gasprice.NewOracle(quai.APIBackend, config.GPO, logger)
 
 	// Register the backend on the node
 	stack.RegisterAPIs(quai.APIs())
@@ -318,6 +337,10 @@ func (s *Quai) APIs() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   NewPrivateDebugAPI(s),
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   tracers.NewAPI(s.APIBackend),
 		},
 	}...)
 }
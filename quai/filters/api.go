@@ -30,6 +30,7 @@ import (
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/common/hexutil"
 	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
 	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/dominant-strategies/go-quai/ethdb"
 	"github.com/dominant-strategies/go-quai/event"
@@ -41,6 +42,10 @@ import (
 const (
 	c_pendingHeaderChSize = 20
 	MaxFilterRange        = 10000
+
+	// c_defaultTrimWarningWindow is the number of blocks before its trim
+	// height a UTXO is notified at, if the subscriber does not set it
+	c_defaultTrimWarningWindow = 360
 )
 
 // filter is a helper struct that holds meta information over the filter type
@@ -402,6 +407,121 @@ func (api *PublicFilterAPI) Accesses(ctx context.Context, addr common.Address) (
 	return rpcSub, nil
 }
 
+// TrimWarnings sends a notification each time a UTXO of the address enters the
+// trim window, which is the given number of blocks before the block trimming
+// it. The UTXOs which are trimmed sooner than that are notified as soon as
+// they are created.
+func (api *PublicFilterAPI) TrimWarnings(ctx context.Context, addr common.Address, window *hexutil.Uint64) (*rpc.Subscription, error) {
+	if api.activeSubscriptions >= api.subscriptionLimit {
+		return &rpc.Subscription{}, errors.New("too many subscribers")
+	}
+	if api.backend.NodeCtx() != common.ZONE_CTX {
+		return &rpc.Subscription{}, errors.New("trimWarnings can only be subscribed to in a zone chain")
+	}
+	if !addr.IsInQiLedgerScope() {
+		return &rpc.Subscription{}, fmt.Errorf("address %s is not in Qi ledger scope", addr.Hex())
+	}
+
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	trimWindow := uint64(c_defaultTrimWarningWindow)
+	if window != nil {
+		trimWindow = uint64(*window)
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				api.backend.Logger().WithFields(log.Fields{
+					"error":      r,
+					"stacktrace": string(debug.Stack()),
+				}).Error("Go-Quai Panicked")
+			}
+			api.activeSubscriptions -= 1
+		}()
+		api.activeSubscriptions += 1
+		headers := make(chan *types.WorkObject)
+		headersSub := api.events.SubscribeChainHeadEvent(headers)
+
+		for {
+			select {
+			case h := <-headers:
+				for _, warning := range api.trimWarnings(h, addr, trimWindow) {
+					notifier.Notify(rpcSub.ID, warning)
+				}
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// trimWarnings returns the unspent UTXOs of the address which enter the trim
+// window at the given head.
+func (api *PublicFilterAPI) trimWarnings(head *types.WorkObject, addr common.Address, window uint64) []*types.RPCTrimWarning {
+	db := api.chainDb
+	number := head.NumberU64(api.backend.NodeCtx())
+
+	// Find the blocks which created the UTXOs of each trimmed denomination
+	// entering the window
+	denominations := make(map[uint64]map[uint8]bool)
+	for denomination, depth := range types.TrimDepths {
+		if denomination > types.MaxTrimDenomination {
+			continue
+		}
+		createdAt := number
+		if window < depth {
+			if number+window < depth {
+				continue
+			}
+			createdAt = number + window - depth
+		}
+		if denominations[createdAt] == nil {
+			denominations[createdAt] = make(map[uint8]bool)
+		}
+		denominations[createdAt][denomination] = true
+	}
+
+	warnings := make([]*types.RPCTrimWarning, 0)
+	for createdAt, trimmed := range denominations {
+		hash := head.Hash()
+		if createdAt != number {
+			hash = rawdb.ReadCanonicalHash(db, createdAt)
+		}
+		keys, err := rawdb.ReadCreatedUTXOKeys(db, hash)
+		if err != nil {
+			continue
+		}
+		for _, key := range keys {
+			if len(key) != rawdb.UtxoKeyWithDenominationLength || !trimmed[key[len(key)-1]] {
+				continue
+			}
+			txHash, index, err := rawdb.ReverseUtxoKey(key[:len(key)-1])
+			if err != nil {
+				continue
+			}
+			utxo := rawdb.GetUTXO(db, txHash, index)
+			if utxo == nil || !common.BytesToAddress(utxo.Address, api.backend.NodeLocation()).Equal(addr) {
+				continue
+			}
+			if warning := types.NewRPCTrimWarning(types.OutPoint{TxHash: txHash, Index: index}, utxo, createdAt, api.backend.NodeLocation()); warning != nil {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return warnings
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	if api.activeSubscriptions >= api.subscriptionLimit {
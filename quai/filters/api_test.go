@@ -19,9 +19,14 @@ package filters
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
 	"github.com/dominant-strategies/go-quai/rpc"
 )
 
@@ -184,3 +189,68 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+func TestTrimWarnings(t *testing.T) {
+	var (
+		db       = rawdb.NewMemoryDatabase(log.Global)
+		api      = NewPublicFilterAPI(&testBackend{db: db}, deadline, 1)
+		location = common.Location{0, 0}
+		addr     = common.HexToAddress("0x0080000000000000000000000000000000000001", location)
+		other    = common.HexToAddress("0x0080000000000000000000000000000000000002", location)
+		lock     = big.NewInt(100)
+	)
+	newHead := func(number int64) *types.WorkObject {
+		woBody := types.EmptyWorkObjectBody()
+		woBody.SetHeader(types.EmptyHeader())
+		woHeader := types.NewWorkObjectHeader(types.EmptyRootHash, types.EmptyRootHash, big.NewInt(number), big.NewInt(1), big.NewInt(0), types.EmptyRootHash, types.BlockNonce{}, 0, 0, location, addr)
+		return types.NewWorkObject(woHeader, woBody, nil)
+	}
+	createUTXOs := func(blockHash common.Hash, utxos ...*types.UtxoEntry) []common.Hash {
+		txHashes := make([]common.Hash, len(utxos))
+		keys := make([][]byte, len(utxos))
+		for i, utxo := range utxos {
+			txHashes[i] = common.Hash{blockHash[0], byte(i + 1)}
+			if err := rawdb.CreateUTXO(db, txHashes[i], 0, utxo); err != nil {
+				t.Fatal(err)
+			}
+			keys[i] = rawdb.UtxoKeyWithDenomination(txHashes[i], 0, utxo.Denomination)
+		}
+		if err := rawdb.WriteCreatedUTXOKeys(db, blockHash, keys); err != nil {
+			t.Fatal(err)
+		}
+		return txHashes
+	}
+
+	// The UTXOs of the smallest denominations created at block 290 are
+	// trimmed at block 1010, and enter a 10 block window at block 1000
+	created := common.Hash{1}
+	rawdb.WriteCanonicalHash(db, created, 290)
+	txHashes := createUTXOs(created,
+		types.NewUtxoEntry(types.NewTxOut(1, addr.Bytes(), lock)),
+		types.NewUtxoEntry(types.NewTxOut(1, addr.Bytes(), big.NewInt(0))),
+		types.NewUtxoEntry(types.NewTxOut(3, addr.Bytes(), lock)),
+		types.NewUtxoEntry(types.NewTxOut(0, other.Bytes(), lock)),
+	)
+	warnings := api.trimWarnings(newHead(1000), addr, 10)
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(warnings))
+	}
+	if warnings[0].TxHash != txHashes[0] || warnings[0].CreatedAt != 290 || warnings[0].TrimHeight != 1010 {
+		t.Fatalf("got warning %+v, want the locked UTXO trimmed at 1010", warnings[0])
+	}
+
+	// The UTXO is not notified once it is spent
+	rawdb.DeleteUTXO(db, txHashes[0], 0)
+	if warnings := api.trimWarnings(newHead(1000), addr, 10); len(warnings) != 0 {
+		t.Fatalf("got %d warnings for a spent UTXO", len(warnings))
+	}
+
+	// The UTXOs which are trimmed sooner than the window are notified when
+	// they are created
+	head := newHead(2000)
+	txHashes = createUTXOs(head.Hash(), types.NewUtxoEntry(types.NewTxOut(5, addr.Bytes(), lock)))
+	warnings = api.trimWarnings(head, addr, 5000)
+	if len(warnings) != 1 || warnings[0].TxHash != txHashes[0] || warnings[0].TrimHeight != hexutil.Uint64(2000+types.TrimDepths[5]) {
+		t.Fatalf("got warnings %+v, want the UTXO created at the head", warnings)
+	}
+}
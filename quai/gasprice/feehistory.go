@@ -0,0 +1,297 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus/misc"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+const (
+	// maxFeeHistoryPercentiles is the maximum number of reward percentiles a
+	// fee history request can ask for.
+	maxFeeHistoryPercentiles = 100
+)
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+)
+
+// txFee is the fee per gas paid by a transaction together with the gas it
+// used, which weighs the transaction in the reward percentiles.
+type txFee struct {
+	fee *big.Int
+	gas uint64
+}
+
+// blockFees holds the fee data of a block which the fee history and the fee
+// suggestions are computed from.
+type blockFees struct {
+	number  uint64
+	time    uint64
+	baseFee *big.Int
+
+	gasUsed   uint64
+	gasLimit  uint64
+	qiGasUsed uint64
+
+	// quai and qi hold the fees of the transactions of both ledgers, sorted
+	// by increasing fee per gas. The fees of the Qi transactions are
+	// converted to Quai at the rate of the parent block, which is the gas
+	// price the block is validated and ordered with.
+	quai []txFee
+	qi   []txFee
+}
+
+// gasUsedRatio returns the share of the gas limit the block used.
+func (bf *blockFees) gasUsedRatio(gasUsed uint64) float64 {
+	if bf.gasLimit == 0 {
+		return 0
+	}
+	return float64(gasUsed) / float64(bf.gasLimit)
+}
+
+// percentiles returns the fees per gas at the given percentiles of the gas
+// used by the transactions, or zero fees if there are no transactions.
+func percentiles(fees []txFee, percentiles []float64) []*big.Int {
+	rewards := make([]*big.Int, len(percentiles))
+	var totalGas uint64
+	for _, fee := range fees {
+		totalGas += fee.gas
+	}
+	if len(fees) == 0 {
+		for i := range rewards {
+			rewards[i] = new(big.Int)
+		}
+		return rewards
+	}
+	var txIndex int
+	sumGasUsed := fees[0].gas
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(totalGas) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(fees)-1 {
+			txIndex++
+			sumGasUsed += fees[txIndex].gas
+		}
+		rewards[i] = new(big.Int).Set(fees[txIndex].fee)
+	}
+	return rewards
+}
+
+// blockFees returns the fee data of a canonical block, computing it from the
+// block and its receipts unless it is cached.
+func (oracle *Oracle) blockFees(ctx context.Context, number uint64) (*blockFees, error) {
+	block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block %d not found: %v", number, err)
+	}
+	if fees, ok := oracle.cache.Get(block.Hash()); ok {
+		return fees, nil
+	}
+	fees := &blockFees{
+		number:   number,
+		time:     block.Time(),
+		baseFee:  new(big.Int).Set(block.BaseFee()),
+		gasUsed:  block.GasUsed(),
+		gasLimit: block.GasLimit(),
+	}
+	receipts, err := oracle.backend.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	gasUsed := make(map[common.Hash]uint64, len(receipts))
+	for _, receipt := range receipts {
+		gasUsed[receipt.TxHash] = receipt.GasUsed
+	}
+
+	var (
+		parent        *types.WorkObject
+		scalingFactor float64
+		spent         map[types.OutPoint]*types.UtxoEntry
+	)
+	for _, tx := range block.Transactions() {
+		switch tx.Type() {
+		case types.QuaiTxType:
+			if gas, ok := gasUsed[tx.Hash()]; ok {
+				fees.quai = append(fees.quai, txFee{fee: tx.GasPrice(), gas: gas})
+			}
+		case types.QiTxType:
+			if parent == nil {
+				if parent, err = oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(number-1)); parent == nil || err != nil {
+					return nil, fmt.Errorf("parent of block %d not found: %v", number, err)
+				}
+				db := oracle.backend.Database()
+				scalingFactor = math.Log(float64(rawdb.ReadUTXOSetSize(db, parent.Hash())))
+				spentUtxos, err := rawdb.ReadSpentUTXOs(db, block.Hash())
+				if err != nil {
+					return nil, err
+				}
+				spent = make(map[types.OutPoint]*types.UtxoEntry, len(spentUtxos))
+				for _, utxo := range spentUtxos {
+					spent[utxo.OutPoint] = utxo.UtxoEntry
+				}
+			}
+			fee := new(big.Int)
+			for _, in := range tx.TxIn() {
+				utxo, ok := spent[in.PreviousOutPoint]
+				if !ok {
+					return nil, fmt.Errorf("spent utxo of qi tx %s not found", tx.Hash())
+				}
+				value, ok := types.Denominations[utxo.Denomination]
+				if !ok {
+					return nil, fmt.Errorf("invalid denomination in qi tx %s", tx.Hash())
+				}
+				fee.Add(fee, value)
+			}
+			for _, out := range tx.TxOut() {
+				if value, ok := types.Denominations[out.Denomination]; ok {
+					fee.Sub(fee, value)
+				}
+			}
+			gas := types.CalculateBlockQiTxGas(tx, scalingFactor, oracle.backend.NodeLocation())
+			if gas == 0 || fee.Sign() < 0 {
+				continue
+			}
+			fees.qiGasUsed += gas
+			feeInQuai := misc.QiToQuai(parent, fee)
+			fees.qi = append(fees.qi, txFee{fee: feeInQuai.Div(feeInQuai, new(big.Int).SetUint64(gas)), gas: gas})
+		}
+	}
+	for _, txFees := range [][]txFee{fees.quai, fees.qi} {
+		sort.SliceStable(txFees, func(i, j int) bool {
+			return txFees[i].fee.Cmp(txFees[j].fee) < 0
+		})
+	}
+	oracle.cache.Add(block.Hash(), fees)
+	return fees, nil
+}
+
+// FeeHistory is the fee history of a range of blocks.
+type FeeHistory struct {
+	OldestBlock *big.Int
+
+	// BaseFee holds the base fees of the blocks and of the block after the
+	// range, and GasUsedRatio the share of the gas limit used by each block.
+	BaseFee      []*big.Int
+	GasUsedRatio []float64
+
+	// Reward holds the gas prices of the Quai transactions at the requested
+	// percentiles of the gas they used.
+	Reward [][]*big.Int
+
+	// QiGasUsedRatio is the share of the gas limit used by Qi transactions,
+	// and QiReward the gas prices of the Qi transactions in Quai at the
+	// requested percentiles of the gas they used.
+	QiGasUsedRatio []float64
+	QiReward       [][]*big.Int
+}
+
+// headNumber returns the number of the current head, which is also the
+// number of the pending block as it has no fee data yet.
+func (oracle *Oracle) headNumber(ctx context.Context) (uint64, error) {
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil || err != nil {
+		return 0, fmt.Errorf("head block not found: %v", err)
+	}
+	return head.NumberU64(common.ZONE_CTX), nil
+}
+
+// nextBaseFee returns the base fee of the block after the given one, which
+// is the minimum gas price the pool accepts if the block is the head.
+func (oracle *Oracle) nextBaseFee(ctx context.Context, number uint64, head uint64) (*big.Int, error) {
+	if number >= head {
+		return new(big.Int).Set(oracle.backend.GetMinGasPrice()), nil
+	}
+	next, err := oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(number+1))
+	if next == nil || err != nil {
+		return nil, fmt.Errorf("block %d not found: %v", number+1, err)
+	}
+	return new(big.Int).Set(next.BaseFee()), nil
+}
+
+// FeeHistory returns the fee history of up to the given number of blocks
+// ending with lastBlock, along with the gas prices of the transactions of
+// both ledgers at the given percentiles, which have to be increasing. The
+// number of blocks is capped to the maximum history of the oracle and to the
+// blocks before lastBlock.
+func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*FeeHistory, error) {
+	if blocks < 1 {
+		return &FeeHistory{OldestBlock: new(big.Int)}, nil
+	}
+	if blocks > oracle.maxBlockHistory {
+		oracle.logger.WithFields(log.Fields{
+			"requested": blocks,
+			"truncated": oracle.maxBlockHistory,
+		}).Warn("Sanitizing fee history length")
+		blocks = oracle.maxBlockHistory
+	}
+	if len(rewardPercentiles) > maxFeeHistoryPercentiles {
+		return nil, fmt.Errorf("%w: over the query limit %d", errInvalidPercentile, maxFeeHistoryPercentiles)
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p <= rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("%w: #%d:%f >= #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+	head, err := oracle.headNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	last := head
+	if lastBlock >= 0 {
+		if uint64(lastBlock) > head {
+			return nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, head)
+		}
+		last = uint64(lastBlock)
+	} else if lastBlock != rpc.LatestBlockNumber && lastBlock != rpc.PendingBlockNumber {
+		return nil, fmt.Errorf("unsupported block number %d", lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	oldest := last + 1 - uint64(blocks)
+
+	history := &FeeHistory{
+		OldestBlock:    new(big.Int).SetUint64(oldest),
+		BaseFee:        make([]*big.Int, blocks+1),
+		GasUsedRatio:   make([]float64, blocks),
+		QiGasUsedRatio: make([]float64, blocks),
+	}
+	if len(rewardPercentiles) != 0 {
+		history.Reward = make([][]*big.Int, blocks)
+		history.QiReward = make([][]*big.Int, blocks)
+	}
+	for i := 0; i < blocks; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		fees, err := oracle.blockFees(ctx, oldest+uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		history.BaseFee[i] = new(big.Int).Set(fees.baseFee)
+		history.GasUsedRatio[i] = fees.gasUsedRatio(fees.gasUsed)
+		history.QiGasUsedRatio[i] = fees.gasUsedRatio(fees.qiGasUsed)
+		if len(rewardPercentiles) != 0 {
+			history.Reward[i] = percentiles(fees.quai, rewardPercentiles)
+			history.QiReward[i] = percentiles(fees.qi, rewardPercentiles)
+		}
+	}
+	if history.BaseFee[blocks], err = oracle.nextBaseFee(ctx, last, head); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
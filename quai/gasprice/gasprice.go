@@ -0,0 +1,228 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+const (
+	// blockFeesCacheLimit is the number of blocks whose fee data is cached.
+	blockFeesCacheLimit = 2048
+
+	// fullBlockRatio is the share of the gas limit above which a block is
+	// considered full, so that it only included the fees above its lowest
+	// included fee.
+	fullBlockRatio = 0.9
+)
+
+// Config holds the settings of the fee oracle.
+type Config struct {
+	// Blocks is the number of recent blocks the fee suggestions are computed
+	// from, and MaxBlockHistory the maximum number of blocks a fee history
+	// request can span.
+	Blocks          int
+	MaxBlockHistory int
+
+	// LowPercentile, MediumPercentile and HighPercentile are the percentiles
+	// of the gas prices paid in recent blocks the fee suggestions are taken
+	// from.
+	LowPercentile    int
+	MediumPercentile int
+	HighPercentile   int
+}
+
+// DefaultConfig contains the default settings of the fee oracle.
+var DefaultConfig = Config{
+	Blocks:           20,
+	MaxBlockHistory:  1024,
+	LowPercentile:    10,
+	MediumPercentile: 50,
+	HighPercentile:   90,
+}
+
+// OracleBackend includes all necessary background APIs for the oracle.
+type OracleBackend interface {
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.WorkObject, error)
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.WorkObject, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	Database() ethdb.Database
+	NodeLocation() common.Location
+	GetMinGasPrice() *big.Int
+}
+
+// Oracle suggests the fees of Quai and Qi transactions from the fees paid in
+// recent blocks.
+type Oracle struct {
+	backend         OracleBackend
+	blocks          int
+	maxBlockHistory int
+	percentiles     []float64
+	cache           *lru.Cache[common.Hash, *blockFees]
+	logger          *log.Logger
+}
+
+// NewOracle returns a new fee oracle which can recommend suitable fees for
+// newly created transactions.
+func NewOracle(backend OracleBackend, config Config, logger *log.Logger) *Oracle {
+	blocks := config.Blocks
+	if blocks < 1 {
+		blocks = 1
+		logger.WithFields(log.Fields{
+			"provided": config.Blocks,
+			"updated":  blocks,
+		}).Warn("Sanitizing invalid gasprice oracle sample blocks")
+	}
+	maxBlockHistory := config.MaxBlockHistory
+	if maxBlockHistory < blocks {
+		maxBlockHistory = blocks
+		logger.WithFields(log.Fields{
+			"provided": config.MaxBlockHistory,
+			"updated":  maxBlockHistory,
+		}).Warn("Sanitizing invalid gasprice oracle max block history")
+	}
+	percentiles := []float64{float64(config.LowPercentile), float64(config.MediumPercentile), float64(config.HighPercentile)}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 || (i > 0 && p < percentiles[i-1]) {
+			logger.WithFields(log.Fields{
+				"provided": []int{config.LowPercentile, config.MediumPercentile, config.HighPercentile},
+				"updated":  []int{DefaultConfig.LowPercentile, DefaultConfig.MediumPercentile, DefaultConfig.HighPercentile},
+			}).Warn("Sanitizing invalid gasprice oracle percentiles")
+			percentiles = []float64{float64(DefaultConfig.LowPercentile), float64(DefaultConfig.MediumPercentile), float64(DefaultConfig.HighPercentile)}
+			break
+		}
+	}
+	cache, _ := lru.New[common.Hash, *blockFees](blockFeesCacheLimit)
+	return &Oracle{
+		backend:         backend,
+		blocks:          blocks,
+		maxBlockHistory: maxBlockHistory,
+		percentiles:     percentiles,
+		cache:           cache,
+		logger:          logger,
+	}
+}
+
+// FeeSuggestion is a suggested gas price together with the time a
+// transaction paying it is expected to take to be included.
+type FeeSuggestion struct {
+	Fee           *big.Int
+	InclusionTime time.Duration
+}
+
+// LedgerFeeSuggestions are the low, medium and high fee suggestions for the
+// transactions of a ledger.
+type LedgerFeeSuggestions struct {
+	Low    FeeSuggestion
+	Medium FeeSuggestion
+	High   FeeSuggestion
+}
+
+// FeeSuggestions are the fee suggestions for both ledgers. The fees of Qi
+// transactions are gas prices in Quai, which the fee of a Qi transaction is
+// converted to and divided by its gas to be ordered.
+type FeeSuggestions struct {
+	BaseFee *big.Int
+	Quai    LedgerFeeSuggestions
+	Qi      LedgerFeeSuggestions
+}
+
+// SuggestFees returns low, medium and high gas prices for the transactions
+// of both ledgers, taken from the gas prices paid in recent blocks, with the
+// time a transaction paying them is expected to take to be included.
+func (oracle *Oracle) SuggestFees(ctx context.Context) (*FeeSuggestions, error) {
+	head, err := oracle.headNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocks := oracle.blocks
+	if uint64(blocks) > head+1 {
+		blocks = int(head + 1)
+	}
+	recent := make([]*blockFees, blocks)
+	for i := range recent {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if recent[i], err = oracle.blockFees(ctx, head+1-uint64(blocks-i)); err != nil {
+			return nil, err
+		}
+	}
+	baseFee, err := oracle.nextBaseFee(ctx, head, head)
+	if err != nil {
+		return nil, err
+	}
+	var interval time.Duration
+	if blocks > 1 && recent[blocks-1].time > recent[0].time {
+		interval = time.Duration(recent[blocks-1].time-recent[0].time) * time.Second / time.Duration(blocks-1)
+	}
+	suggest := func(txFees func(*blockFees) []txFee) LedgerFeeSuggestions {
+		levels := oracle.suggest(recent, txFees, baseFee, interval)
+		return LedgerFeeSuggestions{Low: levels[0], Medium: levels[1], High: levels[2]}
+	}
+	return &FeeSuggestions{
+		BaseFee: baseFee,
+		Quai:    suggest(func(fees *blockFees) []txFee { return fees.quai }),
+		Qi:      suggest(func(fees *blockFees) []txFee { return fees.qi }),
+	}, nil
+}
+
+// suggest returns the fee suggestions of a ledger at the percentiles of the
+// oracle. The fee of each level is the median across the blocks of the gas
+// price paid at the percentile, and is at least the base fee and the fee of
+// the level below.
+func (oracle *Oracle) suggest(recent []*blockFees, txFees func(*blockFees) []txFee, baseFee *big.Int, interval time.Duration) []FeeSuggestion {
+	samples := make([][]*big.Int, len(oracle.percentiles))
+	for _, fees := range recent {
+		if len(txFees(fees)) == 0 {
+			continue
+		}
+		for i, fee := range percentiles(txFees(fees), oracle.percentiles) {
+			samples[i] = append(samples[i], fee)
+		}
+	}
+	suggestions := make([]FeeSuggestion, len(oracle.percentiles))
+	fee := baseFee
+	for i, levelFees := range samples {
+		if len(levelFees) > 0 {
+			sort.Slice(levelFees, func(a, b int) bool {
+				return levelFees[a].Cmp(levelFees[b]) < 0
+			})
+			if median := levelFees[len(levelFees)/2]; median.Cmp(fee) > 0 {
+				fee = median
+			}
+		}
+		suggestions[i] = FeeSuggestion{
+			Fee:           new(big.Int).Set(fee),
+			InclusionTime: time.Duration(inclusionBlocks(recent, txFees, fee)) * interval,
+		}
+	}
+	return suggestions
+}
+
+// inclusionBlocks returns the number of blocks a transaction paying the fee
+// is expected to wait to be included, from the share of recent blocks which
+// would have included it. A block includes a fee if it was not full or if
+// the fee is at least the lowest fee it included.
+func inclusionBlocks(recent []*blockFees, txFees func(*blockFees) []txFee, fee *big.Int) int {
+	var accepted int
+	for _, fees := range recent {
+		included := txFees(fees)
+		if fees.gasUsedRatio(fees.gasUsed) < fullBlockRatio || (len(included) > 0 && fee.Cmp(included[0].fee) >= 0) {
+			accepted++
+		}
+	}
+	if accepted == 0 {
+		return len(recent)
+	}
+	return (len(recent) + accepted - 1) / accepted
+}
@@ -0,0 +1,182 @@
+package gasprice
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus/misc"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+var testLocation = common.Location{0, 0}
+
+type testBackend struct {
+	db          ethdb.Database
+	blocks      []*types.WorkObject
+	receipts    map[common.Hash]types.Receipts
+	minGasPrice *big.Int
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.WorkObject, error) {
+	return b.BlockByNumber(ctx, number)
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.WorkObject, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return b.blocks[len(b.blocks)-1], nil
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *testBackend) Database() ethdb.Database      { return b.db }
+func (b *testBackend) NodeLocation() common.Location { return testLocation }
+func (b *testBackend) GetMinGasPrice() *big.Int      { return b.minGasPrice }
+
+// addBlock appends a block paying the Quai gas prices, with each transaction
+// using the given gas, and including the Qi transactions.
+func (b *testBackend) addBlock(gasUsed uint64, gasPrices []int64, txGas uint64, qiTxs ...*types.Transaction) *types.WorkObject {
+	number := len(b.blocks)
+	wo := types.EmptyZoneWorkObject()
+	wo.WorkObjectHeader().SetNumber(big.NewInt(int64(number)))
+	wo.WorkObjectHeader().SetDifficulty(big.NewInt(1e12))
+	wo.WorkObjectHeader().SetTime(uint64(number) * 5)
+	header := wo.Body().Header()
+	header.SetGasUsed(gasUsed)
+	header.SetGasLimit(10000000)
+	header.SetBaseFee(big.NewInt(int64(100 + number)))
+	header.SetExchangeRate(new(big.Int).Mul(common.Big2e64, big.NewInt(1e9)))
+
+	var txs types.Transactions
+	var receipts types.Receipts
+	to := common.HexToAddress("0x0094f5ea0ba39494ce83a213fffba74279579268", testLocation)
+	for i, gasPrice := range gasPrices {
+		tx := types.NewTx(&types.QuaiTx{
+			ChainID:    big.NewInt(1),
+			Nonce:      uint64(number*100 + i),
+			MinerTip:   big.NewInt(1),
+			GasPrice:   big.NewInt(gasPrice),
+			Gas:        txGas,
+			To:         &to,
+			Value:      big.NewInt(0),
+			AccessList: types.AccessList{},
+			V:          big.NewInt(0),
+			R:          big.NewInt(0),
+			S:          big.NewInt(0),
+		})
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{TxHash: tx.Hash(), GasUsed: txGas})
+	}
+	txs = append(txs, qiTxs...)
+	block := wo.WithBody(header, txs, nil, nil, nil, nil)
+	b.blocks = append(b.blocks, block)
+	b.receipts[block.Hash()] = receipts
+	return block
+}
+
+func newTestBackend() *testBackend {
+	backend := &testBackend{
+		db:          rawdb.NewMemoryDatabase(log.Global),
+		receipts:    make(map[common.Hash]types.Receipts),
+		minGasPrice: big.NewInt(110),
+	}
+	backend.addBlock(0, nil, 0)
+	return backend
+}
+
+func TestFeeHistory(t *testing.T) {
+	backend := newTestBackend()
+	backend.addBlock(200000, []int64{300, 100, 200}, 21000)
+
+	// The Qi transaction spends a utxo of denomination 5 to pay one of
+	// denomination 3, leaving the difference as fee
+	qiTx := types.NewTx(&types.QiTx{
+		ChainID: big.NewInt(1),
+		TxIn:    types.TxIns{{PreviousOutPoint: types.OutPoint{TxHash: common.Hash{1}, Index: 0}}},
+		TxOut:   types.TxOuts{{Denomination: 3, Address: common.HexToAddress("0x0011111111111111111111111111111111111111", testLocation).Bytes()}},
+	})
+	parent := backend.blocks[1]
+	rawdb.WriteUTXOSetSize(backend.db, parent.Hash(), 1000)
+	block := backend.addBlock(9500000, []int64{150}, 50000, qiTx)
+	spent := []*types.SpentUtxoEntry{{OutPoint: types.OutPoint{TxHash: common.Hash{1}, Index: 0}, UtxoEntry: types.NewUtxoEntry(&types.TxOut{Denomination: 5})}}
+	require.NoError(t, rawdb.WriteSpentUTXOs(backend.db, block.Hash(), spent))
+
+	oracle := NewOracle(backend, DefaultConfig, log.Global)
+	history, err := oracle.FeeHistory(context.Background(), 10, rpc.LatestBlockNumber, []float64{0, 50, 100})
+	require.NoError(t, err)
+
+	qiGas := types.CalculateBlockQiTxGas(qiTx, math.Log(1000), testLocation)
+	qiGasPrice := misc.QiToQuai(parent, new(big.Int).Sub(types.Denominations[5], types.Denominations[3]))
+	qiGasPrice.Div(qiGasPrice, new(big.Int).SetUint64(qiGas))
+	require.Positive(t, qiGasPrice.Sign())
+	require.Equal(t, big.NewInt(0), history.OldestBlock)
+	require.Equal(t, []*big.Int{big.NewInt(100), big.NewInt(101), big.NewInt(102), big.NewInt(110)}, history.BaseFee)
+	require.Equal(t, []float64{0, 0.02, 0.95}, history.GasUsedRatio)
+	require.Equal(t, []float64{0, 0, float64(qiGas) / 10000000}, history.QiGasUsedRatio)
+	require.Equal(t, [][]*big.Int{
+		{new(big.Int), new(big.Int), new(big.Int)},
+		{big.NewInt(100), big.NewInt(200), big.NewInt(300)},
+		{big.NewInt(150), big.NewInt(150), big.NewInt(150)},
+	}, history.Reward)
+	require.Equal(t, []*big.Int{qiGasPrice, qiGasPrice, qiGasPrice}, history.QiReward[2])
+
+	// The range is capped to the blocks before the last block, and the next
+	// base fee of a past range is the base fee of the following block
+	history, err = oracle.FeeHistory(context.Background(), 5, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(0), history.OldestBlock)
+	require.Equal(t, []*big.Int{big.NewInt(100), big.NewInt(101), big.NewInt(102)}, history.BaseFee)
+	require.Nil(t, history.Reward)
+
+	_, err = oracle.FeeHistory(context.Background(), 1, 3, nil)
+	require.ErrorIs(t, err, errRequestBeyondHead)
+	_, err = oracle.FeeHistory(context.Background(), 1, rpc.LatestBlockNumber, []float64{50, 10})
+	require.ErrorIs(t, err, errInvalidPercentile)
+	_, err = oracle.FeeHistory(context.Background(), 1, rpc.LatestBlockNumber, []float64{101})
+	require.ErrorIs(t, err, errInvalidPercentile)
+}
+
+func TestSuggestFees(t *testing.T) {
+	backend := newTestBackend()
+	for i := 0; i < 4; i++ {
+		// Every other block is full and only includes the higher fees
+		if i%2 == 0 {
+			backend.addBlock(9500000, []int64{400, 500, 600}, 21000)
+		} else {
+			backend.addBlock(100000, []int64{120, 200, 900}, 21000)
+		}
+	}
+	oracle := NewOracle(backend, DefaultConfig, log.Global)
+	suggestions, err := oracle.SuggestFees(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, big.NewInt(110), suggestions.BaseFee)
+	require.Equal(t, big.NewInt(400), suggestions.Quai.Low.Fee)
+	require.Equal(t, big.NewInt(500), suggestions.Quai.Medium.Fee)
+	require.Equal(t, big.NewInt(900), suggestions.Quai.High.Fee)
+	// The low fee is included by all blocks, which are five seconds apart
+	require.Equal(t, 5*time.Second, suggestions.Quai.Low.InclusionTime)
+	require.Equal(t, 5*time.Second, suggestions.Quai.High.InclusionTime)
+
+	// Without Qi transactions the Qi fees fall back to the base fee, which
+	// only the blocks that were not full accept
+	require.Equal(t, big.NewInt(110), suggestions.Qi.Low.Fee)
+	require.Equal(t, big.NewInt(110), suggestions.Qi.High.Fee)
+	require.Equal(t, 10*time.Second, suggestions.Qi.Medium.InclusionTime)
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	goquai "github.com/dominant-strategies/go-quai"
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core"
 	"github.com/dominant-strategies/go-quai/core/types"
@@ -28,6 +29,10 @@ const (
 	c_recentBlockReqTimeout = 1 * time.Minute
 	// c_primeBlockSyncDepth is how far back the prime block downloading will start
 	c_primeBlockSyncDepth = 500
+	// c_txAnnounceChanSize is the size of the channel queueing the transactions to announce
+	c_txAnnounceChanSize = 4096
+	// c_txAnnounceInterval is the interval at which queued transactions are announced
+	c_txAnnounceInterval = 500 * time.Millisecond
 )
 
 var (
@@ -39,13 +44,15 @@ type handler struct {
 	nodeLocation    common.Location
 	p2pBackend      NetworkingAPI
 	core            *core.Core
+	syncer          *syncer
 	missingBlockCh  chan types.BlockRequest
 	missingBlockSub event.Subscription
 	wg              sync.WaitGroup
 	quitCh          chan struct{}
 	logger          *log.Logger
 
-	txs types.Transactions
+	txs          types.Transactions
+	txAnnounceCh chan common.Hash
 
 	recentBlockReqCache *expireLru.LRU[common.Hash, interface{}] // cache the latest requests on a 1 min timer
 
@@ -53,15 +60,17 @@ type handler struct {
 	cancelFunc context.CancelFunc
 }
 
-func newHandler(p2pBackend NetworkingAPI, core *core.Core, nodeLocation common.Location, logger *log.Logger) *handler {
+func newHandler(p2pBackend NetworkingAPI, core *core.Core, nodeLocation common.Location, snapSync bool, logger *log.Logger) *handler {
 	ctx, cancel := context.WithCancel(context.Background())
 	handler := &handler{
 		nodeLocation: nodeLocation,
 		p2pBackend:   p2pBackend,
 		core:         core,
+		syncer:       newSyncer(p2pBackend, core, nodeLocation, snapSync, logger),
 		quitCh:       make(chan struct{}),
 		logger:       logger,
 		txs:          make(types.Transactions, 0),
+		txAnnounceCh: make(chan common.Hash, c_txAnnounceChanSize),
 		ctx:          ctx,
 		cancelFunc:   cancel,
 	}
@@ -79,6 +88,14 @@ func (h *handler) Start() {
 	if nodeCtx == common.PRIME_CTX {
 		h.wg.Add(1)
 		go h.checkNextPrimeBlock()
+	} else {
+		h.wg.Add(1)
+		go h.syncLoop()
+	}
+
+	if nodeCtx == common.ZONE_CTX {
+		h.wg.Add(1)
+		go h.txAnnounceLoop()
 	}
 }
 
@@ -153,6 +170,82 @@ func (h *handler) missingBlockLoop() {
 	}
 }
 
+// syncLoop downloads the region or zone chain header-first from the peers
+func (h *handler) syncLoop() {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Fatal("Go-Quai Panicked")
+		}
+	}()
+	defer h.wg.Done()
+
+	h.syncer.loop(h.ctx)
+}
+
+// SyncProgress returns the progress of the header-first sync of the region or
+// zone chain, or nil if it is not behind its peers.
+func (h *handler) SyncProgress() *goquai.SyncProgress {
+	return h.syncer.Progress()
+}
+
+// AnnounceTx queues the hash of a transaction added to the pool to be
+// announced to the peers of the zone, which fetch the transaction from the
+// node if they do not have it yet. The hash is dropped if the queue is full.
+func (h *handler) AnnounceTx(hash common.Hash) {
+	select {
+	case h.txAnnounceCh <- hash:
+	default:
+		h.logger.WithField("hash", hash).Warn("Transaction announcement queue full, dropping announcement")
+	}
+}
+
+// txAnnounceLoop broadcasts the queued transaction hashes every
+// c_txAnnounceInterval, or as soon as an announcement is full
+func (h *handler) txAnnounceLoop() {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Fatal("Go-Quai Panicked")
+		}
+	}()
+	defer h.wg.Done()
+
+	announceTimer := time.NewTicker(c_txAnnounceInterval)
+	defer announceTimer.Stop()
+
+	hashes := make(common.Hashes, 0, protocol.C_MaxTxAnnouncementHashes)
+	announce := func() {
+		if len(hashes) == 0 {
+			return
+		}
+		if err := h.p2pBackend.Broadcast(h.nodeLocation, hashes); err != nil {
+			h.logger.WithFields(log.Fields{
+				"count": len(hashes),
+				"err":   err,
+			}).Warn("Failed to announce transactions")
+		}
+		hashes = make(common.Hashes, 0, protocol.C_MaxTxAnnouncementHashes)
+	}
+	for {
+		select {
+		case hash := <-h.txAnnounceCh:
+			hashes = append(hashes, hash)
+			if len(hashes) >= protocol.C_MaxTxAnnouncementHashes {
+				announce()
+			}
+		case <-announceTimer.C:
+			announce()
+		case <-h.quitCh:
+			return
+		}
+	}
+}
+
 // checkNextPrimeBlock runs every c_checkNextPrimeBlockInterval and ask the peer for the next Block
 func (h *handler) checkNextPrimeBlock() {
 	defer func() {
@@ -230,9 +323,10 @@ func (h *handler) GetNextPrimeBlock(number *big.Int) error {
 	resultCh := h.p2pBackend.Request(h.nodeLocation, new(big.Int).Add(number, big.NewInt(1)), []*types.WorkObjectBlockView{})
 	blocks := <-resultCh
 	if blocks != nil {
-		// peer returns a slice of blocks from the requested number
+		// peer returns a slice of blocks from the requested number, which
+		// is shorter close to the tip of its chain
 		workObjects := blocks.([]*types.WorkObjectBlockView)
-		if len(workObjects) != protocol.C_NumPrimeBlocksToDownload {
+		if len(workObjects) == 0 || len(workObjects) > protocol.C_NumPrimeBlocksToDownload {
 			h.logger.Error("did not get expected number of workobjects in prime")
 			return nil
 		}
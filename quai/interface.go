@@ -2,13 +2,16 @@ package quai
 
 import (
 	"math/big"
+	"net"
 
 	"github.com/dominant-strategies/go-quai/common"
 	chain "github.com/dominant-strategies/go-quai/core"
 	"github.com/dominant-strategies/go-quai/core/types"
 	"github.com/dominant-strategies/go-quai/internal/quaiapi"
+	"github.com/dominant-strategies/go-quai/p2p"
 
 	"github.com/dominant-strategies/go-quai/trie"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core"
 )
 
@@ -34,6 +37,23 @@ type ConsensusAPI interface {
 	// and return the data in the trie node.
 	GetTrieNode(hash common.Hash, location common.Location) *trie.TrieNodeResponse
 
+	// Asks the consensus backend for a range of the UTXO set of a block, for a
+	// peer snap syncing the state at the block
+	GetUtxoRange(req *types.UtxoRangeRequest, location common.Location) *types.UtxoRange
+
+	// Asks the consensus backend for the state of a block kept outside of its
+	// tries and UTXO set, for a peer snap syncing the state at the block
+	GetSyncBlockState(hash common.Hash, location common.Location) *types.SyncBlockState
+
+	// Asks the consensus backend for the transactions of its pool with the
+	// given hashes, for a peer they were announced to
+	LookupTransactions(hashes common.Hashes, location common.Location) types.Transactions
+
+	// Fetches the announced transactions unknown to the pool from the peer
+	// which announced them and adds them to the pool. Returns whether the
+	// announcement should be relayed to other peers.
+	ValidateTxAnnouncement(peer core.PeerID, hashes common.Hashes, location common.Location) pubsub.ValidationResult
+
 	// GetBackend gets the backend for the given location
 	GetBackend(nodeLocation common.Location) *quaiapi.Backend
 
@@ -82,6 +102,9 @@ type NetworkingAPI interface {
 	// Specify location, data hash, and data type to request
 	Request(location common.Location, requestData interface{}, responseDataType interface{}) chan interface{}
 
+	// Method to request data from a given peer, and wait for its response
+	RequestFromPeer(peer core.PeerID, location common.Location, requestData interface{}, responseDataType interface{}) (interface{}, error)
+
 	// Adjust a peer's quality score
 	AdjustPeerQuality(core.PeerID, string, func(int) int)
 
@@ -91,4 +114,15 @@ type NetworkingAPI interface {
 	UnprotectPeer(core.PeerID)
 	// BanPeer will close the connection and prevent future connections with this peer
 	BanPeer(core.PeerID)
+
+	// Returns the connected peers
+	Peers() []p2p.PeerInfo
+	// Connects to the peer at the given multiaddress
+	AddPeer(addr string) error
+	// Closes the connection and prevents future connections with this peer
+	BlockPeer(core.PeerID) error
+	// Allows connections with the addresses of the subnet again
+	UnblockSubnet(*net.IPNet) error
+	// Returns the blocked peers, addresses and subnets
+	ListBlocked() p2p.BlockedPeers
 }
@@ -2,7 +2,10 @@ package quai
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"runtime/debug"
+	"sync"
 
 	"github.com/dominant-strategies/go-quai/common"
 	"github.com/dominant-strategies/go-quai/core"
@@ -13,6 +16,7 @@ import (
 	"github.com/dominant-strategies/go-quai/p2p"
 	"github.com/dominant-strategies/go-quai/rpc"
 	"github.com/dominant-strategies/go-quai/trie"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -37,14 +41,26 @@ var (
 	workShareIngressCounter   = workObjectMetrics.WithLabelValues("workShares/ingress")
 	workShareKnownCounter     = workObjectMetrics.WithLabelValues("workShares/known")
 	workShareMaliciousCounter = workObjectMetrics.WithLabelValues("workShares/malicious")
+
+	// Transaction announcement metrics
+	txAnnouncementIngressCounter = workObjectMetrics.WithLabelValues("txAnnouncements/ingress")
+	txFetchedCounter             = workObjectMetrics.WithLabelValues("txAnnouncements/fetched")
 )
 
+// c_maxTxFetches is the max number of announcements whose transactions are
+// fetched from the announcing peers at once
+const c_maxTxFetches = 16
+
 // QuaiBackend implements the quai consensus protocol
 type QuaiBackend struct {
 	p2pBackend        NetworkingAPI // Interface for all the P2P methods the libp2p exposes to consensus
 	primeApiBackend   *quaiapi.Backend
 	regionApiBackends []*quaiapi.Backend
 	zoneApiBackends   [][]*quaiapi.Backend
+
+	txFetchSem chan struct{}  // Limits the announcements fetched at once
+	txFetching sync.Map       // Hashes of the announced transactions being fetched
+	txFetchWg  sync.WaitGroup // Waits for the announced transactions being fetched
 }
 
 // Create a new instance of the QuaiBackend consensus service
@@ -53,7 +69,11 @@ func NewQuaiBackend() (*QuaiBackend, error) {
 	for i := 0; i < common.MaxRegions; i++ {
 		zoneBackends[i] = make([]*quaiapi.Backend, common.MaxZones)
 	}
-	return &QuaiBackend{regionApiBackends: make([]*quaiapi.Backend, common.MaxZones), zoneApiBackends: zoneBackends}, nil
+	return &QuaiBackend{
+		regionApiBackends: make([]*quaiapi.Backend, common.MaxZones),
+		zoneApiBackends:   zoneBackends,
+		txFetchSem:        make(chan struct{}, c_maxTxFetches),
+	}, nil
 }
 
 // Adds the p2pBackend into the given QuaiBackend
@@ -155,6 +175,10 @@ func (qbe *QuaiBackend) OnNewBroadcast(sourcePeer p2p.PeerID, Id string, topic s
 				txCountersBySlice[sliceName] = newCounter
 			}
 		}
+	case common.Hashes:
+		// The announced transactions were fetched and added to the pool
+		// while validating the announcement
+		txAnnouncementIngressCounter.Inc()
 	default:
 		log.Global.WithFields(log.Fields{
 			"peer":     sourcePeer,
@@ -167,10 +191,166 @@ func (qbe *QuaiBackend) OnNewBroadcast(sourcePeer p2p.PeerID, Id string, topic s
 	return true
 }
 
-// GetTrieNode returns the TrieNodeResponse for a given hash
+// GetTrieNode returns the state trie node, ETX set trie node or contract code
+// of the given hash, or nil if it is not found
 func (qbe *QuaiBackend) GetTrieNode(hash common.Hash, location common.Location) *trie.TrieNodeResponse {
-	// Example/mock implementation
-	panic("todo")
+	be := qbe.GetBackend(location)
+	if be == nil {
+		return nil
+	}
+	data, err := (*be).StateTrieNode(hash)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return &trie.TrieNodeResponse{NodeData: data}
+}
+
+// GetUtxoRange returns the requested range of the UTXO set of a block, or nil
+// if the set of the block cannot be served
+func (qbe *QuaiBackend) GetUtxoRange(req *types.UtxoRangeRequest, location common.Location) *types.UtxoRange {
+	be := qbe.GetBackend(location)
+	if be == nil {
+		return nil
+	}
+	utxoRange, err := (*be).UtxoRange(req.BlockHash, req.Start)
+	if err != nil {
+		(*be).Logger().WithFields(log.Fields{
+			"block": req.BlockHash,
+			"err":   err,
+		}).Debug("Cannot serve utxo range")
+		return nil
+	}
+	return utxoRange
+}
+
+// GetSyncBlockState returns the block state a snap syncing node downloads for
+// the given block, or nil if it is not found
+func (qbe *QuaiBackend) GetSyncBlockState(hash common.Hash, location common.Location) *types.SyncBlockState {
+	be := qbe.GetBackend(location)
+	if be == nil {
+		return nil
+	}
+	return (*be).SyncBlockState(hash)
+}
+
+// LookupTransactions returns the transactions of the pool with the given
+// hashes, skipping the ones which are not in the pool
+func (qbe *QuaiBackend) LookupTransactions(hashes common.Hashes, location common.Location) types.Transactions {
+	be := qbe.GetBackend(location)
+	if be == nil {
+		return nil
+	}
+	var txs types.Transactions
+	for _, hash := range hashes {
+		if tx := (*be).GetPoolTransaction(hash); tx != nil {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// ValidateTxAnnouncement relays the announcements whose transactions are all
+// in the pool already, so that the peers it is relayed to can fetch them from
+// this node. The other announcements are ignored, and their transactions are
+// fetched from the announcing peer in the background, so that validation never
+// waits on the network. Once they are added to the pool, which validates them
+// like any remote transaction, this node announces them itself.
+func (qbe *QuaiBackend) ValidateTxAnnouncement(peer p2p.PeerID, hashes common.Hashes, location common.Location) pubsub.ValidationResult {
+	be := qbe.GetBackend(location)
+	if be == nil || *be == nil {
+		return pubsub.ValidationIgnore
+	}
+	backend := *be
+	if !backend.ProcessingState() || backend.NodeCtx() != common.ZONE_CTX {
+		return pubsub.ValidationIgnore
+	}
+	known := true
+	unknown := make(common.Hashes, 0, len(hashes))
+	for _, hash := range hashes {
+		if backend.GetPoolTransaction(hash) != nil {
+			continue
+		}
+		known = false
+		// Transactions announced by several peers are fetched only once
+		if _, fetching := qbe.txFetching.LoadOrStore(hash, struct{}{}); !fetching {
+			unknown = append(unknown, hash)
+		}
+	}
+	if known {
+		return pubsub.ValidationAccept
+	}
+	if len(unknown) == 0 {
+		return pubsub.ValidationIgnore
+	}
+	select {
+	case qbe.txFetchSem <- struct{}{}:
+		qbe.txFetchWg.Add(1)
+		go qbe.fetchAnnouncedTxs(backend, peer, unknown, location)
+	default:
+		for _, hash := range unknown {
+			qbe.txFetching.Delete(hash)
+		}
+	}
+	return pubsub.ValidationIgnore
+}
+
+// fetchAnnouncedTxs fetches the announced transactions from the peer, adds them
+// to the pool and announces the ones the pool accepted.
+func (qbe *QuaiBackend) fetchAnnouncedTxs(backend quaiapi.Backend, peer p2p.PeerID, hashes common.Hashes, location common.Location) {
+	defer func() {
+		if r := recover(); r != nil {
+			backend.Logger().WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Error("Go-Quai Panicked")
+		}
+	}()
+	defer qbe.txFetchWg.Done()
+	defer func() { <-qbe.txFetchSem }()
+	defer func() {
+		for _, hash := range hashes {
+			qbe.txFetching.Delete(hash)
+		}
+	}()
+
+	resp, err := qbe.p2pBackend.RequestFromPeer(peer, location, hashes, types.Transactions{})
+	if err != nil {
+		backend.Logger().WithFields(log.Fields{
+			"peer": peer,
+			"err":  err,
+		}).Debug("Failed to fetch announced transactions")
+		return
+	}
+	txs, ok := resp.(types.Transactions)
+	if !ok || len(txs) == 0 {
+		return
+	}
+	txFetchedCounter.Add(float64(len(txs)))
+	// The errors of the pool are not in the order of the transactions, so the
+	// transactions it accepted are looked up in it
+	for _, err := range backend.AddPeerTxs(txs) {
+		if err != nil && !errors.Is(err, core.ErrAlreadyKnown) {
+			backend.Logger().WithFields(log.Fields{
+				"peer": peer,
+				"err":  err,
+			}).Debug("Rejected announced transaction")
+		}
+	}
+	added := make(common.Hashes, 0, len(txs))
+	for _, tx := range txs {
+		if backend.GetPoolTransaction(tx.Hash()) != nil {
+			added = append(added, tx.Hash())
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	if err := qbe.p2pBackend.Broadcast(location, added); err != nil {
+		backend.Logger().WithFields(log.Fields{
+			"count": len(added),
+			"err":   err,
+		}).Warn("Failed to announce fetched transactions")
+	}
 }
 
 // Returns the current block height for the given location
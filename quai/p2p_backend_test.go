@@ -0,0 +1,152 @@
+package quai
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/internal/quaiapi"
+	"github.com/dominant-strategies/go-quai/log"
+	p2p "github.com/dominant-strategies/go-quai/p2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// poolBackend is a zone backend whose pool accepts the transactions with a
+// non zero nonce.
+type poolBackend struct {
+	quaiapi.Backend
+	pool map[common.Hash]*types.Transaction
+}
+
+func (b *poolBackend) ProcessingState() bool { return true }
+func (b *poolBackend) NodeCtx() int          { return common.ZONE_CTX }
+func (b *poolBackend) Logger() *log.Logger   { return log.Global }
+func (b *poolBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	return b.pool[hash]
+}
+
+func (b *poolBackend) AddPeerTxs(txs types.Transactions) []error {
+	errs := make([]error, len(txs))
+	for i, tx := range txs {
+		if _, ok := b.pool[tx.Hash()]; ok {
+			errs[i] = core.ErrAlreadyKnown
+		} else if tx.Nonce() == 0 {
+			errs[i] = core.ErrNonceTooLow
+		} else {
+			b.pool[tx.Hash()] = tx
+		}
+	}
+	return errs
+}
+
+// txNetwork serves the transactions a peer announced, and records the hashes
+// it was asked for and the ones it announced.
+type txNetwork struct {
+	NetworkingAPI
+	txs       map[common.Hash]*types.Transaction
+	requested common.Hashes
+	announced common.Hashes
+}
+
+func (n *txNetwork) Broadcast(location common.Location, data interface{}) error {
+	n.announced = append(n.announced, data.(common.Hashes)...)
+	return nil
+}
+
+func (n *txNetwork) RequestFromPeer(peer p2p.PeerID, location common.Location, requestData interface{}, responseDataType interface{}) (interface{}, error) {
+	hashes := requestData.(common.Hashes)
+	n.requested = append(n.requested, hashes...)
+	if n.txs == nil {
+		return nil, errors.New("peer did not respond in time")
+	}
+	var txs types.Transactions
+	for _, hash := range hashes {
+		if tx, ok := n.txs[hash]; ok {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func newAnnouncedTx(nonce uint64) *types.Transaction {
+	to := common.HexToAddress("0x0094f5ea0ba39494ce83a213fffba74279579268", common.Location{0, 0})
+	return types.NewTx(&types.QuaiTx{
+		ChainID:    big.NewInt(1),
+		Nonce:      nonce,
+		MinerTip:   big.NewInt(1),
+		GasPrice:   big.NewInt(1),
+		Gas:        21000,
+		To:         &to,
+		Value:      big.NewInt(0),
+		AccessList: types.AccessList{},
+		V:          big.NewInt(0),
+		R:          big.NewInt(0),
+		S:          big.NewInt(0),
+	})
+}
+
+func TestValidateTxAnnouncement(t *testing.T) {
+	location := common.Location{0, 0}
+	known, valid, invalid := newAnnouncedTx(1), newAnnouncedTx(2), newAnnouncedTx(0)
+
+	var backend quaiapi.Backend = &poolBackend{pool: map[common.Hash]*types.Transaction{known.Hash(): known}}
+	network := &txNetwork{}
+	qbe, _ := NewQuaiBackend()
+	qbe.SetP2PApiBackend(network)
+	qbe.SetApiBackend(&backend, location)
+
+	// Announcements of known transactions are relayed without fetching them
+	if res := qbe.ValidateTxAnnouncement("peer", common.Hashes{known.Hash()}, location); res != pubsub.ValidationAccept {
+		t.Fatalf("got %v for known transactions, want accept", res)
+	}
+	qbe.txFetchWg.Wait()
+	if len(network.requested) != 0 {
+		t.Fatalf("requested %d known transactions", len(network.requested))
+	}
+
+	// Announcements of unknown transactions are not relayed, and nothing is
+	// announced if the peer does not respond
+	if res := qbe.ValidateTxAnnouncement("peer", common.Hashes{valid.Hash()}, location); res != pubsub.ValidationIgnore {
+		t.Fatalf("got %v for unknown transactions, want ignore", res)
+	}
+	qbe.txFetchWg.Wait()
+	if len(network.requested) != 1 || len(network.announced) != 0 {
+		t.Fatalf("requested %v and announced %v without a response", network.requested, network.announced)
+	}
+
+	// Only the unknown transactions are fetched, and the ones added to the
+	// pool are announced by the node
+	network.txs = map[common.Hash]*types.Transaction{valid.Hash(): valid, invalid.Hash(): invalid}
+	network.requested = nil
+	if res := qbe.ValidateTxAnnouncement("peer", common.Hashes{known.Hash(), valid.Hash(), invalid.Hash()}, location); res != pubsub.ValidationIgnore {
+		t.Fatalf("got %v for unknown transactions, want ignore", res)
+	}
+	qbe.txFetchWg.Wait()
+	if len(network.requested) != 2 || network.requested[0] != valid.Hash() || network.requested[1] != invalid.Hash() {
+		t.Fatalf("requested %v, want the unknown transactions", network.requested)
+	}
+	if backend.GetPoolTransaction(valid.Hash()) == nil {
+		t.Fatal("fetched transaction not added to the pool")
+	}
+	if len(network.announced) != 1 || network.announced[0] != valid.Hash() {
+		t.Fatalf("announced %v, want the added transaction", network.announced)
+	}
+
+	// Once fetched, announcements of the transaction are relayed
+	if res := qbe.ValidateTxAnnouncement("peer", common.Hashes{valid.Hash()}, location); res != pubsub.ValidationAccept {
+		t.Fatalf("got %v for a fetched transaction, want accept", res)
+	}
+
+	// Transactions rejected by the pool are fetched again when announced
+	network.requested = nil
+	if res := qbe.ValidateTxAnnouncement("peer", common.Hashes{invalid.Hash()}, location); res != pubsub.ValidationIgnore {
+		t.Fatalf("got %v for an invalid transaction, want ignore", res)
+	}
+	qbe.txFetchWg.Wait()
+	if len(network.requested) != 1 || len(network.announced) != 1 {
+		t.Fatalf("requested %v and announced %v for an invalid transaction", network.requested, network.announced)
+	}
+}
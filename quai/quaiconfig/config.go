@@ -31,6 +31,7 @@ import (
 	"github.com/dominant-strategies/go-quai/metrics_config"
 	"github.com/dominant-strategies/go-quai/node"
 	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/gasprice"
 )
 
 type QuaistatsConfig struct {
@@ -63,6 +64,7 @@ var Defaults = Config{
 		Recommit: 3 * time.Second,
 	},
 	TxPool:      core.DefaultTxPoolConfig,
+	GPO:         gasprice.DefaultConfig,
 	RPCGasCap:   params.GasCeil,
 	RPCTxFeeCap: 10000, // 10000 quai
 }
@@ -124,6 +126,9 @@ type Config struct {
 	// Transaction pool options
 	TxPool core.TxPoolConfig
 
+	// Fee oracle options
+	GPO gasprice.Config
+
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
@@ -160,6 +165,18 @@ type Config struct {
 	// IndexAddressUtxos enables or disables address utxo indexing
 	IndexAddressUtxos bool
 
+	// IndexWorkShares enables or disables indexing the rewards paid to the
+	// coinbases of blocks and workshares
+	IndexWorkShares bool
+
+	// SnapSync enables downloading the state of a recent block from the peers
+	// when a new zone node syncs, instead of processing the whole chain
+	SnapSync bool
+
+	// TimeToStartTx, if set, overrides the number of zone blocks which are
+	// given no gas for transactions
+	TimeToStartTx *uint64 `toml:",omitempty"`
+
 	// DefaultGenesisHash is the hard coded genesis hash
 	DefaultGenesisHash common.Hash
 }
@@ -177,6 +194,7 @@ func CreateProgpowConsensusEngine(stack *node.Node, nodeLocation common.Location
 	}
 	engine := progpow.New(progpow.Config{
 		PowMode:            config.PowMode,
+		GenesisAllocDir:    config.GenesisAllocDir,
 		NotifyFull:         config.NotifyFull,
 		DurationLimit:      config.DurationLimit,
 		NodeLocation:       nodeLocation,
@@ -198,9 +216,12 @@ func CreateBlake3ConsensusEngine(stack *node.Node, nodeLocation common.Location,
 		logger.Warn("Progpow used in test mode")
 	case blake3pow.ModeShared:
 		logger.Warn("Progpow used in shared mode")
+	case blake3pow.ModeDev:
+		logger.Warn("Blake3pow used in dev mode")
 	}
 	engine := blake3pow.New(blake3pow.Config{
 		PowMode:            config.PowMode,
+		GenesisAllocDir:    config.GenesisAllocDir,
 		NotifyFull:         config.NotifyFull,
 		DurationLimit:      config.DurationLimit,
 		NodeLocation:       nodeLocation,
@@ -0,0 +1,535 @@
+package quai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/state"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/crypto/multiset"
+	"github.com/dominant-strategies/go-quai/ethdb"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/trie"
+)
+
+const (
+	// c_snapSyncMinDistance is how far the peers have to be ahead of a new
+	// zone node for it to snap sync the state instead of processing the chain
+	c_snapSyncMinDistance = 1024
+	// c_snapPivotDepth is how far below the highest block of the peers the
+	// snap sync pivot is picked, to keep it clear of reorgs
+	c_snapPivotDepth = 64
+	// c_snapPivotMaxAge is how far the highest block of the peers can move
+	// past the pivot before a new pivot is picked. Peers only serve the UTXO
+	// set of blocks close to their head, so this has to stay well below the
+	// depth they serve it at.
+	c_snapPivotMaxAge = 512
+	// c_snapProbeLimit bounds the number of requests probing the highest
+	// block of the peers
+	c_snapProbeLimit = 64
+	// c_stateFetchers is the number of state requests made concurrently
+	c_stateFetchers = 8
+	// c_trieNodeBatch is the number of trie nodes scheduled per batch
+	c_trieNodeBatch = 256
+	// c_utxoSegments is the number of parts of the outpoint space whose UTXOs
+	// are downloaded concurrently
+	c_utxoSegments = 16
+	// c_stateRequestRetries is how many times a state request is retried
+	// before the state sync round fails
+	c_stateRequestRetries = 3
+	// c_stateRetryDelay is the delay between the retries of a state request
+	c_stateRetryDelay = 2 * time.Second
+	// c_pivotStateQuorum is the number of peers which have to serve the same
+	// token choices and betas of the pivot, which the pivot does not commit to
+	c_pivotStateQuorum = 2
+)
+
+// snapSyncPivot returns the number of the block whose state is snap synced,
+// or zero if the chain is synced by processing its blocks. A new zone node far
+// behind its peers picks a pivot below their highest block, and the pivot is
+// moved up once the chain reaches it if the peers have moved too far past it.
+func (s *syncer) snapSyncPivot(ctx context.Context, head *types.WorkObject) uint64 {
+	nodeCtx := s.nodeLocation.Context()
+	number := head.NumberU64(nodeCtx)
+	pivot := s.core.SnapSyncPivot()
+	if pivot == 0 && number != 0 {
+		// The chain already processes its blocks
+		s.snapSync = false
+		return 0
+	}
+	if pivot != 0 && number < pivot {
+		return pivot
+	}
+	highest := s.probeHeight(ctx, number)
+	s.lock.Lock()
+	if highest > s.highestBlock {
+		s.highestBlock = highest
+	}
+	s.lock.Unlock()
+
+	if pivot == 0 {
+		if highest < number+c_snapSyncMinDistance {
+			s.logger.WithField("highest", highest).Info("Peers are close to genesis, syncing state by processing blocks")
+			s.snapSync = false
+			return 0
+		}
+	} else if highest < pivot+c_snapPivotMaxAge || hasUtxoSnapshot(s.core.Database(), head) {
+		// The pivot is kept if its UTXO set was imported from a snapshot, as
+		// only the UTXO set is served close to the head of the peers
+		return pivot
+	}
+	newPivot := highest - c_snapPivotDepth
+	if err := s.core.SetSnapSyncPivot(newPivot); err != nil {
+		s.logger.WithField("err", err).Error("Failed to set the snap sync pivot")
+		return pivot
+	}
+	s.logger.WithFields(log.Fields{
+		"pivot":   newPivot,
+		"highest": highest,
+	}).Info("Snap syncing the state at the pivot block")
+	return newPivot
+}
+
+// probeHeight returns the number of the highest block the peers serve above
+// the given one, probing exponentially growing numbers and then bisecting.
+func (s *syncer) probeHeight(ctx context.Context, known uint64) uint64 {
+	low, high := known, uint64(0)
+	for step, probes := uint64(1), 0; high == 0; step, probes = step*2, probes+1 {
+		if probes == c_snapProbeLimit || ctx.Err() != nil {
+			return low
+		}
+		if s.hasHeader(ctx, low+step) {
+			low += step
+		} else {
+			high = low + step
+		}
+	}
+	for probes := 0; high-low > 1 && probes < c_snapProbeLimit && ctx.Err() == nil; probes++ {
+		mid := low + (high-low)/2
+		if s.hasHeader(ctx, mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+// hasHeader reports whether a peer serves the header of the given number.
+func (s *syncer) hasHeader(ctx context.Context, number uint64) bool {
+	for result := range s.p2pBackend.Request(s.nodeLocation, new(big.Int).SetUint64(number), &types.WorkObjectHeaderView{}) {
+		if header, ok := result.(*types.WorkObjectHeaderView); ok && header != nil && header.NumberU64(s.nodeLocation.Context()) == number {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// syncState downloads the state at the pivot, which is the head of the chain,
+// and commits it so that the blocks following the pivot are processed on top
+// of it. The state tries are verified node by node against the roots of the
+// pivot, and the UTXO set against its UTXO root. The block states needed to
+// process the following blocks are not committed to by the pivot, and are
+// checked by processing those blocks.
+func (s *syncer) syncState(ctx context.Context, pivot *types.WorkObject) error {
+	s.logger.WithFields(log.Fields{
+		"number": pivot.NumberU64(s.nodeLocation.Context()),
+		"hash":   pivot.Hash(),
+	}).Info("Downloading the state of the snap sync pivot")
+
+	db := s.core.Database()
+	if err := s.syncTries(ctx, db, pivot); err != nil {
+		return err
+	}
+	var (
+		multiSet    *multiset.MultiSet
+		utxoSetSize uint64
+	)
+	if hasUtxoSnapshot(db, pivot) {
+		// The UTXO set at the pivot was imported from a snapshot
+		multiSet, utxoSetSize = rawdb.ReadMultiSet(db, pivot.Hash()), rawdb.ReadUTXOSetSize(db, pivot.Hash())
+	} else {
+		var err error
+		if multiSet, utxoSetSize, err = s.syncUtxos(ctx, db, pivot.Hash()); err != nil {
+			return err
+		}
+	}
+	if root := multiSet.Hash(); root != pivot.UTXORoot() {
+		return fmt.Errorf("downloaded utxo set does not match the pivot utxo root (remote: %x local: %x)", pivot.UTXORoot(), root)
+	}
+	states, err := s.syncBlockStates(ctx, pivot)
+	if err != nil {
+		return err
+	}
+	return s.core.CommitSnapSync(pivot, multiSet, utxoSetSize, states)
+}
+
+// syncTries downloads the missing nodes of the EVM state trie, with its storage
+// tries and contract code, and of the ETX set trie of the pivot.
+func (s *syncer) syncTries(ctx context.Context, db ethdb.Database, pivot *types.WorkObject) error {
+	scheds := []*trie.Sync{
+		state.NewStateSync(pivot.EVMRoot(), db, nil, nil),
+		trie.NewSync(pivot.EtxSetRoot(), db, nil, nil),
+	}
+	for _, sched := range scheds {
+		for sched.Pending() > 0 {
+			nodes, _, codes := sched.Missing(c_trieNodeBatch)
+			hashes := append(nodes, codes...)
+			if len(hashes) == 0 {
+				return errors.New("trie sync has pending nodes but schedules none")
+			}
+			data := make([][]byte, len(hashes))
+			err := s.parallel(ctx, len(hashes), func(i int) error {
+				result, err := s.fetchState(ctx, hashes[i], &trie.TrieNodeResponse{}, func(result interface{}) bool {
+					node, ok := result.(*trie.TrieNodeResponse)
+					return ok && node != nil && crypto.Keccak256Hash(node.NodeData) == hashes[i]
+				})
+				if err != nil {
+					return err
+				}
+				data[i] = result.(*trie.TrieNodeResponse).NodeData
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for i, hash := range hashes {
+				if err := sched.Process(trie.SyncResult{Hash: hash, Data: data[i]}); err != nil && err != trie.ErrAlreadyProcessed {
+					return err
+				}
+			}
+			batch := db.NewBatch()
+			if err := sched.Commit(batch); err != nil {
+				return err
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			s.lock.Lock()
+			s.pulledStates += uint64(len(hashes))
+			s.knownStates = s.pulledStates + uint64(sched.Pending())
+			s.lock.Unlock()
+		}
+	}
+	return nil
+}
+
+// syncUtxos replaces the UTXO set in the database with the set of the block
+// of the given hash downloaded from the peers, and returns its multiset and
+// size. The outpoint space is split in segments downloaded concurrently.
+func (s *syncer) syncUtxos(ctx context.Context, db ethdb.Database, blockHash common.Hash) (*multiset.MultiSet, uint64, error) {
+	// Delete what is left of an interrupted download, so that the set only
+	// holds the downloaded UTXOs
+	if err := core.DeleteUtxoSet(db); err != nil {
+		return nil, 0, err
+	}
+	multiSets := make([]*multiset.MultiSet, c_utxoSegments)
+	sizes := make([]uint64, c_utxoSegments)
+	err := s.parallel(ctx, c_utxoSegments, func(i int) error {
+		var err error
+		start, end := utxoSegment(i)
+		multiSets[i], sizes[i], err = s.syncUtxoSegment(ctx, db, blockHash, start, end)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	multiSet := multiset.New()
+	var size uint64
+	for i := range multiSets {
+		multiSet.Combine(multiSets[i])
+		size += sizes[i]
+	}
+	return multiSet, size, nil
+}
+
+// syncUtxoSegment downloads the UTXOs of the set of the given block from the
+// start outpoint up to the end one, or to the end of the set if end is nil.
+func (s *syncer) syncUtxoSegment(ctx context.Context, db ethdb.Database, blockHash common.Hash, start types.OutPoint, end *types.OutPoint) (*multiset.MultiSet, uint64, error) {
+	multiSet := multiset.New()
+	var size uint64
+	batch := db.NewBatch()
+	for {
+		req := &types.UtxoRangeRequest{BlockHash: blockHash, Start: start}
+		result, err := s.fetchState(ctx, req, &types.UtxoRange{}, func(result interface{}) bool {
+			utxoRange, ok := result.(*types.UtxoRange)
+			return ok && utxoRange != nil && utxoRange.Verify(req.Start) == nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		utxoRange := result.(*types.UtxoRange)
+		for _, utxo := range utxoRange.Utxos {
+			if end != nil && types.CompareOutPoints(utxo.OutPoint, *end) >= 0 {
+				break
+			}
+			if err := rawdb.CreateUTXO(batch, utxo.TxHash, utxo.Index, utxo.UtxoEntry); err != nil {
+				return nil, 0, err
+			}
+			multiSet.Add(types.UTXOHash(utxo.TxHash, utxo.Index, utxo.UtxoEntry).Bytes())
+			size++
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, 0, err
+			}
+			batch.Reset()
+		}
+		s.lock.Lock()
+		s.pulledStates += uint64(len(utxoRange.Utxos))
+		s.knownStates = s.pulledStates
+		s.lock.Unlock()
+
+		if utxoRange.Next == nil || (end != nil && types.CompareOutPoints(*utxoRange.Next, *end) >= 0) {
+			break
+		}
+		start = *utxoRange.Next
+	}
+	if err := batch.Write(); err != nil {
+		return nil, 0, err
+	}
+	return multiSet, size, nil
+}
+
+// syncBlockStates returns the block state of the pivot, and of the blocks
+// below it whose created UTXOs are trimmed by the blocks following it. The
+// created UTXO keys are rebuilt from the bodies of the blocks, so only the token
+// choices and betas of the pivot are downloaded, which the pivot does not
+// commit to and hence have to be served the same by c_pivotStateQuorum peers.
+func (s *syncer) syncBlockStates(ctx context.Context, pivot *types.WorkObject) (map[common.Hash]*types.SyncBlockState, error) {
+	nodeCtx := s.nodeLocation.Context()
+	var maxTrimDepth uint64
+	for denomination, depth := range types.TrimDepths {
+		if denomination <= types.MaxTrimDenomination && depth > maxTrimDepth {
+			maxTrimDepth = depth
+		}
+	}
+	number := pivot.NumberU64(nodeCtx)
+	first := uint64(1)
+	if number+1 > maxTrimDepth+first {
+		first = number + 1 - maxTrimDepth
+	}
+	states := make(map[common.Hash]*types.SyncBlockState, number+1-first)
+	for n := first; n <= number; n++ {
+		header := s.core.GetHeaderByNumber(n)
+		if header == nil {
+			return nil, fmt.Errorf("missing canonical block %d below the pivot", n)
+		}
+		block := s.core.GetBlockByHash(header.Hash())
+		if block == nil {
+			return nil, fmt.Errorf("missing body of block %d below the pivot", n)
+		}
+		createdUtxoKeys, err := core.CreatedUtxoKeys(block, s.nodeLocation)
+		if err != nil {
+			return nil, err
+		}
+		states[block.Hash()] = &types.SyncBlockState{CreatedUtxoKeys: createdUtxoKeys}
+	}
+	pivotState := states[pivot.Hash()]
+	if pivotState == nil {
+		return nil, errors.New("snap sync pivot is not canonical")
+	}
+	result, err := s.fetchAgreedState(ctx, pivot.Hash(), &types.SyncBlockState{}, func(result interface{}) bool {
+		blockState, ok := result.(*types.SyncBlockState)
+		return ok && verifySyncBlockState(blockState, pivotState.CreatedUtxoKeys) == nil
+	}, func(result interface{}) (common.Hash, error) {
+		blockState := result.(*types.SyncBlockState)
+		return syncBlockStateDigest(blockState.TokenChoiceSet, blockState.Betas)
+	})
+	if err != nil {
+		return nil, err
+	}
+	blockState := result.(*types.SyncBlockState)
+	pivotState.TokenChoiceSet, pivotState.Betas = blockState.TokenChoiceSet, blockState.Betas
+	return states, nil
+}
+
+// verifySyncBlockState checks that a block state served for the pivot has
+// token choices and betas, and creates the UTXOs rebuilt from the body of the
+// pivot. The keys are served sorted by denomination only, so they are compared
+// regardless of their order.
+func verifySyncBlockState(blockState *types.SyncBlockState, createdUtxoKeys [][]byte) error {
+	if blockState == nil {
+		return errors.New("missing block state")
+	}
+	if blockState.TokenChoiceSet == nil || blockState.Betas == nil {
+		return errors.New("missing token choices or betas of the pivot")
+	}
+	if len(blockState.CreatedUtxoKeys) != len(createdUtxoKeys) {
+		return fmt.Errorf("block state creates %d utxos, the block creates %d", len(blockState.CreatedUtxoKeys), len(createdUtxoKeys))
+	}
+	served, local := sortedUtxoKeys(blockState.CreatedUtxoKeys), sortedUtxoKeys(createdUtxoKeys)
+	for i := range served {
+		if !bytes.Equal(served[i], local[i]) {
+			return errors.New("created utxo keys do not match the block")
+		}
+	}
+	return nil
+}
+
+// sortedUtxoKeys returns a copy of the UTXO keys in byte order.
+func sortedUtxoKeys(keys [][]byte) [][]byte {
+	sorted := make([][]byte, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	return sorted
+}
+
+// syncBlockStateDigest returns the hash of the encoding of the token choices
+// and betas of a block state, by which the block states served by different
+// peers are compared.
+func syncBlockStateDigest(tokenChoiceSet *types.TokenChoiceSet, betas *types.Betas) (common.Hash, error) {
+	protoState, err := (&types.SyncBlockState{TokenChoiceSet: tokenChoiceSet, Betas: betas}).ProtoEncode()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(protoState)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// fetchState requests state data from the peers until one of them serves a
+// response passing the given check, retrying c_stateRequestRetries times.
+func (s *syncer) fetchState(ctx context.Context, reqData interface{}, respDataType interface{}, valid func(interface{}) bool) (interface{}, error) {
+	for attempt := 0; attempt <= c_stateRequestRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c_stateRetryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		for result := range s.p2pBackend.Request(s.nodeLocation, reqData, respDataType) {
+			if valid(result) {
+				return result, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, fmt.Errorf("no peer served the requested %T", respDataType)
+}
+
+// fetchAgreedState requests state data from the peers until c_pivotStateQuorum
+// of the responses to a request pass the given check and have the same digest,
+// retrying c_stateRequestRetries times. Each peer responds at most once to a
+// request, so the responses agreeing are served by different peers.
+func (s *syncer) fetchAgreedState(ctx context.Context, reqData interface{}, respDataType interface{}, valid func(interface{}) bool, digest func(interface{}) (common.Hash, error)) (interface{}, error) {
+	for attempt := 0; attempt <= c_stateRequestRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c_stateRetryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		votes := make(map[common.Hash]int)
+		for result := range s.p2pBackend.Request(s.nodeLocation, reqData, respDataType) {
+			if !valid(result) {
+				continue
+			}
+			hash, err := digest(result)
+			if err != nil {
+				continue
+			}
+			votes[hash]++
+			if votes[hash] >= c_pivotStateQuorum {
+				return result, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, fmt.Errorf("%d peers did not serve the same requested %T", c_pivotStateQuorum, respDataType)
+}
+
+// parallel calls fn for each index below n, with at most c_stateFetchers calls
+// running concurrently, and returns the first error encountered. No new calls
+// are made once one has failed.
+func (s *syncer) parallel(ctx context.Context, n int, fn func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		failed   = make(chan struct{})
+		fetchers = make(chan struct{}, c_stateFetchers)
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			close(failed)
+		})
+	}
+loop:
+	for i := 0; i < n; i++ {
+		select {
+		case fetchers <- struct{}{}:
+		case <-failed:
+			break loop
+		case <-ctx.Done():
+			fail(ctx.Err())
+			break loop
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					s.logger.WithFields(log.Fields{
+						"error":      r,
+						"stacktrace": string(debug.Stack()),
+					}).Error("Go-Quai Panicked")
+					fail(fmt.Errorf("state fetcher panicked: %v", r))
+				}
+			}()
+			defer func() { <-fetchers }()
+			defer wg.Done()
+			if err := fn(i); err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// utxoSegment returns the bounds of the i-th segment of the outpoint space,
+// split by the first byte of the transaction hash. The last segment has no
+// end.
+func utxoSegment(i int) (types.OutPoint, *types.OutPoint) {
+	var start types.OutPoint
+	start.TxHash[0] = byte(i * 256 / c_utxoSegments)
+	if i+1 == c_utxoSegments {
+		return start, nil
+	}
+	end := new(types.OutPoint)
+	end.TxHash[0] = byte((i + 1) * 256 / c_utxoSegments)
+	return start, end
+}
+
+// hasUtxoSnapshot reports whether the UTXO set at the given block was imported
+// from a UTXO snapshot. The multiset of a block is only stored before its state
+// is committed if it was written by the import.
+func hasUtxoSnapshot(db ethdb.Database, block *types.WorkObject) bool {
+	multiSet := rawdb.ReadMultiSet(db, block.Hash())
+	return multiSet != nil && multiSet.Hash() == block.UTXORoot()
+}
@@ -0,0 +1,187 @@
+package quai
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/chaingen"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto/multiset"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+// utxoNetwork serves UTXO range requests from a fixed sorted UTXO set, with
+// each range holding at most rangeSize UTXOs.
+type utxoNetwork struct {
+	NetworkingAPI
+	utxos     []*types.SpentUtxoEntry
+	rangeSize int
+}
+
+func (n *utxoNetwork) Request(location common.Location, requestData interface{}, responseDataType interface{}) chan interface{} {
+	resultCh := make(chan interface{}, 1)
+	defer close(resultCh)
+
+	req := requestData.(*types.UtxoRangeRequest)
+	first := sort.Search(len(n.utxos), func(i int) bool {
+		return types.CompareOutPoints(n.utxos[i].OutPoint, req.Start) >= 0
+	})
+	last := first + n.rangeSize
+	utxoRange := new(types.UtxoRange)
+	if last < len(n.utxos) {
+		next := n.utxos[last].OutPoint
+		utxoRange.Next = &next
+	} else {
+		last = len(n.utxos)
+	}
+	utxoRange.Utxos = n.utxos[first:last]
+	resultCh <- utxoRange
+	return resultCh
+}
+
+func TestSyncUtxos(t *testing.T) {
+	// Spread the UTXOs over all of the segments the set is downloaded in
+	utxos := make([]*types.SpentUtxoEntry, 0, 200)
+	want := multiset.New()
+	for i := 0; i < cap(utxos); i++ {
+		var txHash common.Hash
+		txHash[0] = byte(i * 256 / cap(utxos))
+		txHash[31] = byte(i)
+		entry := types.NewUtxoEntry(types.NewTxOut(uint8(i%16), common.HexToAddress("0x0010000000000000000000000000000000000001", common.Location{0, 0}).Bytes(), big.NewInt(0)))
+		utxos = append(utxos, &types.SpentUtxoEntry{OutPoint: types.OutPoint{TxHash: txHash, Index: uint16(i % 3)}, UtxoEntry: entry})
+		want.Add(types.UTXOHash(txHash, uint16(i%3), entry).Bytes())
+	}
+	sort.Slice(utxos, func(i, j int) bool {
+		return types.CompareOutPoints(utxos[i].OutPoint, utxos[j].OutPoint) < 0
+	})
+
+	db := rawdb.NewMemoryDatabase(log.Global)
+	// A UTXO left over from an earlier attempt is not part of the set
+	stale := types.NewUtxoEntry(types.NewTxOut(1, common.HexToAddress("0x0010000000000000000000000000000000000002", common.Location{0, 0}).Bytes(), big.NewInt(0)))
+	if err := rawdb.CreateUTXO(db, common.Hash{0xff, 0xff}, 7, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	network := &utxoNetwork{utxos: utxos, rangeSize: 7}
+	s := newSyncer(network, nil, common.Location{0, 0}, false, log.Global)
+	multiSet, size, err := s.syncUtxos(context.Background(), db, common.Hash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != uint64(len(utxos)) {
+		t.Fatalf("got %d UTXOs, want %d", size, len(utxos))
+	}
+	if multiSet.Hash() != want.Hash() {
+		t.Fatalf("got multiset %s, want %s", multiSet.Hash(), want.Hash())
+	}
+	for _, utxo := range utxos {
+		if rawdb.GetUTXO(db, utxo.TxHash, utxo.Index) == nil {
+			t.Fatalf("missing UTXO %s:%d", utxo.TxHash, utxo.Index)
+		}
+	}
+	if rawdb.GetUTXO(db, common.Hash{0xff, 0xff}, 7) != nil {
+		t.Fatal("stale UTXO was not deleted")
+	}
+}
+
+// blockStateNetwork serves block state requests with a fixed set of responses,
+// one per peer.
+type blockStateNetwork struct {
+	NetworkingAPI
+	responses []*types.SyncBlockState
+}
+
+func (n *blockStateNetwork) Request(location common.Location, requestData interface{}, responseDataType interface{}) chan interface{} {
+	resultCh := make(chan interface{}, len(n.responses))
+	defer close(resultCh)
+	for _, response := range n.responses {
+		resultCh <- response
+	}
+	return resultCh
+}
+
+func TestSyncBlockStates(t *testing.T) {
+	location := common.Location{0, 0}
+	qiAccounts := chaingen.Accounts(location, true, 3)
+	sender, recipient := qiAccounts[1], qiAccounts[2]
+	g, err := chaingen.New(chaingen.Config{
+		QiAlloc: map[common.Address]uint8{sender.Address: types.MaxDenomination},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Stop()
+	blocks, err := g.GenerateBlocks(location, 3, func(i int, b *chaingen.BlockGen) {
+		if i == 2 {
+			if _, err := b.SpendQi(sender, chaingen.GenesisOutPoint(sender.Address), recipient.Address, types.MaxDenomination-1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, pivot := g.Core(location), blocks[len(blocks)-1]
+	honest := c.SyncBlockState(pivot.Hash())
+	if honest == nil || len(honest.CreatedUtxoKeys) == 0 {
+		t.Fatal("pivot has no block state creating utxos")
+	}
+	tamper := func(fn func(*types.SyncBlockState)) *types.SyncBlockState {
+		state := *honest
+		state.CreatedUtxoKeys = append([][]byte{}, honest.CreatedUtxoKeys...)
+		fn(&state)
+		return &state
+	}
+	extraKey := tamper(func(state *types.SyncBlockState) {
+		state.CreatedUtxoKeys = append(state.CreatedUtxoKeys, rawdb.UtxoKeyWithDenomination(common.Hash{1}, 0, types.MaxDenomination))
+	})
+	missingKey := tamper(func(state *types.SyncBlockState) {
+		state.CreatedUtxoKeys = state.CreatedUtxoKeys[1:]
+	})
+	betas := func(beta0 float64) *types.SyncBlockState {
+		return tamper(func(state *types.SyncBlockState) {
+			state.Betas = types.NewBetas(big.NewFloat(beta0), honest.Betas.Beta1())
+		})
+	}
+
+	for _, test := range []struct {
+		name      string
+		responses []*types.SyncBlockState
+		ok        bool
+	}{
+		{"agreeing peers", []*types.SyncBlockState{honest, honest}, true},
+		{"tampered keys are skipped", []*types.SyncBlockState{extraKey, honest, missingKey, honest}, true},
+		{"tampered betas are outvoted", []*types.SyncBlockState{betas(0.1), honest, honest}, true},
+		{"single peer", []*types.SyncBlockState{honest}, false},
+		{"tampered keys", []*types.SyncBlockState{extraKey, extraKey, missingKey, missingKey}, false},
+		{"disagreeing betas", []*types.SyncBlockState{betas(0.1), honest, betas(0.2)}, false},
+	} {
+		s := newSyncer(&blockStateNetwork{responses: test.responses}, c, location, false, log.Global)
+		states, err := s.syncBlockStates(context.Background(), pivot)
+		if !test.ok {
+			if err == nil {
+				t.Fatalf("%s: block states accepted", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := states[pivot.Hash()]; got.Betas.Beta0().Cmp(honest.Betas.Beta0()) != 0 || got.TokenChoiceSet == nil {
+			t.Fatalf("%s: pivot state not the honest one", test.name)
+		}
+		for _, block := range blocks {
+			stored, err := rawdb.ReadCreatedUTXOKeys(c.Database(), block.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := verifySyncBlockState(&types.SyncBlockState{TokenChoiceSet: honest.TokenChoiceSet, Betas: honest.Betas, CreatedUtxoKeys: stored}, states[block.Hash()].CreatedUtxoKeys); err != nil {
+				t.Fatalf("%s: block %d: %v", test.name, block.NumberU64(common.ZONE_CTX), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package quai
+
+import (
+	"context"
+	"math/big"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	goquai "github.com/dominant-strategies/go-quai"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/p2p/protocol"
+)
+
+const (
+	// c_syncInterval is the interval between the sync rounds once the chain
+	// has caught up with its peers
+	c_syncInterval = 10 * time.Second
+	// c_syncHeaderRanges is the number of header ranges requested in parallel
+	// in one sync round
+	c_syncHeaderRanges = 4
+	// c_syncBodyFetchers is the number of block bodies downloaded concurrently
+	c_syncBodyFetchers = 16
+	// c_syncDomAppendTimeout is how long a sync round waits for the dom to
+	// append a block coincident with it
+	c_syncDomAppendTimeout = 30 * time.Second
+	// c_syncDomAppendPollInterval is the interval at which a sync round checks
+	// whether the dom has appended a coincident block
+	c_syncDomAppendPollInterval = 200 * time.Millisecond
+)
+
+// syncer downloads the chain of a region or zone header-first. Each round
+// requests consecutive ranges of header views following the current head from
+// the peers in parallel, verifies them with the consensus engine, downloads
+// the bodies of the verified headers concurrently and appends the blocks in
+// order. Blocks coincident with the dom are handed to the dom to append.
+//
+// A new zone node processing state can instead snap sync: the blocks up to a
+// pivot close to the highest block of the peers are appended without being
+// processed, the state at the pivot is downloaded from the peers, and the
+// blocks following it are processed from there on.
+type syncer struct {
+	nodeLocation common.Location
+	p2pBackend   NetworkingAPI
+	core         *core.Core
+	snapSync     bool // Whether the state may still be snap synced
+	logger       *log.Logger
+
+	lock          sync.RWMutex
+	startingBlock uint64 // Number of the head when the current sync started
+	highestBlock  uint64 // Number of the highest verified header received
+	pulledStates  uint64 // Number of state entries downloaded by the snap sync
+	knownStates   uint64 // Number of state entries known to the snap sync
+}
+
+func newSyncer(p2pBackend NetworkingAPI, core *core.Core, nodeLocation common.Location, snapSync bool, logger *log.Logger) *syncer {
+	return &syncer{
+		nodeLocation: nodeLocation,
+		p2pBackend:   p2pBackend,
+		core:         core,
+		snapSync:     snapSync && nodeLocation.Context() == common.ZONE_CTX && core.ProcessingState(),
+		logger:       logger,
+	}
+}
+
+// Progress returns the progress of the sync, or nil if the chain is not
+// behind the highest header received from the peers.
+func (s *syncer) Progress() *goquai.SyncProgress {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current := s.core.CurrentHeader().NumberU64(s.nodeLocation.Context())
+	if current >= s.highestBlock {
+		return nil
+	}
+	return &goquai.SyncProgress{
+		StartingBlock: s.startingBlock,
+		CurrentBlock:  current,
+		HighestBlock:  s.highestBlock,
+		PulledStates:  s.pulledStates,
+		KnownStates:   s.knownStates,
+	}
+}
+
+// loop runs sync rounds back to back while they make progress, and otherwise
+// every c_syncInterval, until the context is cancelled.
+func (s *syncer) loop(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			for s.round(ctx) {
+			}
+			timer.Reset(c_syncInterval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// round runs a single sync round and reports whether the chain caught up by
+// a full set of header ranges, in which case more blocks are likely available.
+func (s *syncer) round(ctx context.Context) bool {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Error("Go-Quai Panicked")
+		}
+	}()
+	nodeCtx := s.nodeLocation.Context()
+	head := s.core.CurrentHeader()
+	from := head.NumberU64(nodeCtx) + 1
+
+	var pivot uint64
+	if s.snapSync {
+		pivot = s.snapSyncPivot(ctx, head)
+		if pivot != 0 && pivot == head.NumberU64(nodeCtx) {
+			if err := s.syncState(ctx, head); err != nil {
+				s.logger.WithFields(log.Fields{
+					"pivot": pivot,
+					"err":   err,
+				}).Warn("Failed to snap sync the state")
+				return false
+			}
+			return true
+		}
+	}
+
+	headers := s.fetchHeaders(ctx, from)
+	headers = s.verifyHeaders(headers)
+	capped := false
+	for i, header := range headers {
+		// The blocks following the pivot can only be appended once the state
+		// at the pivot is downloaded
+		if pivot != 0 && header.NumberU64(nodeCtx) > pivot {
+			headers, capped = headers[:i], true
+			break
+		}
+	}
+	if pivot != 0 && len(headers) == 0 && !capped && head.NumberU64(nodeCtx) != 0 {
+		// The peers do not serve the chain up to the pivot, so snap sync the
+		// state at the head instead
+		if err := s.core.SetSnapSyncPivot(head.NumberU64(nodeCtx)); err != nil {
+			s.logger.WithField("err", err).Error("Failed to move the snap sync pivot")
+		}
+		return false
+	}
+	if len(headers) == 0 {
+		// Nothing left to sync, so the sync is over until peers announce a
+		// higher chain again
+		s.lock.Lock()
+		s.highestBlock = head.NumberU64(nodeCtx)
+		s.lock.Unlock()
+		return false
+	}
+	s.lock.Lock()
+	if s.highestBlock <= head.NumberU64(nodeCtx) {
+		s.startingBlock = head.NumberU64(nodeCtx)
+	}
+	if last := headers[len(headers)-1].NumberU64(nodeCtx); last > s.highestBlock {
+		s.highestBlock = last
+	}
+	s.lock.Unlock()
+
+	s.logger.WithFields(log.Fields{
+		"from":    from,
+		"headers": len(headers),
+	}).Info("Syncing blocks from peers")
+
+	blocks := headers
+	if nodeCtx != common.ZONE_CTX || s.core.ProcessingState() {
+		blocks = s.fetchBodies(ctx, headers)
+	}
+	inserted := s.insertBlocks(ctx, blocks)
+	if capped {
+		return inserted == len(headers)
+	}
+	return inserted == len(headers) && len(headers) == c_syncHeaderRanges*protocol.C_NumHeadersToDownload
+}
+
+// fetchHeaders requests c_syncHeaderRanges consecutive ranges of header views
+// starting at the given number in parallel, and returns the longest
+// continuous chain of headers they form.
+func (s *syncer) fetchHeaders(ctx context.Context, from uint64) []*types.WorkObject {
+	ranges := make([][]*types.WorkObjectHeaderView, c_syncHeaderRanges)
+	var wg sync.WaitGroup
+	for i := range ranges {
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					s.logger.WithFields(log.Fields{
+						"error":      r,
+						"stacktrace": string(debug.Stack()),
+					}).Error("Go-Quai Panicked")
+				}
+			}()
+			defer wg.Done()
+			number := new(big.Int).SetUint64(from + uint64(i*protocol.C_NumHeadersToDownload))
+			for result := range s.p2pBackend.Request(s.nodeLocation, number, []*types.WorkObjectHeaderView{}) {
+				if headers, ok := result.([]*types.WorkObjectHeaderView); ok && len(headers) > 0 {
+					ranges[i] = headers
+					return
+				}
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	nodeCtx := s.nodeLocation.Context()
+	var headers []*types.WorkObject
+	for i, headerRange := range ranges {
+		if len(headerRange) == 0 {
+			break
+		}
+		// Ranges served by different peers only form a chain if the previous
+		// one was complete and this one extends it
+		if i > 0 {
+			if len(ranges[i-1]) != protocol.C_NumHeadersToDownload || headerRange[0].ParentHash(nodeCtx) != headers[len(headers)-1].Hash() {
+				break
+			}
+		}
+		for _, header := range headerRange {
+			headers = append(headers, header.WorkObject)
+		}
+	}
+	return headers
+}
+
+// verifyHeaders checks the headers with the consensus engine and returns the
+// ones preceding the first invalid one. The headers must start on a known
+// block. Headers failing because the ancestors they are checked against are
+// not written yet are verified again in a later round.
+func (s *syncer) verifyHeaders(headers []*types.WorkObject) []*types.WorkObject {
+	if len(headers) == 0 {
+		return nil
+	}
+	nodeCtx := s.nodeLocation.Context()
+	if s.core.GetHeaderByHash(headers[0].ParentHash(nodeCtx)) == nil {
+		s.logger.WithFields(log.Fields{
+			"number": headers[0].NumberU64(nodeCtx),
+			"parent": headers[0].ParentHash(nodeCtx),
+		}).Debug("Synced headers do not extend a known block")
+		return nil
+	}
+	// Skip the headers already in the chain
+	for len(headers) > 0 && s.core.GetHeaderByHash(headers[0].Hash()) != nil {
+		headers = headers[1:]
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	reader := &syncHeaderReader{
+		ChainHeaderReader: s.core.Slice().HeaderChain(),
+		headers:           make(map[common.Hash]*types.WorkObject, len(headers)),
+	}
+	for _, header := range headers {
+		reader.headers[header.Hash()] = header
+	}
+	abort, results := s.core.Engine().VerifyHeaders(reader, headers)
+	defer close(abort)
+
+	for i := range headers {
+		if err := <-results; err != nil {
+			s.logger.WithFields(log.Fields{
+				"number": headers[i].NumberU64(nodeCtx),
+				"hash":   headers[i].Hash(),
+				"err":    err,
+			}).Debug("Synced header failed verification")
+			return headers[:i]
+		}
+	}
+	return headers
+}
+
+// fetchBodies downloads the blocks of the headers concurrently and returns
+// the ones preceding the first block that could not be downloaded.
+func (s *syncer) fetchBodies(ctx context.Context, headers []*types.WorkObject) []*types.WorkObject {
+	blocks := make([]*types.WorkObject, len(headers))
+	fetchers := make(chan struct{}, c_syncBodyFetchers)
+	var wg sync.WaitGroup
+	for i, header := range headers {
+		if ctx.Err() != nil {
+			break
+		}
+		fetchers <- struct{}{}
+		wg.Add(1)
+		go func(i int, hash common.Hash) {
+			defer func() {
+				if r := recover(); r != nil {
+					s.logger.WithFields(log.Fields{
+						"error":      r,
+						"stacktrace": string(debug.Stack()),
+					}).Error("Go-Quai Panicked")
+				}
+			}()
+			defer func() { <-fetchers }()
+			defer wg.Done()
+			for result := range s.p2pBackend.Request(s.nodeLocation, hash, &types.WorkObjectBlockView{}) {
+				block, ok := result.(*types.WorkObjectBlockView)
+				if !ok || block == nil || block.Hash() != hash {
+					continue
+				}
+				if err := s.core.SanityCheckWorkObjectBlockViewBody(block.WorkObject); err != nil {
+					s.logger.WithFields(log.Fields{
+						"hash": hash,
+						"err":  err,
+					}).Debug("Synced block body failed sanity check")
+					continue
+				}
+				blocks[i] = block.WorkObject
+				return
+			}
+		}(i, header.Hash())
+	}
+	wg.Wait()
+
+	for i, block := range blocks {
+		if block == nil {
+			return blocks[:i]
+		}
+	}
+	return blocks
+}
+
+// insertBlocks appends the blocks in order and returns the number of blocks
+// in the chain afterwards. Consecutive blocks of the order of the node are
+// inserted as a batch, while each block coincident with the dom is handed to
+// the dom, waiting for it to be appended before continuing.
+func (s *syncer) insertBlocks(ctx context.Context, blocks []*types.WorkObject) int {
+	nodeCtx := s.nodeLocation.Context()
+	for start := 0; start < len(blocks); {
+		if ctx.Err() != nil {
+			return start
+		}
+		_, order, err := s.core.CalcOrder(blocks[start])
+		if err != nil {
+			return start
+		}
+		if order < nodeCtx {
+			s.core.WriteBlock(blocks[start])
+			if !s.waitForDomAppend(ctx, blocks[start].Hash()) {
+				s.logger.WithFields(log.Fields{
+					"number": blocks[start].NumberArray(),
+					"hash":   blocks[start].Hash(),
+				}).Info("Dom did not append the synced block in time")
+				return start
+			}
+			start++
+			continue
+		}
+		end := start + 1
+		for ; end < len(blocks); end++ {
+			if _, order, err := s.core.CalcOrder(blocks[end]); err != nil || order < nodeCtx {
+				break
+			}
+		}
+		batch := blocks[start:end]
+		for _, block := range batch {
+			s.core.Slice().WriteBlock(block)
+		}
+		if _, err := s.core.InsertChain(batch); err != nil {
+			s.logger.WithField("err", err).Debug("Failed to insert synced blocks")
+		}
+		for i, block := range batch {
+			if s.core.GetHeaderByHash(block.Hash()) == nil {
+				return start + i
+			}
+		}
+		start = end
+	}
+	return len(blocks)
+}
+
+// waitForDomAppend waits until the block with the given hash is in the chain,
+// for at most c_syncDomAppendTimeout.
+func (s *syncer) waitForDomAppend(ctx context.Context, hash common.Hash) bool {
+	timeout := time.NewTimer(c_syncDomAppendTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(c_syncDomAppendPollInterval)
+	defer ticker.Stop()
+	for {
+		if s.core.GetHeaderByHash(hash) != nil {
+			return true
+		}
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// syncHeaderReader serves the headers of a range under verification on top of
+// the local chain, so that the consensus engine finds the ancestors of headers
+// that are not written yet.
+type syncHeaderReader struct {
+	consensus.ChainHeaderReader
+	headers map[common.Hash]*types.WorkObject
+}
+
+func (r *syncHeaderReader) GetHeaderByHash(hash common.Hash) *types.WorkObject {
+	if header, ok := r.headers[hash]; ok {
+		return header
+	}
+	return r.ChainHeaderReader.GetHeaderByHash(hash)
+}
+
+func (r *syncHeaderReader) GetBlockByHash(hash common.Hash) *types.WorkObject {
+	if header, ok := r.headers[hash]; ok {
+		return header
+	}
+	return r.ChainHeaderReader.GetBlockByHash(hash)
+}
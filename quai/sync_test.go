@@ -0,0 +1,84 @@
+package quai
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/p2p/protocol"
+)
+
+// rangeNetwork serves header range requests from a fixed chain of headers,
+// with each range truncated to the number of headers its peer knows about.
+type rangeNetwork struct {
+	NetworkingAPI
+	chain []*types.WorkObject
+	limit uint64 // Number of the first header the peers do not know about
+	fork  uint64 // Number at which the peers serve a different chain, if any
+}
+
+func (n *rangeNetwork) Request(location common.Location, requestData interface{}, responseDataType interface{}) chan interface{} {
+	resultCh := make(chan interface{}, 1)
+	defer close(resultCh)
+
+	from := requestData.(*big.Int).Uint64()
+	var headers []*types.WorkObjectHeaderView
+	for number := from; number < from+protocol.C_NumHeadersToDownload && number < n.limit; number++ {
+		header := n.chain[number]
+		if n.fork != 0 && number == n.fork {
+			header = types.CopyWorkObject(header)
+			header.SetParentHash(common.Hash{1}, common.ZONE_CTX)
+		}
+		headers = append(headers, &types.WorkObjectHeaderView{WorkObject: header})
+	}
+	if len(headers) > 0 {
+		resultCh <- headers
+	}
+	return resultCh
+}
+
+func makeHeaderChain(n int) []*types.WorkObject {
+	chain := make([]*types.WorkObject, n)
+	for i := range chain {
+		chain[i] = types.EmptyWorkObject(common.ZONE_CTX)
+		chain[i].SetNumber(big.NewInt(int64(i)), common.ZONE_CTX)
+		if i > 0 {
+			chain[i].SetParentHash(chain[i-1].Hash(), common.ZONE_CTX)
+		}
+	}
+	return chain
+}
+
+func TestSyncFetchHeaders(t *testing.T) {
+	chain := makeHeaderChain(c_syncHeaderRanges*protocol.C_NumHeadersToDownload + 10)
+	tests := []struct {
+		name  string
+		limit uint64
+		fork  uint64
+		want  int
+	}{
+		{"all ranges full", uint64(len(chain)), 0, c_syncHeaderRanges * protocol.C_NumHeadersToDownload},
+		{"last range partial", 1 + 2*protocol.C_NumHeadersToDownload + 5, 0, 2*protocol.C_NumHeadersToDownload + 5},
+		{"nothing to sync", 1, 0, 0},
+		{"range off another chain", uint64(len(chain)), 1 + protocol.C_NumHeadersToDownload, protocol.C_NumHeadersToDownload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := &rangeNetwork{chain: chain, limit: tt.limit, fork: tt.fork}
+			s := newSyncer(network, nil, common.Location{0, 0}, false, log.Global)
+
+			headers := s.fetchHeaders(context.Background(), 1)
+			if len(headers) != tt.want {
+				t.Fatalf("got %d headers, want %d", len(headers), tt.want)
+			}
+			for i, header := range headers {
+				if header.Hash() != chain[i+1].Hash() {
+					t.Fatalf("header %d: got hash %s, want %s", i, header.Hash(), chain[i+1].Hash())
+				}
+			}
+		})
+	}
+}
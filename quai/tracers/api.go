@@ -0,0 +1,360 @@
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/state"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/core/vm"
+	"github.com/dominant-strategies/go-quai/internal/quaiapi"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/rpc"
+)
+
+const (
+	// defaultTraceTimeout is the amount of time a single transaction can execute
+	// by default before being forcefully aborted.
+	defaultTraceTimeout = 5 * time.Second
+
+	// defaultTraceReexec is the number of blocks the tracer is willing to go back
+	// and reexecute to produce missing historical state necessary to run a specific
+	// trace.
+	defaultTraceReexec = uint64(128)
+)
+
+// Backend is the part of the node backend the tracing API needs to look up
+// transactions and to rebuild the state they were executed on.
+type Backend interface {
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.WorkObject, error)
+	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
+	RPCGasCap() uint64
+	ChainConfig() *params.ChainConfig
+	NodeLocation() common.Location
+	NodeCtx() int
+	ProcessingState() bool
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.WorkObject, error)
+	StateAtTransaction(ctx context.Context, block *types.WorkObject, txIndex int, reexec uint64) (core.Message, vm.BlockContext, *state.StateDB, error)
+	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.WorkObject, parent *types.WorkObject, vmConfig *vm.Config) (*vm.EVM, func() error, error)
+}
+
+// API is the collection of tracing APIs exposed over the private debugging
+// endpoint.
+type API struct {
+	backend Backend
+}
+
+// NewAPI creates a new API definition for the tracing methods of the Quai service.
+func NewAPI(backend Backend) *API {
+	return &API{backend: backend}
+}
+
+// TraceConfig holds extra parameters to trace functions.
+type TraceConfig struct {
+	*vm.LogConfig
+	Tracer  *string
+	Timeout *string
+	Reexec  *uint64
+}
+
+// TraceCallConfig is the config for traceCall API. It holds one more
+// field to override the state for tracing.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *quaiapi.StateOverride
+}
+
+// txTraceResult is the result of a single transaction trace.
+type txTraceResult struct {
+	TxHash common.Hash `json:"txHash"`           // Hash of the traced transaction
+	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// structLogResult is the result of the default struct logger. Next to the
+// execution result it lists the ETXs emitted by the transaction.
+type structLogResult struct {
+	*quaiapi.ExecutionResult
+	Etxs []*ETXResult `json:"etxs"`
+}
+
+// checkAvailable returns an error if the node cannot replay transactions.
+func (api *API) checkAvailable(method string) error {
+	if api.backend.NodeCtx() != common.ZONE_CTX {
+		return fmt.Errorf("%s can only be called in zone chain", method)
+	}
+	if !api.backend.ProcessingState() {
+		return fmt.Errorf("%s can only be called on chain processing the state", method)
+	}
+	return nil
+}
+
+// TraceTransaction returns the structured logs created during the execution of
+// the EVM and returns them as a JSON object. Only transactions of the Quai
+// ledger can be traced, as they are the only ones executed by the EVM.
+func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	if err := api.checkAvailable("traceTransaction"); err != nil {
+		return nil, err
+	}
+	tx, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type() != types.QuaiTxType {
+		return nil, fmt.Errorf("transaction %#x is not a Quai transaction", hash)
+	}
+	// It shouldn't happen in practice.
+	if blockNumber == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	block, err := api.backend.BlockByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
+	msg, vmctx, statedb, err := api.backend.StateAtTransaction(ctx, block, int(index), reexec)
+	if err != nil {
+		return nil, err
+	}
+	txctx := &Context{
+		BlockHash: blockHash,
+		TxIndex:   int(index),
+		TxHash:    hash,
+		GasLimit:  msg.Gas(),
+	}
+	return api.traceTx(ctx, msg, txctx, vmctx, statedb, config)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution of
+// the EVM for every Quai transaction of the block. Qi transactions and ETXs are
+// not traced, but the ETXs paying Quai are applied to the state of the
+// transactions after them.
+func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	if err := api.checkAvailable("traceBlockByHash"); err != nil {
+		return nil, err
+	}
+	block, err := api.backend.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// traceBlock replays all the Quai transactions of the block on top of each
+// other and traces each of them.
+func (api *API) traceBlock(ctx context.Context, block *types.WorkObject, config *TraceConfig) ([]*txTraceResult, error) {
+	nodeCtx := api.backend.NodeCtx()
+	if block.NumberU64(nodeCtx) == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	var (
+		txs     = block.Transactions()
+		results = make([]*txTraceResult, 0, len(txs))
+		first   = -1
+	)
+	for i, tx := range txs {
+		if tx.Type() == types.QuaiTxType {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return results, nil
+	}
+	// The state at the first Quai transaction is the state all the Quai
+	// transactions of the block are executed on
+	_, vmctx, statedb, err := api.backend.StateAtTransaction(ctx, block, first, reexec)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.MakeSigner(api.backend.ChainConfig(), block.Number(nodeCtx))
+	etxApplied := false
+	for i := first; i < len(txs); i++ {
+		tx := txs[i]
+		if tx.Type() == types.ExternalTxType {
+			etxApplied = true
+		}
+		if tx.Type() != types.QuaiTxType {
+			continue
+		}
+		// The ETXs are applied to the state by the chain, so the state of
+		// a transaction after them is rebuilt from the parent block
+		if etxApplied {
+			if _, vmctx, statedb, err = api.backend.StateAtTransaction(ctx, block, i, reexec); err != nil {
+				return nil, err
+			}
+			etxApplied = false
+		}
+		msg, err := tx.AsMessage(signer, block.BaseFee())
+		if err != nil {
+			return nil, err
+		}
+		txctx := &Context{
+			BlockHash: block.Hash(),
+			TxIndex:   i,
+			TxHash:    tx.Hash(),
+			GasLimit:  msg.Gas(),
+		}
+		res, err := api.traceTx(ctx, msg, txctx, vmctx, statedb, config)
+		if err != nil {
+			results = append(results, &txTraceResult{TxHash: tx.Hash(), Error: err.Error()})
+			continue
+		}
+		// Ensure any modifications are committed to the state
+		statedb.Finalise(true)
+		results = append(results, &txTraceResult{TxHash: tx.Hash(), Result: res})
+	}
+	return results, nil
+}
+
+// TraceCall lets you trace a given quai_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object.
+func (api *API) TraceCall(ctx context.Context, args quaiapi.TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	if err := api.checkAvailable("traceCall"); err != nil {
+		return nil, err
+	}
+	nodeLocation := api.backend.NodeLocation()
+	// Reset to and from in case of type unmarshal error
+	if args.To != nil {
+		to := common.BytesToAddress(args.To.Bytes(), nodeLocation)
+		args.To = &to
+	}
+	if args.From != nil {
+		from := common.BytesToAddress(args.From.Bytes(), nodeLocation)
+		args.From = &from
+	}
+	statedb, header, err := api.backend.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, errors.New("state not found")
+	}
+	parent, err := api.backend.BlockByHash(ctx, header.ParentHash(api.backend.NodeCtx()))
+	if err != nil {
+		return nil, err
+	}
+	var traceConfig *TraceConfig
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb, nodeLocation); err != nil {
+			return nil, err
+		}
+		traceConfig = &config.TraceConfig
+	}
+	// The call is executed with the current nonce of the sender
+	from := common.ZeroAddress(nodeLocation)
+	if args.From != nil && !args.From.Equal(common.Zero) {
+		from = *args.From
+	}
+	internal, err := from.InternalAndQuaiAddress()
+	if err != nil {
+		return nil, err
+	}
+	nonce := statedb.GetNonce(internal)
+	args.Nonce = (*hexutil.Uint64)(&nonce)
+
+	msg, err := args.ToMessage(api.backend.RPCGasCap(), header.BaseFee(), nodeLocation)
+	if err != nil {
+		return nil, err
+	}
+	// The EVM is only assembled here to get the block context of the call
+	evm, _, err := api.backend.GetEVM(ctx, msg, statedb, header, parent, &vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return api.traceTx(ctx, msg, &Context{GasLimit: msg.Gas()}, evm.Context, statedb, traceConfig)
+}
+
+// traceTx configures a new tracer according to the provided configuration, and
+// executes the given message in the provided environment. The return value will
+// be tracer dependent.
+func (api *API) traceTx(ctx context.Context, message core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	var (
+		tracer    vm.Tracer
+		err       error
+		txContext = core.NewEVMTxContext(message)
+	)
+	switch {
+	case config == nil:
+		tracer = vm.NewStructLogger(nil)
+	case config.Tracer != nil:
+		// Define a meaningful timeout of a single transaction trace
+		timeout := defaultTraceTimeout
+		if config.Timeout != nil {
+			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+				return nil, err
+			}
+		}
+		t, err := New(*config.Tracer, txctx)
+		if err != nil {
+			return nil, err
+		}
+		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
+		go func() {
+			<-deadlineCtx.Done()
+			if errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) {
+				t.Stop(errors.New("execution timeout"))
+			}
+		}()
+		defer cancel()
+		tracer = t
+	default:
+		tracer = vm.NewStructLogger(config.LogConfig)
+	}
+	// Run the transaction with tracing enabled. The base fee check is skipped
+	// so that calls without a gas price can be traced, which does not change
+	// the execution of transactions paying a gas price.
+	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Debug: true, Tracer: tracer, NoBaseFee: true})
+
+	// Call Prepare to clear out the statedb access list
+	statedb.Prepare(txctx.TxHash, txctx.TxIndex)
+
+	result, err := core.ApplyMessage(vmenv, message, new(types.GasPool).AddGas(message.Gas()))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+
+	// Depending on the tracer type, format and return the output.
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		// If the result contains a revert reason, return it.
+		returnVal := fmt.Sprintf("%x", result.Return())
+		if len(result.Revert()) > 0 {
+			returnVal = fmt.Sprintf("%x", result.Revert())
+		}
+		return &structLogResult{
+			ExecutionResult: &quaiapi.ExecutionResult{
+				Gas:         result.UsedGas,
+				Failed:      result.Failed(),
+				ReturnValue: returnVal,
+				StructLogs:  quaiapi.FormatLogs(tracer.StructLogs()),
+			},
+			Etxs: NewETXResults(result.Etxs),
+		}, nil
+
+	case Tracer:
+		return tracer.GetResult()
+
+	default:
+		panic(fmt.Sprintf("bad tracer type %T", tracer))
+	}
+}
@@ -0,0 +1,172 @@
+// Package native contains the tracers that are implemented in Go and can be
+// selected by name in the debug_trace* RPC methods.
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core/vm"
+	"github.com/dominant-strategies/go-quai/quai/tracers"
+)
+
+func init() {
+	tracers.Register("callTracer", newCallTracer)
+}
+
+// callFrame is a single call of the call tree. Next to the nested calls it
+// lists the ETXs the call emitted itself, i.e. not the ones emitted by the
+// calls nested in it.
+type callFrame struct {
+	Type    string               `json:"type"`
+	From    common.Address       `json:"from"`
+	To      common.Address       `json:"to"`
+	Value   *hexutil.Big         `json:"value,omitempty"`
+	Gas     hexutil.Uint64       `json:"gas"`
+	GasUsed hexutil.Uint64       `json:"gasUsed"`
+	Input   hexutil.Bytes        `json:"input"`
+	Output  hexutil.Bytes        `json:"output,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	Calls   []callFrame          `json:"calls,omitempty"`
+	Etxs    []*tracers.ETXResult `json:"etxs,omitempty"`
+}
+
+type callTracer struct {
+	env       *vm.EVM
+	callstack []callFrame
+	etxs      int    // Number of entries of the ETX cache already assigned to a frame
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newCallTracer returns a native go tracer which tracks the call frames of a
+// transaction, and implements vm.Tracer.
+func newCallTracer(ctx *tracers.Context) tracers.Tracer {
+	// First callframe contains tx context info
+	// and is populated on start and end.
+	return &callTracer{callstack: make([]callFrame, 1)}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.callstack[0] = callFrame{
+		Type:  "CALL",
+		From:  from,
+		To:    to,
+		Input: common.CopyBytes(input),
+		Gas:   hexutil.Uint64(gas),
+	}
+	if value != nil {
+		t.callstack[0].Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	if create {
+		t.callstack[0].Type = "CREATE"
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	t.collectEtxs()
+	t.callstack[0].GasUsed = hexutil.Uint64(gasUsed)
+	if err != nil {
+		t.callstack[0].Error = err.Error()
+		if errors.Is(err, vm.ErrExecutionReverted) && len(output) > 0 {
+			t.callstack[0].Output = common.CopyBytes(output)
+		}
+	} else {
+		t.callstack[0].Output = common.CopyBytes(output)
+	}
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error, nodeLocation common.Location) {
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault.
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call or create).
+func (t *callTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Abort the execution if tracing was interrupted. The frame is still
+	// recorded, as its exit is reported nevertheless.
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+	}
+	t.collectEtxs()
+
+	call := callFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    to,
+		Input: common.CopyBytes(input),
+		Gas:   hexutil.Uint64(gas),
+	}
+	if value != nil {
+		call.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.callstack = append(t.callstack, call)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+	t.collectEtxs()
+
+	// pop call
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	size -= 1
+
+	call.GasUsed = hexutil.Uint64(gasUsed)
+	if err == nil {
+		call.Output = common.CopyBytes(output)
+	} else {
+		call.Error = err.Error()
+	}
+	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
+}
+
+// collectEtxs assigns the ETXs emitted since the last call frame change to the
+// innermost frame, which is the one that emitted them.
+func (t *callTracer) collectEtxs() {
+	if t.env == nil {
+		return
+	}
+	t.env.ETXCacheLock.RLock()
+	defer t.env.ETXCacheLock.RUnlock()
+
+	top := &t.callstack[len(t.callstack)-1]
+	for ; t.etxs < len(t.env.ETXCache); t.etxs++ {
+		top.Etxs = append(top.Etxs, tracers.NewETXResult(t.env.ETXCache[t.etxs]))
+	}
+}
+
+// GetResult returns the json-encoded nested list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if len(t.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	res, err := json.Marshal(t.callstack[0])
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *callTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
@@ -0,0 +1,194 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core/vm"
+	"github.com/dominant-strategies/go-quai/quai/tracers"
+)
+
+func init() {
+	tracers.Register("prestateTracer", newPrestateTracer)
+}
+
+type prestate = map[common.InternalAddress]*account
+
+type account struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+type prestateTracer struct {
+	env       *vm.EVM
+	location  common.Location
+	prestate  prestate
+	created   map[common.InternalAddress]bool
+	gasLimit  uint64
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newPrestateTracer returns a native go tracer which collects the accounts
+// and storage slots touched by a transaction as they were before it was
+// executed. Only accounts of the executing chain are included, while the
+// recipients of ETXs are not, as their state lives in other chains.
+func newPrestateTracer(ctx *tracers.Context) tracers.Tracer {
+	return &prestateTracer{
+		prestate: prestate{},
+		created:  make(map[common.InternalAddress]bool),
+		gasLimit: ctx.GasLimit,
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.location = env.ChainConfig().Location
+
+	fromInternal, err := from.InternalAndQuaiAddress()
+	if err != nil {
+		return
+	}
+	t.lookupAccount(fromInternal)
+
+	// The sender balance is after reducing the gas limit and, unless the
+	// recipient lives in another chain and an ETX is emitted instead, the
+	// value. We need to re-add them to get the pre-tx balance.
+	toInternal, toErr := to.InternalAndQuaiAddress()
+	fromBal := new(big.Int).Set(t.prestate[fromInternal].Balance.ToInt())
+	fromBal.Add(fromBal, new(big.Int).Mul(env.TxContext.GasPrice, new(big.Int).SetUint64(t.gasLimit)))
+	if toErr == nil {
+		fromBal.Add(fromBal, value)
+	}
+	t.prestate[fromInternal].Balance = (*hexutil.Big)(fromBal)
+	t.prestate[fromInternal].Nonce--
+
+	// The recipient of an ETX is not part of the pre-state, as its state
+	// lives in another chain
+	if toErr != nil {
+		return
+	}
+	if create {
+		t.created[toInternal] = true
+		return
+	}
+	t.lookupAccount(toInternal)
+
+	// The recipient balance includes the value transferred.
+	toBal := new(big.Int).Sub(t.prestate[toInternal].Balance.ToInt(), value)
+	t.prestate[toInternal].Balance = (*hexutil.Big)(toBal)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error, nodeLocation common.Location) {
+	stack := scope.Stack
+	stackData := stack.Data()
+	stackLen := len(stackData)
+	switch {
+	case stackLen >= 1 && (op == vm.SLOAD || op == vm.SSTORE):
+		slot := common.Hash(stackData[stackLen-1].Bytes32())
+		addr, err := scope.Contract.Address().InternalAndQuaiAddress()
+		if err != nil {
+			return
+		}
+		t.lookupStorage(addr, slot)
+	case stackLen >= 1 && (op == vm.EXTCODECOPY || op == vm.EXTCODEHASH || op == vm.EXTCODESIZE || op == vm.BALANCE || op == vm.SELFDESTRUCT):
+		t.lookupStackAddress(stackData[stackLen-1].Bytes20())
+	case stackLen >= 5 && (op == vm.DELEGATECALL || op == vm.CALL || op == vm.STATICCALL || op == vm.CALLCODE):
+		t.lookupStackAddress(stackData[stackLen-2].Bytes20())
+	}
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault.
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call or create).
+// Contracts created by the transaction did not exist before it, so they are
+// left out of the pre-state.
+func (t *prestateTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Abort the execution if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+		return
+	}
+	if typ != vm.CREATE && typ != vm.CREATE2 {
+		return
+	}
+	if internal, err := to.InternalAndQuaiAddress(); err == nil {
+		t.created[internal] = true
+	}
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *prestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// GetResult returns the json-encoded pre-state of the accounts touched by the
+// transaction, and any error arising from the encoding or forceful termination
+// (via `Stop`).
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	for addr := range t.created {
+		delete(t.prestate, addr)
+	}
+	res, err := json.Marshal(t.prestate)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// lookupStackAddress fetches the details of an address taken from the stack,
+// provided it belongs to the executing chain.
+func (t *prestateTracer) lookupStackAddress(addr common.AddressBytes) {
+	internal, err := common.Bytes20ToAddress(addr, t.location).InternalAndQuaiAddress()
+	if err != nil {
+		return
+	}
+	t.lookupAccount(internal)
+}
+
+// lookupAccount fetches details of an account and adds it to the prestate
+// if it doesn't exist there.
+func (t *prestateTracer) lookupAccount(addr common.InternalAddress) {
+	if _, ok := t.prestate[addr]; ok || t.created[addr] {
+		return
+	}
+	t.prestate[addr] = &account{
+		Balance: (*hexutil.Big)(new(big.Int).Set(t.env.StateDB.GetBalance(addr))),
+		Nonce:   t.env.StateDB.GetNonce(addr),
+		Code:    common.CopyBytes(t.env.StateDB.GetCode(addr)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage fetches the requested storage slot and adds
+// it to the prestate of the given contract.
+func (t *prestateTracer) lookupStorage(addr common.InternalAddress, key common.Hash) {
+	t.lookupAccount(addr)
+	if t.created[addr] {
+		return
+	}
+	if _, ok := t.prestate[addr].Storage[key]; ok {
+		return
+	}
+	t.prestate[addr].Storage[key] = t.env.StateDB.GetState(addr, key)
+}
@@ -0,0 +1,135 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/rawdb"
+	"github.com/dominant-strategies/go-quai/core/state"
+	"github.com/dominant-strategies/go-quai/core/vm"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/params"
+	"github.com/dominant-strategies/go-quai/quai/tracers"
+)
+
+var (
+	testLocation = common.Location{0, 0}
+	testCaller   = common.HexToAddress("0x00000000000000000000000000000000000000c0", testLocation)
+	testContract = common.HexToAddress("0x00000000000000000000000000000000000000aa", testLocation)
+	testCallee   = common.HexToAddress("0x00000000000000000000000000000000000000bb", testLocation)
+	testRemote   = common.HexToAddress("0x01000000000000000000000000000000000000cc", testLocation)
+)
+
+// runTrace executes a call from testCaller into testContract with the tracer
+// registered under the given name attached. The contract calls testCallee,
+// which writes a storage slot, and then emits an ETX sending one wei to
+// testRemote in another zone.
+func runTrace(t *testing.T, name string) json.RawMessage {
+	db := state.NewDatabase(rawdb.NewMemoryDatabase(log.Global))
+	statedb, err := state.New(common.Hash{}, common.Hash{}, big.NewInt(0), db, db, nil, testLocation, log.Global)
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	contract, _ := testContract.InternalAndQuaiAddress()
+	callee, _ := testCallee.InternalAndQuaiAddress()
+
+	// CALL(0xffff, callee, 0, 0, 0, 0, 0); POP
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, byte(vm.PUSH20)}
+	code = append(code, testCallee.Bytes()...)
+	code = append(code, byte(vm.PUSH2), 0xff, 0xff, byte(vm.CALL), byte(vm.POP))
+	// ETX(remote, value 1, gas 21000, no fees, no data, no access list); POP; STOP
+	code = append(code, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, byte(vm.PUSH2), 0x52, 0x08, 0x60, 0x01, byte(vm.PUSH20))
+	code = append(code, testRemote.Bytes()...)
+	code = append(code, 0x60, 0x00, byte(vm.ETX), byte(vm.POP), byte(vm.STOP))
+
+	statedb.SetCode(contract, code)
+	statedb.SetBalance(contract, big.NewInt(10))
+	statedb.SetCode(callee, []byte{0x60, 0x01, 0x60, 0x00, byte(vm.SSTORE), byte(vm.STOP)})
+	statedb.SetState(callee, common.Hash{}, common.BigToHash(big.NewInt(7)))
+	statedb.Finalise(true)
+	statedb.PrepareAccessList(testCaller, &testContract, nil, nil, true)
+
+	config := *params.TestChainConfig
+	config.Location = testLocation
+	blockCtx := vm.BlockContext{
+		CanTransfer:        core.CanTransfer,
+		Transfer:           core.Transfer,
+		GetHash:            func(uint64) common.Hash { return common.Hash{} },
+		CheckIfEtxEligible: func(common.Hash, common.Location) bool { return true },
+		PrimaryCoinbase:    testCaller,
+		GasLimit:           10000000,
+		BlockNumber:        big.NewInt(1),
+		Time:               big.NewInt(1),
+		Difficulty:         big.NewInt(1),
+		BaseFee:            big.NewInt(0),
+		AverageBaseFee:     big.NewInt(0),
+		QuaiStateSize:      big.NewInt(0),
+	}
+	tracer, err := tracers.New(name, &tracers.Context{})
+	if err != nil {
+		t.Fatalf("failed to create tracer %s: %v", name, err)
+	}
+	txCtx := vm.TxContext{Origin: testCaller, GasPrice: big.NewInt(0)}
+	evm := vm.NewEVM(blockCtx, txCtx, statedb, &config, vm.Config{Debug: true, Tracer: tracer})
+	if _, _, _, err := evm.Call(vm.AccountRef(testCaller), testContract, nil, 1000000, big.NewInt(0)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to get result: %v", err)
+	}
+	return res
+}
+
+func TestCallTracer(t *testing.T) {
+	var frame callFrame
+	if err := json.Unmarshal(runTrace(t, "callTracer"), &frame); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if frame.Type != "CALL" || !frame.To.Equal(testContract) {
+		t.Fatalf("unexpected top level call: %s to %s", frame.Type, frame.To.Hex())
+	}
+	if len(frame.Calls) != 1 {
+		t.Fatalf("expected 1 nested call, got %d", len(frame.Calls))
+	}
+	if call := frame.Calls[0]; call.Type != "CALL" || !call.To.Equal(testCallee) || call.Error != "" {
+		t.Fatalf("unexpected nested call: %s to %s, err %q", call.Type, call.To.Hex(), call.Error)
+	}
+	if len(frame.Calls[0].Etxs) != 0 {
+		t.Fatalf("nested call should not emit etxs, got %d", len(frame.Calls[0].Etxs))
+	}
+	if len(frame.Etxs) != 1 {
+		t.Fatalf("expected 1 etx, got %d", len(frame.Etxs))
+	}
+	etx := frame.Etxs[0]
+	if !etx.To.Equal(testRemote) || !etx.From.Equal(testContract) || etx.Value.ToInt().Cmp(big.NewInt(1)) != 0 || etx.Conversion {
+		t.Fatalf("unexpected etx: to %s from %s value %v", etx.To.Hex(), etx.From.Hex(), etx.Value)
+	}
+}
+
+func TestPrestateTracer(t *testing.T) {
+	var pre map[common.InternalAddress]*account
+	if err := json.Unmarshal(runTrace(t, "prestateTracer"), &pre); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	contract, _ := testContract.InternalAndQuaiAddress()
+	callee, _ := testCallee.InternalAndQuaiAddress()
+	remote := common.InternalAddress(testRemote.Bytes20())
+
+	if acc, ok := pre[contract]; !ok || acc.Balance.ToInt().Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("missing or wrong pre-state of the contract: %+v", acc)
+	}
+	acc, ok := pre[callee]
+	if !ok {
+		t.Fatalf("missing pre-state of the callee")
+	}
+	if value := acc.Storage[common.Hash{}]; value != common.BigToHash(big.NewInt(7)) {
+		t.Fatalf("wrong pre-state of the callee storage: have %x, want 7", value)
+	}
+	if _, ok := pre[remote]; ok {
+		t.Fatalf("etx recipient should not be part of the pre-state")
+	}
+}
@@ -0,0 +1,108 @@
+// Package tracers implements the debug_trace* RPC methods, which replay
+// transactions of the Quai ledger through the EVM with a tracer attached.
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/core/vm"
+)
+
+// Context contains some contextual infos for a transaction execution that is
+// not available from within the EVM object.
+type Context struct {
+	BlockHash common.Hash // Hash of the block the tx is contained within (zero if dangling tx or call)
+	TxIndex   int         // Index of the transaction within a block (zero if dangling tx or call)
+	TxHash    common.Hash // Hash of the transaction being traced (zero if dangling call)
+	GasLimit  uint64      // Gas limit of the message being traced
+}
+
+// Tracer interface extends vm.Tracer and additionally allows collecting the
+// tracing result.
+type Tracer interface {
+	vm.Tracer
+	GetResult() (json.RawMessage, error)
+	// Stop terminates execution of the tracer at the first opportune moment.
+	Stop(err error)
+}
+
+// Constructor creates a new instance of a tracer for a single transaction.
+type Constructor func(ctx *Context) Tracer
+
+var (
+	// ErrTracerNotFound is returned if no tracer is registered under the
+	// requested name
+	ErrTracerNotFound = errors.New("tracer not found")
+
+	lookupLock sync.RWMutex
+	lookup     = make(map[string]Constructor)
+)
+
+// Register makes a tracer available to the trace RPC methods under the given
+// name. It is meant to be called from the init function of the package
+// implementing the tracer, and panics if the name is already taken.
+func Register(name string, ctor Constructor) {
+	lookupLock.Lock()
+	defer lookupLock.Unlock()
+
+	if _, exists := lookup[name]; exists {
+		panic("tracer " + name + " registered twice")
+	}
+	lookup[name] = ctor
+}
+
+// New returns a new instance of the tracer registered under the given name.
+func New(name string, ctx *Context) (Tracer, error) {
+	lookupLock.RLock()
+	ctor, exists := lookup[name]
+	lookupLock.RUnlock()
+
+	if !exists {
+		return nil, ErrTracerNotFound
+	}
+	return ctor(ctx), nil
+}
+
+// ETXResult is the trace representation of an external transaction emitted
+// while executing a transaction, i.e. a send to another chain or a conversion
+// to the Qi ledger.
+type ETXResult struct {
+	Hash       common.Hash    `json:"hash"`
+	EtxType    hexutil.Uint64 `json:"etxType"`
+	EtxIndex   hexutil.Uint64 `json:"etxIndex"`
+	From       common.Address `json:"from"`
+	To         common.Address `json:"to"`
+	Value      *hexutil.Big   `json:"value"`
+	Gas        hexutil.Uint64 `json:"gas"`
+	Input      hexutil.Bytes  `json:"input"`
+	Conversion bool           `json:"conversion"`
+}
+
+// NewETXResult returns the trace representation of the given ETX.
+func NewETXResult(etx *types.Transaction) *ETXResult {
+	return &ETXResult{
+		Hash:       etx.Hash(),
+		EtxType:    hexutil.Uint64(etx.EtxType()),
+		EtxIndex:   hexutil.Uint64(etx.ETXIndex()),
+		From:       etx.ETXSender(),
+		To:         *etx.To(),
+		Value:      (*hexutil.Big)(etx.Value()),
+		Gas:        hexutil.Uint64(etx.Gas()),
+		Input:      etx.Data(),
+		Conversion: types.IsConversionTx(etx),
+	}
+}
+
+// NewETXResults returns the trace representation of the given ETXs.
+func NewETXResults(etxs []*types.Transaction) []*ETXResult {
+	results := make([]*ETXResult, len(etxs))
+	for i, etx := range etxs {
+		results[i] = NewETXResult(etx)
+	}
+	return results
+}
@@ -350,6 +350,22 @@ func (ec *Client) GetUTXO(ctx context.Context, txHash common.Hash, index uint16)
 	return utxo, err
 }
 
+// GetTrimWarningsByAddress returns the UTXOs of the given Qi address that will
+// be trimmed, and the block at which each of them will be trimmed.
+func (ec *Client) GetTrimWarningsByAddress(ctx context.Context, address common.MixedcaseAddress) ([]*types.RPCTrimWarning, error) {
+	var warnings []*types.RPCTrimWarning
+	err := ec.c.CallContext(ctx, &warnings, "quai_getTrimWarningsByAddress", address.Original())
+	return warnings, err
+}
+
+// GetTrimWarnings returns which of the given outpoints will be trimmed, and
+// the block at which each of them will be trimmed.
+func (ec *Client) GetTrimWarnings(ctx context.Context, outpoints []types.OutpointJSON) ([]*types.RPCTrimWarning, error) {
+	var warnings []*types.RPCTrimWarning
+	err := ec.c.CallContext(ctx, &warnings, "quai_getTrimWarnings", outpoints)
+	return warnings, err
+}
+
 // StorageAt returns the value of key in the contract storage of the given account.
 // The block number can be nil, in which case the value is taken from the latest known block.
 func (ec *Client) StorageAt(ctx context.Context, account common.MixedcaseAddress, key common.Hash, blockNumber *big.Int) ([]byte, error) {
@@ -79,6 +79,7 @@ type Client struct {
 	isHTTP   bool
 	services *serviceRegistry
 	log      *log.Logger
+	connCtx  context.Context // parent of the contexts of the calls served on the connection
 
 	idCounter uint32
 
@@ -112,7 +113,7 @@ type clientConn struct {
 }
 
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
-	ctx := context.WithValue(context.Background(), clientContextKey{}, c)
+	ctx := context.WithValue(c.connCtx, clientContextKey{}, c)
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.log)
 	return &clientConn{conn, handler}
 }
@@ -154,8 +155,8 @@ func (op *requestOp) wait(ctx context.Context, c *Client) (*jsonrpcMessage, erro
 //
 // The currently supported URL schemes are "http", "https", "ws" and "wss". If rawurl is a
 // file name with no URL scheme, a local socket connection is established using UNIX
-// domain sockets on supported platforms and named pipes on Windows. If you want to
-// configure transport options, use DialHTTP, DialWebsocket.
+// domain sockets on supported platforms, Windows named pipes are not supported. If
+// you want to configure transport options, use DialHTTP, DialWebsocket.
 //
 // For websocket connections, the origin is set to the local host name.
 //
@@ -180,6 +181,8 @@ func DialContext(ctx context.Context, rawurl string) (*Client, error) {
 		return DialWebsocket(ctx, rawurl, "")
 	case "stdio":
 		return DialStdIO(ctx)
+	case "":
+		return DialIPC(ctx, rawurl)
 	default:
 		return nil, fmt.Errorf("no known transport for URL scheme %q", u.Scheme)
 	}
@@ -197,14 +200,15 @@ func newClient(initctx context.Context, connect reconnectFunc) (*Client, error)
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), new(serviceRegistry), log.Global)
+	c := initClient(context.Background(), conn, randomIDGenerator(), new(serviceRegistry), log.Global)
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, log *log.Logger) *Client {
+func initClient(connCtx context.Context, conn ServerCodec, idgen func() ID, services *serviceRegistry, log *log.Logger) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
+		connCtx:     connCtx,
 		idgen:       idgen,
 		isHTTP:      isHTTP,
 		services:    services,
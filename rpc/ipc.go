@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"context"
+	"net"
+)
+
+// ServeListener accepts connections on l, serving JSON-RPC on them.
+func (s *Server) ServeListener(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if isTemporaryError(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		s.log.WithField("conn", conn.RemoteAddr()).Trace("Accepted RPC connection")
+		go s.ServeCodec(NewCodec(conn), 0)
+	}
+}
+
+// isTemporaryError reports whether the accept error is transient, which
+// net.Error only signals through the deprecated Temporary method.
+func isTemporaryError(err error) bool {
+	tempErr, ok := err.(interface {
+		Temporary() bool
+	})
+	return ok && tempErr.Temporary()
+}
+
+// DialIPC create a new IPC client that connects to the given endpoint. On Unix it assumes
+// the endpoint is the full path to a unix socket.
+//
+// The context is used for the initial connection establishment. It does not
+// affect subsequent interactions with the client.
+func DialIPC(ctx context.Context, endpoint string) (*Client, error) {
+	return newClient(ctx, func(ctx context.Context) (ServerCodec, error) {
+		conn, err := newIPCConnection(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return NewCodec(conn), err
+	})
+}
+
+// IPCListen creates the listener of the IPC endpoint. On Unix it creates the
+// unix socket at the endpoint path, replacing a stale socket left by a previous
+// run.
+func IPCListen(endpoint string) (net.Listener, error) {
+	return ipcListen(endpoint)
+}
@@ -0,0 +1,67 @@
+//go:build darwin || dragonfly || freebsd || linux || nacl || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux nacl netbsd openbsd solaris
+
+package rpc
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIPC(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	endpoint := filepath.Join(t.TempDir(), "zone-0-0.ipc")
+	listener, err := IPCListen(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go server.ServeListener(listener)
+
+	client, err := Dial(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	if result.String != "hello" || result.Int != 10 || result.Args == nil || result.Args.S != "world" {
+		t.Fatalf("wrong echo result: %v", result)
+	}
+
+	// Subscriptions are supported over IPC
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	values := make(chan int, 3)
+	sub, err := client.Subscribe(ctx, "nftest", values, "someSubscription", 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	for want := 1; want <= 3; want++ {
+		select {
+		case have := <-values:
+			if have != want {
+				t.Fatalf("wrong notification: have %d, want %d", have, want)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for notifications")
+		}
+	}
+}
+
+func TestIPCPathTooLong(t *testing.T) {
+	endpoint := filepath.Join(t.TempDir(), strings.Repeat("x", int(max_path_size)), "zone-0-0.ipc")
+	if _, err := IPCListen(endpoint); err == nil {
+		t.Fatal("expected an error for a socket path longer than the limit")
+	}
+}
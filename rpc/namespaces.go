@@ -0,0 +1,35 @@
+package rpc
+
+import "context"
+
+type namespacesKey struct{}
+
+// WithNamespaces returns a copy of ctx restricting the calls served with it to
+// the given API namespaces. The rpc metadata namespace is always allowed.
+func WithNamespaces(ctx context.Context, namespaces []string) context.Context {
+	allowed := make(map[string]struct{}, len(namespaces))
+	for _, namespace := range namespaces {
+		allowed[namespace] = struct{}{}
+	}
+	return context.WithValue(ctx, namespacesKey{}, allowed)
+}
+
+// withNamespacesOf returns a copy of ctx with the namespace restriction of
+// from, if it has one.
+func withNamespacesOf(ctx context.Context, from context.Context) context.Context {
+	if allowed := from.Value(namespacesKey{}); allowed != nil {
+		return context.WithValue(ctx, namespacesKey{}, allowed)
+	}
+	return ctx
+}
+
+// namespaceAllowed reports whether calls of the namespace may be served with
+// ctx.
+func namespaceAllowed(ctx context.Context, namespace string) bool {
+	allowed, ok := ctx.Value(namespacesKey{}).(map[string]struct{})
+	if !ok || namespace == MetadataApi {
+		return true
+	}
+	_, ok = allowed[namespace]
+	return ok
+}
@@ -75,6 +75,12 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 //
 // Note that codec options are no longer supported.
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
+	s.serveCodec(context.Background(), codec)
+}
+
+// serveCodec serves the requests read from codec, the calls are run with contexts
+// derived from connCtx.
+func (s *Server) serveCodec(connCtx context.Context, codec ServerCodec) {
 	defer func() {
 		if r := recover(); r != nil {
 			s.log.WithFields(log.Fields{
@@ -94,7 +100,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services, s.log)
+	c := initClient(connCtx, codec, s.idgen, &s.services, s.log)
 	<-codec.closed()
 	c.Close()
 }
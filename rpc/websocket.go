@@ -61,7 +61,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn)
-		s.ServeCodec(codec, 0)
+		// The request context is not kept for the lifetime of the connection,
+		// only the namespaces it was authorized for
+		s.serveCodec(withNamespacesOf(context.Background(), r.Context()), codec)
 	})
 }
 
@@ -0,0 +1,335 @@
+package stratum
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+const (
+	// Maximum size of a request line
+	c_maxRequestSize = 4096
+
+	// Time after which the connection of a miner without requests is closed
+	c_idleTimeout = 10 * time.Minute
+
+	// Time allowed to write a message to a miner
+	c_writeTimeout = 10 * time.Second
+)
+
+// stratumError is an error returned to a miner, which is encoded as the
+// [code, message, traceback] array of the stratum protocol.
+type stratumError struct {
+	code    int
+	message string
+}
+
+func (e *stratumError) Error() string {
+	return e.message
+}
+
+func (e *stratumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.code, e.message, nil})
+}
+
+var (
+	errOther          = &stratumError{20, "Other/Unknown"}
+	errJobNotFound    = &stratumError{21, "Job not found"}
+	errDuplicateShare = &stratumError{22, "Duplicate share"}
+	errLowDifficulty  = &stratumError{23, "Low difficulty share"}
+	errUnauthorized   = &stratumError{24, "Unauthorized worker"}
+	errNotSubscribed  = &stratumError{25, "Not subscribed"}
+)
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// session is the connection of a miner, which may run several workers.
+type session struct {
+	server *Server
+	conn   net.Conn
+	id     string
+
+	mu             sync.Mutex // Protects the fields below and the writes to the connection
+	subscribed     bool
+	workers        map[string]*big.Int // Difficulty requested by each worker, nil for the default one
+	difficulty     *big.Int            // Default difficulty of the workers, which the miner may suggest
+	lastDifficulty *big.Int            // Share difficulty last sent to the miner
+}
+
+func newSession(server *Server, conn net.Conn, id uint64) *session {
+	return &session{
+		server:     server,
+		conn:       conn,
+		id:         fmt.Sprintf("%016x", id),
+		workers:    make(map[string]*big.Int),
+		difficulty: server.difficulty,
+	}
+}
+
+// serve handles the requests of the miner until the connection is closed.
+func (sess *session) serve() {
+	defer sess.conn.Close()
+	logger := sess.server.logger.WithField("remote", sess.conn.RemoteAddr())
+	logger.Debug("Stratum miner connected")
+
+	scanner := bufio.NewScanner(sess.conn)
+	scanner.Buffer(make([]byte, 0, c_maxRequestSize), c_maxRequestSize)
+	for {
+		sess.conn.SetReadDeadline(time.Now().Add(c_idleTimeout))
+		if !scanner.Scan() {
+			break
+		}
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			logger.WithField("err", err).Debug("Invalid stratum request")
+			return
+		}
+		result, err := sess.handle(&req)
+		if err != nil {
+			var serr *stratumError
+			if !errors.As(err, &serr) {
+				serr = &stratumError{errOther.code, err.Error()}
+			}
+			logger.WithFields(log.Fields{
+				"method": req.Method,
+				"err":    err,
+			}).Debug("Stratum request failed")
+			sess.send(&response{ID: req.ID, Error: serr})
+		} else {
+			sess.send(&response{ID: req.ID, Result: result})
+		}
+		if req.Method == "mining.authorize" && err == nil {
+			// The first job is sent once the worker is authorized
+			if j := sess.server.latestJob(); j != nil {
+				sess.notifyJob(j, true)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logger.WithField("err", err).Debug("Stratum miner connection failed")
+	}
+	logger.Debug("Stratum miner disconnected")
+}
+
+func (sess *session) handle(req *request) (interface{}, error) {
+	switch req.Method {
+	case "mining.subscribe":
+		sess.mu.Lock()
+		sess.subscribed = true
+		sess.mu.Unlock()
+		// Miners roll the whole nonce, so there is no extranonce
+		return []interface{}{
+			[][]string{{"mining.set_difficulty", sess.id}, {"mining.notify", sess.id}},
+			"",
+			0,
+		}, nil
+
+	case "mining.authorize":
+		var login, password string
+		if err := parseParams(req.Params, 1, &login, &password); err != nil {
+			return nil, err
+		}
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		if !sess.subscribed {
+			return nil, errNotSubscribed
+		}
+		// The password may request a difficulty for the worker, as in d=1000
+		var difficulty *big.Int
+		for _, option := range strings.Split(password, ",") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(option), "d="); ok {
+				var err error
+				if difficulty, err = parseDifficulty(value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		sess.workers[login] = difficulty
+		return true, nil
+
+	case "mining.suggest_difficulty":
+		if len(req.Params) == 0 {
+			return nil, errors.New("missing difficulty")
+		}
+		difficulty, err := parseDifficulty(strings.Trim(string(req.Params[0]), `"`))
+		if err != nil {
+			return nil, err
+		}
+		sess.mu.Lock()
+		sess.difficulty = difficulty
+		sess.mu.Unlock()
+		if j := sess.server.latestJob(); j != nil {
+			sess.notifyJob(j, false)
+		}
+		return true, nil
+
+	case "mining.extranonce.subscribe":
+		return true, nil
+
+	case "mining.submit":
+		var worker, jobID, nonceHex, mixHex string
+		if err := parseParams(req.Params, 3, &worker, &jobID, &nonceHex, &mixHex); err != nil {
+			return nil, err
+		}
+		sess.mu.Lock()
+		difficulty, authorized := sess.workerDifficulty(worker)
+		sess.mu.Unlock()
+		if !authorized {
+			return nil, errUnauthorized
+		}
+		nonce, err := strconv.ParseUint(strings.TrimPrefix(nonceHex, "0x"), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nonce: %w", err)
+		}
+		var mixHash common.Hash
+		if mixHex != "" {
+			mix, err := hex.DecodeString(strings.TrimPrefix(mixHex, "0x"))
+			if err != nil || len(mix) != common.HashLength {
+				return nil, errors.New("invalid mix hash")
+			}
+			mixHash = common.BytesToHash(mix)
+		}
+		if err := sess.server.submit(worker, difficulty, jobID, types.EncodeNonce(nonce), mixHash); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported method %s", req.Method)
+	}
+}
+
+// workerDifficulty returns the share difficulty of the worker and whether it is
+// authorized. The session lock has to be held.
+func (sess *session) workerDifficulty(worker string) (*big.Int, bool) {
+	difficulty, authorized := sess.workers[worker]
+	if difficulty == nil {
+		difficulty = sess.difficulty
+	}
+	return difficulty, authorized
+}
+
+// notifyJob sends the job to the miner once it is authorized, along with its
+// share difficulty if it changed. The notification carries the job id, the
+// seal hash, the seed hash, the share target, whether the previous jobs are
+// stale and the prime terminus number the progpow epoch is derived from.
+//
+// The miner is sent a single difficulty for all of its workers, the lowest of
+// them, so that every worker finds its shares. The shares are then checked
+// against the difficulty of the worker submitting them.
+func (sess *session) notifyJob(j *job, clean bool) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if len(sess.workers) == 0 {
+		return
+	}
+	var lowest *big.Int
+	for worker := range sess.workers {
+		if difficulty, _ := sess.workerDifficulty(worker); lowest == nil || difficulty.Cmp(lowest) < 0 {
+			lowest = difficulty
+		}
+	}
+	header := j.header.WorkObjectHeader()
+	threshold := shareThreshold(header.Difficulty(), lowest)
+	difficulty := shareDifficulty(header.Difficulty(), threshold)
+	if sess.lastDifficulty == nil || sess.lastDifficulty.Cmp(difficulty) != 0 {
+		sess.lastDifficulty = difficulty
+		sess.write(&notification{
+			Method: "mining.set_difficulty",
+			Params: []interface{}{difficulty},
+		})
+	}
+	sess.write(&notification{
+		Method: "mining.notify",
+		Params: []interface{}{
+			j.id,
+			header.SealHash().Hex(),
+			sess.server.seedHash(header).Hex(),
+			common.BytesToHash(shareTarget(header, threshold).Bytes()).Hex(),
+			clean,
+			header.PrimeTerminusNumber().Uint64(),
+		},
+	})
+}
+
+func (sess *session) send(msg interface{}) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.write(msg)
+}
+
+// write sends a message to the miner, closing the connection if it fails. The
+// session lock has to be held.
+func (sess *session) write(msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		sess.server.logger.WithField("err", err).Error("Failed to encode a stratum message")
+		return
+	}
+	sess.conn.SetWriteDeadline(time.Now().Add(c_writeTimeout))
+	if _, err := sess.conn.Write(append(data, '\n')); err != nil {
+		sess.conn.Close()
+	}
+}
+
+// parseParams decodes the positional parameters of a request into the given
+// strings, of which the first ones are required.
+func parseParams(params []json.RawMessage, required int, values ...*string) error {
+	if len(params) < required {
+		return errors.New("missing parameters")
+	}
+	for i, value := range values {
+		if i >= len(params) {
+			break
+		}
+		if err := json.Unmarshal(params[i], value); err != nil {
+			return fmt.Errorf("invalid parameter %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// parseDifficulty parses a positive share difficulty, which may be fractional.
+func parseDifficulty(value string) (*big.Int, error) {
+	difficulty, ok := new(big.Float).SetString(value)
+	if !ok || difficulty.IsInf() {
+		return nil, fmt.Errorf("invalid difficulty %s", value)
+	}
+	if difficulty.Cmp(big.NewFloat(1)) < 0 {
+		return big.NewInt(1), nil
+	}
+	result, _ := difficulty.Int(nil)
+	return result, nil
+}
@@ -0,0 +1,382 @@
+// Package stratum implements a Stratum v1 mining server, which hands the
+// pending headers of a zone out to pool miners as jobs and submits the blocks
+// and the work shares they find.
+package stratum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"runtime/debug"
+	"strconv"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/common/math"
+	"github.com/dominant-strategies/go-quai/consensus"
+	"github.com/dominant-strategies/go-quai/consensus/progpow"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/event"
+	"github.com/dominant-strategies/go-quai/internal/quaiapi"
+	"github.com/dominant-strategies/go-quai/log"
+	"github.com/dominant-strategies/go-quai/node"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+const (
+	// pendingHeaderChanSize is the size of channel listening to the pending headers.
+	pendingHeaderChanSize = 10
+
+	// Number of the latest jobs on the same parent for which shares are accepted
+	c_maxJobs = 8
+)
+
+// backend encompasses the functionality the stratum server needs to hand out
+// the pending headers of the node
+type backend interface {
+	SubscribePendingHeaderEvent(ch chan<- *types.WorkObject) event.Subscription
+	GetPendingHeader() (*types.WorkObject, error)
+	Engine() consensus.Engine
+	Logger() *log.Logger
+}
+
+// minerAPI receives the solutions found by the miners, the same way as the
+// blocks and the work shares submitted over RPC
+type minerAPI interface {
+	ReceiveMinedHeader(ctx context.Context, raw hexutil.Bytes) error
+	ReceiveWorkShare(ctx context.Context, workShare *types.WorkObjectHeader) error
+}
+
+// job is a pending header handed out to the miners
+type job struct {
+	id     string
+	header *types.WorkObject             // Pending header, without the block body
+	shares map[types.BlockNonce]struct{} // Nonces of the accepted shares, guarded by Server.mu
+}
+
+// Server is a Stratum v1 server. It sends each pending header of the node to
+// the connected miners as a job, with a share target derived from the
+// difficulty of each worker, and accepts the shares meeting that target.
+// The shares meeting the block difficulty are submitted as blocks, and those
+// meeting the work share threshold as work shares.
+type Server struct {
+	backend    backend
+	miner      minerAPI
+	engine     consensus.Engine
+	logger     *log.Logger
+	addr       string
+	difficulty *big.Int // Default share difficulty of the workers
+
+	listener  net.Listener
+	headerSub event.Subscription
+
+	mu         sync.Mutex
+	jobs       []*job // Latest jobs, from the oldest to the newest
+	jobCounter uint64
+	sessions   map[*session]struct{}
+	sessionID  uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a stratum server listening on the given address and registers
+// it on the node. Workers mine with the given share difficulty unless they
+// request another one.
+func New(node *node.Node, backend quaiapi.Backend, addr string, difficulty *big.Int) error {
+	if difficulty == nil || difficulty.Sign() <= 0 {
+		return errors.New("share difficulty has to be positive")
+	}
+	node.RegisterLifecycle(newServer(backend, quaiapi.NewPublicBlockChainQuaiAPI(backend), addr, difficulty))
+	return nil
+}
+
+func newServer(backend backend, miner minerAPI, addr string, difficulty *big.Int) *Server {
+	return &Server{
+		backend:    backend,
+		miner:      miner,
+		engine:     backend.Engine(),
+		logger:     backend.Logger(),
+		addr:       addr,
+		difficulty: new(big.Int).Set(difficulty),
+		sessions:   make(map[*session]struct{}),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting to accept connections of miners.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	headerCh := make(chan *types.WorkObject, pendingHeaderChanSize)
+	s.headerSub = s.backend.SubscribePendingHeaderEvent(headerCh)
+	if pendingHeader, err := s.backend.GetPendingHeader(); err == nil {
+		s.newJob(pendingHeader)
+	}
+
+	s.wg.Add(2)
+	go s.loopHeaders(headerCh)
+	go s.loopAccept()
+
+	s.logger.WithField("addr", listener.Addr()).Info("Stratum server started")
+	return nil
+}
+
+// Stop implements node.Lifecycle, closing the connections of the miners.
+func (s *Server) Stop() error {
+	close(s.quit)
+	s.headerSub.Unsubscribe()
+	s.listener.Close()
+	s.mu.Lock()
+	for sess := range s.sessions {
+		sess.conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+
+	s.logger.Info("Stratum server stopped")
+	return nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) loopHeaders(headerCh chan *types.WorkObject) {
+	defer s.wg.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.WithFields(log.Fields{
+				"error":      r,
+				"stacktrace": string(debug.Stack()),
+			}).Error("Go-Quai Panicked")
+		}
+	}()
+	for {
+		select {
+		case pendingHeader := <-headerCh:
+			s.newJob(pendingHeader)
+		case <-s.headerSub.Err():
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Server) loopAccept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+			default:
+				s.logger.WithField("err", err).Error("Stratum server failed to accept a connection")
+			}
+			return
+		}
+		s.mu.Lock()
+		s.sessionID++
+		sess := newSession(s, conn, s.sessionID)
+		s.sessions[sess] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			sess.serve()
+
+			s.mu.Lock()
+			delete(s.sessions, sess)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// newJob hands the pending header out to the miners, unless it is the same as
+// the one of the latest job. The previous jobs are dropped if the parent of the
+// pending header changed, as the shares found for them are stale.
+func (s *Server) newJob(pendingHeader *types.WorkObject) {
+	if pendingHeader == nil || pendingHeader.Header() == nil || pendingHeader.Difficulty() == nil || pendingHeader.Difficulty().Sign() <= 0 {
+		return
+	}
+	// Only keep the Header in the body, as quai_getPendingHeader does
+	header := pendingHeader.WithBody(pendingHeader.Header(), nil, nil, nil, nil, nil)
+	sealHash := header.SealHash()
+
+	s.mu.Lock()
+	clean := true
+	if len(s.jobs) > 0 {
+		latest := s.jobs[len(s.jobs)-1].header
+		if latest.SealHash() == sealHash {
+			s.mu.Unlock()
+			return
+		}
+		clean = latest.WorkObjectHeader().ParentHash() != header.WorkObjectHeader().ParentHash()
+	}
+	if clean {
+		s.jobs = s.jobs[:0]
+	} else if len(s.jobs) >= c_maxJobs {
+		s.jobs = append(s.jobs[:0], s.jobs[len(s.jobs)-c_maxJobs+1:]...)
+	}
+	s.jobCounter++
+	j := &job{
+		id:     strconv.FormatUint(s.jobCounter, 16),
+		header: header,
+		shares: make(map[types.BlockNonce]struct{}),
+	}
+	s.jobs = append(s.jobs, j)
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+
+	s.logger.WithFields(log.Fields{
+		"job":      j.id,
+		"number":   header.NumberArray(),
+		"sealHash": sealHash,
+		"clean":    clean,
+		"sessions": len(sessions),
+	}).Debug("New stratum job")
+	for _, sess := range sessions {
+		sess.notifyJob(j, clean)
+	}
+}
+
+// latestJob returns the job the miners are currently working on.
+func (s *Server) latestJob() *job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.jobs) == 0 {
+		return nil
+	}
+	return s.jobs[len(s.jobs)-1]
+}
+
+// submit checks a share found by a worker for the given job against the share
+// target of the worker, and submits it as a block or as a work share if it
+// meets their difficulty.
+func (s *Server) submit(worker string, difficulty *big.Int, jobID string, nonce types.BlockNonce, mixHash common.Hash) error {
+	s.mu.Lock()
+	var j *job
+	for _, candidate := range s.jobs {
+		if candidate.id == jobID {
+			j = candidate
+		}
+	}
+	s.mu.Unlock()
+	if j == nil {
+		return errJobNotFound
+	}
+
+	header := types.CopyWorkObjectHeader(j.header.WorkObjectHeader())
+	header.SetNonce(nonce)
+	if _, ok := s.engine.(*progpow.Progpow); ok {
+		header.SetMixHash(mixHash)
+	}
+	if !s.engine.CheckWorkThreshold(header, shareThreshold(header.Difficulty(), difficulty)) {
+		return errLowDifficulty
+	}
+
+	s.mu.Lock()
+	if _, ok := j.shares[nonce]; ok {
+		s.mu.Unlock()
+		return errDuplicateShare
+	}
+	j.shares[nonce] = struct{}{}
+	s.mu.Unlock()
+
+	if _, err := s.engine.VerifySeal(header); err == nil {
+		return s.submitBlock(worker, j, header)
+	}
+	if s.engine.CheckWorkThreshold(header, params.WorkSharesThresholdDiff) {
+		if err := s.miner.ReceiveWorkShare(context.Background(), header); err != nil {
+			s.logger.WithFields(log.Fields{
+				"worker": worker,
+				"hash":   header.Hash(),
+				"err":    err,
+			}).Error("Failed to submit the work share of a stratum worker")
+		}
+	}
+	return nil
+}
+
+func (s *Server) submitBlock(worker string, j *job, header *types.WorkObjectHeader) error {
+	block := types.NewWorkObject(header, j.header.Body(), j.header.Tx())
+	protoWo, err := block.ProtoEncode(types.PEtxObject)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(protoWo)
+	if err != nil {
+		return err
+	}
+	if err := s.miner.ReceiveMinedHeader(context.Background(), data); err != nil {
+		s.logger.WithFields(log.Fields{
+			"worker": worker,
+			"number": block.NumberArray(),
+			"hash":   block.Hash(),
+			"err":    err,
+		}).Error("Failed to submit the block of a stratum worker")
+		return err
+	}
+	s.logger.WithFields(log.Fields{
+		"worker": worker,
+		"number": block.NumberArray(),
+		"hash":   block.Hash(),
+	}).Info("Stratum worker mined a block")
+	return nil
+}
+
+// shareThreshold returns the number of bits by which the share target of a
+// worker is above the block target, so that the shares are at least as hard
+// as the difficulty of the worker. Shares are at most half as hard as the
+// block, as the threshold has to be positive.
+func shareThreshold(blockDifficulty *big.Int, difficulty *big.Int) int {
+	threshold := new(big.Int).Div(blockDifficulty, difficulty).BitLen() - 1
+	if threshold < 1 {
+		return 1
+	}
+	return threshold
+}
+
+// shareDifficulty returns the difficulty of the shares of a worker with the
+// given threshold.
+func shareDifficulty(blockDifficulty *big.Int, threshold int) *big.Int {
+	difficulty := new(big.Int).Rsh(blockDifficulty, uint(threshold))
+	if difficulty.Sign() == 0 {
+		return big.NewInt(1)
+	}
+	return difficulty
+}
+
+// shareTarget returns the target of the shares of a worker with the given
+// threshold.
+func shareTarget(header *types.WorkObjectHeader, threshold int) *big.Int {
+	target, err := consensus.CalcWorkShareThreshold(header, threshold)
+	if err != nil || target.Cmp(math.MaxBig256) > 0 {
+		return new(big.Int).Set(math.MaxBig256)
+	}
+	return target
+}
+
+// seedHash returns the hash the miners need along with the seal hash to compute
+// the pow hash of a header: the seed of the progpow epoch, or the mix hash
+// blake3 hashes ahead of the seal hash.
+func (s *Server) seedHash(header *types.WorkObjectHeader) common.Hash {
+	if _, ok := s.engine.(*progpow.Progpow); ok {
+		return common.BytesToHash(progpow.SeedHash(header.PrimeTerminusNumber().Uint64()))
+	}
+	return header.MixHash()
+}
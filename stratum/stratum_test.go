@@ -0,0 +1,249 @@
+package stratum
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/common/hexutil"
+	"github.com/dominant-strategies/go-quai/consensus"
+	"github.com/dominant-strategies/go-quai/consensus/blake3pow"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/event"
+	"github.com/dominant-strategies/go-quai/log"
+)
+
+type testBackend struct {
+	engine        consensus.Engine
+	feed          event.Feed
+	pendingHeader *types.WorkObject
+}
+
+func (b *testBackend) SubscribePendingHeaderEvent(ch chan<- *types.WorkObject) event.Subscription {
+	return b.feed.Subscribe(ch)
+}
+
+func (b *testBackend) GetPendingHeader() (*types.WorkObject, error) {
+	return b.pendingHeader, nil
+}
+
+func (b *testBackend) Engine() consensus.Engine { return b.engine }
+func (b *testBackend) Logger() *log.Logger      { return log.Global }
+
+type testMiner struct {
+	blocks     chan *types.WorkObject
+	workShares chan *types.WorkObjectHeader
+}
+
+func (m *testMiner) ReceiveMinedHeader(ctx context.Context, raw hexutil.Bytes) error {
+	protoWo := &types.ProtoWorkObject{}
+	if err := proto.Unmarshal(raw, protoWo); err != nil {
+		return err
+	}
+	block := &types.WorkObject{}
+	if err := block.ProtoDecode(protoWo, common.Location{0, 0}, types.PEtxObject); err != nil {
+		return err
+	}
+	m.blocks <- block
+	return nil
+}
+
+func (m *testMiner) ReceiveWorkShare(ctx context.Context, workShare *types.WorkObjectHeader) error {
+	m.workShares <- workShare
+	return nil
+}
+
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	id     int
+}
+
+type testMessage struct {
+	ID     *int              `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Result json.RawMessage   `json:"result"`
+	Error  []interface{}     `json:"error"`
+}
+
+func (c *testClient) read() *testMessage {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.reader.ReadBytes('\n')
+	require.NoError(c.t, err)
+	var msg testMessage
+	require.NoError(c.t, json.Unmarshal(line, &msg))
+	return &msg
+}
+
+func (c *testClient) call(method string, params ...interface{}) *testMessage {
+	c.id++
+	data, err := json.Marshal(map[string]interface{}{"id": c.id, "method": method, "params": params})
+	require.NoError(c.t, err)
+	_, err = c.conn.Write(append(data, '\n'))
+	require.NoError(c.t, err)
+	msg := c.read()
+	require.NotNil(c.t, msg.ID)
+	require.Equal(c.t, c.id, *msg.ID)
+	return msg
+}
+
+// readJob reads a mining.notify notification and returns its job id, seal
+// hash, share target and whether the previous jobs are stale.
+func (c *testClient) readJob() (string, common.Hash, *big.Int, bool) {
+	msg := c.read()
+	require.Equal(c.t, "mining.notify", msg.Method)
+	var id, sealHash, seedHash, target string
+	var clean bool
+	for i, value := range []interface{}{&id, &sealHash, &seedHash, &target, &clean} {
+		require.NoError(c.t, json.Unmarshal(msg.Params[i], value))
+	}
+	return id, common.HexToHash(sealHash), common.HexToHash(target).Big(), clean
+}
+
+func errorCode(t *testing.T, msg *testMessage) int {
+	require.Len(t, msg.Error, 3, "no error returned")
+	return int(msg.Error[0].(float64))
+}
+
+func newPendingHeader(parentHash common.Hash, difficulty int64) *types.WorkObject {
+	pendingHeader := types.EmptyZoneWorkObject()
+	pendingHeader.WorkObjectHeader().SetParentHash(parentHash)
+	pendingHeader.WorkObjectHeader().SetNumber(big.NewInt(1))
+	pendingHeader.WorkObjectHeader().SetDifficulty(big.NewInt(difficulty))
+	return pendingHeader
+}
+
+// findNonce returns the first nonce from start whose pow hash is at most the
+// upper target and above the lower one.
+func findNonce(header *types.WorkObjectHeader, start uint64, lower *big.Int, upper *big.Int) uint64 {
+	header = types.CopyWorkObjectHeader(header)
+	for nonce := start; ; nonce++ {
+		header.SetNonce(types.EncodeNonce(nonce))
+		powHash := new(big.Int).SetBytes(header.Hash().Bytes())
+		if powHash.Cmp(lower) > 0 && powHash.Cmp(upper) <= 0 {
+			return nonce
+		}
+	}
+}
+
+func TestStratumServer(t *testing.T) {
+	backend := &testBackend{
+		engine:        blake3pow.NewTester(nil, false),
+		pendingHeader: newPendingHeader(common.Hash{1}, 1024),
+	}
+	miner := &testMiner{
+		blocks:     make(chan *types.WorkObject, 1),
+		workShares: make(chan *types.WorkObjectHeader, 100),
+	}
+	server := newServer(backend, miner, "127.0.0.1:0", big.NewInt(16))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	conn, err := net.Dial("tcp", server.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := &testClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+
+	// Workers have to subscribe before being authorized
+	require.Equal(t, errNotSubscribed.code, errorCode(t, client.call("mining.authorize", "worker", "")))
+	require.Nil(t, client.call("mining.subscribe", "test-miner").Error)
+
+	// The worker requests shares 16 times easier than the block
+	require.Equal(t, "true", string(client.call("mining.authorize", "worker", "x,d=64").Result))
+	msg := client.read()
+	require.Equal(t, "mining.set_difficulty", msg.Method)
+	require.Equal(t, "64", string(msg.Params[0]))
+	jobID, sealHash, shareTarget, clean := client.readJob()
+	header := backend.pendingHeader.WorkObjectHeader()
+	require.True(t, clean)
+	require.Equal(t, header.SealHash(), sealHash)
+	blockTarget := new(big.Int).Div(common.Big2e256, header.Difficulty())
+	require.Equal(t, new(big.Int).Mul(blockTarget, big.NewInt(16)), shareTarget)
+
+	submit := func(worker string, jobID string, nonce uint64) *testMessage {
+		return client.call("mining.submit", worker, jobID, fmt.Sprintf("%016x", nonce))
+	}
+	share := findNonce(header, 0, blockTarget, shareTarget)
+	require.Equal(t, errUnauthorized.code, errorCode(t, submit("other", jobID, share)))
+	require.Equal(t, errJobNotFound.code, errorCode(t, submit("worker", "unknown", share)))
+	require.Equal(t, errLowDifficulty.code, errorCode(t, submit("worker", jobID, findNonce(header, 0, shareTarget, common.Big2e256))))
+	require.Equal(t, "true", string(submit("worker", jobID, share).Result))
+	require.Equal(t, errDuplicateShare.code, errorCode(t, submit("worker", jobID, share)))
+
+	// A share meeting the block difficulty is submitted as a block
+	nonce := findNonce(header, 0, common.Big0, blockTarget)
+	require.Equal(t, "true", string(submit("worker", jobID, nonce).Result))
+	select {
+	case block := <-miner.blocks:
+		require.Equal(t, types.EncodeNonce(nonce), block.Nonce())
+		require.Equal(t, sealHash, block.SealHash())
+	case <-time.After(5 * time.Second):
+		t.Fatal("block not submitted")
+	}
+
+	// A pending header on a new parent makes the previous jobs stale
+	backend.feed.Send(newPendingHeader(common.Hash{2}, 1024))
+	newJobID, newSealHash, _, clean := client.readJob()
+	require.True(t, clean)
+	require.NotEqual(t, jobID, newJobID)
+	require.NotEqual(t, sealHash, newSealHash)
+	require.Equal(t, errJobNotFound.code, errorCode(t, submit("worker", jobID, findNonce(header, nonce+1, common.Big0, shareTarget))))
+}
+
+func TestStratumWorkerDifficulty(t *testing.T) {
+	backend := &testBackend{
+		engine:        blake3pow.NewTester(nil, false),
+		pendingHeader: newPendingHeader(common.Hash{1}, 1024),
+	}
+	miner := &testMiner{
+		blocks:     make(chan *types.WorkObject, 1),
+		workShares: make(chan *types.WorkObjectHeader, 100),
+	}
+	server := newServer(backend, miner, "127.0.0.1:0", big.NewInt(16))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	conn, err := net.Dial("tcp", server.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := &testClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	require.Nil(t, client.call("mining.subscribe", "test-miner").Error)
+
+	// Two workers of the same miner request shares 4 and 16 times easier than
+	// the block, and the miner is sent the lowest difficulty
+	require.Equal(t, "true", string(client.call("mining.authorize", "high", "x,d=256").Result))
+	msg := client.read()
+	require.Equal(t, "mining.set_difficulty", msg.Method)
+	require.Equal(t, "256", string(msg.Params[0]))
+	client.readJob()
+	require.Equal(t, "true", string(client.call("mining.authorize", "low", "x,d=64").Result))
+	msg = client.read()
+	require.Equal(t, "mining.set_difficulty", msg.Method)
+	require.Equal(t, "64", string(msg.Params[0]))
+	jobID, _, shareTarget, _ := client.readJob()
+	header := backend.pendingHeader.WorkObjectHeader()
+	blockTarget := new(big.Int).Div(common.Big2e256, header.Difficulty())
+	require.Equal(t, new(big.Int).Mul(blockTarget, big.NewInt(16)), shareTarget)
+
+	// Each share is checked against the difficulty of the worker submitting it
+	submit := func(worker string, nonce uint64) *testMessage {
+		return client.call("mining.submit", worker, jobID, fmt.Sprintf("%016x", nonce))
+	}
+	highTarget := new(big.Int).Mul(blockTarget, big.NewInt(4))
+	lowShare := findNonce(header, 0, highTarget, shareTarget)
+	require.Equal(t, errLowDifficulty.code, errorCode(t, submit("high", lowShare)))
+	require.Equal(t, "true", string(submit("low", lowShare).Result))
+	highShare := findNonce(header, 0, blockTarget, highTarget)
+	require.Equal(t, "true", string(submit("high", highShare).Result))
+}
@@ -0,0 +1,210 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus/misc"
+	"github.com/dominant-strategies/go-quai/core/types"
+)
+
+var (
+	// ErrNoPayments is returned if a transaction is requested without any
+	// payment
+	ErrNoPayments = errors.New("no payments")
+
+	// ErrInsufficientFunds is returned if the spendable UTXOs of the wallet
+	// do not cover the payments and the fee
+	ErrInsufficientFunds = errors.New("insufficient funds for payments and fee")
+
+	// ErrCombineDenominations is returned if the payments can only be covered
+	// by combining smaller denominations into a larger one, which the Qi
+	// ledger does not allow
+	ErrCombineDenominations = errors.New("payments require combining smaller denominations into a larger one")
+)
+
+// Payment is an output paying a single denomination to an address. An address
+// may only receive one output of a transaction, so paying a value that is not
+// a single denomination takes one payment to a distinct address per output.
+type Payment struct {
+	To           common.Address
+	Denomination uint8
+}
+
+// CreateTransaction selects UTXOs of the wallet to cover the payments and the
+// fee, sends the change to new addresses of the wallet and returns the signed
+// transaction. The header is the latest block of the zone: its base fee and
+// Qi to Quai rate determine the fee, and UTXOs still locked at its number are
+// not spent. The scaling factor is the natural logarithm of the UTXO set size
+// at that block, as used by types.CalculateQiTxGas.
+//
+// The spent UTXOs are left in the wallet, call MarkSpent once the transaction
+// has been sent.
+func (w *Wallet) CreateTransaction(header *types.WorkObject, qiScalingFactor float64, payments []Payment) (*types.Transaction, error) {
+	if len(payments) == 0 {
+		return nil, ErrNoPayments
+	}
+	recipients := make(map[common.AddressBytes]struct{})
+	for _, payment := range payments {
+		if payment.Denomination > types.MaxDenomination {
+			return nil, fmt.Errorf("payment to %s has invalid denomination %d", payment.To.Hex(), payment.Denomination)
+		}
+		if !payment.To.IsInQiLedgerScope() {
+			return nil, fmt.Errorf("payment to %s is not in the Qi ledger scope", payment.To.Hex())
+		}
+		if _, exists := recipients[payment.To.Bytes20()]; exists {
+			return nil, fmt.Errorf("duplicate payment to %s", payment.To.Hex())
+		}
+		recipients[payment.To.Bytes20()] = struct{}{}
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// The outputs of the recipients cannot be spent by the transaction, as no
+	// address may appear on both of its sides
+	number := header.Number(common.ZONE_CTX)
+	candidates := make([]*Utxo, 0, len(w.utxos))
+	for _, utxo := range w.utxos {
+		if _, exists := recipients[utxo.Address.Bytes20()]; exists || !utxo.spendable(number) {
+			continue
+		}
+		candidates = append(candidates, utxo)
+	}
+	sortUtxos(candidates)
+
+	// Prefer the smallest single UTXO covering the payments and otherwise
+	// accumulate UTXOs from the largest one, which keeps the number of inputs
+	// and with it the fee low
+	err := ErrInsufficientFunds
+	for i := len(candidates) - 1; i >= 0; i-- {
+		var change map[uint8]uint64
+		if change, err = w.planChange(candidates[i:i+1], payments, header, qiScalingFactor); err == nil {
+			return w.signTx(candidates[i:i+1], payments, change)
+		}
+	}
+	for n := 2; n <= len(candidates); n++ {
+		var change map[uint8]uint64
+		if change, err = w.planChange(candidates[:n], payments, header, qiScalingFactor); err == nil {
+			return w.signTx(candidates[:n], payments, change)
+		}
+	}
+	return nil, err
+}
+
+// planChange returns the number of change outputs per denomination of a
+// transaction spending the given inputs to the payments. The change is what
+// remains after the fee for the gas of the transaction, change outputs
+// included, at the base fee of the header.
+func (w *Wallet) planChange(inputs []*Utxo, payments []Payment, header *types.WorkObject, qiScalingFactor float64) (map[uint8]uint64, error) {
+	totalIn := new(big.Int)
+	inputCounts := make(map[uint]uint64)
+	for _, in := range inputs {
+		totalIn.Add(totalIn, in.Value())
+		inputCounts[uint(in.Denomination)]++
+	}
+	totalOut := new(big.Int)
+	outputCounts := make(map[uint]uint64)
+	for _, payment := range payments {
+		totalOut.Add(totalOut, types.Denominations[payment.Denomination])
+		outputCounts[uint(payment.Denomination)]++
+	}
+	// More change outputs cost more gas and leave less change, so look for
+	// the least number of change outputs whose fee covers the split change.
+	// Stepping one output at a time keeps the overpaid fee to a minimum.
+	for changeOutputs := uint64(0); ; changeOutputs++ {
+		gas := types.CalculateQiTxGas(draftTx(inputs, payments, changeOutputs), qiScalingFactor, w.location)
+		change := new(big.Int).Sub(totalIn, totalOut)
+		change.Sub(change, minimumFee(header, gas))
+		if change.Sign() < 0 {
+			return nil, ErrInsufficientFunds
+		}
+		split, err := splitChange(change, inputCounts, outputCounts)
+		if err != nil {
+			return nil, err
+		}
+		var n uint64
+		for _, count := range split {
+			n += count
+		}
+		if uint64(len(payments))+n > types.MaxOutputIndex+1 {
+			return nil, fmt.Errorf("transaction exceeds max output index of %d", types.MaxOutputIndex)
+		}
+		if n <= changeOutputs {
+			return split, nil
+		}
+	}
+}
+
+// draftTx returns an unsigned transaction with the inputs and outputs of the
+// planned one, which is enough to calculate its gas. The change outputs are
+// sent to the address of the first input, as their gas only depends on the
+// zone they are in.
+func draftTx(inputs []*Utxo, payments []Payment, changeOutputs uint64) *types.Transaction {
+	tx := &types.QiTx{
+		TxIn:  make(types.TxIns, 0, len(inputs)),
+		TxOut: make(types.TxOuts, 0, uint64(len(payments))+changeOutputs),
+	}
+	for _, in := range inputs {
+		tx.TxIn = append(tx.TxIn, types.TxIn{PreviousOutPoint: in.OutPoint})
+	}
+	for _, payment := range payments {
+		tx.TxOut = append(tx.TxOut, types.TxOut{Denomination: payment.Denomination, Address: payment.To.Bytes()})
+	}
+	for i := uint64(0); i < changeOutputs; i++ {
+		tx.TxOut = append(tx.TxOut, types.TxOut{Address: inputs[0].Address.Bytes()})
+	}
+	return types.NewTx(tx)
+}
+
+// splitChange splits the change of a transaction into the least number of
+// outputs with misc.FindMinDenominations, and then breaks down the outputs of
+// any denomination the inputs cannot cover without being combined, following
+// the rules of core.CheckDenominations. The counts are keyed by denomination.
+func splitChange(change *big.Int, inputs, outputs map[uint]uint64) (map[uint8]uint64, error) {
+	split := misc.FindMinDenominations(change)
+
+	// Walk the denominations from the largest, carrying what the inputs of a
+	// denomination leave over to the next smaller one
+	var carry uint64
+	for i := types.MaxDenomination; i >= 0; i-- {
+		available := inputs[uint(i)] + carry
+		if outputs[uint(i)] > available {
+			return nil, ErrCombineDenominations
+		}
+		available -= outputs[uint(i)]
+		if i == 0 {
+			if split[0] > available {
+				return nil, ErrCombineDenominations
+			}
+			break
+		}
+		ratio := new(big.Int).Div(types.Denominations[uint8(i)], types.Denominations[uint8(i-1)]).Uint64()
+		if excess := split[uint8(i)]; excess > available {
+			split[uint8(i-1)] += (excess - available) * ratio
+			split[uint8(i)] = available
+		}
+		carry = (available - split[uint8(i)]) * ratio
+	}
+	for denomination, count := range split {
+		if count == 0 {
+			delete(split, denomination)
+		}
+	}
+	return split, nil
+}
+
+// minimumFee returns the smallest fee in qits that pays for the gas at the
+// base fee of the header, converted at its Qi to Quai rate.
+func minimumFee(header *types.WorkObject, gas uint64) *big.Int {
+	feeInQuai := new(big.Int).Mul(new(big.Int).SetUint64(gas), header.BaseFee())
+
+	// misc.QiToQuai rounds down, so round up the conversion to not fall
+	// short of the base fee
+	quaiReward := misc.CalculateQuaiReward(header)
+	fee := new(big.Int).Mul(feeInQuai, misc.CalculateQiReward(header.WorkObjectHeader()))
+	fee.Add(fee, quaiReward)
+	fee.Sub(fee, common.Big1)
+	return fee.Quo(fee, quaiReward)
+}
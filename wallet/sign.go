@@ -0,0 +1,171 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/dominant-strategies/go-quai/core/types"
+)
+
+// signTx builds the transaction spending the inputs to the payments, sends the
+// change to new addresses of the wallet and signs it. The caller must hold the
+// write lock.
+func (w *Wallet) signTx(inputs []*Utxo, payments []Payment, change map[uint8]uint64) (*types.Transaction, error) {
+	tx := &types.QiTx{
+		ChainID: w.chainID,
+		TxIn:    make(types.TxIns, 0, len(inputs)),
+		TxOut:   make(types.TxOuts, 0, len(payments)+len(change)),
+	}
+	keys := make([]*btcec.PrivateKey, 0, len(inputs))
+	for _, in := range inputs {
+		key, ok := w.keys[in.Address.Bytes20()]
+		if !ok {
+			return nil, fmt.Errorf("no key for address %s", in.Address.Hex())
+		}
+		keys = append(keys, key)
+		tx.TxIn = append(tx.TxIn, types.TxIn{
+			PreviousOutPoint: in.OutPoint,
+			PubKey:           key.PubKey().SerializeUncompressed(),
+		})
+	}
+	for _, payment := range payments {
+		tx.TxOut = append(tx.TxOut, types.TxOut{Denomination: payment.Denomination, Address: payment.To.Bytes()})
+	}
+	for i := types.MaxDenomination; i >= 0; i-- {
+		for n := uint64(0); n < change[uint8(i)]; n++ {
+			addr, err := w.newAddress()
+			if err != nil {
+				return nil, err
+			}
+			tx.TxOut = append(tx.TxOut, types.TxOut{Denomination: uint8(i), Address: addr.Bytes()})
+		}
+	}
+	digest := w.signer.Hash(types.NewTx(tx))
+	sig, err := Sign(keys, digest)
+	if err != nil {
+		return nil, err
+	}
+	tx.Signature = sig
+	return types.NewTx(tx), nil
+}
+
+// AggregateKey returns the key that verifies the signature of a transaction
+// spending outputs of the given keys, in the order of its inputs. A single key
+// is returned as is, while multiple keys are aggregated with MuSig2 in the
+// way the transaction validation does.
+func AggregateKey(pubKeys []*btcec.PublicKey) (*btcec.PublicKey, error) {
+	switch len(pubKeys) {
+	case 0:
+		return nil, errors.New("no keys to aggregate")
+	case 1:
+		return pubKeys[0], nil
+	}
+	aggKey, _, _, err := musig2.AggregateKeys(pubKeys, false)
+	if err != nil {
+		return nil, err
+	}
+	return aggKey.FinalKey, nil
+}
+
+// Sign returns the schnorr signature of a transaction digest by the keys of
+// its inputs, in their order. The keys of multiple inputs produce a MuSig2
+// signature of their aggregate key, which is the only signature a Qi
+// transaction carries. A key may sign for several inputs.
+func Sign(keys []*btcec.PrivateKey, digest [32]byte) (*schnorr.Signature, error) {
+	switch len(keys) {
+	case 0:
+		return nil, errors.New("no keys to sign with")
+	case 1:
+		return schnorr.Sign(keys[0], digest[:])
+	}
+	pubKeys := make([]*btcec.PublicKey, len(keys))
+	for i, key := range keys {
+		pubKeys[i] = key.PubKey()
+	}
+	// Every input is a signer of its own, with a fresh nonce
+	nonces := make([]*musig2.Nonces, len(keys))
+	pubNonces := make([][musig2.PubNonceSize]byte, len(keys))
+	for i, key := range keys {
+		nonce, err := musig2.GenNonces(musig2.WithPublicKey(pubKeys[i]), musig2.WithNonceSecretKeyAux(key), musig2.WithNonceMessageAux(digest))
+		if err != nil {
+			return nil, err
+		}
+		nonces[i], pubNonces[i] = nonce, nonce.PubNonce
+	}
+	aggNonce, err := musig2.AggregateNonces(pubNonces)
+	if err != nil {
+		return nil, err
+	}
+	partialSigs := make([]*musig2.PartialSignature, len(keys))
+	for i, key := range keys {
+		if partialSigs[i], err = musig2.Sign(nonces[i].SecNonce, key, aggNonce, pubKeys, digest); err != nil {
+			return nil, err
+		}
+	}
+	return CombineSigs(pubKeys, pubNonces, partialSigs, digest)
+}
+
+// CombineSigs combines the MuSig2 partial signatures of a transaction digest
+// into the signature of the aggregate key of its inputs. The public nonces and
+// partial signatures are given in the order of the keys, which allows the
+// inputs of a transaction to be signed by different parties. The combined
+// signature is verified before it is returned.
+func CombineSigs(pubKeys []*btcec.PublicKey, pubNonces [][musig2.PubNonceSize]byte, partialSigs []*musig2.PartialSignature, digest [32]byte) (*schnorr.Signature, error) {
+	if len(pubNonces) != len(pubKeys) || len(partialSigs) != len(pubKeys) {
+		return nil, fmt.Errorf("have %d nonces and %d partial signatures for %d keys", len(pubNonces), len(partialSigs), len(pubKeys))
+	}
+	aggKey, err := AggregateKey(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	aggNonce, err := musig2.AggregateNonces(pubNonces)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := finalNonce(aggNonce, aggKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	sig := musig2.CombineSigs(nonce, partialSigs)
+	if !sig.Verify(digest[:], aggKey) {
+		return nil, errors.New("invalid aggregate signature")
+	}
+	return sig, nil
+}
+
+// finalNonce computes the nonce R of a MuSig2 signature from the aggregate of
+// the public nonces of its signers, R = R1 + b*R2 with the nonce coefficient
+// b = hash(aggNonce || aggKey || digest).
+func finalNonce(aggNonce [musig2.PubNonceSize]byte, aggKey *btcec.PublicKey, digest [32]byte) (*btcec.PublicKey, error) {
+	var buf bytes.Buffer
+	buf.Write(aggNonce[:])
+	buf.Write(schnorr.SerializePubKey(aggKey))
+	buf.Write(digest[:])
+
+	var b btcec.ModNScalar
+	b.SetByteSlice(chainhash.TaggedHash(musig2.NonceBlindTag, buf.Bytes())[:])
+
+	r1, err := btcec.ParseJacobian(aggNonce[:btcec.PubKeyBytesLenCompressed])
+	if err != nil {
+		return nil, err
+	}
+	r2, err := btcec.ParseJacobian(aggNonce[btcec.PubKeyBytesLenCompressed:])
+	if err != nil {
+		return nil, err
+	}
+	var r btcec.JacobianPoint
+	btcec.ScalarMultNonConst(&b, &r2, &r2)
+	btcec.AddNonConst(&r1, &r2, &r)
+
+	// A nonce at infinity is replaced by the generator
+	if r == (btcec.JacobianPoint{}) {
+		btcec.Generator().AsJacobian(&r)
+	}
+	r.ToAffine()
+	return btcec.NewPublicKey(&r.X, &r.Y), nil
+}
@@ -0,0 +1,229 @@
+// Package wallet implements a wallet for the Qi ledger. It tracks the UTXOs
+// owned by its keys through the outpoint index of a node, selects the inputs
+// and the change of payments in valid denominations, and signs the resulting
+// transactions, aggregating the keys of multiple inputs with MuSig2.
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+)
+
+// OutpointSource provides the unspent outputs owned by an address, as served
+// by the quai_getOutpointsByAddress RPC method. It is implemented by the
+// ethclient of the quaiclient package.
+type OutpointSource interface {
+	GetOutpointsByAddress(ctx context.Context, address common.MixedcaseAddress) ([]*types.OutpointAndDenomination, error)
+}
+
+// Utxo is an unspent output owned by one of the keys of the wallet.
+type Utxo struct {
+	types.OutPoint
+	Address      common.Address
+	Denomination uint8
+	Lock         *big.Int // Block number before which the output cannot be spent
+}
+
+// Value returns the value of the output in qits.
+func (u *Utxo) Value() *big.Int {
+	return types.Denominations[u.Denomination]
+}
+
+// spendable reports whether the output can be spent in a block following the
+// given one.
+func (u *Utxo) spendable(number *big.Int) bool {
+	return u.Lock == nil || u.Lock.Cmp(number) <= 0
+}
+
+// Wallet holds the keys of a set of Qi addresses of one zone and the UTXOs
+// owned by them.
+type Wallet struct {
+	location common.Location
+	chainID  *big.Int
+	signer   types.Signer
+
+	lock  sync.RWMutex
+	keys  map[common.AddressBytes]*btcec.PrivateKey
+	utxos map[types.OutPoint]*Utxo
+}
+
+// New creates an empty wallet for the zone at the given location.
+func New(chainID *big.Int, location common.Location) *Wallet {
+	return &Wallet{
+		location: location,
+		chainID:  new(big.Int).Set(chainID),
+		signer:   types.LatestSignerForChainID(chainID, location),
+		keys:     make(map[common.AddressBytes]*btcec.PrivateKey),
+		utxos:    make(map[types.OutPoint]*Utxo),
+	}
+}
+
+// Location returns the location of the zone the wallet spends in.
+func (w *Wallet) Location() common.Location {
+	return w.location
+}
+
+// Import adds a key to the wallet and returns its address. The address must
+// be in the Qi ledger scope of the zone of the wallet.
+func (w *Wallet) Import(key *ecdsa.PrivateKey) (common.Address, error) {
+	priv, _ := btcec.PrivKeyFromBytes(crypto.FromECDSA(key))
+	addr := w.address(priv.PubKey())
+	if !addr.Location().Equal(w.location) {
+		return common.Address{}, fmt.Errorf("address %s is not in zone %s", addr.Hex(), w.location.Name())
+	}
+	if !addr.IsInQiLedgerScope() {
+		return common.Address{}, fmt.Errorf("address %s is not in the Qi ledger scope", addr.Hex())
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.keys[addr.Bytes20()] = priv
+	return addr, nil
+}
+
+// NewAddress generates a new key and returns its address.
+func (w *Wallet) NewAddress() (common.Address, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.newAddress()
+}
+
+// newAddress generates keys until one of them has an address in the Qi ledger
+// scope of the zone of the wallet, and adds it to the wallet. The caller must
+// hold the write lock.
+func (w *Wallet) newAddress() (common.Address, error) {
+	for {
+		priv, err := btcec.NewPrivateKey()
+		if err != nil {
+			return common.Address{}, err
+		}
+		addr := w.address(priv.PubKey())
+		if addr.Location().Equal(w.location) && addr.IsInQiLedgerScope() {
+			w.keys[addr.Bytes20()] = priv
+			return addr, nil
+		}
+	}
+}
+
+// address returns the address of a public key, which is derived from its
+// uncompressed encoding as the transaction validation does.
+func (w *Wallet) address(pub *btcec.PublicKey) common.Address {
+	return crypto.PubkeyBytesToAddress(pub.SerializeUncompressed(), w.location)
+}
+
+// Addresses returns the addresses of all the keys of the wallet.
+func (w *Wallet) Addresses() []common.Address {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	addresses := make([]common.Address, 0, len(w.keys))
+	for addr := range w.keys {
+		addresses = append(addresses, common.Bytes20ToAddress(addr, w.location))
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Hex() < addresses[j].Hex()
+	})
+	return addresses
+}
+
+// Refresh replaces the UTXOs of the wallet with the ones the source reports
+// for its addresses.
+func (w *Wallet) Refresh(ctx context.Context, source OutpointSource) error {
+	utxos := make(map[types.OutPoint]*Utxo)
+	for _, addr := range w.Addresses() {
+		outpoints, err := source.GetOutpointsByAddress(ctx, addr.MixedcaseAddress())
+		if err != nil {
+			return err
+		}
+		for _, outpoint := range outpoints {
+			if outpoint == nil {
+				continue
+			}
+			if outpoint.Denomination > types.MaxDenomination {
+				return fmt.Errorf("outpoint %032x:%d of %s has invalid denomination %d", outpoint.TxHash, outpoint.Index, addr.Hex(), outpoint.Denomination)
+			}
+			utxo := &Utxo{
+				OutPoint:     types.OutPoint{TxHash: outpoint.TxHash, Index: outpoint.Index},
+				Address:      addr,
+				Denomination: outpoint.Denomination,
+			}
+			if outpoint.Lock != nil {
+				utxo.Lock = new(big.Int).Set(outpoint.Lock)
+			}
+			utxos[utxo.OutPoint] = utxo
+		}
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.utxos = utxos
+	return nil
+}
+
+// Utxos returns the UTXOs of the wallet, largest denomination first.
+func (w *Wallet) Utxos() []*Utxo {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	utxos := make([]*Utxo, 0, len(w.utxos))
+	for _, utxo := range w.utxos {
+		utxos = append(utxos, utxo)
+	}
+	sortUtxos(utxos)
+	return utxos
+}
+
+// Balance returns the total value in qits of the UTXOs of the wallet that can
+// be spent in a block following the given one.
+func (w *Wallet) Balance(number *big.Int) *big.Int {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	balance := new(big.Int)
+	for _, utxo := range w.utxos {
+		if utxo.spendable(number) {
+			balance.Add(balance, utxo.Value())
+		}
+	}
+	return balance
+}
+
+// MarkSpent removes the UTXOs spent by a transaction from the wallet, so that
+// they are not selected again before the next refresh.
+func (w *Wallet) MarkSpent(tx *types.Transaction) error {
+	if tx.Type() != types.QiTxType {
+		return errors.New("not a Qi transaction")
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, in := range tx.TxIn() {
+		delete(w.utxos, in.PreviousOutPoint)
+	}
+	return nil
+}
+
+// sortUtxos sorts UTXOs by denomination in descending order, breaking ties by
+// outpoint so that the coin selection is deterministic.
+func sortUtxos(utxos []*Utxo) {
+	sort.Slice(utxos, func(i, j int) bool {
+		if utxos[i].Denomination != utxos[j].Denomination {
+			return utxos[i].Denomination > utxos[j].Denomination
+		}
+		if utxos[i].TxHash != utxos[j].TxHash {
+			return utxos[i].TxHash.Big().Cmp(utxos[j].TxHash.Big()) < 0
+		}
+		return utxos[i].Index < utxos[j].Index
+	})
+}
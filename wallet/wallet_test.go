@@ -0,0 +1,388 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/dominant-strategies/go-quai/common"
+	"github.com/dominant-strategies/go-quai/consensus/misc"
+	"github.com/dominant-strategies/go-quai/core"
+	"github.com/dominant-strategies/go-quai/core/types"
+	"github.com/dominant-strategies/go-quai/crypto"
+	"github.com/dominant-strategies/go-quai/params"
+)
+
+var (
+	testLocation = common.Location{0, 0}
+	testChainID  = big.NewInt(1337)
+)
+
+// testSource serves the outpoints of addresses from memory.
+type testSource map[common.AddressBytes][]*types.OutpointAndDenomination
+
+func (s testSource) GetOutpointsByAddress(ctx context.Context, address common.MixedcaseAddress) ([]*types.OutpointAndDenomination, error) {
+	return s[address.Address().Bytes20()], nil
+}
+
+// fund adds an outpoint of the given denomination to the address, with a hash
+// derived from the number of outpoints already in the source.
+func (s testSource) fund(addr common.Address, denomination uint8, lock int64) {
+	n := 0
+	for _, outpoints := range s {
+		n += len(outpoints)
+	}
+	s[addr.Bytes20()] = append(s[addr.Bytes20()], &types.OutpointAndDenomination{
+		TxHash:       common.BigToHash(big.NewInt(int64(n + 1))),
+		Denomination: denomination,
+		Lock:         big.NewInt(lock),
+	})
+}
+
+func testHeader() *types.WorkObject {
+	header := types.EmptyZoneWorkObject()
+	header.WorkObjectHeader().SetNumber(big.NewInt(100))
+	header.WorkObjectHeader().SetDifficulty(big.NewInt(1000000000000))
+	header.Header().SetBaseFee(big.NewInt(100 * params.GWei))
+	header.Header().SetExchangeRate(params.ExchangeRate)
+	return header
+}
+
+// recipient returns a Qi address of the test zone, which does not belong to
+// any wallet.
+func recipient(t *testing.T) common.Address {
+	addr, err := New(testChainID, testLocation).NewAddress()
+	if err != nil {
+		t.Fatalf("failed to generate address: %v", err)
+	}
+	return addr
+}
+
+// checkTx verifies a transaction of the wallet against the rules of the Qi
+// transaction validation: known and unlocked inputs spent with matching keys,
+// no address reuse, no combined denominations, the fee and the signature.
+func checkTx(t *testing.T, w *Wallet, header *types.WorkObject, tx *types.Transaction) {
+	t.Helper()
+
+	utxos := make(map[types.OutPoint]*Utxo)
+	for _, utxo := range w.Utxos() {
+		utxos[utxo.OutPoint] = utxo
+	}
+	addresses := make(map[common.AddressBytes]struct{})
+	inputs, outputs := make(map[uint]uint64), make(map[uint]uint64)
+	totalIn, totalOut := new(big.Int), new(big.Int)
+	pubKeys := make([]*btcec.PublicKey, 0, len(tx.TxIn()))
+	for _, in := range tx.TxIn() {
+		utxo, ok := utxos[in.PreviousOutPoint]
+		if !ok {
+			t.Fatalf("tx spends unknown outpoint %x:%d", in.PreviousOutPoint.TxHash, in.PreviousOutPoint.Index)
+		}
+		if !utxo.spendable(header.Number(common.ZONE_CTX)) {
+			t.Fatalf("tx spends locked outpoint %x:%d", in.PreviousOutPoint.TxHash, in.PreviousOutPoint.Index)
+		}
+		if addr := crypto.PubkeyBytesToAddress(in.PubKey, testLocation); !addr.Equal(utxo.Address) {
+			t.Fatalf("input pubkey of %s spends outpoint of %s", addr.Hex(), utxo.Address.Hex())
+		}
+		pubKey, err := btcec.ParsePubKey(in.PubKey)
+		if err != nil {
+			t.Fatalf("invalid input pubkey: %v", err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+		addresses[utxo.Address.Bytes20()] = struct{}{}
+		inputs[uint(utxo.Denomination)]++
+		totalIn.Add(totalIn, utxo.Value())
+	}
+	for _, out := range tx.TxOut() {
+		addr := common.BytesToAddress(out.Address, testLocation)
+		if _, exists := addresses[addr.Bytes20()]; exists {
+			t.Fatalf("tx reuses address %s", addr.Hex())
+		}
+		addresses[addr.Bytes20()] = struct{}{}
+		outputs[uint(out.Denomination)]++
+		totalOut.Add(totalOut, types.Denominations[out.Denomination])
+	}
+	if err := core.CheckDenominations(inputs, outputs); err != nil {
+		t.Fatalf("invalid denominations: %v", err)
+	}
+	gas := types.CalculateQiTxGas(tx, 0, testLocation)
+	fee := new(big.Int).Sub(totalIn, totalOut)
+	if want := minimumFee(header, gas); fee.Cmp(want) != 0 {
+		t.Fatalf("fee mismatch: have %v, want %v", fee, want)
+	}
+	required := new(big.Int).Mul(new(big.Int).SetUint64(gas), header.BaseFee())
+	if have := misc.QiToQuai(header, fee); have.Cmp(required) < 0 {
+		t.Fatalf("fee below base fee: have %v, want %v", have, required)
+	}
+	aggKey, err := AggregateKey(pubKeys)
+	if err != nil {
+		t.Fatalf("failed to aggregate keys: %v", err)
+	}
+	digest := w.signer.Hash(tx)
+	if !tx.GetSchnorrSignature().Verify(digest[:], aggKey) {
+		t.Fatalf("invalid signature")
+	}
+}
+
+func TestCreateTransactionSingleInput(t *testing.T) {
+	w := New(testChainID, testLocation)
+	owner, _ := w.NewAddress()
+	locked, _ := w.NewAddress()
+
+	source := testSource{}
+	source.fund(owner, 6, 0)    // 1000 qits
+	source.fund(owner, 5, 0)    // 500 qits
+	source.fund(locked, 9, 200) // 20000 qits, locked past the header
+	if err := w.Refresh(context.Background(), source); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	header := testHeader()
+	if balance := w.Balance(header.Number(common.ZONE_CTX)); balance.Cmp(big.NewInt(1500)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want 1500", balance)
+	}
+	to := recipient(t)
+	tx, err := w.CreateTransaction(header, 0, []Payment{{To: to, Denomination: 4}})
+	if err != nil {
+		t.Fatalf("failed to create transaction: %v", err)
+	}
+	checkTx(t, w, header, tx)
+
+	// The smallest single UTXO covering the payment is spent, and the change
+	// goes to new addresses of the wallet
+	if len(tx.TxIn()) != 1 || tx.TxIn()[0].PreviousOutPoint.TxHash != common.BigToHash(big.NewInt(2)) {
+		t.Fatalf("unexpected inputs: %v", tx.TxIn())
+	}
+	if out := tx.TxOut()[0]; out.Denomination != 4 || !bytes.Equal(out.Address, to.Bytes()) {
+		t.Fatalf("unexpected payment output: %+v", out)
+	}
+	owned := make(map[common.AddressBytes]bool)
+	for _, addr := range w.Addresses() {
+		owned[addr.Bytes20()] = true
+	}
+	for _, out := range tx.TxOut()[1:] {
+		if !owned[common.AddressBytes(out.Address)] {
+			t.Fatalf("change output to unknown address %x", out.Address)
+		}
+	}
+	if err := w.MarkSpent(tx); err != nil {
+		t.Fatalf("failed to mark spent: %v", err)
+	}
+	if balance := w.Balance(header.Number(common.ZONE_CTX)); balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("balance mismatch after spend: have %v, want 1000", balance)
+	}
+}
+
+func TestCreateTransactionMultipleInputs(t *testing.T) {
+	w := New(testChainID, testLocation)
+	first, _ := w.NewAddress()
+	second, _ := w.NewAddress()
+
+	// Two of the inputs are spent with the same key
+	source := testSource{}
+	source.fund(first, 4, 0)
+	source.fund(first, 4, 0)
+	source.fund(second, 4, 0)
+	if err := w.Refresh(context.Background(), source); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	header := testHeader()
+	tx, err := w.CreateTransaction(header, 0, []Payment{{To: recipient(t), Denomination: 4}, {To: recipient(t), Denomination: 4}})
+	if err != nil {
+		t.Fatalf("failed to create transaction: %v", err)
+	}
+	if len(tx.TxIn()) != 3 {
+		t.Fatalf("unexpected number of inputs: have %d, want 3", len(tx.TxIn()))
+	}
+	checkTx(t, w, header, tx)
+}
+
+func TestCreateTransactionFailures(t *testing.T) {
+	w := New(testChainID, testLocation)
+	owner, _ := w.NewAddress()
+
+	source := testSource{}
+	for i := 0; i < 20; i++ {
+		source.fund(owner, 2, 0) // 10 qits each
+	}
+	if err := w.Refresh(context.Background(), source); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	header := testHeader()
+
+	// The 200 qits of the wallet cover a payment of 100, but only by
+	// combining smaller denominations
+	if _, err := w.CreateTransaction(header, 0, []Payment{{To: recipient(t), Denomination: 4}}); !errors.Is(err, ErrCombineDenominations) {
+		t.Fatalf("combining denominations: have %v, want %v", err, ErrCombineDenominations)
+	}
+	if _, err := w.CreateTransaction(header, 0, []Payment{{To: recipient(t), Denomination: 6}}); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("overspending: have %v, want %v", err, ErrInsufficientFunds)
+	}
+	// An address of the wallet cannot be paid with its own outputs
+	if _, err := w.CreateTransaction(header, 0, []Payment{{To: owner, Denomination: 1}}); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("paying an input address: have %v, want %v", err, ErrInsufficientFunds)
+	}
+	if _, err := w.CreateTransaction(header, 0, []Payment{{To: common.ZeroAddress(testLocation), Denomination: 1}}); err == nil {
+		t.Fatalf("payment to the Quai ledger accepted")
+	}
+	to := recipient(t)
+	if _, err := w.CreateTransaction(header, 0, []Payment{{To: to, Denomination: 1}, {To: to, Denomination: 1}}); err == nil {
+		t.Fatalf("duplicate payment accepted")
+	}
+}
+
+func TestSplitChange(t *testing.T) {
+	tests := []struct {
+		change  int64
+		inputs  map[uint]uint64
+		outputs map[uint]uint64
+		want    map[uint8]uint64
+		err     error
+	}{
+		// The least number of outputs, as covered by the inputs
+		{1500, map[uint]uint64{6: 1, 5: 2}, map[uint]uint64{5: 1}, map[uint8]uint64{6: 1, 5: 1}, nil},
+		// A 1000 qit output would combine the 500 qit inputs
+		{1000, map[uint]uint64{5: 3}, map[uint]uint64{5: 1}, map[uint8]uint64{5: 2}, nil},
+		// Broken down over several denominations
+		{1120, map[uint]uint64{5: 2, 4: 2}, nil, map[uint8]uint64{5: 2, 4: 1, 2: 2}, nil},
+		{100, map[uint]uint64{2: 20}, map[uint]uint64{4: 1}, nil, ErrCombineDenominations},
+	}
+	for i, tt := range tests {
+		split, err := splitChange(big.NewInt(tt.change), tt.inputs, tt.outputs)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			continue
+		}
+		if len(split) != len(tt.want) {
+			t.Errorf("test %d: split mismatch: have %v, want %v", i, split, tt.want)
+			continue
+		}
+		for denomination, count := range tt.want {
+			if split[denomination] != count {
+				t.Errorf("test %d: split mismatch: have %v, want %v", i, split, tt.want)
+				break
+			}
+		}
+	}
+}
+
+type keyAggVectors struct {
+	Pubkeys        []string `json:"pubkeys"`
+	ValidTestCases []struct {
+		KeyIndices []int  `json:"key_indices"`
+		Expected   string `json:"expected"`
+	} `json:"valid_test_cases"`
+}
+
+type sigAggVectors struct {
+	Pubkeys        []string `json:"pubkeys"`
+	PNonces        []string `json:"pnonces"`
+	PSigs          []string `json:"psigs"`
+	Msg            string   `json:"msg"`
+	ValidTestCases []struct {
+		NonceIndices []int  `json:"nonce_indices"`
+		KeyIndices   []int  `json:"key_indices"`
+		TweakIndices []int  `json:"tweak_indices"`
+		PSigIndices  []int  `json:"psig_indices"`
+		Expected     string `json:"expected"`
+	} `json:"valid_test_cases"`
+}
+
+func loadVectors(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile("../core/types/" + name)
+	if err != nil {
+		t.Fatalf("failed to read vectors: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode vectors: %v", err)
+	}
+}
+
+func parsePubKeys(t *testing.T, pubkeys []string, indices []int) []*btcec.PublicKey {
+	keys := make([]*btcec.PublicKey, len(indices))
+	for i, idx := range indices {
+		key, err := btcec.ParsePubKey(common.FromHex(pubkeys[idx]))
+		if err != nil {
+			t.Fatalf("failed to parse pubkey %d: %v", idx, err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestAggregateKeyVectors(t *testing.T) {
+	var vectors keyAggVectors
+	loadVectors(t, "key_agg_vectors.json", &vectors)
+
+	for i, tt := range vectors.ValidTestCases {
+		aggKey, err := AggregateKey(parsePubKeys(t, vectors.Pubkeys, tt.KeyIndices))
+		if err != nil {
+			t.Fatalf("test %d: failed to aggregate keys: %v", i, err)
+		}
+		if have := aggKey.SerializeCompressed()[1:]; !bytes.Equal(have, common.FromHex(tt.Expected)) {
+			t.Errorf("test %d: aggregate key mismatch: have %x, want %s", i, have, tt.Expected)
+		}
+	}
+}
+
+func TestCombineSigsVectors(t *testing.T) {
+	var vectors sigAggVectors
+	loadVectors(t, "sig_agg_vectors.json", &vectors)
+
+	var digest [32]byte
+	copy(digest[:], common.FromHex(vectors.Msg))
+	for i, tt := range vectors.ValidTestCases {
+		// Transactions are signed by the untweaked aggregate key
+		if len(tt.TweakIndices) > 0 {
+			continue
+		}
+		pubNonces := make([][musig2.PubNonceSize]byte, len(tt.NonceIndices))
+		for j, idx := range tt.NonceIndices {
+			copy(pubNonces[j][:], common.FromHex(vectors.PNonces[idx]))
+		}
+		partialSigs := make([]*musig2.PartialSignature, len(tt.PSigIndices))
+		for j, idx := range tt.PSigIndices {
+			partialSigs[j] = new(musig2.PartialSignature)
+			if err := partialSigs[j].Decode(bytes.NewReader(common.FromHex(vectors.PSigs[idx]))); err != nil {
+				t.Fatalf("test %d: failed to decode partial signature %d: %v", i, idx, err)
+			}
+		}
+		sig, err := CombineSigs(parsePubKeys(t, vectors.Pubkeys, tt.KeyIndices), pubNonces, partialSigs, digest)
+		if err != nil {
+			t.Fatalf("test %d: failed to combine signatures: %v", i, err)
+		}
+		if have := sig.Serialize(); !bytes.Equal(have, common.FromHex(tt.Expected)) {
+			t.Errorf("test %d: signature mismatch: have %x, want %s", i, have, tt.Expected)
+		}
+	}
+}
+
+func TestSignMultipleKeys(t *testing.T) {
+	keys := make([]*btcec.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = btcec.NewPrivateKey()
+	}
+	// The same key signing for several inputs
+	keys = append(keys, keys[0])
+
+	pubKeys := make([]*btcec.PublicKey, len(keys))
+	for i, key := range keys {
+		pubKeys[i] = key.PubKey()
+	}
+	digest := crypto.Keccak256Hash([]byte("qi"))
+	sig, err := Sign(keys, digest)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	aggKey, _, _, err := musig2.AggregateKeys(pubKeys, false)
+	if err != nil {
+		t.Fatalf("failed to aggregate keys: %v", err)
+	}
+	if !sig.Verify(digest[:], aggKey.FinalKey) {
+		t.Fatalf("invalid aggregate signature")
+	}
+}